@@ -1,23 +1,28 @@
 package main
 
 import (
+	"crypto/subtle"
 	"os"
 	urlpath "path"
 	"path/filepath"
 	"strings"
 
+	"go-fiber-starter/internal/api/admin"
 	"go-fiber-starter/internal/api/auth"
+	"go-fiber-starter/internal/api/health"
 	lotteryApi "go-fiber-starter/internal/api/lottery"
+	"go-fiber-starter/internal/metrics"
 	"go-fiber-starter/internal/middleware"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	fiberLogger "github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	jwtware "github.com/gofiber/jwt/v3"
 	"github.com/gofiber/swagger"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func api() {
@@ -30,26 +35,24 @@ func api() {
 
 	app.Use(recover.New())
 	app.Use(cors.New())
-	app.Use(fiberLogger.New(fiberLogger.Config{
-		Format: "${ip} ${status} ${latency} ${method} ${path}\n",
-		Output: logger.GetFiberLogWriter(),
-	}))
+	app.Use(middleware.RequestID)
+	app.Use(middleware.AccessLog)
+	app.Use(middleware.Tracing)
+	app.Use(middleware.Metrics)
 
+	registerMetrics(app)
+	health.RegisterRoutes(app)
 	auth.RegisterUnProtectedRoutes(app)
 
 	api := app.Group("/api")
-	api.Use(jwtware.New(jwtware.Config{
-		SigningKey: []byte(config.Current.Jwt.Secret),
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			logger.Error("JWT验证失败: %v", err)
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"code":    fiber.StatusUnauthorized,
-				"message": "认证失败，请先登录",
-			})
-		},
-	}))
+	api.Use(middleware.Authenticate(jwtware.New(jwtware.Config{
+		SigningKey:   []byte(config.Current.Jwt.Secret),
+		ErrorHandler: middleware.JWTErrorHandler,
+	})))
+	api.Use(middleware.RequireActiveUser)
 
 	auth.RegisterRoutes(api)
+	admin.RegisterRoutes(api)
 	lotteryApi.RegisterRoutes(api)
 	registerFrontend(app)
 
@@ -59,6 +62,24 @@ func api() {
 	logger.Info("服务器启动成功: http://127.0.0.1:%v ", config.Current.App.Port)
 }
 
+// registerMetrics 暴露 Prometheus 指标，配置了 metrics.token 时校验 Bearer 令牌。
+func registerMetrics(app *fiber.App) {
+	if !config.Current.Metrics.Enabled {
+		return
+	}
+	handler := adaptor.HTTPHandler(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+	app.Get("/metrics", func(c *fiber.Ctx) error {
+		token := config.Current.Metrics.Token
+		if token != "" {
+			provided := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				return c.SendStatus(fiber.StatusUnauthorized)
+			}
+		}
+		return handler(c)
+	})
+}
+
 func registerFrontend(app *fiber.App) {
 	webRoot := filepath.Join(".", "web")
 	indexPath := filepath.Join(webRoot, "index.html")
@@ -73,7 +94,7 @@ func registerFrontend(app *fiber.App) {
 	})
 
 	app.Use(func(c *fiber.Ctx) error {
-		if strings.HasPrefix(c.Path(), "/api") || strings.HasPrefix(c.Path(), "/swagger") || strings.HasPrefix(c.Path(), "/uploads") {
+		if strings.HasPrefix(c.Path(), "/api") || strings.HasPrefix(c.Path(), "/swagger") || strings.HasPrefix(c.Path(), "/uploads") || c.Path() == "/metrics" {
 			return fiber.ErrNotFound
 		}
 		setNoStoreHeaders(c)
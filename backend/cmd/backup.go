@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"go-fiber-starter/internal/service/backup"
+	"go-fiber-starter/pkg/db"
+)
+
+const backupUsage = `用法:
+  backup create            立即备份数据库和上传目录，并按保留策略清理旧备份
+  backup list              查看备份目录中的全部备份
+  backup verify <备份>     解压并校验备份，不修改任何数据
+  backup restore <备份>    校验通过后用备份替换当前数据库和上传目录，执行前请先停止服务
+
+<备份> 可以是备份目录中的文件名，也可以是备份文件的完整路径。`
+
+func runBackupCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("缺少 backup 子命令\n%s", backupUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		if err := db.Open(); err != nil {
+			return fmt.Errorf("连接数据库失败: %w", err)
+		}
+		result, err := backup.Create(context.Background())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("已生成备份 %s（%d 字节，迁移版本 %d）\n", result.Archive.Name, result.Archive.Size, result.Manifest.SchemaVersion)
+		for _, name := range result.Pruned {
+			fmt.Printf("已清理旧备份 %s\n", name)
+		}
+		return nil
+	case "list":
+		return printBackups()
+	case "verify":
+		if len(args) != 2 {
+			return fmt.Errorf("请指定要校验的备份\n%s", backupUsage)
+		}
+		manifest, err := backup.Verify(args[1])
+		if err != nil {
+			return err
+		}
+		printBackupManifest(manifest)
+		fmt.Println("校验通过")
+		return nil
+	case "restore":
+		if len(args) != 2 {
+			return fmt.Errorf("请指定要恢复的备份\n%s", backupUsage)
+		}
+		result, err := backup.Restore(context.Background(), args[1])
+		if err != nil {
+			return err
+		}
+		printBackupManifest(&result.Manifest)
+		if result.SafetyBackup != "" {
+			fmt.Printf("恢复前的数据已备份为 %s\n", result.SafetyBackup)
+		}
+		if result.PreviousDatabase != "" {
+			fmt.Printf("原数据库文件已保留为 %s\n", result.PreviousDatabase)
+		}
+		if result.PreviousUploads != "" {
+			fmt.Printf("原上传目录已保留为 %s\n", result.PreviousUploads)
+		}
+		if latest := db.LatestSchemaVersion(); result.Manifest.SchemaVersion < latest {
+			fmt.Printf("备份的迁移版本为 %d，当前程序为 %d，启动服务前请运行 migrate up 或开启 migrateOnStart\n", result.Manifest.SchemaVersion, latest)
+		}
+		fmt.Println("恢复完成")
+		return nil
+	default:
+		return fmt.Errorf("未知的 backup 子命令 %q\n%s", args[0], backupUsage)
+	}
+}
+
+func printBackups() error {
+	archives, err := backup.List()
+	if err != nil {
+		return err
+	}
+	if len(archives) == 0 {
+		fmt.Println("还没有备份")
+		return nil
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "文件名\t大小（字节）\t备份时间")
+	for _, archive := range archives {
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", archive.Name, archive.Size, archive.CreatedAt.Format(time.DateTime))
+	}
+	return writer.Flush()
+}
+
+func printBackupManifest(manifest *backup.Manifest) {
+	fmt.Printf("备份时间 %s，驱动 %s，格式 %s，迁移版本 %d，文件 %d 个\n",
+		manifest.CreatedAt.Local().Format(time.DateTime), manifest.Driver, manifest.Format, manifest.SchemaVersion, len(manifest.Files))
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "数据表\t行数")
+	for _, table := range manifest.Tables {
+		fmt.Fprintf(writer, "%s\t%d\n", table.Name, table.Rows)
+	}
+	_ = writer.Flush()
+}
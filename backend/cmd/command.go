@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// runCommand 执行运维子命令，例如 ./lottery migrate status。子命令只连接数据库，不启动 HTTP 服务和定时任务。
+func runCommand(name string, args []string) error {
+	switch name {
+	case "migrate":
+		return runMigrateCommand(args)
+	case "backup":
+		return runBackupCommand(args)
+	case "copy-db":
+		return runCopyDBCommand(args)
+	default:
+		return fmt.Errorf("未知命令 %q，可用命令: %s", name, strings.Join([]string{"migrate", "backup", "copy-db"}, ", "))
+	}
+}
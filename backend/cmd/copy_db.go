@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+)
+
+const copyDBUsage = `用法:
+  copy-db [-from-driver 驱动] [-from-path 路径] [-from-dsn 连接串]
+          [-to-driver 驱动] [-to-path 路径] [-to-dsn 连接串] [-truncate]
+
+源库和目标库默认都使用配置中的 database，参数只覆盖对应字段，两者不能指向同一个数据库。
+例如配置仍为 SQLite 时，copy-db -to-driver postgres 会把数据复制到配置中填写的 PostgreSQL。
+目标库已有数据时需要加 -truncate 才会清空后复制。上传目录中的文件不会复制。`
+
+func runCopyDBCommand(args []string) error {
+	flags := flag.NewFlagSet("copy-db", flag.ContinueOnError)
+	fromDriver := flags.String("from-driver", "", "源库驱动，sqlite 或 postgres")
+	fromPath := flags.String("from-path", "", "源库 SQLite 文件路径")
+	fromDSN := flags.String("from-dsn", "", "源库 PostgreSQL 连接串")
+	toDriver := flags.String("to-driver", "", "目标库驱动，sqlite 或 postgres")
+	toPath := flags.String("to-path", "", "目标库 SQLite 文件路径")
+	toDSN := flags.String("to-dsn", "", "目标库 PostgreSQL 连接串")
+	truncate := flags.Bool("truncate", false, "目标库已有数据时先清空")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w\n%s", err, copyDBUsage)
+	}
+
+	sourceConfig := overrideDatabaseConfig(config.Current.Database, *fromDriver, *fromPath, *fromDSN)
+	targetConfig := overrideDatabaseConfig(config.Current.Database, *toDriver, *toPath, *toDSN)
+	sourceName, targetName := db.DescribeConfig(sourceConfig), db.DescribeConfig(targetConfig)
+	if sourceName == targetName {
+		return fmt.Errorf("源库和目标库都是 %s，请用 -from-* 或 -to-* 指定另一个数据库\n%s", sourceName, copyDBUsage)
+	}
+	if db.IsSQLiteConfig(sourceConfig) {
+		if _, err := os.Stat(sourceConfig.Path); err != nil {
+			return fmt.Errorf("源库文件 %s 不存在", sourceConfig.Path)
+		}
+	}
+
+	source, err := db.OpenConfig(sourceConfig)
+	if err != nil {
+		return fmt.Errorf("连接源库失败: %w", err)
+	}
+	defer db.CloseDatabase(source)
+	target, err := db.OpenConfig(targetConfig)
+	if err != nil {
+		return fmt.Errorf("连接目标库失败: %w", err)
+	}
+	defer db.CloseDatabase(target)
+
+	fmt.Printf("正在从 %s 复制到 %s\n", sourceName, targetName)
+	stats, err := db.CopyDatabase(source, target, db.CopyOptions{Truncate: *truncate})
+	if len(stats) > 0 {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "数据表\t源库行数\t目标库行数\t结果")
+		for _, stat := range stats {
+			result := "一致"
+			if stat.SourceRows != stat.TargetRows {
+				result = "不一致"
+			}
+			fmt.Fprintf(writer, "%s\t%d\t%d\t%s\n", stat.Table, stat.SourceRows, stat.TargetRows, result)
+		}
+		_ = writer.Flush()
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("复制完成，各表行数一致")
+	return nil
+}
+
+// overrideDatabaseConfig 在配置的基础上覆盖驱动、SQLite 路径和 PostgreSQL 连接串，空值表示沿用配置。
+func overrideDatabaseConfig(base config.DatabaseConfig, driver string, path string, dsn string) config.DatabaseConfig {
+	if driver != "" {
+		base.Driver = driver
+	}
+	if path != "" {
+		base.Path = path
+	}
+	if dsn != "" {
+		base.DSN = dsn
+	}
+	return base
+}
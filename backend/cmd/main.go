@@ -9,25 +9,60 @@
 package main
 
 import (
+	"context"
+	"os"
+	"time"
+
 	_ "go-fiber-starter/docs"
 	lotteryService "go-fiber-starter/internal/service/lottery"
+	"go-fiber-starter/internal/tracing"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/db"
 	"go-fiber-starter/pkg/logger"
 )
 
 func main() {
-	if err := logger.Init(); err != nil {
+	if err := logger.Init(logger.DefaultOptions()); err != nil {
 		panic(err)
 	}
 
 	if err := config.Init(); err != nil {
 		logger.Fatal("加载配置失败: %v", err)
 	}
+	if err := logger.Init(logger.Options{
+		Level:      config.Current.Log.Level,
+		Paths:      config.Current.Log.Paths,
+		MaxSizeMB:  config.Current.Log.MaxSizeMB,
+		MaxBackups: config.Current.Log.MaxBackups,
+		MaxAgeDays: config.Current.Log.MaxAgeDays,
+	}); err != nil {
+		logger.Fatal("初始化日志失败: %v", err)
+	}
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			logger.Fatal("%v", err)
+		}
+		return
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		logger.Fatal("初始化链路追踪失败: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Warn("刷新链路追踪数据失败: %v", err)
+		}
+	}()
 
 	if err := db.Init(); err != nil {
 		logger.Fatal("初始化数据库失败: %v", err)
 	}
+	if err := db.DB.Use(tracing.GormPlugin{}); err != nil {
+		logger.Fatal("注册数据库链路追踪失败: %v", err)
+	}
 
 	if err := lotteryService.Bootstrap(); err != nil {
 		logger.Fatal("初始化彩票模块失败: %v", err)
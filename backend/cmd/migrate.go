@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"go-fiber-starter/pkg/db"
+)
+
+const migrateUsage = `用法:
+  migrate status             查看全部迁移及执行状态
+  migrate up [-to 版本]      执行未执行的迁移，指定 -to 时只执行到该版本
+  migrate down [-steps 数量] 回滚最近执行的迁移，默认 1 个，基线迁移不会回滚`
+
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("缺少 migrate 子命令\n%s", migrateUsage)
+	}
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	to := flags.Int64("to", 0, "执行到的迁移版本，0 表示全部")
+	steps := flags.Int("steps", 1, "回滚的迁移数量")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if err := db.Open(); err != nil {
+		return fmt.Errorf("连接数据库失败: %w", err)
+	}
+
+	switch args[0] {
+	case "status":
+		return printMigrationStatus()
+	case "up":
+		executed, err := db.MigrateUp(*to)
+		for _, item := range executed {
+			fmt.Printf("已执行 %d_%s\n", item.Version, item.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(executed) == 0 {
+			fmt.Println("没有需要执行的迁移")
+		}
+		return nil
+	case "down":
+		if *steps <= 0 {
+			return fmt.Errorf("-steps 必须大于 0")
+		}
+		rolledBack, err := db.MigrateDown(*steps)
+		for _, item := range rolledBack {
+			fmt.Printf("已回滚 %d_%s\n", item.Version, item.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("没有可以回滚的迁移")
+		}
+		return nil
+	default:
+		return fmt.Errorf("未知的 migrate 子命令 %q\n%s", args[0], migrateUsage)
+	}
+}
+
+func printMigrationStatus() error {
+	states, err := db.MigrationStatus()
+	if err != nil {
+		return err
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "版本\t名称\t状态\t执行时间")
+	for _, state := range states {
+		status := "未执行"
+		appliedAt := "-"
+		if state.Applied {
+			status = "已执行"
+			appliedAt = state.AppliedAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", state.Version, state.Name, status, appliedAt)
+	}
+	return writer.Flush()
+}
@@ -15,238 +15,215 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "用户登录接口",
-                "consumes": [
-                    "application/json"
+        "/admin/backups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "按时间倒序返回备份目录中的全部备份文件",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
-                ],
-                "summary": "用户登录",
-                "parameters": [
-                    {
-                        "description": "登录信息",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_api_auth.AuthRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "获取备份列表",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_auth.LoginResponse"
+                            "$ref": "#/definitions/internal_api_admin.BackupListResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/auth/profile": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "返回当前登录用户信息",
+                "description": "备份数据库和上传目录并按保留策略清理旧备份，已有备份任务执行时返回 409",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "获取当前用户",
+                "summary": "立即备份",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_auth.UserResponse"
+                            "$ref": "#/definitions/internal_api_admin.BackupCreateResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/register": {
+        "/admin/backups/{name}/verify": {
             "post": {
-                "description": "创建账号并返回用户信息",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "解压备份并核对文件摘要、迁移版本和各表行数，不修改任何数据；恢复需要停止服务后使用 backup restore 命令",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "用户注册",
+                "summary": "校验备份",
                 "parameters": [
                     {
-                        "description": "注册信息",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_api_auth.AuthRequest"
-                        }
+                        "type": "string",
+                        "description": "备份文件名",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_auth.UserResponse"
+                            "$ref": "#/definitions/internal_api_admin.BackupManifestResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/": {
-            "get": {
+        "/admin/compensation/run": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "返回系统当前已加载并入库的彩票类型",
+                "description": "按配置中的任务名称立即执行一次数据补偿",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "admin"
                 ],
-                "summary": "获取已启用彩票列表",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.LotteryListResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                "summary": "手动执行补偿任务",
+                "parameters": [
+                    {
+                        "description": "补偿任务名称",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.RunCompensationRequest"
                         }
                     }
-                }
-            }
-        },
-        "/lotteries/dashboard": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "返回当前账户全部已录入票据的汇总统计",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "lottery"
                 ],
-                "summary": "获取全局看板",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.DashboardResponse"
+                            "$ref": "#/definitions/internal_api_admin.CompensationRunResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/draws/sync-history": {
-            "post": {
+        "/admin/config": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "批量调用历史开奖同步，lotteryCodes 为空时同步所有已启用彩票",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "返回脱敏后的运行配置，便于管理员确认环境变量和本地覆盖是否生效",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
-                ],
-                "summary": "批量同步多种彩票历史开奖",
-                "parameters": [
-                    {
-                        "description": "批量历史同步参数",
-                        "name": "request",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.SyncDrawRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "查看当前配置",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.BatchSyncResultResponse"
+                            "$ref": "#/definitions/internal_api_admin.ConfigResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/recommendations": {
+        "/admin/external-logs": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "返回全部彩票推荐记录，支持按彩种、开奖状态筛选，并按时间或金额排序，适合移动端动态加载",
+                "description": "按时间倒序分页返回极速数据、AI 和 PaddleOCR 的调用记录，列表不含请求和响应内容",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "admin"
                 ],
-                "summary": "获取全部推荐列表",
+                "summary": "检索第三方接口调用记录",
                 "parameters": [
                     {
                         "type": "integer",
@@ -256,26 +233,68 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "每页数量，默认 10，最大 50",
+                        "description": "每页数量，默认 20，最大 100",
                         "name": "pageSize",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "lotteryCode",
+                        "description": "服务方，可选 jisuapi、openai-compatible、paddleocr",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否调用成功",
+                        "name": "success",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "HTTP 状态码",
+                        "name": "statusCode",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "状态，可选 pending、won、not_won",
-                        "name": "status",
+                        "description": "触发调用的请求 ID",
+                        "name": "requestId",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "排序，可选 latest、oldest、draw_latest、draw_oldest、prize_high",
-                        "name": "sort",
+                        "description": "触发调用的用户 ID",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "关联的票据上传记录 ID",
+                        "name": "uploadId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "关联的推荐记录 ID",
+                        "name": "recommendationId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "按接口地址或错误信息模糊匹配",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始日期，格式 YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束日期，格式 YYYY-MM-DD，包含当天",
+                        "name": "to",
                         "in": "query"
                     }
                 ],
@@ -283,52 +302,96 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.RecommendationPageResponse"
+                            "$ref": "#/definitions/internal_api_admin.ExternalAPILogPageResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/tickets": {
+        "/admin/external-logs/{logId}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "返回最近录入的全部票据记录，适合在不预先区分彩种的记录页使用",
+                "description": "返回单条调用记录，包含脱敏并截断后的请求和响应内容",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "admin"
                 ],
-                "summary": "获取全部票据列表",
+                "summary": "查看第三方接口调用详情",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "返回数量，默认 20",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "调用记录 ID",
+                        "name": "logId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketListResponse"
+                            "$ref": "#/definitions/internal_api_admin.ExternalAPILogResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/invites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回全部邀请码及其使用次数、有效期和吊销状态",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "获取邀请码列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.InviteCodeListResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
@@ -339,7 +402,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "基于上传记录和识别结果确认票据入库，彩种由识别结果或推荐记录自动决定",
+                "description": "生成注册邀请码，maxUses 为 0 表示不限次数，expiresInDays 为 0 表示永不过期",
                 "consumes": [
                     "application/json"
                 ],
@@ -347,80 +410,114 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "admin"
                 ],
-                "summary": "通用票据入库并判奖",
+                "summary": "创建邀请码",
                 "parameters": [
                     {
-                        "description": "入库参数",
+                        "description": "邀请码设置",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.CreateTicketRequest"
+                            "$ref": "#/definitions/internal_api_admin.CreateInviteCodeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                            "$ref": "#/definitions/internal_api_admin.InviteCodeResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/tickets/history": {
-            "get": {
+        "/admin/invites/{inviteId}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "支持按彩种、中奖状态筛选，并按时间或金额排序，适合移动端历史列表动态加载",
+                "description": "吊销后邀请码不能再用于注册，已注册的账号不受影响",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "admin"
                 ],
-                "summary": "分页获取历史票据",
+                "summary": "吊销邀请码",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "页码，默认 1",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "每页数量，默认 10，最大 50",
-                        "name": "pageSize",
+                        "type": "string",
+                        "description": "邀请码 ID",
+                        "name": "inviteId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.CompensationRunResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "管理员分页查看全部账号，支持按用户名关键字和角色筛选",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "获取用户列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码，默认 1",
+                        "name": "page",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "lotteryCode",
+                        "type": "integer",
+                        "description": "每页数量，默认 20，最大 100",
+                        "name": "pageSize",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "状态，可选 pending、won、not_won",
-                        "name": "status",
+                        "description": "用户名关键字",
+                        "name": "keyword",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "排序，可选 latest、oldest、prize_high、cost_high",
-                        "name": "sort",
+                        "description": "角色，可选 admin、user",
+                        "name": "role",
                         "in": "query"
                     }
                 ],
@@ -428,26 +525,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketPageResponse"
+                            "$ref": "#/definitions/internal_api_admin.UserPageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/tickets/recognize": {
+        "/admin/users/{userId}/reset-password": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "基于已上传原图执行 OCR 识别，并自动判断彩票类型",
+                "description": "管理员为账号设置新密码，password 为空时生成临时密码并在响应中返回",
                 "consumes": [
                     "application/json"
                 ],
@@ -455,17 +558,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "admin"
                 ],
-                "summary": "识别通用彩票内容",
+                "summary": "重置账号密码",
                 "parameters": [
                     {
-                        "description": "识别参数",
+                        "type": "string",
+                        "description": "用户 ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新密码",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.RecognizeTicketRequest"
+                            "$ref": "#/definitions/internal_api_admin.ResetPasswordRequest"
                         }
                     }
                 ],
@@ -473,115 +582,142 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketRecognitionResponse"
+                            "$ref": "#/definitions/internal_api_admin.ResetPasswordResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/tickets/upload-image": {
-            "post": {
+        "/admin/users/{userId}/role": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "上传时不要求先知道彩票类型，图片会在识别阶段自动判断彩种",
+                "description": "设置账号为 admin 或 user，系统至少保留一个可用管理员",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "admin"
                 ],
-                "summary": "上传通用彩票原图",
+                "summary": "修改账号角色",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "彩票图片",
-                        "name": "image",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "用户 ID",
+                        "name": "userId",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "角色",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.UpdateUserRoleRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketUploadResponse"
+                            "$ref": "#/definitions/internal_api_admin.AdminUserResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/tickets/{ticketId}": {
-            "delete": {
+        "/admin/users/{userId}/status": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "删除票据、票据明细，以及关联的上传记录和可清理的原图文件",
+                "description": "禁用后该账号无法登录或调用接口，也不会再参与定时推荐生成",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "admin"
                 ],
-                "summary": "删除票据记录",
+                "summary": "启用或禁用账号",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "票据 ID",
-                        "name": "ticketId",
+                        "description": "用户 ID",
+                        "name": "userId",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "账号状态",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.UpdateUserStatusRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.DeleteResponse"
+                            "$ref": "#/definitions/internal_api_admin.AdminUserResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_admin.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/tickets/{ticketId}/recheck": {
-            "post": {
+        "/auth/account": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "按票据自身的彩种与期号重新同步开奖并再次判奖",
+                "description": "校验密码后永久删除当前账号，以及该账号的票据、票据明细、上传图片、推荐和登录会话。未设置密码的账号不需要填写密码，但必须在 10 分钟内重新通过单点登录验证身份",
                 "consumes": [
                     "application/json"
                 ],
@@ -589,133 +725,115 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "通用重新判奖",
+                "summary": "注销账号",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "票据 ID",
-                        "name": "ticketId",
-                        "in": "path",
-                        "required": true
+                        "description": "当前密码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.DeleteAccountRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                            "$ref": "#/definitions/internal_api_auth.LogoutResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/dashboard": {
+        "/auth/identities": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "返回指定彩票的最新开奖、最新推荐、最近票据和统计信息",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
-                ],
-                "summary": "获取彩票看板",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "获取已绑定的外部身份",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.DashboardResponse"
+                            "$ref": "#/definitions/internal_api_auth.IdentityListResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/draws/sync": {
-            "post": {
+        "/auth/identities/{identityId}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "使用极速数据 query 接口同步当前期或指定期的开奖信息，并触发票据与推荐结算",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "未设置密码的账号不能解绑最后一个外部身份",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "同步当期开奖",
+                "summary": "解绑外部身份",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "code",
+                        "description": "外部身份 ID",
+                        "name": "identityId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "同步参数，issue 为空时同步当前期",
-                        "name": "request",
-                        "in": "body",
-                        "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.SyncDrawRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.SyncResultResponse"
+                            "$ref": "#/definitions/internal_api_auth.LogoutResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/draws/sync-history": {
+        "/auth/login": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "使用极速数据 history 接口分页同步指定彩票的历史开奖信息，默认最近 100 期",
+                "description": "用户登录接口，同一 IP 或用户名连续失败次数过多时会临时锁定并返回 429，retryAfter 为需要等待的秒数",
                 "consumes": [
                     "application/json"
                 ],
@@ -723,23 +841,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "同步单种彩票历史开奖",
+                "summary": "用户登录",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "历史同步参数",
+                        "description": "登录信息",
                         "name": "request",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.SyncDrawRequest"
+                            "$ref": "#/definitions/internal_api_auth.AuthRequest"
                         }
                     }
                 ],
@@ -747,288 +859,277 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.SyncResultResponse"
+                            "$ref": "#/definitions/internal_api_auth.LoginResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/recommendations": {
-            "get": {
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "返回指定彩票最近生成的推荐记录列表，包含是否已记录购买票据",
+                "description": "吊销当前访问令牌所属的登录会话，对应的刷新令牌同时失效",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
-                ],
-                "summary": "获取推荐列表",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "返回数量，默认 20",
-                        "name": "limit",
-                        "in": "query"
-                    }
+                    "auth"
                 ],
+                "summary": "退出登录",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.RecommendationListResponse"
+                            "$ref": "#/definitions/internal_api_auth.LogoutResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/recommendations/generate": {
+        "/auth/logout-all": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "按当前彩票配置的 AI 模型和提示词生成推荐号码",
+                "description": "吊销当前账号的全部登录会话",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
-                ],
-                "summary": "生成推荐号码",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "退出全部设备",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.RecommendationResponse"
+                            "$ref": "#/definitions/internal_api_auth.LogoutResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/recommendations/latest": {
+        "/auth/oidc/callback": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "返回指定彩票最近一次生成的推荐号码",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "身份提供方授权完成后的回调地址。登录成功时跳转到前端并在 URL 片段中携带 token、refreshToken 和 expiresIn；绑定成功时携带 oidc=linked；失败时携带 oidcError",
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "获取最新推荐",
+                "summary": "单点登录回调",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
+                        "description": "授权码",
                         "name": "code",
-                        "in": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "登录请求标识",
+                        "name": "state",
+                        "in": "query",
                         "required": true
                     }
                 ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    }
+                }
+            }
+        },
+        "/auth/oidc/config": {
+            "get": {
+                "description": "返回是否启用 OIDC 登录以及登录按钮展示的名称",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "获取单点登录配置",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.RecommendationResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.OIDCSettingsResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/recommendations/{recommendationId}": {
-            "get": {
+        "/auth/oidc/link": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "返回指定推荐记录的完整号码、命中情况和购买关联信息",
+                "description": "为当前账号发起外部身份绑定，返回授权地址，前端跳转后由回调完成绑定",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "获取推荐详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
+                "summary": "绑定外部身份",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.OIDCAuthorizationResponse"
+                        }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "跳转到身份提供方的授权页面，授权完成后回到 /api/auth/oidc/callback。首次登录自动创建账号时同样受注册模式限制，邀请注册模式下需要携带邀请码",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "发起单点登录",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "推荐记录 ID",
-                        "name": "recommendationId",
-                        "in": "path",
-                        "required": true
+                        "description": "邀请码，仅邀请注册模式下首次登录时需要",
+                        "name": "inviteCode",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.RecommendationDetailResponse"
-                        }
+                    "302": {
+                        "description": "Found"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/password": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "删除推荐记录及推荐明细，并解除与购买记录的关联，不删除已录入票据",
+                "description": "校验当前密码后设置新密码，除当前会话外的其他登录会话会被吊销。通过单点登录创建、尚未设置密码的账号可以不填当前密码，直接设置首个密码",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "删除推荐记录",
+                "summary": "修改密码",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "推荐记录 ID",
-                        "name": "recommendationId",
-                        "in": "path",
-                        "required": true
+                        "description": "当前密码和新密码",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.ChangePasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.DeleteResponse"
+                            "$ref": "#/definitions/internal_api_auth.LogoutResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/tickets": {
+        "/auth/profile": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "返回指定彩票最近录入的票据记录",
+                "description": "返回当前登录用户信息",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
-                ],
-                "summary": "获取票据列表",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "返回数量，默认 20",
-                        "name": "limit",
-                        "in": "query"
-                    }
+                    "auth"
                 ],
+                "summary": "获取当前用户",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketListResponse"
+                            "$ref": "#/definitions/internal_api_auth.UserResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "基于上传记录和识别结果确认票据入库，并在已开奖时自动判奖",
+                "description": "修改当前账号的显示名称，传空字符串表示清除；language 可选 zh-CN 或 en，用于接口错误消息的语言，传空字符串表示跟随 Accept-Language，不传则保持不变",
                 "consumes": [
                     "application/json"
                 ],
@@ -1036,51 +1137,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "确认入库并判奖",
+                "summary": "更新个人资料",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "入库参数",
+                        "description": "个人资料",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.CreateTicketRequest"
+                            "$ref": "#/definitions/internal_api_auth.UpdateProfileRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                            "$ref": "#/definitions/internal_api_auth.UserResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/tickets/recognize": {
+        "/auth/refresh": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "基于已上传原图执行 OCR 识别，只返回识别结果，不入库",
+                "description": "使用刷新令牌换取新的访问令牌和刷新令牌，旧刷新令牌会立即失效",
                 "consumes": [
                     "application/json"
                 ],
@@ -1088,24 +1177,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "识别彩票内容",
+                "summary": "刷新访问令牌",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "识别参数",
+                        "description": "刷新令牌",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.RecognizeTicketRequest"
+                            "$ref": "#/definitions/internal_api_auth.RefreshRequest"
                         }
                     }
                 ],
@@ -1113,162 +1195,210 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketRecognitionResponse"
+                            "$ref": "#/definitions/internal_api_auth.LoginResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/tickets/scan": {
+        "/auth/register": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "上传票据图片并识别号码，识别成功后自动入库并尝试判奖",
+                "description": "创建账号并返回用户信息。注册模式为 invite 时需要填写邀请码，为 closed 时拒绝注册；系统中第一个注册的账号自动成为管理员",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "扫描彩票票据",
+                "summary": "用户注册",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
+                        "description": "注册信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.UserResponse"
+                        }
                     },
-                    {
-                        "type": "file",
-                        "description": "彩票图片",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "期号",
-                        "name": "issue",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "购买时间，RFC3339 格式",
-                        "name": "purchasedAt",
-                        "in": "formData"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "OCR 降级文本",
-                        "name": "ocrText",
-                        "in": "formData"
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "备注",
-                        "name": "notes",
-                        "in": "formData"
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                        }
                     }
+                }
+            }
+        },
+        "/auth/registration": {
+            "get": {
+                "description": "返回当前注册模式，前端据此决定是否展示注册入口和邀请码输入框",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
                 ],
+                "summary": "获取注册状态",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                            "$ref": "#/definitions/internal_api_auth.RegistrationStatusResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回当前账号仍然有效的登录会话，current 表示发起请求的会话",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "获取登录会话列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.SessionListResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/lotteries/{code}/tickets/upload-image": {
-            "post": {
+        "/auth/sessions/{sessionId}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "仅上传并保存彩票原图，不执行 OCR 和入库",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                "description": "吊销指定的登录会话，常用于移除丢失设备上的登录状态",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "上传彩票原图",
+                "summary": "吊销登录会话",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "彩票编码，如 ssq",
-                        "name": "code",
+                        "description": "会话 ID",
+                        "name": "sessionId",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "彩票图片",
-                        "name": "image",
-                        "in": "formData",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketUploadResponse"
+                            "$ref": "#/definitions/internal_api_auth.LogoutResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回当前账号未吊销的访问令牌，包含最近使用时间和来源 IP，不包含令牌明文",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "获取个人访问令牌列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.AccessTokenListResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/lotteries/{code}/tickets/{ticketId}/recheck": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "按当前期号重新同步开奖并再次判奖，适合补录历史开奖后修正票据状态",
+                "description": "为脚本或快捷指令创建访问令牌，可选权限范围 read、tickets:write、recommendations:write、draws:sync；expiresInDays 为 0 表示永不过期。明文令牌只在本次响应中返回",
                 "consumes": [
                     "application/json"
                 ],
@@ -1276,21 +1406,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "lottery"
+                    "auth"
                 ],
-                "summary": "重新判奖",
+                "summary": "创建个人访问令牌",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "彩票编码，如 ssq、dlt",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
+                        "description": "令牌名称、权限范围和有效期",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.CreateAccessTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.CreatedAccessTokenResponse"
+                        }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/tokens/{tokenId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "吊销后使用该令牌的脚本会立即收到 401",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "吊销个人访问令牌",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "票据 ID",
-                        "name": "ticketId",
+                        "description": "令牌 ID",
+                        "name": "tokenId",
                         "in": "path",
                         "required": true
                     }
@@ -1299,92 +1464,4423 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                            "$ref": "#/definitions/internal_api_auth.LogoutResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                            "$ref": "#/definitions/internal_api_auth.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "go-fiber-starter_internal_model_lottery.DrawPrize": {
-            "type": "object",
-            "properties": {
-                "createdAt": {
-                    "description": "指定为自动创建时间",
-                    "type": "string"
-                },
-                "drawResultId": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "prizeName": {
-                    "type": "string"
-                },
-                "prizeRule": {
-                    "type": "string"
-                },
-                "singleBonus": {
-                    "type": "number"
-                },
-                "updatedAt": {
-                    "description": "指定为自动更新时间",
-                    "type": "string"
-                },
-                "winnerCount": {
-                    "type": "integer"
+        },
+        "/health/live": {
+            "get": {
+                "description": "进程能够处理请求即返回 200，不检查任何依赖，适合作为容器存活探针",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "存活检查",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_health.LiveResponse"
+                        }
+                    }
                 }
             }
         },
-        "go-fiber-starter_internal_model_lottery.DrawResult": {
-            "type": "object",
-            "properties": {
-                "blueNumbers": {
-                    "type": "string"
-                },
-                "createdAt": {
-                    "description": "指定为自动创建时间",
-                    "type": "string"
-                },
-                "drawDate": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "issue": {
-                    "type": "string"
-                },
-                "lotteryCode": {
-                    "type": "string"
-                },
-                "prizeDetails": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.DrawPrize"
-                    }
-                },
-                "prizePoolAmount": {
-                    "type": "number"
-                },
-                "rawPayload": {
-                    "type": "string"
-                },
-                "redNumbers": {
-                    "type": "string"
-                },
+        "/health/ready": {
+            "get": {
+                "description": "检查数据库连接、数据表迁移、定时任务调度器和上传目录可写性，全部通过时返回 200，否则返回 503。\n开启 health.checkExternal 后同时报告极速数据、AI 接口和 PaddleOCR 的连通性（结果带缓存），外部服务不可达只会让整体状态变为 degraded",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "就绪检查",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_health.ReadyResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_health.NotReadyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledgers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回当前用户参与的共享账本及其角色",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "获取我的账本",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LedgerListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "创建共享账本，创建者成为账本所有者",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "创建账本",
+                "parameters": [
+                    {
+                        "description": "账本名称",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LedgerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LedgerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledgers/{ledgerId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "重命名账本",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "账本 ID",
+                        "name": "ledgerId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "账本名称",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LedgerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LedgerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除账本及其中的全部票据和推荐，只有所有者可以操作",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "删除账本",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "账本 ID",
+                        "name": "ledgerId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.DeleteResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledgers/{ledgerId}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "获取账本成员",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "账本 ID",
+                        "name": "ledgerId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LedgerMemberListResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按用户名添加成员，角色可选 editor（可录票、生成推荐）或 viewer（只读），默认 viewer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "邀请账本成员",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "账本 ID",
+                        "name": "ledgerId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "成员信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LedgerMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LedgerMemberResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ledgers/{ledgerId}/members/{userId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "修改成员角色",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "账本 ID",
+                        "name": "ledgerId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "成员用户 ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新角色",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LedgerMemberRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "所有者可以移除其他成员，成员可以移除自己以退出账本；成员录入的数据仍保留在账本中",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "移除账本成员",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "账本 ID",
+                        "name": "ledgerId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "成员用户 ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.DeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回系统当前已加载并入库的彩票类型",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取已启用彩票列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.LotteryListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回当前账户全部已录入票据的汇总统计",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取全局看板",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.DashboardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/draws/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回系统已同步入库的历史开奖记录，支持按彩种、期号、开奖日期或日期区间、开奖号码筛选，并按开奖时间排序。\n传入上一页返回的 nextCursor 即按游标翻页，不传 cursor 时按 page 分页",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "分页获取历史开奖记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码，默认 1，传 cursor 时忽略",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认 20，最大 50",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上一页返回的 nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "lotteryCode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "期号",
+                        "name": "issue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开奖日期，格式 2026-03-22",
+                        "name": "drawDate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开奖日期起，格式 2026-03-01",
+                        "name": "drawDateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开奖日期止（含当天），格式 2026-03-31",
+                        "name": "drawDateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "开奖号码包含的号码",
+                        "name": "number",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "号码区域，可选 red、blue，不传时两区都匹配",
+                        "name": "numberZone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序，可选 latest、oldest",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.DrawPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/draws/sync-history": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "批量调用历史开奖同步，lotteryCodes 为空时同步所有已启用彩票",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "批量同步多种彩票历史开奖",
+                "parameters": [
+                    {
+                        "description": "批量历史同步参数",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.SyncDrawRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.BatchSyncResultResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回全部彩票推荐记录，支持按彩种、开奖状态筛选，并按时间或金额排序，适合移动端动态加载",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取全部推荐列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码，默认 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认 10，最大 50",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "lotteryCode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "状态，可选 pending、won、not_won",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序，可选 latest、oldest、draw_latest、draw_oldest、prize_high",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.RecommendationPageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/syndicates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回当前用户作为参与人的合买记录，包括他人或共享账本录入的票据，以及本人分摊的成本和奖金",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取我参与的合买",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq",
+                        "name": "code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.SyndicateShareListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回最近录入的全部票据记录，适合在不预先区分彩种的记录页使用",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取全部票据列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "返回数量，默认 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "基于上传记录和识别结果确认票据入库，彩种自动决定，金额由服务端核算",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "通用票据入库并判奖",
+                "parameters": [
+                    {
+                        "description": "入库参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.CreateTicketRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，保留期内相同键的重试直接返回首次成功的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/bulk/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "在同一个事务中删除多张票据及其明细、上传记录，返回每张票据的处理结果；单张失败不影响其余票据，\n全部删除后统一清理不再被引用的原图。单次最多 200 张",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "批量删除票据",
+                "parameters": [
+                    {
+                        "description": "票据 ID 列表或筛选条件",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.BulkTicketRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.BulkTicketResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/bulk/link": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "把多张票据关联到同一条推荐记录，recommendationId 为空时解除关联；推荐与票据彩种不一致的票据单独标记失败。单次最多 200 张",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "批量关联推荐",
+                "parameters": [
+                    {
+                        "description": "票据 ID 列表或筛选条件，以及推荐记录 ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.BulkLinkTicketRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.BulkTicketResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/bulk/recheck": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "逐张按票据自身的彩种与期号重新同步开奖并判奖，返回每张票据的处理结果和最新详情。单次最多 200 张",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "批量重新判奖",
+                "parameters": [
+                    {
+                        "description": "票据 ID 列表或筛选条件",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.BulkTicketRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.BulkTicketResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "支持按彩种、状态、期号、购买和开奖日期、奖金区间、奖级、来源、是否关联推荐以及包含的号码筛选，并按时间或金额排序。\n传入上一页返回的 nextCursor 即按游标翻页，翻页期间新增票据不会导致重复或遗漏；不传 cursor 时按 page 分页",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "分页获取历史票据",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码，默认 1，传 cursor 时忽略",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认 10，最大 50",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上一页返回的 nextCursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "lotteryCode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "状态，可选 pending、won、not_won",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "期号",
+                        "name": "issue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "购买日期起，格式 2026-03-01",
+                        "name": "purchasedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "购买日期止（含当天），格式 2026-03-31",
+                        "name": "purchasedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开奖日期起，格式 2026-03-01",
+                        "name": "drawDateFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开奖日期止（含当天），格式 2026-03-31",
+                        "name": "drawDateTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "最低奖金",
+                        "name": "minPrize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "最高奖金",
+                        "name": "maxPrize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "奖级，如 一等奖、六等奖",
+                        "name": "prizeName",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "来源，可选 ocr、manual、import",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "是否关联推荐",
+                        "name": "hasRecommendation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "包含的号码",
+                        "name": "number",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "号码区域，可选 red、blue，不传时两区都匹配",
+                        "name": "numberZone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "排序，可选 latest、oldest、prize_high、cost_high",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketPageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "上传 Excel 批量导入票据，图片压缩包可选；一行一注，同彩种同一期号会自动合并为一次购买记录，并按号码自动关联推荐",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "批量导入历史票据",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Excel 文件，支持 xlsx",
+                        "name": "workbook",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "图片压缩包，Excel 中 imageName 列会按文件名匹配",
+                        "name": "imagesZip",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，保留期内相同键的重试直接返回首次成功的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/recognize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "基于已上传原图执行 OCR 识别，并自动判断彩票类型",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "识别通用彩票内容",
+                "parameters": [
+                    {
+                        "description": "识别参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.RecognizeTicketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketRecognitionResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/upload-image": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "上传时不要求先知道彩票类型，图片会在识别阶段自动判断彩种",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "上传通用彩票原图",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "彩票图片",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/{ticketId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "编辑已录入的购买记录，金额由服务端重新核算，保存后会重置并重新判奖",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "通用票据更新",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "票据 ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.CreateTicketRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除票据、票据明细，以及关联的上传记录和可清理的原图文件",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "删除票据记录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "票据 ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.DeleteResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/{ticketId}/recheck": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按票据自身的彩种与期号重新同步开奖并再次判奖",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "通用重新判奖",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "票据 ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/tickets/{ticketId}/shares": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "覆盖票据的合买参与人和出资份额，成本和奖金按份额比例分摊；传入空列表时取消合买",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "设置合买参与人",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "票据 ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "合买参与人",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketSharesRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回指定彩票的最新开奖、最新推荐、最近票据和统计信息",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取彩票看板",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.DashboardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/draws/frequency": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "统计指定彩种最近若干期开奖中每个号码的出现次数，红球（前区）和蓝球（后区）分别列出号码范围内的全部号码",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取开奖号码频次",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "统计最近的期数，默认 30，最大 500",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.NumberFrequencyResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/draws/sync": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "使用极速数据 query 接口同步当前期或指定期的开奖信息，并触发票据与推荐结算",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "同步当期开奖",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "同步参数，issue 为空时同步当前期",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.SyncDrawRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.SyncResultResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/draws/sync-history": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "使用极速数据 history 接口分页同步指定彩票的历史开奖信息，默认最近 100 期",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "同步单种彩票历史开奖",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "历史同步参数",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.SyncDrawRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.SyncResultResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回指定彩票最近生成的推荐记录列表，包含是否已记录购买票据",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取推荐列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返回数量，默认 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.RecommendationListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/recommendations/generate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按当前彩票配置的 AI 模型和提示词生成推荐号码",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "生成推荐号码",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.RecommendationResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/recommendations/latest": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回指定彩票最近一次生成的推荐号码",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取最新推荐",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.RecommendationResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/recommendations/{recommendationId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回指定推荐记录的完整号码、命中情况和购买关联信息",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取推荐详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "推荐记录 ID",
+                        "name": "recommendationId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.RecommendationDetailResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "删除推荐记录及推荐明细，并解除与购买记录的关联，不删除已录入票据",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "删除推荐记录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "推荐记录 ID",
+                        "name": "recommendationId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.DeleteResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/recommendations/{recommendationId}/recheck": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按推荐期号重新同步开奖并再次判奖，适合补录开奖后修正推荐状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "重新判奖推荐",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "推荐记录 ID",
+                        "name": "recommendationId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.RecommendationDetailResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/tickets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "返回指定彩票最近录入的票据记录",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取票据列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返回数量，默认 20",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "基于上传记录和识别结果确认票据入库，金额按号码、倍数和追加由服务端核算，并在已开奖时自动判奖",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "确认入库并判奖",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "入库参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.CreateTicketRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "幂等键，保留期内相同键的重试直接返回首次成功的响应",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/tickets/frequency": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "统计当前账户或共享账本已录入的指定彩种投注中，每个号码被选中的注数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "获取投注号码频次",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.NumberFrequencyResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/tickets/recognize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "基于已上传原图执行 OCR 识别，只返回识别结果，不入库",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "识别彩票内容",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "识别参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.RecognizeTicketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketRecognitionResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/tickets/scan": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "上传票据图片并识别号码，识别成功后自动入库并尝试判奖",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "扫描彩票票据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "彩票图片",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "期号",
+                        "name": "issue",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "购买时间，RFC3339 格式",
+                        "name": "purchasedAt",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "OCR 降级文本",
+                        "name": "ocrText",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "备注",
+                        "name": "notes",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/tickets/upload-image": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "仅上传并保存彩票原图，不执行 OCR 和入库",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "上传彩票原图",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "彩票图片",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/tickets/{ticketId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "编辑已录入的购买记录，金额由服务端重新核算，保存后会重置并重新判奖",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "更新票据",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "票据 ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新参数",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.CreateTicketRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lotteries/{code}/tickets/{ticketId}/recheck": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "按当前期号重新同步开奖并再次判奖，适合补录历史开奖后修正票据状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lottery"
+                ],
+                "summary": "重新判奖",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "彩票编码，如 ssq、dlt",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "票据 ID",
+                        "name": "ticketId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "共享账本 ID，不传时为个人数据",
+                        "name": "ledgerId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.TicketDetailResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api_lottery.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "go-fiber-starter_internal_model_lottery.DrawPrize": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "drawResultId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "prizeName": {
+                    "type": "string"
+                },
+                "prizeRule": {
+                    "type": "string"
+                },
+                "singleBonus": {
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "winnerCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_lottery.DrawResult": {
+            "type": "object",
+            "properties": {
+                "blueNumbers": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "drawDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issue": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "prizeDetails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.DrawPrize"
+                    }
+                },
+                "prizePoolAmount": {
+                    "type": "number"
+                },
+                "rawPayload": {
+                    "type": "string"
+                },
+                "redNumbers": {
+                    "type": "string"
+                },
+                "saleAmount": {
+                    "type": "number"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_lottery.ExternalAPILog": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "durationMs": {
+                    "type": "integer"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "jobName": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "recommendationId": {
+                    "type": "string"
+                },
+                "request": {
+                    "type": "string"
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "response": {
+                    "type": "string"
+                },
+                "statusCode": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "traceId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "uploadId": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_lottery.LotteryType": {
+            "type": "object",
+            "properties": {
+                "blueCount": {
+                    "type": "integer"
+                },
+                "blueMax": {
+                    "type": "integer"
+                },
+                "blueMin": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "recommendationCount": {
+                    "type": "integer"
+                },
+                "recommendationModel": {
+                    "type": "string"
+                },
+                "recommendationProvider": {
+                    "type": "string"
+                },
+                "redCount": {
+                    "type": "integer"
+                },
+                "redMax": {
+                    "type": "integer"
+                },
+                "redMin": {
+                    "type": "integer"
+                },
+                "remoteLotteryId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "visionModel": {
+                    "type": "string"
+                },
+                "visionProvider": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_lottery.Recommendation": {
+            "type": "object",
+            "properties": {
+                "basis": {
+                    "type": "string"
+                },
+                "checkedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "drawDate": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.RecommendationEntry"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issue": {
+                    "type": "string"
+                },
+                "ledgerId": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "prizeAmount": {
+                    "type": "number"
+                },
+                "promptVersion": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "rawPayload": {
+                    "type": "string"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_lottery.RecommendationEntry": {
+            "type": "object",
+            "properties": {
+                "blueNumbers": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isWinning": {
+                    "type": "boolean"
+                },
+                "matchSummary": {
+                    "type": "string"
+                },
+                "prizeAmount": {
+                    "type": "number"
+                },
+                "prizeName": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "recommendationId": {
+                    "type": "string"
+                },
+                "redNumbers": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_lottery.TicketEntry": {
+            "type": "object",
+            "properties": {
+                "blueNumbers": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isAdditional": {
+                    "type": "boolean"
+                },
+                "isWinning": {
+                    "type": "boolean"
+                },
+                "matchSummary": {
+                    "type": "string"
+                },
+                "multiple": {
+                    "type": "integer"
+                },
+                "prizeAmount": {
+                    "type": "number"
+                },
+                "prizeName": {
+                    "type": "string"
+                },
+                "redNumbers": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "ticketId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_lottery.TicketShare": {
+            "type": "object",
+            "properties": {
+                "costAmount": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prizeAmount": {
+                    "type": "number"
+                },
+                "shareAmount": {
+                    "type": "number"
+                },
+                "ticketId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_user.AccessToken": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "lastUsedIp": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "string"
+                },
+                "tokenHint": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_user.Identity": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "lastLoginAt": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_user.InviteCode": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "maxUses": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "usedCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_model_user.User": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "displayName": {
+                    "type": "string",
+                    "example": "小王"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string",
+                    "example": "zh-CN"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.CreatedAccessToken": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "lastUsedIp": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "tokenHint": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
+                    "type": "string"
+                },
+                "critical": {
+                    "type": "boolean"
+                },
+                "latencyMs": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.HealthReport": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service.HealthCheck"
+                    }
+                },
+                "ready": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.OIDCAuthorization": {
+            "type": "object",
+            "properties": {
+                "authorizationUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.OIDCSettings": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "providerName": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.RegistrationStatus": {
+            "type": "object",
+            "properties": {
+                "inviteRequired": {
+                    "type": "boolean"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "open": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.ResetPasswordResult": {
+            "type": "object",
+            "properties": {
+                "temporaryPassword": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.SessionDetail": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.TokenPair": {
+            "type": "object",
+            "properties": {
+                "expiresIn": {
+                    "type": "integer"
+                },
+                "refreshExpiresIn": {
+                    "type": "integer"
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "sessionId": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service.UserPageResult": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_user.User"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_backup.Archive": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "lottery-backup-20260316-043000.tar.gz"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 1048576
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_backup.CreateResult": {
+            "type": "object",
+            "properties": {
+                "archive": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_backup.Archive"
+                },
+                "manifest": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_backup.Manifest"
+                },
+                "pruned": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_backup.FileManifest": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_backup.Manifest": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "driver": {
+                    "type": "string"
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_backup.FileManifest"
+                    }
+                },
+                "format": {
+                    "type": "string"
+                },
+                "formatVersion": {
+                    "type": "integer"
+                },
+                "schemaVersion": {
+                    "type": "integer"
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_backup.TableManifest"
+                    }
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_backup.TableManifest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.BatchSyncResult": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.SyncResult"
+                    }
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.BulkTicketItem": {
+            "type": "object",
+            "properties": {
+                "errorCode": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "ticket": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketDetail"
+                },
+                "ticketId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.BulkTicketResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.BulkTicketItem"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.DashboardData": {
+            "type": "object",
+            "properties": {
+                "latestDraw": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.DrawResult"
+                },
+                "latestRecommendation": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.Recommendation"
+                },
+                "lottery": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.LotteryType"
+                },
+                "recentTickets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketDetail"
+                    }
+                },
+                "stats": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.DashboardStats"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.DashboardStats": {
+            "type": "object",
+            "properties": {
+                "purchasedRecommendations": {
+                    "type": "integer"
+                },
+                "syndicateCost": {
+                    "type": "number"
+                },
+                "syndicatePrize": {
+                    "type": "number"
+                },
+                "syndicateTickets": {
+                    "type": "integer"
+                },
+                "totalCost": {
+                    "type": "number"
+                },
+                "totalPrize": {
+                    "type": "number"
+                },
+                "totalRecommendations": {
+                    "type": "integer"
+                },
+                "totalTickets": {
+                    "type": "integer"
+                },
+                "wonTickets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.DrawHistoryItem": {
+            "type": "object",
+            "properties": {
+                "blueNumbers": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "drawDate": {
+                    "type": "string"
+                },
+                "firstPrizeAmount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issue": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "prizeDetails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.DrawPrizeItem"
+                    }
+                },
+                "prizePoolAmount": {
+                    "type": "number"
+                },
+                "rawPayload": {
+                    "type": "string"
+                },
+                "redNumbers": {
+                    "type": "string"
+                },
                 "saleAmount": {
                     "type": "number"
                 },
-                "source": {
+                "secondPrizeAmount": {
+                    "type": "number"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.DrawPageResult": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.DrawHistoryItem"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.DrawPrizeItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "prizeName": {
+                    "type": "string"
+                },
+                "prizeRule": {
+                    "type": "string"
+                },
+                "singleBonus": {
+                    "type": "number"
+                },
+                "winnerCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.ExternalAPILogPage": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.ExternalAPILog"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.LedgerMemberDetail": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ledgerId": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.LedgerSummary": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "memberCount": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.NumberFrequency": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.NumberFrequencyResult": {
+            "type": "object",
+            "properties": {
+                "blue": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.NumberFrequency"
+                    }
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "red": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.NumberFrequency"
+                    }
+                },
+                "sampleSize": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.ParsedEntry": {
+            "type": "object",
+            "properties": {
+                "blue": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "isAdditional": {
+                    "type": "boolean"
+                },
+                "multiple": {
+                    "type": "integer"
+                },
+                "red": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.RecommendationDetail": {
+            "type": "object",
+            "properties": {
+                "basis": {
+                    "type": "string"
+                },
+                "checkedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "drawBlueNumbers": {
+                    "type": "string"
+                },
+                "drawDate": {
+                    "type": "string"
+                },
+                "drawRedNumbers": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.RecommendationEntry"
+                    }
+                },
+                "entryCount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isPurchased": {
+                    "type": "boolean"
+                },
+                "issue": {
+                    "type": "string"
+                },
+                "ledgerId": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "model": {
+                    "type": "string"
+                },
+                "prizeAmount": {
+                    "type": "number"
+                },
+                "promptVersion": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "purchasedCount": {
+                    "type": "integer"
+                },
+                "purchasedTickets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketDetail"
+                    }
+                },
+                "rawPayload": {
+                    "type": "string"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "winningCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.RecommendationPageResult": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.RecommendationDetail"
+                    }
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.SyncResult": {
+            "type": "object",
+            "properties": {
+                "issue": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "requestedCount": {
+                    "type": "integer"
+                },
+                "syncedCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.SyndicateShareDetail": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
+                    "type": "string"
+                },
+                "costAmount": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issue": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prizeAmount": {
+                    "type": "number"
+                },
+                "purchasedAt": {
+                    "type": "string"
+                },
+                "shareAmount": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "ticketCostAmount": {
+                    "type": "number"
+                },
+                "ticketId": {
+                    "type": "string"
+                },
+                "ticketPrizeAmount": {
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.TicketDetail": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
+                    "type": "string"
+                },
+                "costAmount": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "drawBlueNumbers": {
+                    "type": "string"
+                },
+                "drawDate": {
+                    "type": "string"
+                },
+                "drawRedNumbers": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.TicketEntry"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imagePath": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "isSyndicate": {
+                    "type": "boolean"
+                },
+                "issue": {
+                    "type": "string"
+                },
+                "ledgerId": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "manualDrawDate": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "prizeAmount": {
+                    "type": "number"
+                },
+                "purchasedAt": {
+                    "type": "string"
+                },
+                "recognizedText": {
+                    "type": "string"
+                },
+                "recommendation": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketRecommendationDetail"
+                },
+                "recommendationId": {
+                    "type": "string"
+                },
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.TicketShare"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "指定为自动更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.TicketImportResult": {
+            "type": "object",
+            "properties": {
+                "failedCount": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketImportRowResult"
+                    }
+                },
+                "successCount": {
+                    "type": "integer"
+                },
+                "totalCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.TicketImportRowResult": {
+            "type": "object",
+            "properties": {
+                "issue": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "ticketId": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.TicketPageResult": {
+            "type": "object",
+            "properties": {
+                "hasMore": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketDetail"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.TicketRecognitionDraft": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number"
+                },
+                "costAmount": {
+                    "type": "number"
+                },
+                "drawDate": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.ParsedEntry"
+                    }
+                },
+                "issue": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "rawText": {
+                    "type": "string"
+                },
+                "upload": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketUploadDetail"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.TicketRecommendationDetail": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "drawDate": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketRecommendationEntry"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issue": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.TicketRecommendationEntry": {
+            "type": "object",
+            "properties": {
+                "blueNumbers": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "prizeAmount": {
+                    "type": "number"
+                },
+                "prizeName": {
+                    "type": "string"
+                },
+                "redNumbers": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.TicketShareInput": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "shareAmount": {
+                    "type": "number"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "go-fiber-starter_internal_service_lottery.TicketUploadDetail": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "指定为自动创建时间",
+                    "type": "string"
+                },
+                "errorMessage": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imagePath": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "lotteryCode": {
+                    "type": "string"
+                },
+                "originalFilename": {
+                    "type": "string"
+                },
+                "recognitionConfidence": {
+                    "type": "number"
+                },
+                "recognitionIssue": {
+                    "type": "string"
+                },
+                "recognitionPayload": {
+                    "type": "string"
+                },
+                "recognizedText": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 },
                 "updatedAt": {
@@ -1393,452 +5889,834 @@ const docTemplate = `{
                 }
             }
         },
-        "go-fiber-starter_internal_model_lottery.LotteryType": {
+        "internal_api_admin.AdminUserResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_model_user.User"
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.BackupCreateResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_backup.CreateResult"
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.BackupListResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_backup.Archive"
+                    }
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.BackupManifestResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_backup.Manifest"
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.CompensationRunResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.ConfigResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.CreateInviteCodeRequest": {
+            "type": "object",
+            "properties": {
+                "expiresInDays": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "maxUses": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "note": {
+                    "type": "string",
+                    "example": "给家人使用"
+                }
+            }
+        },
+        "internal_api_admin.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 403
+                },
+                "errorCode": {
+                    "type": "string",
+                    "example": "FORBIDDEN"
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "没有权限执行该操作"
+                },
+                "requestId": {
+                    "type": "string",
+                    "example": "0f8fad5b-d9cb-469f-a165-70867728950e"
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.ExternalAPILogPageResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.ExternalAPILogPage"
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.ExternalAPILogResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.ExternalAPILog"
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.InviteCodeListResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_user.InviteCode"
+                    }
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.InviteCodeResponse": {
             "type": "object",
             "properties": {
-                "blueCount": {
-                    "type": "integer"
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "blueMax": {
-                    "type": "integer"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_model_user.InviteCode"
                 },
-                "blueMin": {
-                    "type": "integer"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.ResetPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "newPass123"
+                }
+            }
+        },
+        "internal_api_admin.ResetPasswordResponse": {
+            "type": "object",
+            "properties": {
                 "code": {
-                    "type": "string"
+                    "type": "integer",
+                    "example": 200
                 },
-                "createdAt": {
-                    "description": "指定为自动创建时间",
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service.ResetPasswordResult"
                 },
-                "id": {
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_admin.RunCompensationRequest": {
+            "type": "object",
+            "properties": {
                 "name": {
-                    "type": "string"
-                },
-                "recommendationCount": {
-                    "type": "integer"
-                },
-                "recommendationModel": {
-                    "type": "string"
-                },
-                "recommendationProvider": {
-                    "type": "string"
-                },
-                "redCount": {
-                    "type": "integer"
-                },
-                "redMax": {
-                    "type": "integer"
-                },
-                "redMin": {
-                    "type": "integer"
-                },
-                "remoteLotteryId": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "updatedAt": {
-                    "description": "指定为自动更新时间",
-                    "type": "string"
-                },
-                "visionModel": {
-                    "type": "string"
-                },
-                "visionProvider": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "previous-draw-prize"
                 }
             }
         },
-        "go-fiber-starter_internal_model_lottery.Recommendation": {
+        "internal_api_admin.UpdateUserRoleRequest": {
             "type": "object",
             "properties": {
-                "basis": {
-                    "type": "string"
+                "role": {
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "internal_api_admin.UpdateUserStatusRequest": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_api_admin.UserPageResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "checkedAt": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service.UserPageResult"
                 },
-                "createdAt": {
-                    "description": "指定为自动创建时间",
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "drawDate": {
-                    "type": "string"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_auth.AccessTokenListResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "entries": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.RecommendationEntry"
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_user.AccessToken"
                     }
                 },
-                "id": {
-                    "type": "string"
-                },
-                "issue": {
-                    "type": "string"
-                },
-                "lotteryCode": {
-                    "type": "string"
-                },
-                "model": {
-                    "type": "string"
-                },
-                "prizeAmount": {
-                    "type": "number"
-                },
-                "promptVersion": {
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "provider": {
-                    "type": "string"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_auth.AuthRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "pass123"
                 },
-                "rawPayload": {
-                    "type": "string"
+                "username": {
+                    "type": "string",
+                    "example": "alice"
+                }
+            }
+        },
+        "internal_api_auth.ChangePasswordRequest": {
+            "type": "object",
+            "properties": {
+                "currentPassword": {
+                    "type": "string",
+                    "example": "pass123"
                 },
-                "strategy": {
-                    "type": "string"
+                "newPassword": {
+                    "type": "string",
+                    "example": "newPass456"
+                }
+            }
+        },
+        "internal_api_auth.CreateAccessTokenRequest": {
+            "type": "object",
+            "properties": {
+                "expiresInDays": {
+                    "type": "integer",
+                    "example": 90
                 },
-                "summary": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "iOS 快捷指令"
                 },
-                "updatedAt": {
-                    "description": "指定为自动更新时间",
-                    "type": "string"
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "tickets:write"
+                    ]
                 }
             }
         },
-        "go-fiber-starter_internal_model_lottery.RecommendationEntry": {
+        "internal_api_auth.CreatedAccessTokenResponse": {
             "type": "object",
             "properties": {
-                "blueNumbers": {
-                    "type": "string"
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "confidence": {
-                    "type": "number"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service.CreatedAccessToken"
                 },
-                "createdAt": {
-                    "description": "指定为自动创建时间",
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "id": {
-                    "type": "string"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_auth.DeleteAccountRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "pass123"
+                }
+            }
+        },
+        "internal_api_auth.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 500
                 },
-                "isWinning": {
-                    "type": "boolean"
+                "errorCode": {
+                    "type": "string",
+                    "example": "INTERNAL_ERROR"
                 },
-                "matchSummary": {
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "prizeAmount": {
-                    "type": "number"
+                "msg": {
+                    "type": "string",
+                    "example": "请求失败"
                 },
-                "prizeName": {
-                    "type": "string"
+                "requestId": {
+                    "type": "string",
+                    "example": "0f8fad5b-d9cb-469f-a165-70867728950e"
                 },
-                "reason": {
-                    "type": "string"
+                "retryAfter": {
+                    "type": "integer",
+                    "example": 60
                 },
-                "recommendationId": {
-                    "type": "string"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_auth.IdentityListResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "redNumbers": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_user.Identity"
+                    }
                 },
-                "sequence": {
-                    "type": "integer"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "updatedAt": {
-                    "description": "指定为自动更新时间",
-                    "type": "string"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "go-fiber-starter_internal_model_lottery.TicketEntry": {
+        "internal_api_auth.LoginResponse": {
             "type": "object",
             "properties": {
-                "blueNumbers": {
-                    "type": "string"
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "createdAt": {
-                    "description": "指定为自动创建时间",
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service.TokenPair"
                 },
-                "id": {
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "isAdditional": {
-                    "type": "boolean"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_auth.LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "isWinning": {
-                    "type": "boolean"
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
                 },
-                "matchSummary": {
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "multiple": {
-                    "type": "integer"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_auth.OIDCAuthorizationResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "prizeAmount": {
-                    "type": "number"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service.OIDCAuthorization"
                 },
-                "prizeName": {
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "redNumbers": {
-                    "type": "string"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_auth.OIDCSettingsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "sequence": {
-                    "type": "integer"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service.OIDCSettings"
                 },
-                "ticketId": {
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "updatedAt": {
-                    "description": "指定为自动更新时间",
-                    "type": "string"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.BatchSyncResult": {
+        "internal_api_auth.RefreshRequest": {
             "type": "object",
             "properties": {
-                "results": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.SyncResult"
-                    }
+                "refreshToken": {
+                    "type": "string",
+                    "example": "Jx3Jv9...refresh"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.DashboardData": {
+        "internal_api_auth.RegisterRequest": {
             "type": "object",
             "properties": {
-                "latestDraw": {
-                    "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.DrawResult"
-                },
-                "latestRecommendation": {
-                    "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.Recommendation"
-                },
-                "lottery": {
-                    "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.LotteryType"
+                "inviteCode": {
+                    "type": "string",
+                    "example": "K7MX2QH9TR"
                 },
-                "recentTickets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketDetail"
-                    }
+                "password": {
+                    "type": "string",
+                    "example": "pass123"
                 },
-                "stats": {
-                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.DashboardStats"
+                "username": {
+                    "type": "string",
+                    "example": "alice"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.DashboardStats": {
+        "internal_api_auth.RegistrationStatusResponse": {
             "type": "object",
             "properties": {
-                "purchasedRecommendations": {
-                    "type": "integer"
-                },
-                "totalCost": {
-                    "type": "number"
-                },
-                "totalPrize": {
-                    "type": "number"
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "totalRecommendations": {
-                    "type": "integer"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service.RegistrationStatus"
                 },
-                "totalTickets": {
-                    "type": "integer"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "wonTickets": {
-                    "type": "integer"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.ParsedEntry": {
+        "internal_api_auth.SessionListResponse": {
             "type": "object",
             "properties": {
-                "blue": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/go-fiber-starter_internal_service.SessionDetail"
                     }
                 },
-                "isAdditional": {
-                    "type": "boolean"
-                },
-                "multiple": {
-                    "type": "integer"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "red": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.RecommendationDetail": {
+        "internal_api_auth.UpdateProfileRequest": {
             "type": "object",
             "properties": {
-                "basis": {
-                    "type": "string"
-                },
-                "checkedAt": {
-                    "type": "string"
-                },
-                "createdAt": {
-                    "description": "指定为自动创建时间",
-                    "type": "string"
-                },
-                "drawBlueNumbers": {
-                    "type": "string"
-                },
-                "drawDate": {
-                    "type": "string"
-                },
-                "drawRedNumbers": {
-                    "type": "string"
+                "displayName": {
+                    "type": "string",
+                    "example": "小王"
                 },
-                "entries": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.RecommendationEntry"
-                    }
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                }
+            }
+        },
+        "internal_api_auth.UserData": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "entryCount": {
-                    "type": "integer"
+                "displayName": {
+                    "type": "string",
+                    "example": "小王"
                 },
                 "id": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "3fa85f64-5717-4562-b3fc-2c963f66afa6"
                 },
-                "isPurchased": {
-                    "type": "boolean"
+                "language": {
+                    "type": "string",
+                    "example": "zh-CN"
                 },
-                "issue": {
-                    "type": "string"
+                "role": {
+                    "type": "string",
+                    "example": "user"
                 },
-                "lotteryCode": {
-                    "type": "string"
+                "username": {
+                    "type": "string",
+                    "example": "alice"
+                }
+            }
+        },
+        "internal_api_auth.UserResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "model": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/internal_api_auth.UserData"
                 },
-                "prizeAmount": {
-                    "type": "number"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "promptVersion": {
-                    "type": "string"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_health.LiveData": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "internal_api_health.LiveResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "provider": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/internal_api_health.LiveData"
                 },
-                "purchasedCount": {
-                    "type": "integer"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "purchasedTickets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketDetail"
-                    }
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
+        "internal_api_health.NotReadyResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 503
                 },
-                "rawPayload": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service.HealthReport"
                 },
-                "strategy": {
-                    "type": "string"
+                "errorCode": {
+                    "type": "string",
+                    "example": "SERVICE_NOT_READY"
                 },
-                "summary": {
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "updatedAt": {
-                    "description": "指定为自动更新时间",
-                    "type": "string"
+                "msg": {
+                    "type": "string",
+                    "example": "服务未就绪"
                 },
-                "winningCount": {
-                    "type": "integer"
+                "requestId": {
+                    "type": "string",
+                    "example": "0f8fad5b-d9cb-469f-a165-70867728950e"
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.RecommendationPageResult": {
+        "internal_api_health.ReadyResponse": {
             "type": "object",
             "properties": {
-                "hasMore": {
-                    "type": "boolean"
-                },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.RecommendationDetail"
-                    }
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "page": {
-                    "type": "integer"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service.HealthReport"
                 },
-                "pageSize": {
-                    "type": "integer"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "total": {
-                    "type": "integer"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.SyncResult": {
+        "internal_api_lottery.BatchSyncResultResponse": {
             "type": "object",
             "properties": {
-                "issue": {
-                    "type": "string"
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "lotteryCode": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.BatchSyncResult"
                 },
-                "syncedCount": {
-                    "type": "integer"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.TicketDetail": {
+        "internal_api_lottery.BulkLinkTicketRequest": {
             "type": "object",
             "properties": {
-                "checkedAt": {
-                    "type": "string"
-                },
-                "costAmount": {
-                    "type": "number"
-                },
-                "createdAt": {
-                    "description": "指定为自动创建时间",
-                    "type": "string"
-                },
-                "drawBlueNumbers": {
-                    "type": "string"
-                },
-                "drawDate": {
-                    "type": "string"
+                "filter": {
+                    "$ref": "#/definitions/internal_api_lottery.BulkTicketFilter"
                 },
-                "drawRedNumbers": {
+                "recommendationId": {
                     "type": "string"
                 },
-                "entries": {
+                "ticketIds": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.TicketEntry"
+                        "type": "string"
                     }
-                },
-                "id": {
+                }
+            }
+        },
+        "internal_api_lottery.BulkTicketFilter": {
+            "type": "object",
+            "properties": {
+                "drawDateFrom": {
                     "type": "string"
                 },
-                "imagePath": {
+                "drawDateTo": {
                     "type": "string"
                 },
-                "imageUrl": {
-                    "type": "string"
+                "hasRecommendation": {
+                    "type": "boolean"
                 },
                 "issue": {
                     "type": "string"
@@ -1846,25 +6724,25 @@ const docTemplate = `{
                 "lotteryCode": {
                     "type": "string"
                 },
-                "manualDrawDate": {
-                    "type": "string"
-                },
-                "notes": {
-                    "type": "string"
+                "maxPrize": {
+                    "type": "number"
                 },
-                "prizeAmount": {
+                "minPrize": {
                     "type": "number"
                 },
-                "purchasedAt": {
+                "number": {
+                    "type": "integer"
+                },
+                "numberZone": {
                     "type": "string"
                 },
-                "recognizedText": {
+                "prizeName": {
                     "type": "string"
                 },
-                "recommendation": {
-                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketRecommendationDetail"
+                "purchasedFrom": {
+                    "type": "string"
                 },
-                "recommendationId": {
+                "purchasedTo": {
                     "type": "string"
                 },
                 "source": {
@@ -1872,190 +6750,129 @@ const docTemplate = `{
                 },
                 "status": {
                     "type": "string"
-                },
-                "updatedAt": {
-                    "description": "指定为自动更新时间",
-                    "type": "string"
-                }
-            }
-        },
-        "go-fiber-starter_internal_service_lottery.TicketPageResult": {
-            "type": "object",
-            "properties": {
-                "hasMore": {
-                    "type": "boolean"
-                },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketDetail"
-                    }
-                },
-                "page": {
-                    "type": "integer"
-                },
-                "pageSize": {
-                    "type": "integer"
-                },
-                "total": {
-                    "type": "integer"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.TicketRecognitionDraft": {
+        "internal_api_lottery.BulkTicketRequest": {
             "type": "object",
             "properties": {
-                "confidence": {
-                    "type": "number"
-                },
-                "costAmount": {
-                    "type": "number"
-                },
-                "drawDate": {
-                    "type": "string"
+                "filter": {
+                    "$ref": "#/definitions/internal_api_lottery.BulkTicketFilter"
                 },
-                "entries": {
+                "ticketIds": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.ParsedEntry"
+                        "type": "string"
                     }
-                },
-                "issue": {
-                    "type": "string"
-                },
-                "lotteryCode": {
-                    "type": "string"
-                },
-                "rawText": {
-                    "type": "string"
-                },
-                "upload": {
-                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketUploadDetail"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.TicketRecommendationDetail": {
+        "internal_api_lottery.BulkTicketResponse": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "drawDate": {
-                    "type": "string"
-                },
-                "entries": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketRecommendationEntry"
-                    }
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "id": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.BulkTicketResult"
                 },
-                "issue": {
-                    "type": "string"
+                "flag": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "summary": {
-                    "type": "string"
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.TicketRecommendationEntry": {
+        "internal_api_lottery.CreateTicketEntryRequest": {
             "type": "object",
             "properties": {
                 "blueNumbers": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
-                },
-                "prizeAmount": {
-                    "type": "number"
+                "isAdditional": {
+                    "type": "boolean"
                 },
-                "prizeName": {
-                    "type": "string"
+                "multiple": {
+                    "type": "integer"
                 },
                 "redNumbers": {
                     "type": "string"
-                },
-                "sequence": {
-                    "type": "integer"
                 }
             }
         },
-        "go-fiber-starter_internal_service_lottery.TicketUploadDetail": {
+        "internal_api_lottery.CreateTicketRequest": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "description": "指定为自动创建时间",
-                    "type": "string"
-                },
-                "errorMessage": {
-                    "type": "string"
+                "costAmount": {
+                    "type": "number"
                 },
-                "id": {
+                "drawDate": {
                     "type": "string"
                 },
-                "imagePath": {
-                    "type": "string"
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_api_lottery.CreateTicketEntryRequest"
+                    }
                 },
-                "imageUrl": {
+                "issue": {
                     "type": "string"
                 },
                 "lotteryCode": {
                     "type": "string"
                 },
-                "originalFilename": {
-                    "type": "string"
-                },
-                "recognitionConfidence": {
-                    "type": "number"
-                },
-                "recognitionIssue": {
-                    "type": "string"
-                },
-                "recognitionPayload": {
+                "notes": {
                     "type": "string"
                 },
-                "recognizedText": {
+                "purchasedAt": {
                     "type": "string"
                 },
-                "status": {
+                "recommendationId": {
                     "type": "string"
                 },
-                "updatedAt": {
-                    "description": "指定为自动更新时间",
+                "uploadId": {
                     "type": "string"
                 }
             }
         },
-        "internal_api_auth.AuthRequest": {
+        "internal_api_lottery.DashboardResponse": {
             "type": "object",
             "properties": {
-                "password": {
-                    "type": "string",
-                    "example": "pass123"
+                "code": {
+                    "type": "integer",
+                    "example": 200
                 },
-                "username": {
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.DashboardData"
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
                     "type": "string",
-                    "example": "alice"
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "internal_api_auth.ErrorResponse": {
+        "internal_api_lottery.DeleteResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer",
-                    "example": 500
+                    "example": 200
+                },
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {}
                 },
                 "flag": {
                     "type": "boolean",
-                    "example": false
-                },
-                "msg": {
-                    "type": "string",
-                    "example": "请求失败"
+                    "example": true
                 },
                 "time": {
                     "type": "string",
@@ -2063,16 +6880,7 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_api_auth.LoginData": {
-            "type": "object",
-            "properties": {
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9"
-                }
-            }
-        },
-        "internal_api_auth.LoginResponse": {
+        "internal_api_lottery.DrawPageResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2080,7 +6888,7 @@ const docTemplate = `{
                     "example": 200
                 },
                 "data": {
-                    "$ref": "#/definitions/internal_api_auth.LoginData"
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.DrawPageResult"
                 },
                 "flag": {
                     "type": "boolean",
@@ -2092,20 +6900,40 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_api_auth.UserData": {
+        "internal_api_lottery.ErrorResponse": {
             "type": "object",
             "properties": {
-                "id": {
+                "code": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "errorCode": {
                     "type": "string",
-                    "example": "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+                    "example": "INTERNAL_ERROR"
                 },
-                "username": {
+                "flag": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "msg": {
                     "type": "string",
-                    "example": "alice"
+                    "example": "请求失败"
+                },
+                "requestId": {
+                    "type": "string",
+                    "example": "0f8fad5b-d9cb-469f-a165-70867728950e"
+                },
+                "retryAfter": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
                 }
             }
         },
-        "internal_api_auth.UserResponse": {
+        "internal_api_lottery.LedgerListResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2113,7 +6941,10 @@ const docTemplate = `{
                     "example": 200
                 },
                 "data": {
-                    "$ref": "#/definitions/internal_api_auth.UserData"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.LedgerSummary"
+                    }
                 },
                 "flag": {
                     "type": "boolean",
@@ -2125,7 +6956,7 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_api_lottery.BatchSyncResultResponse": {
+        "internal_api_lottery.LedgerMemberListResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2133,7 +6964,10 @@ const docTemplate = `{
                     "example": 200
                 },
                 "data": {
-                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.BatchSyncResult"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.LedgerMemberDetail"
+                    }
                 },
                 "flag": {
                     "type": "boolean",
@@ -2145,67 +6979,27 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_api_lottery.CreateTicketEntryRequest": {
-            "type": "object",
-            "properties": {
-                "blueNumbers": {
-                    "type": "string"
-                },
-                "isAdditional": {
-                    "type": "boolean"
-                },
-                "multiple": {
-                    "type": "integer"
-                },
-                "redNumbers": {
-                    "type": "string"
-                }
-            }
-        },
-        "internal_api_lottery.CreateTicketRequest": {
+        "internal_api_lottery.LedgerMemberRequest": {
             "type": "object",
             "properties": {
-                "costAmount": {
-                    "type": "number"
-                },
-                "drawDate": {
-                    "type": "string"
-                },
-                "entries": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/internal_api_lottery.CreateTicketEntryRequest"
-                    }
-                },
-                "issue": {
-                    "type": "string"
-                },
-                "lotteryCode": {
-                    "type": "string"
-                },
-                "notes": {
-                    "type": "string"
-                },
-                "purchasedAt": {
-                    "type": "string"
-                },
-                "recommendationId": {
-                    "type": "string"
+                "role": {
+                    "type": "string",
+                    "example": "editor"
                 },
-                "uploadId": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "internal_api_lottery.DashboardResponse": {
+        "internal_api_lottery.LedgerMemberResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer",
-                    "example": 200
+                    "example": 201
                 },
                 "data": {
-                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.DashboardData"
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.LedgerMemberDetail"
                 },
                 "flag": {
                     "type": "boolean",
@@ -2217,7 +7011,24 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_api_lottery.DeleteResponse": {
+        "internal_api_lottery.LedgerMemberRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "example": "viewer"
+                }
+            }
+        },
+        "internal_api_lottery.LedgerRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api_lottery.LedgerResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2225,8 +7036,7 @@ const docTemplate = `{
                     "example": 200
                 },
                 "data": {
-                    "type": "object",
-                    "additionalProperties": {}
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.LedgerSummary"
                 },
                 "flag": {
                     "type": "boolean",
@@ -2238,20 +7048,22 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_api_lottery.ErrorResponse": {
+        "internal_api_lottery.LotteryListResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer",
-                    "example": 500
+                    "example": 200
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.LotteryType"
+                    }
                 },
                 "flag": {
                     "type": "boolean",
-                    "example": false
-                },
-                "msg": {
-                    "type": "string",
-                    "example": "请求失败"
+                    "example": true
                 },
                 "time": {
                     "type": "string",
@@ -2259,7 +7071,7 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_api_lottery.LotteryListResponse": {
+        "internal_api_lottery.NumberFrequencyResponse": {
             "type": "object",
             "properties": {
                 "code": {
@@ -2267,10 +7079,7 @@ const docTemplate = `{
                     "example": 200
                 },
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/go-fiber-starter_internal_model_lottery.LotteryType"
-                    }
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.NumberFrequencyResult"
                 },
                 "flag": {
                     "type": "boolean",
@@ -2416,6 +7225,29 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_api_lottery.SyndicateShareListResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.SyndicateShareDetail"
+                    }
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
         "internal_api_lottery.TicketDetailResponse": {
             "type": "object",
             "properties": {
@@ -2436,6 +7268,26 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_api_lottery.TicketImportResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "data": {
+                    "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketImportResult"
+                },
+                "flag": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2026-03-16T10:00:00Z"
+                }
+            }
+        },
         "internal_api_lottery.TicketListResponse": {
             "type": "object",
             "properties": {
@@ -2499,6 +7351,17 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_api_lottery.TicketSharesRequest": {
+            "type": "object",
+            "properties": {
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/go-fiber-starter_internal_service_lottery.TicketShareInput"
+                    }
+                }
+            }
+        },
         "internal_api_lottery.TicketUploadResponse": {
             "type": "object",
             "properties": {
@@ -0,0 +1,388 @@
+package admin
+
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+
+	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/internal/service"
+	"go-fiber-starter/internal/service/backup"
+	lotteryService "go-fiber-starter/internal/service/lottery"
+	"go-fiber-starter/pkg/config"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type UpdateUserStatusRequest struct {
+	Disabled bool `json:"disabled" example:"true"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" example:"admin"`
+}
+
+type ResetPasswordRequest struct {
+	Password string `json:"password" example:"newPass123"`
+}
+
+type CreateInviteCodeRequest struct {
+	MaxUses       int    `json:"maxUses" example:"1"`
+	ExpiresInDays int    `json:"expiresInDays" example:"7"`
+	Note          string `json:"note" example:"给家人使用"`
+}
+
+type RunCompensationRequest struct {
+	Name string `json:"name" example:"previous-draw-prize"`
+}
+
+// @Summary 获取用户列表
+// @Description 管理员分页查看全部账号，支持按用户名关键字和角色筛选
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认 1"
+// @Param pageSize query int false "每页数量，默认 20，最大 100"
+// @Param keyword query string false "用户名关键字"
+// @Param role query string false "角色，可选 admin、user"
+// @Success 200 {object} UserPageResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/users [get]
+func ListUsers(c *fiber.Ctx) error {
+	data, err := service.QueryUsers(service.UserQueryOptions{
+		Page:     parseIntValue(c.Query("page"), 1),
+		PageSize: parseIntValue(c.Query("pageSize"), 20),
+		Keyword:  c.Query("keyword"),
+		Role:     c.Query("role"),
+	})
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 启用或禁用账号
+// @Description 禁用后该账号无法登录或调用接口，也不会再参与定时推荐生成
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "用户 ID"
+// @Param request body UpdateUserStatusRequest true "账号状态"
+// @Success 200 {object} AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{userId}/status [put]
+func UpdateUserStatus(c *fiber.Ctx) error {
+	request := UpdateUserStatusRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	operator, err := service.CurrentUser(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := service.SetUserDisabled(operator.Id.String(), c.Params("userId"), request.Disabled)
+	if err != nil {
+		return userActionError(c, err)
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 修改账号角色
+// @Description 设置账号为 admin 或 user，系统至少保留一个可用管理员
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "用户 ID"
+// @Param request body UpdateUserRoleRequest true "角色"
+// @Success 200 {object} AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{userId}/role [put]
+func UpdateUserRole(c *fiber.Ctx) error {
+	request := UpdateUserRoleRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	operator, err := service.CurrentUser(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := service.SetUserRole(operator.Id.String(), c.Params("userId"), request.Role)
+	if err != nil {
+		return userActionError(c, err)
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 重置账号密码
+// @Description 管理员为账号设置新密码，password 为空时生成临时密码并在响应中返回
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "用户 ID"
+// @Param request body ResetPasswordRequest false "新密码"
+// @Success 200 {object} ResetPasswordResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{userId}/reset-password [post]
+func ResetUserPassword(c *fiber.Ctx) error {
+	request := ResetPasswordRequest{}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+		}
+	}
+
+	data, err := service.ResetUserPassword(c.Params("userId"), request.Password)
+	if err != nil {
+		return userActionError(c, err)
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 获取邀请码列表
+// @Description 返回全部邀请码及其使用次数、有效期和吊销状态
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} InviteCodeListResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/invites [get]
+func ListInviteCodes(c *fiber.Ctx) error {
+	items, err := service.ListInviteCodes()
+	if err != nil {
+		return err
+	}
+	return response.Success(c, items)
+}
+
+// @Summary 创建邀请码
+// @Description 生成注册邀请码，maxUses 为 0 表示不限次数，expiresInDays 为 0 表示永不过期
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateInviteCodeRequest true "邀请码设置"
+// @Success 200 {object} InviteCodeResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /admin/invites [post]
+func CreateInviteCode(c *fiber.Ctx) error {
+	request := CreateInviteCodeRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	operator, err := service.CurrentUser(c)
+	if err != nil {
+		return err
+	}
+
+	invite, err := service.CreateInviteCode(operator.Id.String(), service.CreateInviteCodeInput{
+		MaxUses:       request.MaxUses,
+		ExpiresInDays: request.ExpiresInDays,
+		Note:          request.Note,
+	})
+	if err != nil {
+		return err
+	}
+	return response.Success(c, invite)
+}
+
+// @Summary 吊销邀请码
+// @Description 吊销后邀请码不能再用于注册，已注册的账号不受影响
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param inviteId path string true "邀请码 ID"
+// @Success 200 {object} CompensationRunResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/invites/{inviteId} [delete]
+func RevokeInviteCode(c *fiber.Ctx) error {
+	if err := service.RevokeInviteCode(c.Params("inviteId")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Error(c, "邀请码不存在", fiber.StatusNotFound)
+		}
+		return err
+	}
+	return response.Success(c, fiber.Map{"revoked": 1})
+}
+
+// @Summary 查看当前配置
+// @Description 返回脱敏后的运行配置，便于管理员确认环境变量和本地覆盖是否生效
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} ConfigResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/config [get]
+func GetConfig(c *fiber.Ctx) error {
+	return response.Success(c, config.Sanitized())
+}
+
+// @Summary 手动执行补偿任务
+// @Description 按配置中的任务名称立即执行一次数据补偿
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RunCompensationRequest true "补偿任务名称"
+// @Success 200 {object} CompensationRunResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/compensation/run [post]
+func RunCompensation(c *fiber.Ctx) error {
+	request := RunCompensationRequest{}
+	if err := c.BodyParser(&request); err != nil || request.Name == "" {
+		return response.Error(c, "请指定补偿任务名称", fiber.StatusBadRequest)
+	}
+	if err := lotteryService.RunCompensationJobByName(c.UserContext(), request.Name); err != nil {
+		return err
+	}
+	return response.Success(c, fiber.Map{"name": request.Name, "executed": true})
+}
+
+// @Summary 检索第三方接口调用记录
+// @Description 按时间倒序分页返回极速数据、AI 和 PaddleOCR 的调用记录，列表不含请求和响应内容
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认 1"
+// @Param pageSize query int false "每页数量，默认 20，最大 100"
+// @Param provider query string false "服务方，可选 jisuapi、openai-compatible、paddleocr"
+// @Param success query bool false "是否调用成功"
+// @Param statusCode query int false "HTTP 状态码"
+// @Param requestId query string false "触发调用的请求 ID"
+// @Param userId query string false "触发调用的用户 ID"
+// @Param uploadId query string false "关联的票据上传记录 ID"
+// @Param recommendationId query string false "关联的推荐记录 ID"
+// @Param keyword query string false "按接口地址或错误信息模糊匹配"
+// @Param from query string false "开始日期，格式 YYYY-MM-DD"
+// @Param to query string false "结束日期，格式 YYYY-MM-DD，包含当天"
+// @Success 200 {object} ExternalAPILogPageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/external-logs [get]
+func ListExternalAPILogs(c *fiber.Ctx) error {
+	options := lotteryService.ExternalAPILogQueryOptions{
+		Page:             parseIntValue(c.Query("page"), 1),
+		PageSize:         parseIntValue(c.Query("pageSize"), 20),
+		Provider:         c.Query("provider"),
+		StatusCode:       parseIntValue(c.Query("statusCode"), 0),
+		RequestID:        c.Query("requestId"),
+		UserID:           c.Query("userId"),
+		UploadID:         c.Query("uploadId"),
+		RecommendationID: c.Query("recommendationId"),
+		Keyword:          c.Query("keyword"),
+		From:             c.Query("from"),
+		To:               c.Query("to"),
+	}
+	if value := c.Query("success"); value != "" {
+		success, err := strconv.ParseBool(value)
+		if err != nil {
+			return lotteryService.ErrInvalidQueryFilter.WithMessage("success 只能是 true 或 false")
+		}
+		options.Success = &success
+	}
+	data, err := lotteryService.QueryExternalAPILogs(options)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 查看第三方接口调用详情
+// @Description 返回单条调用记录，包含脱敏并截断后的请求和响应内容
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param logId path string true "调用记录 ID"
+// @Success 200 {object} ExternalAPILogResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/external-logs/{logId} [get]
+func GetExternalAPILog(c *fiber.Ctx) error {
+	data, err := lotteryService.GetExternalAPILog(c.Params("logId"))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 获取备份列表
+// @Description 按时间倒序返回备份目录中的全部备份文件
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} BackupListResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /admin/backups [get]
+func ListBackups(c *fiber.Ctx) error {
+	items, err := backup.List()
+	if err != nil {
+		return err
+	}
+	return response.Success(c, items)
+}
+
+// @Summary 立即备份
+// @Description 备份数据库和上传目录并按保留策略清理旧备份，已有备份任务执行时返回 409
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} BackupCreateResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/backups [post]
+func CreateBackup(c *fiber.Ctx) error {
+	data, err := backup.Create(c.UserContext())
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 校验备份
+// @Description 解压备份并核对文件摘要、迁移版本和各表行数，不修改任何数据；恢复需要停止服务后使用 backup restore 命令
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "备份文件名"
+// @Success 200 {object} BackupManifestResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Router /admin/backups/{name}/verify [post]
+func VerifyBackup(c *fiber.Ctx) error {
+	name := c.Params("name")
+	if name == "" || filepath.Base(name) != name {
+		return backup.ErrBackupNotFound
+	}
+	data, err := backup.Verify(name)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data)
+}
+
+func userActionError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.Error(c, "用户不存在", fiber.StatusNotFound)
+	}
+	return err
+}
+
+func parseIntValue(value string, fallback int) int {
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
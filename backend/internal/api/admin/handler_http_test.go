@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofiber/fiber/v2"
+	jwtware "github.com/gofiber/jwt/v3"
+	"gorm.io/gorm"
+
+	"go-fiber-starter/internal/middleware"
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+)
+
+type responseEnvelope struct {
+	Flag bool            `json:"flag"`
+	Code int             `json:"code"`
+	Data json.RawMessage `json:"data"`
+	Msg  string          `json:"msg"`
+}
+
+func setupTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+
+	prevConfig := config.Current
+	config.Current.Jwt.Secret = "test-secret"
+	config.Current.Jwt.Expiration = 3600
+
+	prevDB := db.DB
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		t.Fatalf("get sql db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := gormDB.AutoMigrate(&model.User{}, &model.Session{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	db.DB = gormDB
+
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+		config.Current = prevConfig
+		db.DB = prevDB
+	})
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
+	api := app.Group("/api")
+	api.Use(jwtware.New(jwtware.Config{SigningKey: []byte(config.Current.Jwt.Secret)}))
+	api.Use(middleware.RequireActiveUser)
+	RegisterRoutes(api)
+	return app
+}
+
+func createTestUser(t *testing.T, username string, role string) (model.User, string) {
+	t.Helper()
+
+	user := model.User{Username: username, Password: "x", Role: role}
+	if err := db.DB.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	tokens, err := service.IssueSession(&user, service.SessionMeta{})
+	if err != nil {
+		t.Fatalf("issue session: %v", err)
+	}
+	return user, tokens.Token
+}
+
+func doRequest(t *testing.T, app *fiber.App, method string, path string, body any, token string) (*http.Response, responseEnvelope) {
+	t.Helper()
+
+	payload := []byte(nil)
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	envelope := responseEnvelope{}
+	_ = json.NewDecoder(resp.Body).Decode(&envelope)
+	return resp, envelope
+}
+
+func TestAdminRoutesRejectRegularUser(t *testing.T) {
+	app := setupTestApp(t)
+	_, token := createTestUser(t, "bob", model.RoleUser)
+
+	resp, _ := doRequest(t, app, http.MethodGet, "/api/admin/users", nil, token)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected forbidden, got %d", resp.StatusCode)
+	}
+}
+
+func TestAdminCanDisableUser(t *testing.T) {
+	app := setupTestApp(t)
+	_, adminToken := createTestUser(t, "root", model.RoleAdmin)
+	member, memberToken := createTestUser(t, "alice", model.RoleUser)
+
+	resp, envelope := doRequest(t, app, http.MethodGet, "/api/admin/users", nil, adminToken)
+	if resp.StatusCode != http.StatusOK || !envelope.Flag {
+		t.Fatalf("list users failed: %d %s", resp.StatusCode, envelope.Msg)
+	}
+	page := service.UserPageResult{}
+	if err := json.Unmarshal(envelope.Data, &page); err != nil {
+		t.Fatalf("decode users: %v", err)
+	}
+	if page.Total != 2 {
+		t.Fatalf("expected 2 users, got %d", page.Total)
+	}
+
+	resp, envelope = doRequest(t, app, http.MethodPut, "/api/admin/users/"+member.Id.String()+"/status", fiber.Map{"disabled": true}, adminToken)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("disable user failed: %d %s", resp.StatusCode, envelope.Msg)
+	}
+
+	// 禁用用户会吊销其全部会话，原有令牌立即失效。
+	resp, _ = doRequest(t, app, http.MethodGet, "/api/admin/users", nil, memberToken)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("disabled user's token should be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestAdminCannotDemoteLastAdmin(t *testing.T) {
+	app := setupTestApp(t)
+	admin, adminToken := createTestUser(t, "root", model.RoleAdmin)
+
+	resp, envelope := doRequest(t, app, http.MethodPut, "/api/admin/users/"+admin.Id.String()+"/role", fiber.Map{"role": model.RoleUser}, adminToken)
+	if resp.StatusCode != http.StatusBadRequest || envelope.Flag {
+		t.Fatalf("expected demoting self to fail, got %d", resp.StatusCode)
+	}
+}
+
+func TestAdminResetPasswordGeneratesTemporaryPassword(t *testing.T) {
+	app := setupTestApp(t)
+	_, adminToken := createTestUser(t, "root", model.RoleAdmin)
+	member, _ := createTestUser(t, "alice", model.RoleUser)
+
+	resp, envelope := doRequest(t, app, http.MethodPost, "/api/admin/users/"+member.Id.String()+"/reset-password", nil, adminToken)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("reset password failed: %d %s", resp.StatusCode, envelope.Msg)
+	}
+	result := service.ResetPasswordResult{}
+	if err := json.Unmarshal(envelope.Data, &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	if result.TemporaryPassword == "" {
+		t.Fatalf("expected temporary password")
+	}
+}
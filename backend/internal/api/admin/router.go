@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"go-fiber-starter/internal/middleware"
+	userModel "go-fiber-starter/internal/model/user"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterRoutes(router fiber.Router) {
+	grp := router.Group("/admin", middleware.RequireRole(userModel.RoleAdmin))
+	grp.Get("/users", ListUsers)
+	grp.Put("/users/:userId/status", UpdateUserStatus)
+	grp.Put("/users/:userId/role", UpdateUserRole)
+	grp.Post("/users/:userId/reset-password", ResetUserPassword)
+	grp.Get("/invites", ListInviteCodes)
+	grp.Post("/invites", CreateInviteCode)
+	grp.Delete("/invites/:inviteId", RevokeInviteCode)
+	grp.Get("/config", GetConfig)
+	grp.Post("/compensation/run", RunCompensation)
+	grp.Get("/external-logs", ListExternalAPILogs)
+	grp.Get("/external-logs/:logId", GetExternalAPILog)
+	grp.Get("/backups", ListBackups)
+	grp.Post("/backups", CreateBackup)
+	grp.Post("/backups/:name/verify", VerifyBackup)
+}
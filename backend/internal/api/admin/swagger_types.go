@@ -0,0 +1,102 @@
+package admin
+
+import (
+	lotteryModel "go-fiber-starter/internal/model/lottery"
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service"
+	"go-fiber-starter/internal/service/backup"
+	lotteryService "go-fiber-starter/internal/service/lottery"
+)
+
+type ErrorResponse struct {
+	Flag      bool   `json:"flag" example:"false"`
+	Code      int    `json:"code" example:"403"`
+	Msg       string `json:"msg" example:"没有权限执行该操作"`
+	ErrorCode string `json:"errorCode" example:"FORBIDDEN"`
+	RequestID string `json:"requestId" example:"0f8fad5b-d9cb-469f-a165-70867728950e"`
+	Time      string `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type UserPageResponse struct {
+	Flag bool                   `json:"flag" example:"true"`
+	Code int                    `json:"code" example:"200"`
+	Data service.UserPageResult `json:"data"`
+	Time string                 `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type AdminUserResponse struct {
+	Flag bool       `json:"flag" example:"true"`
+	Code int        `json:"code" example:"200"`
+	Data model.User `json:"data"`
+	Time string     `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type ResetPasswordResponse struct {
+	Flag bool                        `json:"flag" example:"true"`
+	Code int                         `json:"code" example:"200"`
+	Data service.ResetPasswordResult `json:"data"`
+	Time string                      `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type InviteCodeResponse struct {
+	Flag bool             `json:"flag" example:"true"`
+	Code int              `json:"code" example:"200"`
+	Data model.InviteCode `json:"data"`
+	Time string           `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type InviteCodeListResponse struct {
+	Flag bool               `json:"flag" example:"true"`
+	Code int                `json:"code" example:"200"`
+	Data []model.InviteCode `json:"data"`
+	Time string             `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type ConfigResponse struct {
+	Flag bool           `json:"flag" example:"true"`
+	Code int            `json:"code" example:"200"`
+	Data map[string]any `json:"data"`
+	Time string         `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type CompensationRunResponse struct {
+	Flag bool           `json:"flag" example:"true"`
+	Code int            `json:"code" example:"200"`
+	Data map[string]any `json:"data"`
+	Time string         `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type ExternalAPILogPageResponse struct {
+	Flag bool                              `json:"flag" example:"true"`
+	Code int                               `json:"code" example:"200"`
+	Data lotteryService.ExternalAPILogPage `json:"data"`
+	Time string                            `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type ExternalAPILogResponse struct {
+	Flag bool                        `json:"flag" example:"true"`
+	Code int                         `json:"code" example:"200"`
+	Data lotteryModel.ExternalAPILog `json:"data"`
+	Time string                      `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type BackupListResponse struct {
+	Flag bool             `json:"flag" example:"true"`
+	Code int              `json:"code" example:"200"`
+	Data []backup.Archive `json:"data"`
+	Time string           `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type BackupCreateResponse struct {
+	Flag bool                `json:"flag" example:"true"`
+	Code int                 `json:"code" example:"200"`
+	Data backup.CreateResult `json:"data"`
+	Time string              `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type BackupManifestResponse struct {
+	Flag bool            `json:"flag" example:"true"`
+	Code int             `json:"code" example:"200"`
+	Data backup.Manifest `json:"data"`
+	Time string          `json:"time" example:"2026-03-16T10:00:00Z"`
+}
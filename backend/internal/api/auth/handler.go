@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"errors"
+
 	"go-fiber-starter/internal/api/response"
 	model "go-fiber-starter/internal/model/user"
 	"go-fiber-starter/internal/service"
@@ -8,6 +10,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 var generateFromPassword = bcrypt.GenerateFromPassword
@@ -17,64 +20,241 @@ type AuthRequest struct {
 	Password string `json:"password" example:"pass123"`
 }
 
+type RegisterRequest struct {
+	Username   string `json:"username" example:"alice"`
+	Password   string `json:"password" example:"pass123"`
+	InviteCode string `json:"inviteCode" example:"K7MX2QH9TR"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" example:"pass123"`
+	NewPassword     string `json:"newPassword" example:"newPass456"`
+}
+
+type UpdateProfileRequest struct {
+	DisplayName string  `json:"displayName" example:"小王"`
+	Language    *string `json:"language" example:"en"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" example:"pass123"`
+}
+
+type CreateAccessTokenRequest struct {
+	Name          string   `json:"name" example:"iOS 快捷指令"`
+	Scopes        []string `json:"scopes" example:"read,tickets:write"`
+	ExpiresInDays int      `json:"expiresInDays" example:"90"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" example:"Jx3Jv9...refresh"`
+}
+
 // @Summary 用户注册
-// @Description 创建账号并返回用户信息
+// @Description 创建账号并返回用户信息。注册模式为 invite 时需要填写邀请码，为 closed 时拒绝注册；系统中第一个注册的账号自动成为管理员
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param request body AuthRequest true "注册信息"
+// @Param request body RegisterRequest true "注册信息"
 // @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/register [post]
 func Register(c *fiber.Ctx) error {
-	var req AuthRequest
+	var req RegisterRequest
 
 	if err := c.BodyParser(&req); err != nil {
-		return response.Error(c, "参数不正确")
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
 	}
 
 	hash, err := generateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return response.Error(c, "密码加密失败")
 	}
-	user := model.User{Username: req.Username, Password: string(hash)}
-	if err := db.DB.Create(&user).Error; err != nil {
-		return response.Error(c, "用户名已存在")
+	user, err := service.RegisterUser(service.RegisterInput{
+		Username:     req.Username,
+		PasswordHash: string(hash),
+		InviteCode:   req.InviteCode,
+	})
+	if err != nil {
+		return err
 	}
 
 	return response.Success(c, user)
 }
 
+// @Summary 获取注册状态
+// @Description 返回当前注册模式，前端据此决定是否展示注册入口和邀请码输入框
+// @Tags auth
+// @Produce json
+// @Success 200 {object} RegistrationStatusResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/registration [get]
+func RegistrationStatus(c *fiber.Ctx) error {
+	status, err := service.GetRegistrationStatus()
+	if err != nil {
+		return err
+	}
+	return response.Success(c, status)
+}
+
 // @Summary 用户登录
-// @Description 用户登录接口
+// @Description 用户登录接口，同一 IP 或用户名连续失败次数过多时会临时锁定并返回 429，retryAfter 为需要等待的秒数
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body AuthRequest true "登录信息"
 // @Success 200 {object} LoginResponse
+// @Failure 429 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/login [post]
 func Login(c *fiber.Ctx) error {
 	var req AuthRequest
 
 	if err := c.BodyParser(&req); err != nil {
-		return response.Error(c, "参数不正确")
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+
+	if wait, ok := service.CheckLoginAllowed(c.IP(), req.Username); !ok {
+		return response.TooManyRequests(c, "登录失败次数过多，请稍后再试", wait)
 	}
 
+	// 用户名不存在和密码错误返回同一个错误，避免通过登录接口探测用户名是否已注册。
 	var user model.User
 	if err := db.DB.Where("username = ?", req.Username).First(&user).Error; err != nil {
-		return response.Error(c, "用户名不存在")
+		return loginFailed(c, req.Username, service.ErrInvalidCredentials)
 	}
 
 	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
-		return response.Error(c, "密码不正确")
+		return loginFailed(c, req.Username, service.ErrInvalidCredentials)
+	}
+	service.RecordLoginSuccess(c.IP(), req.Username)
+	if user.Disabled {
+		return service.ErrUserDisabled
 	}
 
-	token, err := service.GenerateJWT(&user)
+	tokens, err := service.IssueSession(&user, service.SessionMetaFromCtx(c))
 	if err != nil {
 		return response.Error(c, "token生成失败")
 	}
-	return response.Success(c, fiber.Map{"token": token})
+	return response.Success(c, tokens)
+}
+
+// loginFailed 记录失败次数，本次失败触发锁定时直接返回 429 和等待时长。
+func loginFailed(c *fiber.Ctx, username string, err error) error {
+	if lockout := service.RecordLoginFailure(c.IP(), username); lockout > 0 {
+		return response.TooManyRequests(c, "登录失败次数过多，请稍后再试", lockout)
+	}
+	return err
+}
+
+// @Summary 刷新访问令牌
+// @Description 使用刷新令牌换取新的访问令牌和刷新令牌，旧刷新令牌会立即失效
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "刷新令牌"
+// @Success 200 {object} LoginResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /auth/refresh [post]
+func Refresh(c *fiber.Ctx) error {
+	var req RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+
+	tokens, err := service.RefreshSession(req.RefreshToken, service.SessionMetaFromCtx(c))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, tokens)
+}
+
+// @Summary 退出登录
+// @Description 吊销当前访问令牌所属的登录会话，对应的刷新令牌同时失效
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} LogoutResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/logout [post]
+func Logout(c *fiber.Ctx) error {
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+	sessionID := service.CurrentSessionID(c)
+	if sessionID == "" {
+		return response.Success(c, fiber.Map{"revoked": 0})
+	}
+	if err := service.RevokeSession(user.Id.String(), sessionID); err != nil {
+		return err
+	}
+	return response.Success(c, fiber.Map{"revoked": 1})
+}
+
+// @Summary 退出全部设备
+// @Description 吊销当前账号的全部登录会话
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} LogoutResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/logout-all [post]
+func LogoutAll(c *fiber.Ctx) error {
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+	if err := service.RevokeAllSessions(user.Id.String()); err != nil {
+		return err
+	}
+	return response.Success(c, fiber.Map{"revoked": "all"})
+}
+
+// @Summary 获取登录会话列表
+// @Description 返回当前账号仍然有效的登录会话，current 表示发起请求的会话
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} SessionListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/sessions [get]
+func ListSessions(c *fiber.Ctx) error {
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+	sessions, err := service.ListSessions(user.Id.String(), service.CurrentSessionID(c))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, sessions)
+}
+
+// @Summary 吊销登录会话
+// @Description 吊销指定的登录会话，常用于移除丢失设备上的登录状态
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path string true "会话 ID"
+// @Success 200 {object} LogoutResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /auth/sessions/{sessionId} [delete]
+func RevokeSession(c *fiber.Ctx) error {
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+	if err := service.RevokeSession(user.Id.String(), c.Params("sessionId")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Error(c, "会话不存在", fiber.StatusNotFound)
+		}
+		return err
+	}
+	return response.Success(c, fiber.Map{"revoked": 1})
 }
 
 // @Summary 获取当前用户
@@ -88,7 +268,163 @@ func Login(c *fiber.Ctx) error {
 func Profile(c *fiber.Ctx) error {
 	user, err := service.CurrentUser(c)
 	if err != nil {
-		return response.Error(c, "用户未找到")
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
 	}
 	return response.Success(c, user)
 }
+
+// @Summary 创建个人访问令牌
+// @Description 为脚本或快捷指令创建访问令牌，可选权限范围 read、tickets:write、recommendations:write、draws:sync；expiresInDays 为 0 表示永不过期。明文令牌只在本次响应中返回
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAccessTokenRequest true "令牌名称、权限范围和有效期"
+// @Success 200 {object} CreatedAccessTokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /auth/tokens [post]
+func CreateAccessToken(c *fiber.Ctx) error {
+	var req CreateAccessTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+
+	token, err := service.CreateAccessToken(user.Id.String(), service.CreateAccessTokenInput{
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		ExpiresInDays: req.ExpiresInDays,
+	})
+	if err != nil {
+		return err
+	}
+	return response.Success(c, token)
+}
+
+// @Summary 获取个人访问令牌列表
+// @Description 返回当前账号未吊销的访问令牌，包含最近使用时间和来源 IP，不包含令牌明文
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} AccessTokenListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/tokens [get]
+func ListAccessTokens(c *fiber.Ctx) error {
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+	tokens, err := service.ListAccessTokens(user.Id.String())
+	if err != nil {
+		return err
+	}
+	return response.Success(c, tokens)
+}
+
+// @Summary 吊销个人访问令牌
+// @Description 吊销后使用该令牌的脚本会立即收到 401
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param tokenId path string true "令牌 ID"
+// @Success 200 {object} LogoutResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /auth/tokens/{tokenId} [delete]
+func RevokeAccessToken(c *fiber.Ctx) error {
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+	if err := service.RevokeAccessToken(user.Id.String(), c.Params("tokenId")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Error(c, "访问令牌不存在", fiber.StatusNotFound)
+		}
+		return err
+	}
+	return response.Success(c, fiber.Map{"revoked": 1})
+}
+
+// @Summary 修改密码
+// @Description 校验当前密码后设置新密码，除当前会话外的其他登录会话会被吊销。通过单点登录创建、尚未设置密码的账号可以不填当前密码，直接设置首个密码
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "当前密码和新密码"
+// @Success 200 {object} LogoutResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /auth/password [put]
+func ChangePassword(c *fiber.Ctx) error {
+	var req ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+
+	if err := service.ChangePassword(user.Id.String(), service.CurrentSessionID(c), req.CurrentPassword, req.NewPassword); err != nil {
+		return err
+	}
+	return response.Success(c, fiber.Map{"changed": true})
+}
+
+// @Summary 更新个人资料
+// @Description 修改当前账号的显示名称，传空字符串表示清除；language 可选 zh-CN 或 en，用于接口错误消息的语言，传空字符串表示跟随 Accept-Language，不传则保持不变
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileRequest true "个人资料"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /auth/profile [put]
+func UpdateProfile(c *fiber.Ctx) error {
+	var req UpdateProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+
+	updated, err := service.UpdateProfile(user.Id.String(), service.UpdateProfileInput{DisplayName: req.DisplayName, Language: req.Language})
+	if err != nil {
+		return err
+	}
+	return response.Success(c, updated)
+}
+
+// @Summary 注销账号
+// @Description 校验密码后永久删除当前账号，以及该账号的票据、票据明细、上传图片、推荐和登录会话。未设置密码的账号不需要填写密码，但必须在 10 分钟内重新通过单点登录验证身份
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteAccountRequest true "当前密码"
+// @Success 200 {object} LogoutResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /auth/account [delete]
+func DeleteAccount(c *fiber.Ctx) error {
+	var req DeleteAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+
+	if err := service.DeleteAccount(user.Id.String(), req.Password); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Error(c, "用户不存在", fiber.StatusNotFound)
+		}
+		return err
+	}
+	return response.Success(c, fiber.Map{"deleted": true})
+}
@@ -12,24 +12,31 @@ import (
 	jwtware "github.com/gofiber/jwt/v3"
 	"gorm.io/gorm"
 
+	"go-fiber-starter/internal/middleware"
 	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/db"
 )
 
 type responseEnvelope struct {
-	Flag bool            `json:"flag"`
-	Code int             `json:"code"`
-	Data json.RawMessage `json:"data"`
-	Msg  string          `json:"msg"`
+	Flag       bool            `json:"flag"`
+	Code       int             `json:"code"`
+	Data       json.RawMessage `json:"data"`
+	Msg        string          `json:"msg"`
+	RetryAfter int             `json:"retryAfter"`
 }
 
 type tokenResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	SessionID    string `json:"sessionId"`
 }
 
 type userResponse struct {
+	Id       string `json:"id"`
 	Username string `json:"username"`
+	Role     string `json:"role"`
 }
 
 func setupTestApp(t *testing.T) *fiber.App {
@@ -48,7 +55,7 @@ func setupTestApp(t *testing.T) *fiber.App {
 	if err != nil {
 		t.Fatalf("open db: %v", err)
 	}
-	if err := gormDB.AutoMigrate(&model.User{}); err != nil {
+	if err := gormDB.AutoMigrate(&model.User{}, &model.Session{}, &model.AccessToken{}, &model.InviteCode{}, &model.Identity{}); err != nil {
 		t.Fatalf("auto migrate: %v", err)
 	}
 	closeSQLDB(t, gormDB)
@@ -59,19 +66,14 @@ func setupTestApp(t *testing.T) *fiber.App {
 		db.DB = prevDB
 	})
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
 	RegisterUnProtectedRoutes(app)
 
 	api := app.Group("/api")
-	api.Use(jwtware.New(jwtware.Config{
-		SigningKey: []byte(config.Current.Jwt.Secret),
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"code":    fiber.StatusUnauthorized,
-				"message": "认证失败，请先登录",
-			})
-		},
-	}))
+	api.Use(middleware.Authenticate(jwtware.New(jwtware.Config{
+		SigningKey:   []byte(config.Current.Jwt.Secret),
+		ErrorHandler: middleware.JWTErrorHandler,
+	})))
 	RegisterRoutes(api)
 
 	return app
@@ -200,3 +202,268 @@ func TestProfileRequiresAuth(t *testing.T) {
 		t.Fatalf("expected unauthorized, got %d", resp.StatusCode)
 	}
 }
+
+func registerAndLogin(t *testing.T, app *fiber.App, username string) tokenResponse {
+	t.Helper()
+
+	credentials := fiber.Map{"username": username, "password": "pass123"}
+	registerEnvelope := decodeEnvelope(t, doJSONRequest(t, app, http.MethodPost, "/api/auth/register", credentials, nil))
+	if !registerEnvelope.Flag {
+		t.Fatalf("register failed: %s", registerEnvelope.Msg)
+	}
+	loginEnvelope := decodeEnvelope(t, doJSONRequest(t, app, http.MethodPost, "/api/auth/login", credentials, nil))
+	if !loginEnvelope.Flag {
+		t.Fatalf("login failed: %s", loginEnvelope.Msg)
+	}
+	var token tokenResponse
+	if err := json.Unmarshal(loginEnvelope.Data, &token); err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+	if token.Token == "" || token.RefreshToken == "" || token.SessionID == "" {
+		t.Fatalf("incomplete token pair: %+v", token)
+	}
+	return token
+}
+
+func TestRefreshRotatesTokenAndDetectsReuse(t *testing.T) {
+	app := setupTestApp(t)
+	login := registerAndLogin(t, app, "alice")
+
+	refreshResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/refresh", fiber.Map{"refreshToken": login.RefreshToken}, nil)
+	if refreshResp.StatusCode != http.StatusOK {
+		t.Fatalf("refresh status: %d", refreshResp.StatusCode)
+	}
+	var refreshed tokenResponse
+	if err := json.Unmarshal(decodeEnvelope(t, refreshResp).Data, &refreshed); err != nil {
+		t.Fatalf("decode refreshed token: %v", err)
+	}
+	if refreshed.RefreshToken == login.RefreshToken || refreshed.SessionID != login.SessionID {
+		t.Fatalf("expected rotated refresh token on same session: %+v", refreshed)
+	}
+
+	reuseResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/refresh", fiber.Map{"refreshToken": login.RefreshToken}, nil)
+	if reuseResp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected reused token to be rejected, got %d", reuseResp.StatusCode)
+	}
+
+	// 旧令牌被重放后整个会话会被吊销，新签发的刷新令牌也随之失效。
+	afterReuseResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/refresh", fiber.Map{"refreshToken": refreshed.RefreshToken}, nil)
+	if afterReuseResp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected session to be revoked after reuse, got %d", afterReuseResp.StatusCode)
+	}
+}
+
+func TestLogoutRevokesCurrentSession(t *testing.T) {
+	app := setupTestApp(t)
+	first := registerAndLogin(t, app, "alice")
+
+	secondEnvelope := decodeEnvelope(t, doJSONRequest(t, app, http.MethodPost, "/api/auth/login", fiber.Map{
+		"username": "alice",
+		"password": "pass123",
+	}, nil))
+	var second tokenResponse
+	if err := json.Unmarshal(secondEnvelope.Data, &second); err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+
+	listEnvelope := decodeEnvelope(t, doJSONRequest(t, app, http.MethodGet, "/api/auth/sessions", nil, map[string]string{
+		"Authorization": "Bearer " + first.Token,
+	}))
+	var sessions []struct {
+		Id      string `json:"id"`
+		Current bool   `json:"current"`
+	}
+	if err := json.Unmarshal(listEnvelope.Data, &sessions); err != nil {
+		t.Fatalf("decode sessions: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+
+	logoutResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/logout", nil, map[string]string{
+		"Authorization": "Bearer " + first.Token,
+	})
+	if logoutResp.StatusCode != http.StatusOK {
+		t.Fatalf("logout status: %d", logoutResp.StatusCode)
+	}
+
+	profileEnvelope := decodeEnvelope(t, doJSONRequest(t, app, http.MethodGet, "/api/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + first.Token,
+	}))
+	if profileEnvelope.Flag {
+		t.Fatalf("expected revoked access token to be rejected")
+	}
+	refreshResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/refresh", fiber.Map{"refreshToken": first.RefreshToken}, nil)
+	if refreshResp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected refresh after logout to fail, got %d", refreshResp.StatusCode)
+	}
+
+	secondProfile := decodeEnvelope(t, doJSONRequest(t, app, http.MethodGet, "/api/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + second.Token,
+	}))
+	if !secondProfile.Flag {
+		t.Fatalf("other session should stay active: %s", secondProfile.Msg)
+	}
+}
+
+func TestAccessTokenAuthenticatesWithinScope(t *testing.T) {
+	app := setupTestApp(t)
+	login := registerAndLogin(t, app, "alice")
+	jwtHeaders := map[string]string{"Authorization": "Bearer " + login.Token}
+
+	createResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/tokens", fiber.Map{
+		"name":   "shortcut",
+		"scopes": []string{"read"},
+	}, jwtHeaders)
+	if createResp.StatusCode != http.StatusOK {
+		t.Fatalf("create token status: %d", createResp.StatusCode)
+	}
+	var created struct {
+		Id    string `json:"id"`
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(decodeEnvelope(t, createResp).Data, &created); err != nil {
+		t.Fatalf("decode token: %v", err)
+	}
+	patHeaders := map[string]string{"Authorization": "Bearer " + created.Token}
+
+	profile := decodeEnvelope(t, doJSONRequest(t, app, http.MethodGet, "/api/auth/profile", nil, patHeaders))
+	if !profile.Flag {
+		t.Fatalf("access token should read profile: %s", profile.Msg)
+	}
+
+	// 访问令牌不能用来创建新令牌，防止脚本自行扩大权限。
+	escalateResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/tokens", fiber.Map{
+		"name":   "escalate",
+		"scopes": []string{"tickets:write"},
+	}, patHeaders)
+	if escalateResp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected token management to be forbidden, got %d", escalateResp.StatusCode)
+	}
+
+	listEnvelope := decodeEnvelope(t, doJSONRequest(t, app, http.MethodGet, "/api/auth/tokens", nil, jwtHeaders))
+	var tokens []struct {
+		LastUsedAt *string `json:"lastUsedAt"`
+	}
+	if err := json.Unmarshal(listEnvelope.Data, &tokens); err != nil {
+		t.Fatalf("decode tokens: %v", err)
+	}
+	if len(tokens) != 1 || tokens[0].LastUsedAt == nil {
+		t.Fatalf("expected one token with last used time, got %+v", tokens)
+	}
+
+	revokeResp := doJSONRequest(t, app, http.MethodDelete, "/api/auth/tokens/"+created.Id, nil, jwtHeaders)
+	if revokeResp.StatusCode != http.StatusOK {
+		t.Fatalf("revoke token status: %d", revokeResp.StatusCode)
+	}
+	afterRevoke := doJSONRequest(t, app, http.MethodGet, "/api/auth/profile", nil, patHeaders)
+	if afterRevoke.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected revoked token to be rejected, got %d", afterRevoke.StatusCode)
+	}
+}
+
+func TestRegisterRespectsInviteMode(t *testing.T) {
+	app := setupTestApp(t)
+	config.Current.Registration.Mode = config.RegistrationInvite
+
+	register := func(username string, inviteCode string) *http.Response {
+		return doJSONRequest(t, app, http.MethodPost, "/api/auth/register", fiber.Map{
+			"username":   username,
+			"password":   "pass123",
+			"inviteCode": inviteCode,
+		}, nil)
+	}
+
+	// 首个账号不需要邀请码，并自动成为管理员。
+	firstResp := register("root", "")
+	if firstResp.StatusCode != http.StatusOK {
+		t.Fatalf("first register status: %d", firstResp.StatusCode)
+	}
+	var first userResponse
+	if err := json.Unmarshal(decodeEnvelope(t, firstResp).Data, &first); err != nil {
+		t.Fatalf("decode user: %v", err)
+	}
+	if first.Role != model.RoleAdmin {
+		t.Fatalf("expected first user to be admin, got %q", first.Role)
+	}
+
+	if resp := register("alice", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected invite code to be required, got %d", resp.StatusCode)
+	}
+
+	invite, err := service.CreateInviteCode(first.Id, service.CreateInviteCodeInput{MaxUses: 1})
+	if err != nil {
+		t.Fatalf("create invite: %v", err)
+	}
+	aliceResp := register("alice", invite.Code)
+	if aliceResp.StatusCode != http.StatusOK {
+		t.Fatalf("register with invite status: %d", aliceResp.StatusCode)
+	}
+	var alice userResponse
+	if err := json.Unmarshal(decodeEnvelope(t, aliceResp).Data, &alice); err != nil {
+		t.Fatalf("decode user: %v", err)
+	}
+	if alice.Role != model.RoleUser {
+		t.Fatalf("expected invited user to be regular user, got %q", alice.Role)
+	}
+	if resp := register("bob", invite.Code); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected used-up invite to be rejected, got %d", resp.StatusCode)
+	}
+
+	config.Current.Registration.Mode = config.RegistrationClosed
+	if resp := register("carol", ""); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected closed registration to be forbidden, got %d", resp.StatusCode)
+	}
+}
+
+func TestLoginLocksOutAfterRepeatedFailures(t *testing.T) {
+	app := setupTestApp(t)
+	registerAndLogin(t, app, "alice")
+	config.Current.Security.Login = config.LoginProtectionConfig{
+		Enabled:        true,
+		MaxAttempts:    2,
+		WindowSeconds:  900,
+		LockoutSeconds: 60,
+	}
+
+	login := func(password string) *http.Response {
+		return doJSONRequest(t, app, http.MethodPost, "/api/auth/login", fiber.Map{
+			"username": "alice",
+			"password": password,
+		}, nil)
+	}
+
+	if resp := login("wrong"); resp.StatusCode == http.StatusTooManyRequests {
+		t.Fatalf("first failure should not lock")
+	}
+	lockedResp := login("wrong")
+	if lockedResp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected lockout, got %d", lockedResp.StatusCode)
+	}
+	if lockedResp.Header.Get("Retry-After") != "60" {
+		t.Fatalf("expected Retry-After header 60, got %q", lockedResp.Header.Get("Retry-After"))
+	}
+	if envelope := decodeEnvelope(t, lockedResp); envelope.Code != http.StatusTooManyRequests || envelope.RetryAfter != 60 {
+		t.Fatalf("unexpected lockout envelope: %+v", envelope)
+	}
+
+	// 锁定期间即使密码正确也不能登录。
+	if resp := login("pass123"); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected locked account to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestLoginDoesNotRevealWhetherUsernameExists(t *testing.T) {
+	app := setupTestApp(t)
+	registerAndLogin(t, app, "alice")
+
+	unknownResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/login", fiber.Map{"username": "nobody", "password": "pass123"}, nil)
+	wrongResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/login", fiber.Map{"username": "alice", "password": "wrong"}, nil)
+	if unknownResp.StatusCode != http.StatusUnauthorized || wrongResp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for both failures, got %d and %d", unknownResp.StatusCode, wrongResp.StatusCode)
+	}
+	unknown, wrong := decodeEnvelope(t, unknownResp), decodeEnvelope(t, wrongResp)
+	if unknown.Msg != wrong.Msg || unknown.Code != wrong.Code {
+		t.Fatalf("expected identical failures, got %+v and %+v", unknown, wrong)
+	}
+}
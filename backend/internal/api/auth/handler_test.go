@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 
+	"go-fiber-starter/internal/middleware"
+
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -20,7 +22,7 @@ func TestRegister_HashError(t *testing.T) {
 		return nil, errors.New("boom")
 	}
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler})
 	app.Post("/api/auth/register", Register)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader("{\"username\":\"alice\",\"password\":\"secret\"}"))
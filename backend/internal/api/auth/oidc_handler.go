@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/internal/service"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// oidcStateCookie 把 state 绑定到发起登录的浏览器，防止回调被其他浏览器重放（登录 CSRF）。
+const oidcStateCookie = "lottery_oidc_state"
+
+// @Summary 获取单点登录配置
+// @Description 返回是否启用 OIDC 登录以及登录按钮展示的名称
+// @Tags auth
+// @Produce json
+// @Success 200 {object} OIDCSettingsResponse
+// @Router /auth/oidc/config [get]
+func OIDCConfig(c *fiber.Ctx) error {
+	return response.Success(c, service.GetOIDCSettings())
+}
+
+// @Summary 发起单点登录
+// @Description 跳转到身份提供方的授权页面，授权完成后回到 /api/auth/oidc/callback。首次登录自动创建账号时同样受注册模式限制，邀请注册模式下需要携带邀请码
+// @Tags auth
+// @Param inviteCode query string false "邀请码，仅邀请注册模式下首次登录时需要"
+// @Success 302
+// @Failure 400 {object} ErrorResponse
+// @Router /auth/oidc/login [get]
+func OIDCLogin(c *fiber.Ctx) error {
+	// Query 返回的字符串复用请求缓冲区，邀请码要保存到回调时使用，必须先复制一份。
+	inviteCode := strings.Clone(c.Query("inviteCode"))
+	authorization, err := service.BeginOIDCLogin(c.UserContext(), "", inviteCode)
+	if err != nil {
+		return oidcError(c, err)
+	}
+	setOIDCStateCookie(c, authorization.State)
+	return c.Redirect(authorization.AuthorizationURL, fiber.StatusFound)
+}
+
+// @Summary 绑定外部身份
+// @Description 为当前账号发起外部身份绑定，返回授权地址，前端跳转后由回调完成绑定
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} OIDCAuthorizationResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /auth/oidc/link [post]
+func OIDCLink(c *fiber.Ctx) error {
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+	authorization, err := service.BeginOIDCLogin(c.UserContext(), user.Id.String(), "")
+	if err != nil {
+		return oidcError(c, err)
+	}
+	setOIDCStateCookie(c, authorization.State)
+	return response.Success(c, authorization)
+}
+
+// @Summary 单点登录回调
+// @Description 身份提供方授权完成后的回调地址。登录成功时跳转到前端并在 URL 片段中携带 token、refreshToken 和 expiresIn；绑定成功时携带 oidc=linked；失败时携带 oidcError
+// @Tags auth
+// @Param code query string true "授权码"
+// @Param state query string true "登录请求标识"
+// @Success 302
+// @Router /auth/oidc/callback [get]
+func OIDCCallback(c *fiber.Ctx) error {
+	state := c.Query("state")
+	cookieState := c.Cookies(oidcStateCookie)
+	clearOIDCStateCookie(c)
+
+	if providerError := c.Query("error"); providerError != "" {
+		return redirectToFrontend(c, url.Values{"oidcError": {"身份提供方拒绝了登录请求: " + providerError}})
+	}
+	if state == "" || cookieState != state {
+		return redirectToFrontend(c, url.Values{"oidcError": {service.ErrOIDCStateInvalid.Error()}})
+	}
+
+	result, err := service.CompleteOIDCLogin(c.UserContext(), state, c.Query("code"), service.SessionMetaFromCtx(c))
+	if err != nil {
+		logger.Error("OIDC 登录失败: %v", err)
+		return redirectToFrontend(c, url.Values{"oidcError": {oidcErrorMessage(err)}})
+	}
+	if result.Linked {
+		return redirectToFrontend(c, url.Values{"oidc": {"linked"}})
+	}
+	return redirectToFrontend(c, url.Values{
+		"token":        {result.Tokens.Token},
+		"refreshToken": {result.Tokens.RefreshToken},
+		"expiresIn":    {strconv.Itoa(result.Tokens.ExpiresIn)},
+	})
+}
+
+// @Summary 获取已绑定的外部身份
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} IdentityListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/identities [get]
+func ListIdentities(c *fiber.Ctx) error {
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+	items, err := service.ListIdentities(user.Id.String())
+	if err != nil {
+		return err
+	}
+	return response.Success(c, items)
+}
+
+// @Summary 解绑外部身份
+// @Description 未设置密码的账号不能解绑最后一个外部身份
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param identityId path string true "外部身份 ID"
+// @Success 200 {object} LogoutResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /auth/identities/{identityId} [delete]
+func UnlinkIdentity(c *fiber.Ctx) error {
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Error(c, "用户未找到", fiber.StatusUnauthorized)
+	}
+	if err := service.UnlinkIdentity(user.Id.String(), c.Params("identityId")); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.Error(c, "外部身份不存在", fiber.StatusNotFound)
+		}
+		return err
+	}
+	return response.Success(c, fiber.Map{"unlinked": 1})
+}
+
+// oidcError 把未分类的错误视为身份提供方故障返回 502，已分类的业务错误保持原有状态码。
+func oidcError(c *fiber.Ctx, err error) error {
+	return response.Fail(c, apperr.Upstream(service.CodeOIDCProviderFailed, err))
+}
+
+// oidcErrorMessage 只把可预期的业务错误透传给前端，其余细节写入日志。
+func oidcErrorMessage(err error) string {
+	for _, known := range []error{
+		service.ErrOIDCDisabled,
+		service.ErrOIDCStateInvalid,
+		service.ErrOIDCIdentityNotLinked,
+		service.ErrOIDCIdentityTaken,
+		service.ErrUserDisabled,
+		service.ErrRegistrationClosed,
+		service.ErrInviteCodeRequired,
+		service.ErrInvalidInviteCode,
+	} {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return "单点登录失败，请稍后重试"
+}
+
+func setOIDCStateCookie(c *fiber.Ctx, state string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/auth/oidc",
+		Expires:  time.Now().Add(10 * time.Minute),
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
+
+func clearOIDCStateCookie(c *fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    "",
+		Path:     "/api/auth/oidc",
+		Expires:  time.Unix(0, 0),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
+
+// redirectToFrontend 通过 URL 片段传递结果，片段不会发送到服务器，也不会写入访问日志。
+func redirectToFrontend(c *fiber.Ctx, values url.Values) error {
+	target := strings.TrimSpace(config.Current.OIDC.FrontendURL)
+	if target == "" {
+		target = "/"
+	}
+	target = strings.SplitN(target, "#", 2)[0]
+	return c.Redirect(target+"#"+values.Encode(), fiber.StatusFound)
+}
@@ -0,0 +1,315 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+	"github.com/gofiber/fiber/v2"
+
+	"go-fiber-starter/internal/service"
+	"go-fiber-starter/pkg/config"
+)
+
+// mockIdentityProvider 在 oidctest 的发现和公钥端点之外补充令牌端点，并校验 PKCE。
+type mockIdentityProvider struct {
+	t       *testing.T
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	mu      sync.Mutex
+	subject string
+	grants  map[string]mockGrant
+}
+
+type mockGrant struct {
+	nonce     string
+	challenge string
+}
+
+func newMockIdentityProvider(t *testing.T) *mockIdentityProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	idp := &mockIdentityProvider{t: t, key: key, grants: map[string]mockGrant{}}
+	discovery := &oidctest.Server{
+		PublicKeys: []oidctest.PublicKey{{PublicKey: key.Public(), KeyID: "test-key", Algorithm: oidc.RS256}},
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", discovery)
+	mux.HandleFunc("/token", idp.serveToken)
+	idp.server = httptest.NewServer(mux)
+	discovery.SetIssuer(idp.server.URL)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// authorize 模拟用户在身份提供方完成授权，返回发给回调地址的授权码。
+func (idp *mockIdentityProvider) authorize(authorizationURL string, subject string) (string, string) {
+	idp.t.Helper()
+
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		idp.t.Fatalf("parse authorization url: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		idp.t.Fatalf("expected PKCE S256 challenge, got %q", query.Get("code_challenge_method"))
+	}
+
+	code := "code-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	idp.mu.Lock()
+	idp.subject = subject
+	idp.grants[code] = mockGrant{nonce: query.Get("nonce"), challenge: query.Get("code_challenge")}
+	idp.mu.Unlock()
+	return code, query.Get("state")
+}
+
+func (idp *mockIdentityProvider) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	idp.mu.Lock()
+	grant, ok := idp.grants[r.PostForm.Get("code")]
+	delete(idp.grants, r.PostForm.Get("code"))
+	subject := idp.subject
+	idp.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	claims := fmt.Sprintf(`{"iss":%q,"aud":"lottery-web","sub":%q,"nonce":%q,"exp":%d,"iat":%d,"preferred_username":"alice","email":"alice@example.com"}`,
+		idp.server.URL, subject, grant.nonce, time.Now().Add(time.Hour).Unix(), time.Now().Unix())
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "mock-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     oidctest.SignIDToken(idp.key, "test-key", oidc.RS256, claims),
+	})
+}
+
+func setupOIDCTest(t *testing.T, autoProvision bool) (*fiber.App, *mockIdentityProvider) {
+	t.Helper()
+
+	app := setupTestApp(t)
+	idp := newMockIdentityProvider(t)
+	config.Current.OIDC = config.OIDCConfig{
+		Enabled:       true,
+		Issuer:        idp.server.URL,
+		ClientID:      "lottery-web",
+		RedirectURL:   "http://localhost/api/auth/oidc/callback",
+		Scopes:        []string{"profile", "email"},
+		AutoProvision: autoProvision,
+		FrontendURL:   "/app",
+	}
+	return app, idp
+}
+
+// runOIDCFlow 依次走完发起登录、身份提供方授权和回调，返回前端跳转地址中的片段参数。
+func runOIDCFlow(t *testing.T, app *fiber.App, idp *mockIdentityProvider, startResp *http.Response, authorizationURL string, subject string) url.Values {
+	t.Helper()
+
+	var stateCookie *http.Cookie
+	for _, cookie := range startResp.Cookies() {
+		if cookie.Name == oidcStateCookie {
+			stateCookie = cookie
+		}
+	}
+	if stateCookie == nil {
+		t.Fatalf("state cookie not set")
+	}
+
+	code, state := idp.authorize(authorizationURL, subject)
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/callback?code="+url.QueryEscape(code)+"&state="+url.QueryEscape(state), nil)
+	req.AddCookie(stateCookie)
+	resp, err := app.Test(req, 5000)
+	if err != nil {
+		t.Fatalf("callback request: %v", err)
+	}
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected redirect from callback, got %d", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("parse redirect: %v", err)
+	}
+	if location.Path != "/app" {
+		t.Fatalf("expected redirect to frontend, got %s", location.String())
+	}
+	fragment, err := url.ParseQuery(location.Fragment)
+	if err != nil {
+		t.Fatalf("parse fragment: %v", err)
+	}
+	return fragment
+}
+
+func startOIDCLogin(t *testing.T, app *fiber.App) (*http.Response, string) {
+	t.Helper()
+	return startOIDCLoginWithPath(t, app, "/api/auth/oidc/login")
+}
+
+func startOIDCLoginWithPath(t *testing.T, app *fiber.App, path string) (*http.Response, string) {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil), 5000)
+	if err != nil {
+		t.Fatalf("login request: %v", err)
+	}
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected redirect to identity provider, got %d", resp.StatusCode)
+	}
+	return resp, resp.Header.Get("Location")
+}
+
+func TestOIDCLoginAutoProvisionsAndReusesUser(t *testing.T) {
+	app, idp := setupOIDCTest(t, true)
+
+	startResp, authorizationURL := startOIDCLogin(t, app)
+	fragment := runOIDCFlow(t, app, idp, startResp, authorizationURL, "idp-user-1")
+	if fragment.Get("oidcError") != "" || fragment.Get("token") == "" || fragment.Get("refreshToken") == "" {
+		t.Fatalf("expected tokens in redirect, got %v", fragment)
+	}
+
+	profile := decodeEnvelope(t, doJSONRequest(t, app, http.MethodGet, "/api/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + fragment.Get("token"),
+	}))
+	var user userResponse
+	if err := json.Unmarshal(profile.Data, &user); err != nil {
+		t.Fatalf("decode profile: %v", err)
+	}
+	if user.Username != "alice" {
+		t.Fatalf("expected username from preferred_username claim, got %q", user.Username)
+	}
+
+	startResp, authorizationURL = startOIDCLogin(t, app)
+	second := runOIDCFlow(t, app, idp, startResp, authorizationURL, "idp-user-1")
+	secondProfile := decodeEnvelope(t, doJSONRequest(t, app, http.MethodGet, "/api/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + second.Get("token"),
+	}))
+	var secondUser userResponse
+	if err := json.Unmarshal(secondProfile.Data, &secondUser); err != nil {
+		t.Fatalf("decode profile: %v", err)
+	}
+	if secondUser.Id != user.Id {
+		t.Fatalf("expected same user on second login, got %s and %s", user.Id, secondUser.Id)
+	}
+}
+
+func TestOIDCCallbackRejectsUnlinkedIdentityAndForeignState(t *testing.T) {
+	app, idp := setupOIDCTest(t, false)
+
+	startResp, authorizationURL := startOIDCLogin(t, app)
+	fragment := runOIDCFlow(t, app, idp, startResp, authorizationURL, "idp-user-2")
+	if fragment.Get("token") != "" || fragment.Get("oidcError") == "" {
+		t.Fatalf("expected unlinked identity to be rejected, got %v", fragment)
+	}
+
+	// 没有携带发起登录时写入的 state cookie，视为跨站伪造的回调。
+	_, authorizationURL = startOIDCLogin(t, app)
+	code, state := idp.authorize(authorizationURL, "idp-user-2")
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/auth/oidc/callback?code="+code+"&state="+state, nil), 5000)
+	if err != nil {
+		t.Fatalf("callback request: %v", err)
+	}
+	location, _ := url.Parse(resp.Header.Get("Location"))
+	forged, _ := url.ParseQuery(location.Fragment)
+	if forged.Get("token") != "" || forged.Get("oidcError") == "" {
+		t.Fatalf("expected callback without state cookie to fail, got %v", forged)
+	}
+}
+
+func TestOIDCLinkAttachesIdentityToCurrentUser(t *testing.T) {
+	app, idp := setupOIDCTest(t, false)
+	login := registerAndLogin(t, app, "bob")
+	headers := map[string]string{"Authorization": "Bearer " + login.Token}
+
+	linkResp := doJSONRequest(t, app, http.MethodPost, "/api/auth/oidc/link", nil, headers)
+	if linkResp.StatusCode != http.StatusOK {
+		t.Fatalf("link status: %d", linkResp.StatusCode)
+	}
+	cookies := linkResp.Cookies()
+	var authorization struct {
+		AuthorizationURL string `json:"authorizationUrl"`
+	}
+	if err := json.Unmarshal(decodeEnvelope(t, linkResp).Data, &authorization); err != nil {
+		t.Fatalf("decode authorization: %v", err)
+	}
+	startResp := &http.Response{Header: http.Header{}}
+	for _, cookie := range cookies {
+		startResp.Header.Add("Set-Cookie", cookie.String())
+	}
+	fragment := runOIDCFlow(t, app, idp, startResp, authorization.AuthorizationURL, "idp-user-3")
+	if fragment.Get("oidc") != "linked" {
+		t.Fatalf("expected link result, got %v", fragment)
+	}
+
+	// 绑定后即使关闭自动创建，也能直接用外部身份登录到同一个账号。
+	startLogin, authorizationURL := startOIDCLogin(t, app)
+	loginFragment := runOIDCFlow(t, app, idp, startLogin, authorizationURL, "idp-user-3")
+	profile := decodeEnvelope(t, doJSONRequest(t, app, http.MethodGet, "/api/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + loginFragment.Get("token"),
+	}))
+	var user userResponse
+	if err := json.Unmarshal(profile.Data, &user); err != nil {
+		t.Fatalf("decode profile: %v", err)
+	}
+	if user.Username != "bob" {
+		t.Fatalf("expected linked login to resolve bob, got %q", user.Username)
+	}
+}
+
+func TestOIDCAutoProvisionRespectsRegistrationMode(t *testing.T) {
+	app, idp := setupOIDCTest(t, true)
+	root := registerAndLogin(t, app, "root")
+	var admin userResponse
+	profile := decodeEnvelope(t, doJSONRequest(t, app, http.MethodGet, "/api/auth/profile", nil, map[string]string{
+		"Authorization": "Bearer " + root.Token,
+	}))
+	if err := json.Unmarshal(profile.Data, &admin); err != nil {
+		t.Fatalf("decode profile: %v", err)
+	}
+
+	config.Current.Registration.Mode = config.RegistrationClosed
+	startResp, authorizationURL := startOIDCLogin(t, app)
+	fragment := runOIDCFlow(t, app, idp, startResp, authorizationURL, "idp-user-4")
+	if fragment.Get("token") != "" || fragment.Get("oidcError") == "" {
+		t.Fatalf("expected closed registration to refuse provisioning, got %v", fragment)
+	}
+
+	config.Current.Registration.Mode = config.RegistrationInvite
+	startResp, authorizationURL = startOIDCLogin(t, app)
+	fragment = runOIDCFlow(t, app, idp, startResp, authorizationURL, "idp-user-4")
+	if fragment.Get("token") != "" || fragment.Get("oidcError") == "" {
+		t.Fatalf("expected invite mode without code to refuse provisioning, got %v", fragment)
+	}
+
+	invite, err := service.CreateInviteCode(admin.Id, service.CreateInviteCodeInput{MaxUses: 1})
+	if err != nil {
+		t.Fatalf("create invite: %v", err)
+	}
+	startResp, authorizationURL = startOIDCLoginWithPath(t, app, "/api/auth/oidc/login?inviteCode="+invite.Code)
+	fragment = runOIDCFlow(t, app, idp, startResp, authorizationURL, "idp-user-4")
+	if fragment.Get("oidcError") != "" || fragment.Get("token") == "" {
+		t.Fatalf("expected invite code to allow provisioning, got %v", fragment)
+	}
+}
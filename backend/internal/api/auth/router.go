@@ -13,11 +13,29 @@ import (
 
 func RegisterUnProtectedRoutes(router *fiber.App) {
 	grp := router.Group("/api/auth")
+	grp.Get("/registration", RegistrationStatus)
 	grp.Post("/register", Register)
 	grp.Post("/login", Login)
+	grp.Post("/refresh", Refresh)
+	grp.Get("/oidc/config", OIDCConfig)
+	grp.Get("/oidc/login", OIDCLogin)
+	grp.Get("/oidc/callback", OIDCCallback)
 }
 
 func RegisterRoutes(router fiber.Router) {
 	grp := router.Group("/auth")
 	grp.Get("/profile", Profile)
+	grp.Put("/profile", UpdateProfile)
+	grp.Put("/password", ChangePassword)
+	grp.Delete("/account", DeleteAccount)
+	grp.Post("/logout", Logout)
+	grp.Post("/logout-all", LogoutAll)
+	grp.Get("/sessions", ListSessions)
+	grp.Delete("/sessions/:sessionId", RevokeSession)
+	grp.Post("/oidc/link", OIDCLink)
+	grp.Get("/identities", ListIdentities)
+	grp.Delete("/identities/:identityId", UnlinkIdentity)
+	grp.Get("/tokens", ListAccessTokens)
+	grp.Post("/tokens", CreateAccessToken)
+	grp.Delete("/tokens/:tokenId", RevokeAccessToken)
 }
@@ -1,26 +1,90 @@
 package auth
 
+import (
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service"
+)
+
 type ErrorResponse struct {
-	Flag bool   `json:"flag" example:"false"`
-	Code int    `json:"code" example:"500"`
-	Msg  string `json:"msg" example:"请求失败"`
-	Time string `json:"time" example:"2026-03-16T10:00:00Z"`
+	Flag       bool   `json:"flag" example:"false"`
+	Code       int    `json:"code" example:"500"`
+	Msg        string `json:"msg" example:"请求失败"`
+	ErrorCode  string `json:"errorCode" example:"INTERNAL_ERROR"`
+	RetryAfter int    `json:"retryAfter,omitempty" example:"60"`
+	RequestID  string `json:"requestId" example:"0f8fad5b-d9cb-469f-a165-70867728950e"`
+	Time       string `json:"time" example:"2026-03-16T10:00:00Z"`
 }
 
-type LoginData struct {
-	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9"`
+type LoginResponse struct {
+	Flag bool              `json:"flag" example:"true"`
+	Code int               `json:"code" example:"200"`
+	Data service.TokenPair `json:"data"`
+	Time string            `json:"time" example:"2026-03-16T10:00:00Z"`
 }
 
-type LoginResponse struct {
-	Flag bool      `json:"flag" example:"true"`
-	Code int       `json:"code" example:"200"`
-	Data LoginData `json:"data"`
-	Time string    `json:"time" example:"2026-03-16T10:00:00Z"`
+type LogoutResponse struct {
+	Flag bool           `json:"flag" example:"true"`
+	Code int            `json:"code" example:"200"`
+	Data map[string]any `json:"data"`
+	Time string         `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type SessionListResponse struct {
+	Flag bool                    `json:"flag" example:"true"`
+	Code int                     `json:"code" example:"200"`
+	Data []service.SessionDetail `json:"data"`
+	Time string                  `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type CreatedAccessTokenResponse struct {
+	Flag bool                       `json:"flag" example:"true"`
+	Code int                        `json:"code" example:"200"`
+	Data service.CreatedAccessToken `json:"data"`
+	Time string                     `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type AccessTokenListResponse struct {
+	Flag bool                `json:"flag" example:"true"`
+	Code int                 `json:"code" example:"200"`
+	Data []model.AccessToken `json:"data"`
+	Time string              `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type RegistrationStatusResponse struct {
+	Flag bool                       `json:"flag" example:"true"`
+	Code int                        `json:"code" example:"200"`
+	Data service.RegistrationStatus `json:"data"`
+	Time string                     `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type OIDCSettingsResponse struct {
+	Flag bool                 `json:"flag" example:"true"`
+	Code int                  `json:"code" example:"200"`
+	Data service.OIDCSettings `json:"data"`
+	Time string               `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type OIDCAuthorizationResponse struct {
+	Flag bool                      `json:"flag" example:"true"`
+	Code int                       `json:"code" example:"200"`
+	Data service.OIDCAuthorization `json:"data"`
+	Time string                    `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type IdentityListResponse struct {
+	Flag bool             `json:"flag" example:"true"`
+	Code int              `json:"code" example:"200"`
+	Data []model.Identity `json:"data"`
+	Time string           `json:"time" example:"2026-03-16T10:00:00Z"`
 }
 
 type UserData struct {
-	ID       string `json:"id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
-	Username string `json:"username" example:"alice"`
+	ID          string `json:"id" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Username    string `json:"username" example:"alice"`
+	DisplayName string `json:"displayName" example:"小王"`
+	Role        string `json:"role" example:"user"`
+	Disabled    bool   `json:"disabled" example:"false"`
+	Language    string `json:"language" example:"zh-CN"`
 }
 
 type UserResponse struct {
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"time"
+
+	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/internal/i18n"
+	"go-fiber-starter/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const codeServiceNotReady = "SERVICE_NOT_READY"
+
+// @Summary 存活检查
+// @Description 进程能够处理请求即返回 200，不检查任何依赖，适合作为容器存活探针
+// @Tags health
+// @Produce json
+// @Success 200 {object} LiveResponse
+// @Router /health/live [get]
+func Live(c *fiber.Ctx) error {
+	return response.Success(c, LiveData{Status: service.HealthStatusOK})
+}
+
+// @Summary 就绪检查
+// @Description 检查数据库连接、数据表迁移、定时任务调度器和上传目录可写性，全部通过时返回 200，否则返回 503。
+// @Description 开启 health.checkExternal 后同时报告极速数据、AI 接口和 PaddleOCR 的连通性（结果带缓存），外部服务不可达只会让整体状态变为 degraded
+// @Tags health
+// @Produce json
+// @Success 200 {object} ReadyResponse
+// @Failure 503 {object} NotReadyResponse
+// @Router /health/ready [get]
+func Ready(c *fiber.Ctx) error {
+	report := service.CheckReadiness(c.UserContext())
+	if report.Ready {
+		return response.Success(c, report)
+	}
+	return c.Status(fiber.StatusServiceUnavailable).JSON(response.Response{
+		Flag:      false,
+		Code:      fiber.StatusServiceUnavailable,
+		Data:      report,
+		Msg:       i18n.Localize(c, codeServiceNotReady, "服务未就绪"),
+		ErrorCode: codeServiceNotReady,
+		RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
+	})
+}
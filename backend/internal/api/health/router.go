@@ -0,0 +1,10 @@
+package health
+
+import "github.com/gofiber/fiber/v2"
+
+// RegisterRoutes 注册无需认证的存活与就绪检查接口，供容器编排和负载均衡探测使用。
+func RegisterRoutes(router *fiber.App) {
+	grp := router.Group("/api/health")
+	grp.Get("/live", Live)
+	grp.Get("/ready", Ready)
+}
@@ -0,0 +1,31 @@
+package health
+
+import "go-fiber-starter/internal/service"
+
+type LiveData struct {
+	Status string `json:"status" example:"ok"`
+}
+
+type LiveResponse struct {
+	Flag bool     `json:"flag" example:"true"`
+	Code int      `json:"code" example:"200"`
+	Data LiveData `json:"data"`
+	Time string   `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type ReadyResponse struct {
+	Flag bool                 `json:"flag" example:"true"`
+	Code int                  `json:"code" example:"200"`
+	Data service.HealthReport `json:"data"`
+	Time string               `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type NotReadyResponse struct {
+	Flag      bool                 `json:"flag" example:"false"`
+	Code      int                  `json:"code" example:"503"`
+	Msg       string               `json:"msg" example:"服务未就绪"`
+	ErrorCode string               `json:"errorCode" example:"SERVICE_NOT_READY"`
+	Data      service.HealthReport `json:"data"`
+	RequestID string               `json:"requestId" example:"0f8fad5b-d9cb-469f-a165-70867728950e"`
+	Time      string               `json:"time" example:"2026-03-16T10:00:00Z"`
+}
@@ -0,0 +1,174 @@
+package lottery
+
+import (
+	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/internal/i18n"
+	lotteryModel "go-fiber-starter/internal/model/lottery"
+	lotteryService "go-fiber-starter/internal/service/lottery"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BulkTicketRequest 通过 ticketIds 指定票据，或通过 filter 按历史票据的筛选条件选择票据，两者同时传入时以 ticketIds 为准。
+type BulkTicketRequest struct {
+	TicketIDs []string          `json:"ticketIds"`
+	Filter    *BulkTicketFilter `json:"filter"`
+}
+
+// BulkTicketFilter 的字段含义与 GET /lotteries/tickets/history 的查询参数一致。
+type BulkTicketFilter struct {
+	LotteryCode       string              `json:"lotteryCode"`
+	Status            string              `json:"status"`
+	Issue             string              `json:"issue"`
+	PurchasedFrom     string              `json:"purchasedFrom"`
+	PurchasedTo       string              `json:"purchasedTo"`
+	DrawDateFrom      string              `json:"drawDateFrom"`
+	DrawDateTo        string              `json:"drawDateTo"`
+	MinPrize          *lotteryModel.Money `json:"minPrize" swaggertype:"number"`
+	MaxPrize          *lotteryModel.Money `json:"maxPrize" swaggertype:"number"`
+	PrizeName         string              `json:"prizeName"`
+	Source            string              `json:"source"`
+	HasRecommendation *bool               `json:"hasRecommendation"`
+	Number            int                 `json:"number"`
+	NumberZone        string              `json:"numberZone"`
+}
+
+type BulkLinkTicketRequest struct {
+	BulkTicketRequest
+	RecommendationID string `json:"recommendationId"`
+}
+
+// @Summary 批量删除票据
+// @Description 在同一个事务中删除多张票据及其明细、上传记录，返回每张票据的处理结果；单张失败不影响其余票据，
+// @Description 全部删除后统一清理不再被引用的原图。单次最多 200 张
+// @Tags lottery
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkTicketRequest true "票据 ID 列表或筛选条件"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
+// @Success 200 {object} BulkTicketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /lotteries/tickets/bulk/delete [post]
+func BulkDeleteTickets(c *fiber.Ctx) error {
+	input, err := parseBulkTicketInput(c, &BulkTicketRequest{})
+	if err != nil {
+		return err
+	}
+	data, err := lotteryService.BulkDeleteTickets(input)
+	if err != nil {
+		return err
+	}
+	return bulkTicketSuccess(c, data)
+}
+
+// @Summary 批量重新判奖
+// @Description 逐张按票据自身的彩种与期号重新同步开奖并判奖，返回每张票据的处理结果和最新详情。单次最多 200 张
+// @Tags lottery
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkTicketRequest true "票据 ID 列表或筛选条件"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
+// @Success 200 {object} BulkTicketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /lotteries/tickets/bulk/recheck [post]
+func BulkRecheckTickets(c *fiber.Ctx) error {
+	input, err := parseBulkTicketInput(c, &BulkTicketRequest{})
+	if err != nil {
+		return err
+	}
+	data, err := lotteryService.BulkRecheckTickets(c.UserContext(), input)
+	if err != nil {
+		return err
+	}
+	return bulkTicketSuccess(c, data)
+}
+
+// @Summary 批量关联推荐
+// @Description 把多张票据关联到同一条推荐记录，recommendationId 为空时解除关联；推荐与票据彩种不一致的票据单独标记失败。单次最多 200 张
+// @Tags lottery
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body BulkLinkTicketRequest true "票据 ID 列表或筛选条件，以及推荐记录 ID"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
+// @Success 200 {object} BulkTicketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /lotteries/tickets/bulk/link [post]
+func BulkLinkTickets(c *fiber.Ctx) error {
+	request := &BulkLinkTicketRequest{}
+	input, err := parseBulkTicketInput(c, request)
+	if err != nil {
+		return err
+	}
+	input.RecommendationID = request.RecommendationID
+	data, err := lotteryService.BulkLinkTickets(input)
+	if err != nil {
+		return err
+	}
+	return bulkTicketSuccess(c, data)
+}
+
+// parseBulkTicketInput 解析请求体到 request，并组装带当前账本的批量操作参数。
+func parseBulkTicketInput(c *fiber.Ctx, request bulkTicketTarget) (lotteryService.BulkTicketInput, error) {
+	owner, err := currentOwner(c)
+	if err != nil {
+		return lotteryService.BulkTicketInput{}, err
+	}
+	if err := c.BodyParser(request); err != nil {
+		return lotteryService.BulkTicketInput{}, errRequestBodyInvalid
+	}
+
+	target := request.target()
+	input := lotteryService.BulkTicketInput{
+		UserID:    owner.UserID,
+		LedgerID:  owner.LedgerID,
+		TicketIDs: target.TicketIDs,
+	}
+	if filter := target.Filter; filter != nil {
+		input.Filter = &lotteryService.TicketQueryOptions{
+			LotteryCode:       filter.LotteryCode,
+			Status:            filter.Status,
+			Issue:             filter.Issue,
+			PurchasedFrom:     filter.PurchasedFrom,
+			PurchasedTo:       filter.PurchasedTo,
+			DrawDateFrom:      filter.DrawDateFrom,
+			DrawDateTo:        filter.DrawDateTo,
+			MinPrize:          filter.MinPrize,
+			MaxPrize:          filter.MaxPrize,
+			PrizeName:         filter.PrizeName,
+			Source:            filter.Source,
+			HasRecommendation: filter.HasRecommendation,
+			Number:            filter.Number,
+			NumberZone:        filter.NumberZone,
+		}
+	}
+	return input, nil
+}
+
+type bulkTicketTarget interface {
+	target() *BulkTicketRequest
+}
+
+func (r *BulkTicketRequest) target() *BulkTicketRequest {
+	return r
+}
+
+// bulkTicketSuccess 按请求语言翻译每张票据的失败消息后返回结果。
+func bulkTicketSuccess(c *fiber.Ctx, data *lotteryService.BulkTicketResult) error {
+	for index := range data.Items {
+		item := &data.Items[index]
+		if item.ErrorCode != "" {
+			item.Message = i18n.Localize(c, item.ErrorCode, item.Message)
+		}
+	}
+	return response.Success(c, data)
+}
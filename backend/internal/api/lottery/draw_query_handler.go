@@ -8,17 +8,24 @@ import (
 )
 
 // @Summary 分页获取历史开奖记录
-// @Description 返回系统已同步入库的历史开奖记录，支持按彩种、期号、开奖日期筛选，并按开奖时间排序
+// @Description 返回系统已同步入库的历史开奖记录，支持按彩种、期号、开奖日期或日期区间、开奖号码筛选，并按开奖时间排序。
+// @Description 传入上一页返回的 nextCursor 即按游标翻页，不传 cursor 时按 page 分页
 // @Tags lottery
 // @Produce json
 // @Security BearerAuth
-// @Param page query int false "页码，默认 1"
+// @Param page query int false "页码，默认 1，传 cursor 时忽略"
 // @Param pageSize query int false "每页数量，默认 20，最大 50"
+// @Param cursor query string false "上一页返回的 nextCursor"
 // @Param lotteryCode query string false "彩票编码，如 ssq、dlt"
 // @Param issue query string false "期号"
 // @Param drawDate query string false "开奖日期，格式 2026-03-22"
+// @Param drawDateFrom query string false "开奖日期起，格式 2026-03-01"
+// @Param drawDateTo query string false "开奖日期止（含当天），格式 2026-03-31"
+// @Param number query int false "开奖号码包含的号码"
+// @Param numberZone query string false "号码区域，可选 red、blue，不传时两区都匹配"
 // @Param sort query string false "排序，可选 latest、oldest"
 // @Success 200 {object} DrawPageResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/draws/history [get]
 func ListDrawHistory(c *fiber.Ctx) error {
@@ -27,12 +34,17 @@ func ListDrawHistory(c *fiber.Ctx) error {
 	}
 
 	data, err := lotteryService.QueryDrawResults(lotteryService.DrawQueryOptions{
-		Page:        parseIntValue(c.Query("page"), 1),
-		PageSize:    parseIntValue(c.Query("pageSize"), 20),
-		LotteryCode: c.Query("lotteryCode"),
-		Issue:       c.Query("issue"),
-		DrawDate:    c.Query("drawDate"),
-		Sort:        c.Query("sort", "latest"),
+		Page:         parseIntValue(c.Query("page"), 1),
+		PageSize:     parseIntValue(c.Query("pageSize"), 20),
+		Cursor:       c.Query("cursor"),
+		LotteryCode:  c.Query("lotteryCode"),
+		Issue:        c.Query("issue"),
+		DrawDate:     c.Query("drawDate"),
+		DrawDateFrom: c.Query("drawDateFrom"),
+		DrawDateTo:   c.Query("drawDateTo"),
+		Number:       parseIntValue(c.Query("number"), 0),
+		NumberZone:   c.Query("numberZone"),
+		Sort:         c.Query("sort", "latest"),
 	})
 	if err != nil {
 		return err
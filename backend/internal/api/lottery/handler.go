@@ -2,8 +2,6 @@ package lottery
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
 	"mime/multipart"
 	"path/filepath"
 	"strconv"
@@ -11,7 +9,9 @@ import (
 	"time"
 
 	"go-fiber-starter/internal/api/response"
+	lotteryModel "go-fiber-starter/internal/model/lottery"
 	coreService "go-fiber-starter/internal/service"
+	"go-fiber-starter/internal/service/apperr"
 	lotteryService "go-fiber-starter/internal/service/lottery"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/util"
@@ -20,6 +20,15 @@ import (
 	"github.com/google/uuid"
 )
 
+// 请求参数校验失败时返回的错误，错误码用于客户端区分具体字段并选择对应语言的提示。
+var (
+	errRequestBodyInvalid  = apperr.Invalid("REQUEST_BODY_INVALID", "请求体不是合法 JSON，请检查逗号和引号格式")
+	errPurchasedAtInvalid  = apperr.Invalid("PURCHASED_AT_INVALID", "购买时间格式不正确，应为 RFC3339")
+	errDrawDateInvalid     = apperr.Invalid("DRAW_DATE_INVALID", "开奖日期格式不正确，应为 YYYY-MM-DD")
+	errTicketEntryFormat   = apperr.Invalid("TICKET_ENTRY_FORMAT_INVALID", "每注号码都需要包含 redNumbers 和 blueNumbers")
+	errTicketImageRequired = apperr.Invalid("TICKET_IMAGE_REQUIRED", "请上传彩票图片")
+)
+
 type SyncDrawRequest struct {
 	Issue        string   `json:"issue"`
 	Start        int      `json:"start"`
@@ -46,7 +55,7 @@ type CreateTicketRequest struct {
 	Issue            string                     `json:"issue"`
 	DrawDate         string                     `json:"drawDate"`
 	PurchasedAt      string                     `json:"purchasedAt"`
-	CostAmount       float64                    `json:"costAmount"`
+	CostAmount       lotteryModel.Money         `json:"costAmount" swaggertype:"number"`
 	Notes            string                     `json:"notes"`
 	Entries          []CreateTicketEntryRequest `json:"entries"`
 }
@@ -73,15 +82,16 @@ func ListLotteries(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq、dlt"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} DashboardResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/dashboard [get]
 func GetDashboard(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	data, err := lotteryService.GetDashboard(c.Params("code"), userID)
+	data, err := lotteryService.GetDashboard(c.Params("code"), owner)
 	if err != nil {
 		return err
 	}
@@ -93,15 +103,16 @@ func GetDashboard(c *fiber.Ctx) error {
 // @Tags lottery
 // @Produce json
 // @Security BearerAuth
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} DashboardResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/dashboard [get]
 func GetGlobalDashboard(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	data, err := lotteryService.GetGlobalDashboard(userID)
+	data, err := lotteryService.GetGlobalDashboard(owner)
 	if err != nil {
 		return err
 	}
@@ -115,16 +126,17 @@ func GetGlobalDashboard(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq、dlt"
 // @Param limit query int false "返回数量，默认 20"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} RecommendationListResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/recommendations [get]
 func ListRecommendations(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
-	data, err := lotteryService.ListRecommendations(c.Params("code"), limit, userID)
+	data, err := lotteryService.ListRecommendations(c.Params("code"), limit, owner)
 	if err != nil {
 		return err
 	}
@@ -141,16 +153,18 @@ func ListRecommendations(c *fiber.Ctx) error {
 // @Param lotteryCode query string false "彩票编码，如 ssq、dlt"
 // @Param status query string false "状态，可选 pending、won、not_won"
 // @Param sort query string false "排序，可选 latest、oldest、draw_latest、draw_oldest、prize_high"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} RecommendationPageResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/recommendations [get]
 func ListAllRecommendations(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
 	data, err := lotteryService.QueryRecommendations(lotteryService.RecommendationQueryOptions{
-		UserID:      userID,
+		UserID:      owner.UserID,
+		LedgerID:    owner.LedgerID,
 		Page:        parseIntValue(c.Query("page"), 1),
 		PageSize:    parseIntValue(c.Query("pageSize"), 10),
 		LotteryCode: c.Query("lotteryCode"),
@@ -169,15 +183,16 @@ func ListAllRecommendations(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq、dlt"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} RecommendationResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/recommendations/latest [get]
 func GetLatestRecommendation(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	data, err := lotteryService.GetLatestRecommendation(c.Params("code"), userID)
+	data, err := lotteryService.GetLatestRecommendation(c.Params("code"), owner)
 	if err != nil {
 		return err
 	}
@@ -191,15 +206,16 @@ func GetLatestRecommendation(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq、dlt"
 // @Param recommendationId path string true "推荐记录 ID"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} RecommendationDetailResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/recommendations/{recommendationId} [get]
 func GetRecommendationDetail(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	data, err := lotteryService.GetRecommendationDetail(c.Params("code"), c.Params("recommendationId"), userID)
+	data, err := lotteryService.GetRecommendationDetail(c.Params("code"), c.Params("recommendationId"), owner)
 	if err != nil {
 		return err
 	}
@@ -213,15 +229,16 @@ func GetRecommendationDetail(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq、dlt"
 // @Param recommendationId path string true "推荐记录 ID"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} DeleteResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/recommendations/{recommendationId} [delete]
 func DeleteRecommendation(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	if err := lotteryService.DeleteRecommendation(c.Params("code"), c.Params("recommendationId"), userID); err != nil {
+	if err := lotteryService.DeleteRecommendation(c.Params("code"), c.Params("recommendationId"), owner); err != nil {
 		return err
 	}
 	return response.Success(c, fiber.Map{"deleted": true})
@@ -235,15 +252,16 @@ func DeleteRecommendation(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq、dlt"
 // @Param recommendationId path string true "推荐记录 ID"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} RecommendationDetailResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/recommendations/{recommendationId}/recheck [post]
 func RecheckRecommendation(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	data, err := lotteryService.RecheckRecommendation(c.Context(), c.Params("code"), c.Params("recommendationId"), userID)
+	data, err := lotteryService.RecheckRecommendation(c.UserContext(), c.Params("code"), c.Params("recommendationId"), owner)
 	if err != nil {
 		return err
 	}
@@ -256,15 +274,18 @@ func RecheckRecommendation(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq、dlt"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} RecommendationResponse
+// @Failure 502 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Router /lotteries/{code}/recommendations/generate [post]
 func GenerateRecommendation(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	data, err := lotteryService.GenerateRecommendation(c.Context(), c.Params("code"), 0, userID)
+	data, err := lotteryService.GenerateRecommendation(c.UserContext(), c.Params("code"), 0, owner)
 	if err != nil {
 		return err
 	}
@@ -280,14 +301,16 @@ func GenerateRecommendation(c *fiber.Ctx) error {
 // @Param code path string true "彩票编码，如 ssq、dlt"
 // @Param request body SyncDrawRequest false "同步参数，issue 为空时同步当前期"
 // @Success 200 {object} SyncResultResponse
+// @Failure 502 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Router /lotteries/{code}/draws/sync [post]
 func SyncDraws(c *fiber.Ctx) error {
 	request, err := parseSyncDrawRequest(c)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
-	data, err := lotteryService.SyncLatestDraw(c.Context(), c.Params("code"), request.Issue)
+	data, err := lotteryService.SyncLatestDraw(c.UserContext(), c.Params("code"), request.Issue)
 	if err != nil {
 		return err
 	}
@@ -303,14 +326,16 @@ func SyncDraws(c *fiber.Ctx) error {
 // @Param code path string true "彩票编码，如 ssq、dlt"
 // @Param request body SyncDrawRequest false "历史同步参数"
 // @Success 200 {object} SyncResultResponse
+// @Failure 502 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Router /lotteries/{code}/draws/sync-history [post]
 func SyncDrawHistory(c *fiber.Ctx) error {
 	request, err := parseSyncDrawRequest(c)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
-	data, err := lotteryService.SyncDrawHistory(c.Context(), c.Params("code"), lotteryService.SyncOptions{
+	data, err := lotteryService.SyncDrawHistory(c.UserContext(), c.Params("code"), lotteryService.SyncOptions{
 		Issue: request.Issue,
 		Start: request.Start,
 		Count: request.Count,
@@ -329,14 +354,16 @@ func SyncDrawHistory(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param request body SyncDrawRequest false "批量历史同步参数"
 // @Success 200 {object} BatchSyncResultResponse
+// @Failure 502 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Router /lotteries/draws/sync-history [post]
 func SyncMultipleDraws(c *fiber.Ctx) error {
 	request, err := parseSyncDrawRequest(c)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
-	data, err := lotteryService.SyncMultipleDraws(c.Context(), request.LotteryCodes, lotteryService.SyncOptions{
+	data, err := lotteryService.SyncMultipleDraws(c.UserContext(), request.LotteryCodes, lotteryService.SyncOptions{
 		Issue: request.Issue,
 		Start: request.Start,
 		Count: request.Count,
@@ -354,16 +381,17 @@ func SyncMultipleDraws(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq、dlt"
 // @Param limit query int false "返回数量，默认 20"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} TicketListResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/tickets [get]
 func ListTickets(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
-	items, err := lotteryService.ListTickets(c.Params("code"), limit, userID)
+	items, err := lotteryService.ListTickets(c.Params("code"), limit, owner)
 	if err != nil {
 		return err
 	}
@@ -376,16 +404,17 @@ func ListTickets(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "返回数量，默认 20"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} TicketListResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/tickets [get]
 func ListAllTickets(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
-	items, err := lotteryService.ListAllTickets(limit, userID)
+	items, err := lotteryService.ListAllTickets(limit, owner)
 	if err != nil {
 		return err
 	}
@@ -393,30 +422,72 @@ func ListAllTickets(c *fiber.Ctx) error {
 }
 
 // @Summary 分页获取历史票据
-// @Description 支持按彩种、中奖状态筛选，并按时间或金额排序，适合移动端历史列表动态加载
+// @Description 支持按彩种、状态、期号、购买和开奖日期、奖金区间、奖级、来源、是否关联推荐以及包含的号码筛选，并按时间或金额排序。
+// @Description 传入上一页返回的 nextCursor 即按游标翻页，翻页期间新增票据不会导致重复或遗漏；不传 cursor 时按 page 分页
 // @Tags lottery
 // @Produce json
 // @Security BearerAuth
-// @Param page query int false "页码，默认 1"
+// @Param page query int false "页码，默认 1，传 cursor 时忽略"
 // @Param pageSize query int false "每页数量，默认 10，最大 50"
+// @Param cursor query string false "上一页返回的 nextCursor"
 // @Param lotteryCode query string false "彩票编码，如 ssq、dlt"
 // @Param status query string false "状态，可选 pending、won、not_won"
+// @Param issue query string false "期号"
+// @Param purchasedFrom query string false "购买日期起，格式 2026-03-01"
+// @Param purchasedTo query string false "购买日期止（含当天），格式 2026-03-31"
+// @Param drawDateFrom query string false "开奖日期起，格式 2026-03-01"
+// @Param drawDateTo query string false "开奖日期止（含当天），格式 2026-03-31"
+// @Param minPrize query number false "最低奖金"
+// @Param maxPrize query number false "最高奖金"
+// @Param prizeName query string false "奖级，如 一等奖、六等奖"
+// @Param source query string false "来源，可选 ocr、manual、import"
+// @Param hasRecommendation query bool false "是否关联推荐"
+// @Param number query int false "包含的号码"
+// @Param numberZone query string false "号码区域，可选 red、blue，不传时两区都匹配"
 // @Param sort query string false "排序，可选 latest、oldest、prize_high、cost_high"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} TicketPageResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/tickets/history [get]
 func ListTicketHistory(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
+	if err != nil {
+		return err
+	}
+	minPrize, err := parseOptionalMoneyQuery(c, "minPrize")
+	if err != nil {
+		return err
+	}
+	maxPrize, err := parseOptionalMoneyQuery(c, "maxPrize")
+	if err != nil {
+		return err
+	}
+	hasRecommendation, err := parseOptionalBoolQuery(c, "hasRecommendation")
 	if err != nil {
 		return err
 	}
 	data, err := lotteryService.QueryAllTickets(lotteryService.TicketQueryOptions{
-		UserID:      userID,
-		Page:        parseIntValue(c.Query("page"), 1),
-		PageSize:    parseIntValue(c.Query("pageSize"), 10),
-		LotteryCode: c.Query("lotteryCode"),
-		Status:      c.Query("status"),
-		Sort:        c.Query("sort", "latest"),
+		UserID:            owner.UserID,
+		LedgerID:          owner.LedgerID,
+		Page:              parseIntValue(c.Query("page"), 1),
+		PageSize:          parseIntValue(c.Query("pageSize"), 10),
+		Cursor:            c.Query("cursor"),
+		LotteryCode:       c.Query("lotteryCode"),
+		Status:            c.Query("status"),
+		Issue:             c.Query("issue"),
+		PurchasedFrom:     c.Query("purchasedFrom"),
+		PurchasedTo:       c.Query("purchasedTo"),
+		DrawDateFrom:      c.Query("drawDateFrom"),
+		DrawDateTo:        c.Query("drawDateTo"),
+		MinPrize:          minPrize,
+		MaxPrize:          maxPrize,
+		PrizeName:         c.Query("prizeName"),
+		Source:            c.Query("source"),
+		HasRecommendation: hasRecommendation,
+		Number:            parseIntValue(c.Query("number"), 0),
+		NumberZone:        c.Query("numberZone"),
+		Sort:              c.Query("sort", "latest"),
 	})
 	if err != nil {
 		return err
@@ -432,15 +503,16 @@ func ListTicketHistory(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq、dlt"
 // @Param ticketId path string true "票据 ID"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} TicketDetailResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/tickets/{ticketId}/recheck [post]
 func RecheckTicket(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	data, err := lotteryService.RecheckTicket(c.Context(), c.Params("ticketId"), c.Params("code"), userID)
+	data, err := lotteryService.RecheckTicket(c.UserContext(), c.Params("ticketId"), c.Params("code"), owner)
 	if err != nil {
 		return err
 	}
@@ -454,15 +526,16 @@ func RecheckTicket(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param ticketId path string true "票据 ID"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} TicketDetailResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/tickets/{ticketId}/recheck [post]
 func RecheckGenericTicket(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	data, err := lotteryService.RecheckTicket(c.Context(), c.Params("ticketId"), "", userID)
+	data, err := lotteryService.RecheckTicket(c.UserContext(), c.Params("ticketId"), "", owner)
 	if err != nil {
 		return err
 	}
@@ -475,15 +548,16 @@ func RecheckGenericTicket(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param ticketId path string true "票据 ID"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} DeleteResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/tickets/{ticketId} [delete]
 func DeleteGenericTicket(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
-	if err := lotteryService.DeleteTicket(c.Params("ticketId"), userID); err != nil {
+	if err := lotteryService.DeleteTicket(c.Params("ticketId"), owner); err != nil {
 		return err
 	}
 	return response.Success(c, fiber.Map{"deleted": true})
@@ -564,7 +638,10 @@ func UploadGenericTicketImage(c *fiber.Ctx) error {
 // @Param code path string true "彩票编码，如 ssq"
 // @Param request body RecognizeTicketRequest true "识别参数"
 // @Success 200 {object} TicketRecognitionResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Router /lotteries/{code}/tickets/recognize [post]
 func RecognizeTicket(c *fiber.Ctx) error {
 	userID, err := currentUserID(c)
@@ -576,7 +653,7 @@ func RecognizeTicket(c *fiber.Ctx) error {
 		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
 	}
 
-	data, err := lotteryService.RecognizeUploadedTicket(c.Context(), lotteryService.RecognizeUploadedTicketInput{
+	data, err := lotteryService.RecognizeUploadedTicket(c.UserContext(), lotteryService.RecognizeUploadedTicketInput{
 		UserID:   userID,
 		Code:     c.Params("code"),
 		UploadID: request.UploadID,
@@ -596,7 +673,10 @@ func RecognizeTicket(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param request body RecognizeTicketRequest true "识别参数"
 // @Success 200 {object} TicketRecognitionResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Router /lotteries/tickets/recognize [post]
 func RecognizeGenericTicket(c *fiber.Ctx) error {
 	userID, err := currentUserID(c)
@@ -608,7 +688,7 @@ func RecognizeGenericTicket(c *fiber.Ctx) error {
 		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
 	}
 
-	data, err := lotteryService.RecognizeUploadedTicket(c.Context(), lotteryService.RecognizeUploadedTicketInput{
+	data, err := lotteryService.RecognizeUploadedTicket(c.UserContext(), lotteryService.RecognizeUploadedTicketInput{
 		UserID:   userID,
 		UploadID: request.UploadID,
 		OCRText:  request.OCRText,
@@ -627,11 +707,15 @@ func RecognizeGenericTicket(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param code path string true "彩票编码，如 ssq"
 // @Param request body CreateTicketRequest true "入库参数"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
+// @Param Idempotency-Key header string false "幂等键，保留期内相同键的重试直接返回首次成功的响应"
 // @Success 201 {object} TicketDetailResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/tickets [post]
 func CreateTicket(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
@@ -641,19 +725,20 @@ func CreateTicket(c *fiber.Ctx) error {
 	}
 	purchasedAt, err := parseOptionalTime(request.PurchasedAt)
 	if err != nil {
-		return response.Error(c, "购买时间格式不正确，应为 RFC3339", fiber.StatusBadRequest)
+		return errPurchasedAtInvalid
 	}
 	drawDate, err := parseOptionalDate(request.DrawDate)
 	if err != nil {
-		return response.Error(c, "开奖日期格式不正确，应为 YYYY-MM-DD", fiber.StatusBadRequest)
+		return errDrawDateInvalid
 	}
 	entries, err := parseCreateTicketEntries(request.Entries)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
 
-	data, err := lotteryService.CreateTicket(c.Context(), lotteryService.CreateTicketInput{
-		UserID:           userID,
+	data, err := lotteryService.CreateTicket(c.UserContext(), lotteryService.CreateTicketInput{
+		UserID:           owner.UserID,
+		LedgerID:         owner.LedgerID,
 		Code:             firstNonEmpty(c.Params("code"), request.LotteryCode),
 		UploadID:         request.UploadID,
 		RecommendationID: request.RecommendationID,
@@ -665,9 +750,6 @@ func CreateTicket(c *fiber.Ctx) error {
 		Entries:          entries,
 	})
 	if err != nil {
-		if errors.Is(err, lotteryService.ErrDuplicateTicket) {
-			return response.Error(c, err.Error(), fiber.StatusConflict)
-		}
 		return err
 	}
 	return response.Success(c, data, fiber.StatusCreated)
@@ -680,11 +762,15 @@ func CreateTicket(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param request body CreateTicketRequest true "入库参数"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
+// @Param Idempotency-Key header string false "幂等键，保留期内相同键的重试直接返回首次成功的响应"
 // @Success 201 {object} TicketDetailResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/tickets [post]
 func CreateGenericTicket(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
@@ -695,19 +781,20 @@ func CreateGenericTicket(c *fiber.Ctx) error {
 
 	purchasedAt, err := parseOptionalTime(request.PurchasedAt)
 	if err != nil {
-		return response.Error(c, "购买时间格式不正确，应为 RFC3339", fiber.StatusBadRequest)
+		return errPurchasedAtInvalid
 	}
 	drawDate, err := parseOptionalDate(request.DrawDate)
 	if err != nil {
-		return response.Error(c, "开奖日期格式不正确，应为 YYYY-MM-DD", fiber.StatusBadRequest)
+		return errDrawDateInvalid
 	}
 	entries, err := parseCreateTicketEntries(request.Entries)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
 
-	data, err := lotteryService.CreateTicket(c.Context(), lotteryService.CreateTicketInput{
-		UserID:           userID,
+	data, err := lotteryService.CreateTicket(c.UserContext(), lotteryService.CreateTicketInput{
+		UserID:           owner.UserID,
+		LedgerID:         owner.LedgerID,
 		Code:             request.LotteryCode,
 		UploadID:         request.UploadID,
 		RecommendationID: request.RecommendationID,
@@ -719,9 +806,6 @@ func CreateGenericTicket(c *fiber.Ctx) error {
 		Entries:          entries,
 	})
 	if err != nil {
-		if errors.Is(err, lotteryService.ErrDuplicateTicket) {
-			return response.Error(c, err.Error(), fiber.StatusConflict)
-		}
 		return err
 	}
 	return response.Success(c, data, fiber.StatusCreated)
@@ -736,11 +820,14 @@ func CreateGenericTicket(c *fiber.Ctx) error {
 // @Param code path string true "彩票编码，如 ssq"
 // @Param ticketId path string true "票据 ID"
 // @Param request body CreateTicketRequest true "更新参数"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} TicketDetailResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/{code}/tickets/{ticketId} [put]
 func UpdateTicket(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
@@ -749,15 +836,12 @@ func UpdateTicket(c *fiber.Ctx) error {
 		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
 	}
 
-	input, err := buildUpdateTicketInput(userID, c.Params("ticketId"), firstNonEmpty(c.Params("code"), request.LotteryCode), request)
+	input, err := buildUpdateTicketInput(owner, c.Params("ticketId"), firstNonEmpty(c.Params("code"), request.LotteryCode), request)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
-	data, err := lotteryService.UpdateTicket(c.Context(), input)
+	data, err := lotteryService.UpdateTicket(c.UserContext(), input)
 	if err != nil {
-		if errors.Is(err, lotteryService.ErrDuplicateTicket) {
-			return response.Error(c, err.Error(), fiber.StatusConflict)
-		}
 		return err
 	}
 	return response.Success(c, data)
@@ -771,11 +855,14 @@ func UpdateTicket(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param ticketId path string true "票据 ID"
 // @Param request body CreateTicketRequest true "更新参数"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 200 {object} TicketDetailResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/tickets/{ticketId} [put]
 func UpdateGenericTicket(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
@@ -784,15 +871,12 @@ func UpdateGenericTicket(c *fiber.Ctx) error {
 		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
 	}
 
-	input, err := buildUpdateTicketInput(userID, c.Params("ticketId"), request.LotteryCode, request)
+	input, err := buildUpdateTicketInput(owner, c.Params("ticketId"), request.LotteryCode, request)
 	if err != nil {
-		return response.Error(c, err.Error(), fiber.StatusBadRequest)
+		return err
 	}
-	data, err := lotteryService.UpdateTicket(c.Context(), input)
+	data, err := lotteryService.UpdateTicket(c.UserContext(), input)
 	if err != nil {
-		if errors.Is(err, lotteryService.ErrDuplicateTicket) {
-			return response.Error(c, err.Error(), fiber.StatusConflict)
-		}
 		return err
 	}
 	return response.Success(c, data)
@@ -810,12 +894,16 @@ func UpdateGenericTicket(c *fiber.Ctx) error {
 // @Param purchasedAt formData string false "购买时间，RFC3339 格式"
 // @Param ocrText formData string false "OCR 降级文本"
 // @Param notes formData string false "备注"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
 // @Success 201 {object} TicketDetailResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Router /lotteries/{code}/tickets/scan [post]
 func ScanTicket(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
@@ -826,10 +914,11 @@ func ScanTicket(c *fiber.Ctx) error {
 
 	purchasedAt, err := parseOptionalTime(c.FormValue("purchasedAt"))
 	if err != nil {
-		return response.Error(c, "购买时间格式不正确，应为 RFC3339", fiber.StatusBadRequest)
+		return errPurchasedAtInvalid
 	}
-	data, err := lotteryService.ScanTicket(c.Context(), lotteryService.ScanTicketInput{
-		UserID:      userID,
+	data, err := lotteryService.ScanTicket(c.UserContext(), lotteryService.ScanTicketInput{
+		UserID:      owner.UserID,
+		LedgerID:    owner.LedgerID,
 		Code:        c.Params("code"),
 		Issue:       c.FormValue("issue"),
 		ImagePath:   imagePath,
@@ -846,7 +935,7 @@ func ScanTicket(c *fiber.Ctx) error {
 func saveTicketImage(c *fiber.Ctx) (*multipart.FileHeader, string, error) {
 	file, err := c.FormFile("image")
 	if err != nil {
-		return nil, "", response.Error(c, "请上传彩票图片", fiber.StatusBadRequest)
+		return nil, "", errTicketImageRequired
 	}
 
 	imagePath := filepath.Join(
@@ -864,14 +953,14 @@ func saveTicketImage(c *fiber.Ctx) (*multipart.FileHeader, string, error) {
 	return file, imagePath, nil
 }
 
-func buildUpdateTicketInput(userID string, ticketID string, code string, request CreateTicketRequest) (lotteryService.UpdateTicketInput, error) {
+func buildUpdateTicketInput(owner lotteryService.Owner, ticketID string, code string, request CreateTicketRequest) (lotteryService.UpdateTicketInput, error) {
 	purchasedAt, err := parseOptionalTime(request.PurchasedAt)
 	if err != nil {
-		return lotteryService.UpdateTicketInput{}, fmt.Errorf("购买时间格式不正确，应为 RFC3339")
+		return lotteryService.UpdateTicketInput{}, errPurchasedAtInvalid
 	}
 	drawDate, err := parseOptionalDate(request.DrawDate)
 	if err != nil {
-		return lotteryService.UpdateTicketInput{}, fmt.Errorf("开奖日期格式不正确，应为 YYYY-MM-DD")
+		return lotteryService.UpdateTicketInput{}, errDrawDateInvalid
 	}
 	entries, err := parseCreateTicketEntries(request.Entries)
 	if err != nil {
@@ -879,7 +968,8 @@ func buildUpdateTicketInput(userID string, ticketID string, code string, request
 	}
 
 	return lotteryService.UpdateTicketInput{
-		UserID:           userID,
+		UserID:           owner.UserID,
+		LedgerID:         owner.LedgerID,
 		TicketID:         ticketID,
 		Code:             code,
 		RecommendationID: request.RecommendationID,
@@ -900,7 +990,7 @@ func parseCreateTicketEntries(items []CreateTicketEntryRequest) ([]lotteryServic
 	result := make([]lotteryService.ParsedEntry, 0, len(items))
 	for _, item := range items {
 		if item.RedNumbers == "" || item.BlueNumbers == "" {
-			return nil, fmt.Errorf("每注号码都需要包含 redNumbers 和 blueNumbers")
+			return nil, errTicketEntryFormat
 		}
 		result = append(result, lotteryService.ParsedEntry{
 			Red:          parseCSVValues(item.RedNumbers),
@@ -954,10 +1044,10 @@ func parseSyncDrawRequest(c *fiber.Ctx) (SyncDrawRequest, error) {
 				request.LotteryCodes = parsed.LotteryCodes
 			}
 		} else if err := c.BodyParser(&request); err != nil {
-			return request, fmt.Errorf("请求体不是合法 JSON，请检查逗号和引号格式")
+			return request, errRequestBodyInvalid
 		}
 	} else if err := c.BodyParser(&request); err != nil {
-		return request, fmt.Errorf("请求参数解析失败")
+		return request, apperr.ErrInvalidRequest.WithMessage("请求参数解析失败")
 	}
 
 	if request.Count <= 0 {
@@ -969,6 +1059,30 @@ func parseSyncDrawRequest(c *fiber.Ctx) (SyncDrawRequest, error) {
 	return request, nil
 }
 
+func parseOptionalMoneyQuery(c *fiber.Ctx, key string) (*lotteryModel.Money, error) {
+	value := strings.TrimSpace(c.Query(key))
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := lotteryModel.ParseMoney(value)
+	if err != nil {
+		return nil, lotteryService.ErrInvalidQueryFilter.WithMessage("%s 必须是数字", key)
+	}
+	return &parsed, nil
+}
+
+func parseOptionalBoolQuery(c *fiber.Ctx, key string) (*bool, error) {
+	value := strings.TrimSpace(c.Query(key))
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, lotteryService.ErrInvalidQueryFilter.WithMessage("%s 只能是 true 或 false", key)
+	}
+	return &parsed, nil
+}
+
 func parseIntValue(value string, fallback int) int {
 	if value == "" {
 		return fallback
@@ -18,19 +18,23 @@ import (
 // @Security BearerAuth
 // @Param workbook formData file true "Excel 文件，支持 xlsx"
 // @Param imagesZip formData file false "图片压缩包，Excel 中 imageName 列会按文件名匹配"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
+// @Param Idempotency-Key header string false "幂等键，保留期内相同键的重试直接返回首次成功的响应"
 // @Success 200 {object} TicketImportResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /lotteries/tickets/import [post]
 func ImportTickets(c *fiber.Ctx) error {
-	userID, err := currentUserID(c)
+	owner, err := currentOwner(c)
 	if err != nil {
 		return err
 	}
 
 	workbookFile, err := c.FormFile("workbook")
 	if err != nil {
-		return response.Error(c, "请上传 Excel 文件", fiber.StatusBadRequest)
+		return lotteryService.ErrImportFileInvalid
 	}
 
 	workbookData, err := readUploadedFile(workbookFile)
@@ -46,8 +50,9 @@ func ImportTickets(c *fiber.Ctx) error {
 		}
 	}
 
-	data, err := lotteryService.ImportTickets(c.Context(), lotteryService.ImportTicketsInput{
-		UserID:        userID,
+	data, err := lotteryService.ImportTickets(c.UserContext(), lotteryService.ImportTicketsInput{
+		UserID:        owner.UserID,
+		LedgerID:      owner.LedgerID,
 		Workbook:      workbookData,
 		ImagesArchive: imagesArchive,
 	})
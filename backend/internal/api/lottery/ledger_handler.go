@@ -0,0 +1,232 @@
+package lottery
+
+import (
+	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/internal/middleware"
+	lotteryService "go-fiber-starter/internal/service/lottery"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LedgerRequest struct {
+	Name string `json:"name"`
+}
+
+type LedgerMemberRequest struct {
+	Username string `json:"username"`
+	Role     string `json:"role" example:"editor"`
+}
+
+type LedgerMemberRoleRequest struct {
+	Role string `json:"role" example:"viewer"`
+}
+
+// @Summary 获取我的账本
+// @Description 返回当前用户参与的共享账本及其角色
+// @Tags ledger
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} LedgerListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /ledgers [get]
+func ListLedgers(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	items, err := lotteryService.ListLedgers(userID)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, items)
+}
+
+// @Summary 创建账本
+// @Description 创建共享账本，创建者成为账本所有者
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body LedgerRequest true "账本名称"
+// @Success 201 {object} LedgerResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /ledgers [post]
+func CreateLedger(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	request := LedgerRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	data, err := lotteryService.CreateLedger(userID, request.Name)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data, fiber.StatusCreated)
+}
+
+// @Summary 重命名账本
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ledgerId path string true "账本 ID"
+// @Param request body LedgerRequest true "账本名称"
+// @Success 200 {object} LedgerResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /ledgers/{ledgerId} [put]
+func RenameLedger(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	request := LedgerRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	data, err := lotteryService.RenameLedger(userID, c.Params("ledgerId"), request.Name)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 删除账本
+// @Description 删除账本及其中的全部票据和推荐，只有所有者可以操作
+// @Tags ledger
+// @Produce json
+// @Security BearerAuth
+// @Param ledgerId path string true "账本 ID"
+// @Success 200 {object} DeleteResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /ledgers/{ledgerId} [delete]
+func DeleteLedger(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	if err := lotteryService.DeleteLedger(userID, c.Params("ledgerId")); err != nil {
+		return err
+	}
+	return response.Success(c, fiber.Map{"deleted": 1})
+}
+
+// @Summary 获取账本成员
+// @Tags ledger
+// @Produce json
+// @Security BearerAuth
+// @Param ledgerId path string true "账本 ID"
+// @Success 200 {object} LedgerMemberListResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /ledgers/{ledgerId}/members [get]
+func ListLedgerMembers(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	items, err := lotteryService.ListLedgerMembers(userID, c.Params("ledgerId"))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, items)
+}
+
+// @Summary 邀请账本成员
+// @Description 按用户名添加成员，角色可选 editor（可录票、生成推荐）或 viewer（只读），默认 viewer
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ledgerId path string true "账本 ID"
+// @Param request body LedgerMemberRequest true "成员信息"
+// @Success 201 {object} LedgerMemberResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /ledgers/{ledgerId}/members [post]
+func AddLedgerMember(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	request := LedgerMemberRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	data, err := lotteryService.AddLedgerMember(userID, c.Params("ledgerId"), request.Username, request.Role)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data, fiber.StatusCreated)
+}
+
+// @Summary 修改成员角色
+// @Tags ledger
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ledgerId path string true "账本 ID"
+// @Param userId path string true "成员用户 ID"
+// @Param request body LedgerMemberRoleRequest true "新角色"
+// @Success 200 {object} DeleteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /ledgers/{ledgerId}/members/{userId} [put]
+func UpdateLedgerMember(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	request := LedgerMemberRoleRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+	if err := lotteryService.UpdateLedgerMemberRole(userID, c.Params("ledgerId"), c.Params("userId"), request.Role); err != nil {
+		return err
+	}
+	return response.Success(c, fiber.Map{"updated": 1})
+}
+
+// @Summary 移除账本成员
+// @Description 所有者可以移除其他成员，成员可以移除自己以退出账本；成员录入的数据仍保留在账本中
+// @Tags ledger
+// @Produce json
+// @Security BearerAuth
+// @Param ledgerId path string true "账本 ID"
+// @Param userId path string true "成员用户 ID"
+// @Success 200 {object} DeleteResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /ledgers/{ledgerId}/members/{userId} [delete]
+func RemoveLedgerMember(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	if err := lotteryService.RemoveLedgerMember(userID, c.Params("ledgerId"), c.Params("userId")); err != nil {
+		return err
+	}
+	return response.Success(c, fiber.Map{"deleted": 1})
+}
+
+// currentOwner 按 ledgerId 查询参数或 X-Ledger-Id 请求头确定数据归属并校验成员角色，
+// 非 GET 请求需要 owner 或 editor 角色。
+func currentOwner(c *fiber.Ctx) (lotteryService.Owner, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return lotteryService.Owner{}, err
+	}
+	owner, err := lotteryService.ResolveOwner(userID, middleware.LedgerSelector(c), c.Method() != fiber.MethodGet)
+	if err != nil {
+		return lotteryService.Owner{}, err
+	}
+	return owner, nil
+}
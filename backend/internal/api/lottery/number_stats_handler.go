@@ -0,0 +1,53 @@
+package lottery
+
+import (
+	"go-fiber-starter/internal/api/response"
+	lotteryService "go-fiber-starter/internal/service/lottery"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary 获取开奖号码频次
+// @Description 统计指定彩种最近若干期开奖中每个号码的出现次数，红球（前区）和蓝球（后区）分别列出号码范围内的全部号码
+// @Tags lottery
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "彩票编码，如 ssq、dlt"
+// @Param limit query int false "统计最近的期数，默认 30，最大 500"
+// @Success 200 {object} NumberFrequencyResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /lotteries/{code}/draws/frequency [get]
+func GetDrawNumberFrequency(c *fiber.Ctx) error {
+	if _, err := currentUserID(c); err != nil {
+		return err
+	}
+	data, err := lotteryService.GetDrawNumberFrequency(c.Params("code"), parseIntValue(c.Query("limit"), 0))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 获取投注号码频次
+// @Description 统计当前账户或共享账本已录入的指定彩种投注中，每个号码被选中的注数
+// @Tags lottery
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "彩票编码，如 ssq、dlt"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
+// @Success 200 {object} NumberFrequencyResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /lotteries/{code}/tickets/frequency [get]
+func GetTicketNumberFrequency(c *fiber.Ctx) error {
+	owner, err := currentOwner(c)
+	if err != nil {
+		return err
+	}
+	data, err := lotteryService.GetTicketNumberFrequency(c.Params("code"), owner)
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data)
+}
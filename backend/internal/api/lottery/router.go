@@ -1,37 +1,65 @@
 package lottery
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"go-fiber-starter/internal/middleware"
+	userModel "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
 
 func RegisterRoutes(router fiber.Router) {
+	adminOnly := middleware.RequireRole(userModel.RoleAdmin)
+	ocrLimit := middleware.RateLimit(service.RateLimitOCR)
+	aiLimit := middleware.RateLimit(service.RateLimitAI)
+	syncLimit := middleware.RateLimit(service.RateLimitSync)
+
 	group := router.Group("/lotteries")
 	group.Get("/", ListLotteries)
 	group.Get("/dashboard", GetGlobalDashboard)
 	group.Get("/draws/history", ListDrawHistory)
 	group.Get("/recommendations", ListAllRecommendations)
-	group.Post("/draws/sync-history", SyncMultipleDraws)
+	group.Get("/syndicates", ListSyndicateShares)
+	group.Post("/draws/sync-history", adminOnly, syncLimit, SyncMultipleDraws)
 	group.Get("/tickets/history", ListTicketHistory)
 	group.Get("/tickets", ListAllTickets)
-	group.Post("/tickets/import", ImportTickets)
+	group.Post("/tickets/import", middleware.Idempotency, ImportTickets)
+	group.Post("/tickets/bulk/delete", BulkDeleteTickets)
+	group.Post("/tickets/bulk/recheck", syncLimit, BulkRecheckTickets)
+	group.Post("/tickets/bulk/link", BulkLinkTickets)
 	group.Put("/tickets/:ticketId", UpdateGenericTicket)
 	group.Delete("/tickets/:ticketId", DeleteGenericTicket)
 	group.Post("/tickets/:ticketId/recheck", RecheckGenericTicket)
+	group.Put("/tickets/:ticketId/shares", SetTicketShares)
 	group.Post("/tickets/upload-image", UploadGenericTicketImage)
-	group.Post("/tickets/recognize", RecognizeGenericTicket)
-	group.Post("/tickets", CreateGenericTicket)
+	group.Post("/tickets/recognize", ocrLimit, RecognizeGenericTicket)
+	group.Post("/tickets", middleware.Idempotency, CreateGenericTicket)
 	group.Get("/:code/dashboard", GetDashboard)
 	group.Get("/:code/recommendations", ListRecommendations)
 	group.Get("/:code/recommendations/latest", GetLatestRecommendation)
 	group.Get("/:code/recommendations/:recommendationId", GetRecommendationDetail)
 	group.Delete("/:code/recommendations/:recommendationId", DeleteRecommendation)
 	group.Post("/:code/recommendations/:recommendationId/recheck", RecheckRecommendation)
-	group.Post("/:code/recommendations/generate", GenerateRecommendation)
-	group.Post("/:code/draws/sync", SyncDraws)
-	group.Post("/:code/draws/sync-history", SyncDrawHistory)
+	group.Post("/:code/recommendations/generate", aiLimit, GenerateRecommendation)
+	group.Get("/:code/draws/frequency", GetDrawNumberFrequency)
+	group.Post("/:code/draws/sync", adminOnly, syncLimit, SyncDraws)
+	group.Post("/:code/draws/sync-history", adminOnly, syncLimit, SyncDrawHistory)
 	group.Get("/:code/tickets", ListTickets)
+	group.Get("/:code/tickets/frequency", GetTicketNumberFrequency)
 	group.Put("/:code/tickets/:ticketId", UpdateTicket)
 	group.Post("/:code/tickets/:ticketId/recheck", RecheckTicket)
 	group.Post("/:code/tickets/upload-image", UploadTicketImage)
-	group.Post("/:code/tickets/recognize", RecognizeTicket)
-	group.Post("/:code/tickets", CreateTicket)
-	group.Post("/:code/tickets/scan", ScanTicket)
+	group.Post("/:code/tickets/recognize", ocrLimit, RecognizeTicket)
+	group.Post("/:code/tickets", middleware.Idempotency, CreateTicket)
+	group.Post("/:code/tickets/scan", ocrLimit, ScanTicket)
+
+	ledgers := router.Group("/ledgers")
+	ledgers.Get("/", ListLedgers)
+	ledgers.Post("/", CreateLedger)
+	ledgers.Put("/:ledgerId", RenameLedger)
+	ledgers.Delete("/:ledgerId", DeleteLedger)
+	ledgers.Get("/:ledgerId/members", ListLedgerMembers)
+	ledgers.Post("/:ledgerId/members", AddLedgerMember)
+	ledgers.Put("/:ledgerId/members/:userId", UpdateLedgerMember)
+	ledgers.Delete("/:ledgerId/members/:userId", RemoveLedgerMember)
 }
@@ -6,10 +6,13 @@ import (
 )
 
 type ErrorResponse struct {
-	Flag bool   `json:"flag" example:"false"`
-	Code int    `json:"code" example:"500"`
-	Msg  string `json:"msg" example:"请求失败"`
-	Time string `json:"time" example:"2026-03-16T10:00:00Z"`
+	Flag       bool   `json:"flag" example:"false"`
+	Code       int    `json:"code" example:"500"`
+	Msg        string `json:"msg" example:"请求失败"`
+	ErrorCode  string `json:"errorCode" example:"INTERNAL_ERROR"`
+	RetryAfter int    `json:"retryAfter,omitempty" example:"60"`
+	RequestID  string `json:"requestId" example:"0f8fad5b-d9cb-469f-a165-70867728950e"`
+	Time       string `json:"time" example:"2026-03-16T10:00:00Z"`
 }
 
 type LotteryListResponse struct {
@@ -75,6 +78,13 @@ type DrawPageResponse struct {
 	Time string                        `json:"time" example:"2026-03-16T10:00:00Z"`
 }
 
+type NumberFrequencyResponse struct {
+	Flag bool                                 `json:"flag" example:"true"`
+	Code int                                  `json:"code" example:"200"`
+	Data lotteryService.NumberFrequencyResult `json:"data"`
+	Time string                               `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
 type TicketListResponse struct {
 	Flag bool                          `json:"flag" example:"true"`
 	Code int                           `json:"code" example:"200"`
@@ -123,3 +133,45 @@ type DeleteResponse struct {
 	Data map[string]any `json:"data"`
 	Time string         `json:"time" example:"2026-03-16T10:00:00Z"`
 }
+
+type LedgerResponse struct {
+	Flag bool                         `json:"flag" example:"true"`
+	Code int                          `json:"code" example:"200"`
+	Data lotteryService.LedgerSummary `json:"data"`
+	Time string                       `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type LedgerListResponse struct {
+	Flag bool                           `json:"flag" example:"true"`
+	Code int                            `json:"code" example:"200"`
+	Data []lotteryService.LedgerSummary `json:"data"`
+	Time string                         `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type LedgerMemberResponse struct {
+	Flag bool                              `json:"flag" example:"true"`
+	Code int                               `json:"code" example:"201"`
+	Data lotteryService.LedgerMemberDetail `json:"data"`
+	Time string                            `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type LedgerMemberListResponse struct {
+	Flag bool                                `json:"flag" example:"true"`
+	Code int                                 `json:"code" example:"200"`
+	Data []lotteryService.LedgerMemberDetail `json:"data"`
+	Time string                              `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type SyndicateShareListResponse struct {
+	Flag bool                                  `json:"flag" example:"true"`
+	Code int                                   `json:"code" example:"200"`
+	Data []lotteryService.SyndicateShareDetail `json:"data"`
+	Time string                                `json:"time" example:"2026-03-16T10:00:00Z"`
+}
+
+type BulkTicketResponse struct {
+	Flag bool                            `json:"flag" example:"true"`
+	Code int                             `json:"code" example:"200"`
+	Data lotteryService.BulkTicketResult `json:"data"`
+	Time string                          `json:"time" example:"2026-03-16T10:00:00Z"`
+}
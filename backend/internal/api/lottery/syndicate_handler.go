@@ -0,0 +1,68 @@
+package lottery
+
+import (
+	"go-fiber-starter/internal/api/response"
+	lotteryService "go-fiber-starter/internal/service/lottery"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TicketSharesRequest struct {
+	Shares []lotteryService.TicketShareInput `json:"shares"`
+}
+
+// @Summary 设置合买参与人
+// @Description 覆盖票据的合买参与人和出资份额，成本和奖金按份额比例分摊；传入空列表时取消合买
+// @Tags lottery
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param ticketId path string true "票据 ID"
+// @Param request body TicketSharesRequest true "合买参与人"
+// @Param ledgerId query string false "共享账本 ID，不传时为个人数据"
+// @Success 200 {object} TicketDetailResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /lotteries/tickets/{ticketId}/shares [put]
+func SetTicketShares(c *fiber.Ctx) error {
+	owner, err := currentOwner(c)
+	if err != nil {
+		return err
+	}
+	request := TicketSharesRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return response.Error(c, "参数不正确", fiber.StatusBadRequest)
+	}
+
+	data, err := lotteryService.SetTicketShares(lotteryService.SetTicketSharesInput{
+		UserID:   owner.UserID,
+		LedgerID: owner.LedgerID,
+		TicketID: c.Params("ticketId"),
+		Shares:   request.Shares,
+	})
+	if err != nil {
+		return err
+	}
+	return response.Success(c, data)
+}
+
+// @Summary 获取我参与的合买
+// @Description 返回当前用户作为参与人的合买记录，包括他人或共享账本录入的票据，以及本人分摊的成本和奖金
+// @Tags lottery
+// @Produce json
+// @Security BearerAuth
+// @Param code query string false "彩票编码，如 ssq"
+// @Success 200 {object} SyndicateShareListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /lotteries/syndicates [get]
+func ListSyndicateShares(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+	items, err := lotteryService.ListSyndicateShares(userID, c.Query("code"))
+	if err != nil {
+		return err
+	}
+	return response.Success(c, items)
+}
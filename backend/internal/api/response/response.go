@@ -1,17 +1,25 @@
 package response
 
 import (
+	"math"
+	"strconv"
 	"time"
 
+	"go-fiber-starter/internal/i18n"
+	"go-fiber-starter/internal/service/apperr"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 type Response struct {
-	Flag bool        `json:"flag"`
-	Code int         `json:"code"`
-	Data interface{} `json:"data,omitempty"`
-	Msg  string      `json:"msg,omitempty"`
-	Time string      `json:"time"`
+	Flag       bool        `json:"flag"`
+	Code       int         `json:"code"`
+	Data       interface{} `json:"data,omitempty"`
+	Msg        string      `json:"msg,omitempty"`
+	ErrorCode  string      `json:"errorCode,omitempty"`
+	RetryAfter int         `json:"retryAfter,omitempty"`
+	RequestID  string      `json:"requestId,omitempty"`
+	Time       string      `json:"time"`
 }
 
 func Success(c *fiber.Ctx, data interface{}, code ...int) error {
@@ -21,22 +29,65 @@ func Success(c *fiber.Ctx, data interface{}, code ...int) error {
 	}
 
 	return c.Status(statusCode).JSON(Response{
-		Flag: true,
-		Code: statusCode,
-		Data: data,
-		Time: time.Now().UTC().Format(time.RFC3339Nano),
+		Flag:      true,
+		Code:      statusCode,
+		Data:      data,
+		RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
 	})
 }
 
+// Error 返回失败响应，非默认语言下按状态码对应的通用错误码给出译文。
 func Error(c *fiber.Ctx, msg string, code ...int) error {
 	statusCode := fiber.StatusInternalServerError
 	if len(code) > 0 {
 		statusCode = code[0]
 	}
+	errorCode := apperr.CodeForStatus(statusCode)
+	return c.Status(statusCode).JSON(Response{
+		Flag:      false,
+		Code:      statusCode,
+		Msg:       localize(c, errorCode, msg),
+		ErrorCode: errorCode,
+		RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// Fail 按服务层类型化错误返回对应的状态码和错误码，未分类的错误按 500 处理。
+func Fail(c *fiber.Ctx, err error) error {
+	appErr := apperr.From(err)
+	if appErr == nil {
+		return Error(c, err.Error(), fiber.StatusInternalServerError)
+	}
+	statusCode := apperr.Status(appErr.Kind)
 	return c.Status(statusCode).JSON(Response{
-		Flag: false,
-		Code: statusCode,
-		Msg:  msg,
-		Time: time.Now().UTC().Format(time.RFC3339Nano),
+		Flag:      false,
+		Code:      statusCode,
+		Msg:       localize(c, appErr.Code, err.Error()),
+		ErrorCode: appErr.Code,
+		RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
 	})
 }
+
+// TooManyRequests 返回 429，并通过 Retry-After 响应头和 retryAfter 字段告知客户端等待时长。
+func TooManyRequests(c *fiber.Ctx, msg string, wait time.Duration) error {
+	retryAfter := max(1, int(math.Ceil(wait.Seconds())))
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+	return c.Status(fiber.StatusTooManyRequests).JSON(Response{
+		Flag:       false,
+		Code:       fiber.StatusTooManyRequests,
+		Msg:        localize(c, apperr.CodeTooManyRequests, msg),
+		ErrorCode:  apperr.CodeTooManyRequests,
+		RetryAfter: retryAfter,
+		RequestID:  c.GetRespHeader(fiber.HeaderXRequestID),
+		Time:       time.Now().UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// localize 按请求语言翻译失败消息，并通过 Content-Language 告知客户端实际使用的语言。
+func localize(c *fiber.Ctx, errorCode string, msg string) string {
+	c.Set(fiber.HeaderContentLanguage, i18n.Language(c))
+	return i18n.Localize(c, errorCode, msg)
+}
@@ -0,0 +1,119 @@
+// Package i18n 维护按错误码索引的多语言消息目录，并根据用户偏好或 Accept-Language 选择响应语言。
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	LangZhCN = "zh-CN"
+	LangEN   = "en"
+
+	// DefaultLanguage 是未指定语言时使用的语言，服务层错误自带的中文描述会原样返回。
+	DefaultLanguage = LangZhCN
+)
+
+// preferenceLocalKey 缓存当前用户在个人资料中设置的语言，优先级高于 Accept-Language。
+const preferenceLocalKey = "languagePreference"
+
+// SupportedLanguages 列出消息目录支持的语言。
+var SupportedLanguages = []string{LangZhCN, LangEN}
+
+// Normalize 把 zh、zh-Hans-CN、en-US 等语言标签归一为支持的语言，不支持时返回空字符串。
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+	primary, _, _ := strings.Cut(tag, "-")
+	switch primary {
+	case "zh":
+		return LangZhCN
+	case "en":
+		return LangEN
+	default:
+		return ""
+	}
+}
+
+// ParseAcceptLanguage 按 q 权重从 Accept-Language 请求头中选出第一个支持的语言。
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+		index   int
+	}
+	candidates := make([]candidate, 0)
+	for index, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang := Normalize(tag)
+		if lang == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{lang: lang, quality: quality, index: index})
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].lang
+}
+
+// SetPreference 记录当前用户设置的语言，未设置时不覆盖请求头。
+func SetPreference(c *fiber.Ctx, lang string) {
+	if lang = Normalize(lang); lang != "" {
+		c.Locals(preferenceLocalKey, lang)
+	}
+}
+
+// Language 返回本次请求的响应语言：用户偏好优先，其次是 Accept-Language，最后回退到默认语言。
+func Language(c *fiber.Ctx) string {
+	if lang, ok := c.Locals(preferenceLocalKey).(string); ok && lang != "" {
+		return lang
+	}
+	if lang := ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage)); lang != "" {
+		return lang
+	}
+	return DefaultLanguage
+}
+
+// Message 返回错误码在指定语言下的消息。
+func Message(lang string, code string) (string, bool) {
+	entry, ok := catalog[code]
+	if !ok {
+		return "", false
+	}
+	message, ok := entry[Normalize(lang)]
+	return message, ok && message != ""
+}
+
+// Localize 按请求语言返回错误码对应的消息。默认语言下优先使用服务层给出的具体描述，
+// 其他语言使用目录中的译文，目录缺失时回退到原始描述。
+func Localize(c *fiber.Ctx, code string, fallback string) string {
+	lang := Language(c)
+	if lang == DefaultLanguage && fallback != "" {
+		return fallback
+	}
+	if message, ok := Message(lang, code); ok {
+		return message
+	}
+	return fallback
+}
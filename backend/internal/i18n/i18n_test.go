@@ -0,0 +1,121 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	cases := map[string]string{
+		"":                          "",
+		"en-US,en;q=0.9":            LangEN,
+		"zh-Hans-CN":                LangZhCN,
+		"fr-FR, en;q=0.3, zh;q=0.7": LangZhCN,
+		"en;q=0, zh-TW;q=0.1":       LangZhCN,
+		"de, fr":                    "",
+	}
+	for header, want := range cases {
+		if got := ParseAcceptLanguage(header); got != want {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCatalogCoversSupportedLanguages(t *testing.T) {
+	for code, entry := range catalog {
+		for _, lang := range SupportedLanguages {
+			if entry[lang] == "" {
+				t.Errorf("%s is missing %s message", code, lang)
+			}
+		}
+	}
+	if message, ok := Message("en-GB", "TICKET_DUPLICATE"); !ok || message == "" {
+		t.Errorf("expected regional tag to resolve to en message")
+	}
+	for code, position := range declaredErrorCodes(t) {
+		if _, ok := catalog[code]; !ok {
+			t.Errorf("%s declared at %s is missing from catalog", code, position)
+		}
+	}
+}
+
+// errorCodePattern 匹配错误码字面量，用于排除同样以 Code 开头命名的其他常量。
+var errorCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]+$`)
+
+// apperrCodeArg 记录 apperr 各构造函数中错误码参数的位置。
+var apperrCodeArg = map[string]int{"New": 1, "Invalid": 0, "Unprocessable": 0, "NotFound": 0, "Upstream": 0}
+
+// declaredErrorCodes 扫描 internal 和 pkg 下的源码，收集 apperr 构造函数中的错误码字面量
+// 和以 Code 开头的错误码常量，返回错误码到声明位置的映射。
+func declaredErrorCodes(t *testing.T) map[string]string {
+	t.Helper()
+
+	codes := map[string]string{}
+	fileSet := token.NewFileSet()
+	for _, root := range []string{"../../internal", "../../pkg"} {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			file, err := parser.ParseFile(fileSet, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			collectErrorCodes(file, fileSet, codes)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("scan %s: %v", root, err)
+		}
+	}
+	if len(codes) == 0 {
+		t.Fatalf("expected to find declared error codes")
+	}
+	return codes
+}
+
+func collectErrorCodes(file *ast.File, fileSet *token.FileSet, codes map[string]string) {
+	add := func(expr ast.Expr) {
+		literal, ok := expr.(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			return
+		}
+		value, err := strconv.Unquote(literal.Value)
+		if err == nil && errorCodePattern.MatchString(value) {
+			codes[value] = fileSet.Position(literal.Pos()).String()
+		}
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			name := ""
+			switch fun := node.Fun.(type) {
+			case *ast.SelectorExpr:
+				if pkg, ok := fun.X.(*ast.Ident); ok && pkg.Name == "apperr" {
+					name = fun.Sel.Name
+				}
+			case *ast.Ident:
+				if file.Name.Name == "apperr" {
+					name = fun.Name
+				}
+			}
+			if index, ok := apperrCodeArg[name]; ok && index < len(node.Args) {
+				add(node.Args[index])
+			}
+		case *ast.ValueSpec:
+			for index, ident := range node.Names {
+				if strings.HasPrefix(ident.Name, "Code") && index < len(node.Values) {
+					add(node.Values[index])
+				}
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,95 @@
+package i18n
+
+// catalog 按错误码索引各语言的消息。新增错误码时需同时补充 zh-CN 和 en 译文。
+var catalog = map[string]map[string]string{
+	// 通用错误码
+	"INVALID_REQUEST":   {LangZhCN: "参数不正确", LangEN: "Invalid request parameters"},
+	"UNAUTHORIZED":      {LangZhCN: "认证失败，请先登录", LangEN: "Authentication failed, please sign in"},
+	"FORBIDDEN":         {LangZhCN: "没有权限执行该操作", LangEN: "You do not have permission to perform this action"},
+	"NOT_FOUND":         {LangZhCN: "记录不存在", LangEN: "Resource not found"},
+	"CONFLICT":          {LangZhCN: "数据冲突，请刷新后重试", LangEN: "The request conflicts with existing data"},
+	"UNPROCESSABLE":     {LangZhCN: "请求内容无法处理", LangEN: "The request could not be processed"},
+	"TOO_MANY_REQUESTS": {LangZhCN: "请求过于频繁，请稍后重试", LangEN: "Too many requests, please try again later"},
+	"UPSTREAM_ERROR":    {LangZhCN: "外部服务调用失败", LangEN: "An upstream service request failed"},
+	"INTERNAL_ERROR":    {LangZhCN: "服务器内部错误", LangEN: "Internal server error"},
+	"SERVICE_NOT_READY": {LangZhCN: "服务未就绪", LangEN: "The service is not ready"},
+
+	// 请求校验
+	"REQUEST_BODY_INVALID":        {LangZhCN: "请求体不是合法 JSON，请检查逗号和引号格式", LangEN: "The request body is not valid JSON"},
+	"PURCHASED_AT_INVALID":        {LangZhCN: "购买时间格式不正确，应为 RFC3339", LangEN: "Invalid purchase time, expected RFC3339"},
+	"DRAW_DATE_INVALID":           {LangZhCN: "开奖日期格式不正确，应为 YYYY-MM-DD", LangEN: "Invalid draw date, expected YYYY-MM-DD"},
+	"TICKET_ENTRY_FORMAT_INVALID": {LangZhCN: "每注号码都需要包含 redNumbers 和 blueNumbers", LangEN: "Each entry must include redNumbers and blueNumbers"},
+	"TICKET_IMAGE_REQUIRED":       {LangZhCN: "请上传彩票图片", LangEN: "Please upload a ticket image"},
+	"LANGUAGE_UNSUPPORTED":        {LangZhCN: "不支持的语言，可选 zh-CN 或 en", LangEN: "Unsupported language, use zh-CN or en"},
+	"CURSOR_INVALID":              {LangZhCN: "分页游标无效，请从第一页重新加载", LangEN: "Invalid pagination cursor, reload from the first page"},
+	"QUERY_FILTER_INVALID":        {LangZhCN: "筛选条件不正确", LangEN: "Invalid query filter"},
+	"IDEMPOTENCY_KEY_INVALID":     {LangZhCN: "Idempotency-Key 不能超过 128 个字符", LangEN: "Idempotency-Key must not exceed 128 characters"},
+	"IDEMPOTENCY_KEY_REUSED":      {LangZhCN: "该 Idempotency-Key 已用于内容不同的请求，请更换新的键", LangEN: "This Idempotency-Key was already used with a different request"},
+	"IDEMPOTENCY_IN_PROGRESS":     {LangZhCN: "相同 Idempotency-Key 的请求正在处理中，请稍后重试", LangEN: "A request with this Idempotency-Key is still in progress"},
+
+	// 账号与认证
+	"INVALID_CREDENTIALS":        {LangZhCN: "用户名或密码不正确", LangEN: "Incorrect username or password"},
+	"USER_DISABLED":              {LangZhCN: "账号已被禁用", LangEN: "This account has been disabled"},
+	"USER_NOT_FOUND":             {LangZhCN: "用户不存在", LangEN: "User not found"},
+	"USERNAME_REQUIRED":          {LangZhCN: "用户名不能为空", LangEN: "Username is required"},
+	"USERNAME_TAKEN":             {LangZhCN: "用户名已存在", LangEN: "Username is already taken"},
+	"PASSWORD_INCORRECT":         {LangZhCN: "当前密码不正确", LangEN: "The current password is incorrect"},
+	"PASSWORD_INVALID":           {LangZhCN: "密码不符合要求", LangEN: "The password does not meet the requirements"},
+	"REAUTH_REQUIRED":            {LangZhCN: "账号未设置密码，请先重新通过单点登录验证身份", LangEN: "This account has no password, please sign in again with single sign-on first"},
+	"REGISTRATION_CLOSED":        {LangZhCN: "当前未开放注册", LangEN: "Registration is currently closed"},
+	"INVITE_CODE_REQUIRED":       {LangZhCN: "当前仅支持邀请码注册，请填写邀请码", LangEN: "An invite code is required to register"},
+	"INVITE_CODE_INVALID":        {LangZhCN: "邀请码无效、已过期或已用完", LangEN: "The invite code is invalid, expired or used up"},
+	"REFRESH_TOKEN_INVALID":      {LangZhCN: "刷新令牌无效或已过期，请重新登录", LangEN: "The refresh token is invalid or expired, please sign in again"},
+	"SESSION_REVOKED":            {LangZhCN: "登录会话已失效，请重新登录", LangEN: "Your session has ended, please sign in again"},
+	"ACCESS_TOKEN_INVALID":       {LangZhCN: "访问令牌无效、已过期或已被吊销", LangEN: "The access token is invalid, expired or revoked"},
+	"ACCESS_TOKEN_SCOPE_INVALID": {LangZhCN: "至少需要选择一个权限范围", LangEN: "Select at least one valid scope"},
+	"ADMIN_SELF_CHANGE":          {LangZhCN: "不能修改当前登录管理员的状态或角色", LangEN: "You cannot change your own status or role"},
+	"LAST_ADMIN_REQUIRED":        {LangZhCN: "至少需要保留一个可用的管理员账号", LangEN: "At least one active administrator is required"},
+	"OIDC_DISABLED":              {LangZhCN: "未启用 OIDC 登录", LangEN: "OIDC sign-in is not enabled"},
+	"OIDC_STATE_INVALID":         {LangZhCN: "登录请求已过期或无效，请重新发起登录", LangEN: "The sign-in request is invalid or expired, please try again"},
+	"OIDC_IDENTITY_NOT_LINKED":   {LangZhCN: "该外部账号尚未绑定本地账号，请先使用账号密码登录后绑定", LangEN: "This external account is not linked yet, sign in with your password and link it first"},
+	"OIDC_IDENTITY_TAKEN":        {LangZhCN: "该外部账号已绑定其他用户", LangEN: "This external account is linked to another user"},
+	"OIDC_PROVIDER_FAILED":       {LangZhCN: "身份提供方请求失败", LangEN: "The identity provider request failed"},
+	"LAST_LOGIN_METHOD":          {LangZhCN: "账号未设置密码，不能解绑唯一的外部登录方式", LangEN: "Set a password before unlinking your only sign-in method"},
+
+	// 共享账本
+	"LEDGER_NOT_FOUND":          {LangZhCN: "账本不存在或无权访问", LangEN: "Ledger not found or access denied"},
+	"LEDGER_READ_ONLY":          {LangZhCN: "当前账本角色只能查看，不能修改数据", LangEN: "Your ledger role is read-only"},
+	"LEDGER_OWNER_REQUIRED":     {LangZhCN: "只有账本所有者可以执行该操作", LangEN: "Only the ledger owner can perform this action"},
+	"LEDGER_NAME_REQUIRED":      {LangZhCN: "账本名称不能为空", LangEN: "Ledger name is required"},
+	"LEDGER_MEMBER_EXISTS":      {LangZhCN: "该用户已是账本成员", LangEN: "The user is already a ledger member"},
+	"LEDGER_MEMBER_NOT_FOUND":   {LangZhCN: "账本成员不存在", LangEN: "Ledger member not found"},
+	"LEDGER_ROLE_INVALID":       {LangZhCN: "成员角色只能是 editor 或 viewer", LangEN: "Member role must be editor or viewer"},
+	"LEDGER_OWNER_CANNOT_LEAVE": {LangZhCN: "账本所有者不能退出账本，请先删除账本", LangEN: "The ledger owner cannot leave, delete the ledger instead"},
+
+	// 彩票与票据
+	"LOTTERY_NOT_FOUND":              {LangZhCN: "未找到彩种配置", LangEN: "Lottery not found"},
+	"LOTTERY_CODE_UNRESOLVED":        {LangZhCN: "未识别出彩票类型，请先完成识别或手动指定彩种", LangEN: "The lottery type could not be determined, recognize the ticket or specify it manually"},
+	"ISSUE_REQUIRED":                 {LangZhCN: "票据期号不能为空", LangEN: "Issue number is required"},
+	"TICKET_DUPLICATE":               {LangZhCN: "相同票据已存在，不能重复录入", LangEN: "An identical ticket already exists"},
+	"TICKET_ENTRIES_REQUIRED":        {LangZhCN: "至少需要一注号码", LangEN: "At least one entry is required"},
+	"TICKET_ENTRY_INVALID":           {LangZhCN: "号码不正确", LangEN: "Invalid ticket numbers"},
+	"TICKET_IMAGE_SAVED":             {LangZhCN: "这张图片已经入库，请重新上传新图片", LangEN: "This image has already been saved, please upload a new one"},
+	"TICKET_UPLOAD_BUSY":             {LangZhCN: "这张图片正在处理中，请稍后重试", LangEN: "This image is still being processed, please try again later"},
+	"TICKET_UPLOAD_MISMATCH":         {LangZhCN: "上传记录的彩票类型与当前录入类型不一致", LangEN: "The uploaded ticket belongs to a different lottery"},
+	"TICKET_RECOGNITION_MISSING":     {LangZhCN: "请先调用识别接口获取号码结果", LangEN: "Recognize the ticket before saving it"},
+	"TICKET_SHARES_INVALID":          {LangZhCN: "合买参与人信息不正确", LangEN: "Invalid syndicate participants"},
+	"BULK_TICKETS_REQUIRED":          {LangZhCN: "请传入票据 ID 列表或筛选条件", LangEN: "Provide ticket IDs or a filter"},
+	"BULK_TICKETS_LIMIT":             {LangZhCN: "单次批量操作最多 200 张票据，请缩小筛选范围", LangEN: "A bulk operation is limited to 200 tickets, narrow the filter"},
+	"TICKET_RECOMMENDATION_MISMATCH": {LangZhCN: "推荐记录与票据的彩票类型不一致", LangEN: "The recommendation belongs to a different lottery than the ticket"},
+	"IMPORT_FILE_INVALID":            {LangZhCN: "请上传 Excel 文件", LangEN: "Please upload an Excel file"},
+	"RECOMMENDATION_NOT_FOUND":       {LangZhCN: "推荐记录不存在", LangEN: "Recommendation not found"},
+	"VISION_NOT_CONFIGURED":          {LangZhCN: "未配置视觉模型，请填写 OCR 文本作为降级输入", LangEN: "No vision model is configured, provide OCR text instead"},
+	"NUMBERS_NOT_RECOGNIZED":         {LangZhCN: "未从图片中识别到有效号码", LangEN: "No valid numbers were recognized in the image"},
+
+	// 外部服务
+	"DRAW_SOURCE_FAILED":   {LangZhCN: "开奖数据源请求失败", LangEN: "The draw data source request failed"},
+	"MODEL_REQUEST_FAILED": {LangZhCN: "模型服务请求失败", LangEN: "The model service request failed"},
+	"OCR_REQUEST_FAILED":   {LangZhCN: "OCR 服务请求失败", LangEN: "The OCR service request failed"},
+
+	// 备份与恢复
+	"BACKUP_RUNNING":      {LangZhCN: "已有备份或恢复任务正在执行，请稍后再试", LangEN: "A backup or restore is already running, please try again later"},
+	"BACKUP_NOT_FOUND":    {LangZhCN: "备份文件不存在", LangEN: "Backup not found"},
+	"BACKUP_INVALID":      {LangZhCN: "备份文件校验失败", LangEN: "The backup failed verification"},
+	"BACKUP_INCOMPATIBLE": {LangZhCN: "备份与当前数据库不兼容", LangEN: "The backup is not compatible with the current database"},
+}
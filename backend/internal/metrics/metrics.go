@@ -0,0 +1,133 @@
+// Package metrics 定义对外暴露的 Prometheus 指标。指标注册在独立的 Registry 上，
+// 由 /metrics 接口统一输出，业务代码只通过本包提供的记录函数更新指标。
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "lottery"
+
+// Registry 汇总进程、Go 运行时和业务指标。
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP 请求数，按方法、路由模板和状态码统计。",
+	}, []string{"method", "route", "status"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP 请求耗时，按方法和路由模板统计。",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	externalRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "external_requests_total",
+		Help:      "第三方接口调用次数，按服务、HTTP 状态码和结果统计；未收到响应时状态码为 0。",
+	}, []string{"provider", "status", "outcome"})
+	externalDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "external_request_duration_seconds",
+		Help:      "第三方接口调用耗时，按服务和结果统计。",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"provider", "outcome"})
+
+	paddleOCRSlotsInUse = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "paddleocr_worker_slots_in_use",
+		Help:      "正在占用的 PaddleOCR 处理槽位数。",
+	})
+	paddleOCRSlotsCapacity = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "paddleocr_worker_slots_capacity",
+		Help:      "PaddleOCR 处理槽位总数。",
+	})
+	paddleOCRSlotWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "paddleocr_worker_slot_wait_seconds",
+		Help:      "等待 PaddleOCR 处理槽位的耗时。",
+		Buckets:   []float64{0.01, 0.1, 0.5, 1, 5, 15, 30, 60},
+	})
+
+	jobRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "定时任务执行次数，按任务类型、名称和结果统计。",
+	}, []string{"type", "name", "outcome"})
+	jobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "定时任务执行耗时。",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 15, 30, 60, 300, 900},
+	}, []string{"type", "name"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		externalRequests,
+		externalDuration,
+		paddleOCRSlotsInUse,
+		paddleOCRSlotsCapacity,
+		paddleOCRSlotWait,
+		jobRuns,
+		jobDuration,
+	)
+}
+
+// MustRegister 注册由业务模块提供的采集器，例如抓取时才查询数据库的统计。
+func MustRegister(collectors ...prometheus.Collector) {
+	Registry.MustRegister(collectors...)
+}
+
+// ObserveHTTPRequest 记录一次 HTTP 请求。route 应为路由模板而不是实际路径，避免 ID 导致指标数量膨胀。
+func ObserveHTTPRequest(method string, route string, status int, duration time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveExternalCall 记录一次第三方接口调用。
+func ObserveExternalCall(provider string, statusCode int, success bool, duration time.Duration) {
+	outcome := outcomeLabel(success)
+	externalRequests.WithLabelValues(provider, strconv.Itoa(statusCode), outcome).Inc()
+	externalDuration.WithLabelValues(provider, outcome).Observe(duration.Seconds())
+}
+
+// SetPaddleOCRSlotCapacity 设置 PaddleOCR 处理槽位总数。
+func SetPaddleOCRSlotCapacity(capacity int) {
+	paddleOCRSlotsCapacity.Set(float64(capacity))
+}
+
+// PaddleOCRSlotAcquired 在成功占用槽位后调用，记录等待耗时。
+func PaddleOCRSlotAcquired(wait time.Duration) {
+	paddleOCRSlotsInUse.Inc()
+	paddleOCRSlotWait.Observe(wait.Seconds())
+}
+
+func PaddleOCRSlotReleased() {
+	paddleOCRSlotsInUse.Dec()
+}
+
+// ObserveJob 记录一次定时任务执行结果。
+func ObserveJob(jobType string, name string, success bool, duration time.Duration) {
+	jobRuns.WithLabelValues(jobType, name, outcomeLabel(success)).Inc()
+	jobDuration.WithLabelValues(jobType, name).Observe(duration.Seconds())
+}
+
+func outcomeLabel(success bool) string {
+	if success {
+		return "success"
+	}
+	return "failure"
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-fiber-starter/internal/api/response"
+	userModel "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service/apperr"
+
+	"github.com/gofiber/fiber/v2"
+	jwtware "github.com/gofiber/jwt/v3"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestRequiredAccessTokenScope(t *testing.T) {
+	cases := []struct {
+		method  string
+		path    string
+		scope   string
+		allowed bool
+	}{
+		{fiber.MethodGet, "/api/lotteries/tickets", userModel.ScopeRead, true},
+		{fiber.MethodPost, "/api/lotteries/tickets", userModel.ScopeTicketsWrite, true},
+		{fiber.MethodPost, "/api/lotteries/ssq/tickets/scan", userModel.ScopeTicketsWrite, true},
+		{fiber.MethodPost, "/api/lotteries/ssq/recommendations/generate", userModel.ScopeRecommendationsWrite, true},
+		{fiber.MethodPost, "/api/lotteries/ssq/draws/sync", userModel.ScopeDrawsSync, true},
+		{fiber.MethodPost, "/api/lotteries/ssq/draws/sync-history", "", false},
+		{fiber.MethodGet, "/api/admin/users", "", false},
+		{fiber.MethodGet, "/API/Admin/users", "", false},
+		{fiber.MethodGet, "/api/auth/tokens", "", false},
+	}
+
+	for _, tc := range cases {
+		scope, allowed := requiredAccessTokenScope(tc.method, tc.path)
+		if scope != tc.scope || allowed != tc.allowed {
+			t.Errorf("%s %s: got (%q, %v), want (%q, %v)", tc.method, tc.path, scope, allowed, tc.scope, tc.allowed)
+		}
+	}
+}
+
+func TestJWTErrorHandlerReturnsLocalizedEnvelope(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(RequestID)
+	app.Use(Authenticate(jwtware.New(jwtware.Config{
+		SigningKey:   []byte("test-secret"),
+		ErrorHandler: JWTErrorHandler,
+	})))
+	app.Get("/api/profile", func(c *fiber.Ctx) error {
+		return response.Success(c, nil)
+	})
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": "00000000-0000-0000-0000-000000000001",
+		"exp":     time.Now().Add(-time.Minute).Unix(),
+	}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	for name, token := range map[string]string{"expired": expired, "malformed": "not-a-jwt"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/profile", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		req.Header.Set(fiber.HeaderAcceptLanguage, "en")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", name, err)
+		}
+		envelope := response.Response{}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("%s: decode response: %v", name, err)
+		}
+		if resp.StatusCode != http.StatusUnauthorized || envelope.ErrorCode != apperr.CodeUnauthorized {
+			t.Fatalf("%s: expected 401 %s, got %d %q", name, apperr.CodeUnauthorized, resp.StatusCode, envelope.ErrorCode)
+		}
+		if envelope.Msg != "Authentication failed, please sign in" || envelope.RequestID == "" {
+			t.Fatalf("%s: expected english message with request id, got %+v", name, envelope)
+		}
+	}
+}
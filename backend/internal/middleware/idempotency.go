@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+
+	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/internal/service"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotent-Replayed"
+	// HeaderLedgerID 允许客户端通过请求头指定账本，优先级低于 ledgerId 查询参数。
+	HeaderLedgerID = "X-Ledger-Id"
+)
+
+// Idempotency 为写接口提供 Idempotency-Key 支持：首次请求成功后保存响应，保留期内相同键、
+// 相同内容的重试直接返回原响应；相同键但请求内容不同时返回 422。未携带请求头时不做处理。
+func Idempotency(c *fiber.Ctx) error {
+	key := strings.TrimSpace(c.Get(HeaderIdempotencyKey))
+	if key == "" {
+		return c.Next()
+	}
+	user, err := service.CurrentUser(c)
+	if err != nil {
+		return response.Fail(c, apperr.ErrUnauthorized)
+	}
+	userID := user.Id.String()
+
+	fingerprint, err := requestFingerprint(c)
+	if err != nil {
+		return err
+	}
+	record, err := service.BeginIdempotentRequest(service.IdempotentRequest{
+		UserID:      userID,
+		Key:         key,
+		Method:      c.Method(),
+		Path:        c.Path(),
+		Fingerprint: fingerprint,
+	})
+	if err != nil {
+		return err
+	}
+	if record != nil {
+		c.Set(HeaderIdempotencyReplayed, "true")
+		if record.ContentType != "" {
+			c.Set(fiber.HeaderContentType, record.ContentType)
+		}
+		return c.Status(record.StatusCode).Send(record.ResponseBody)
+	}
+
+	if err := c.Next(); err != nil {
+		releaseIdempotentRequest(userID, key)
+		return err
+	}
+	status := c.Response().StatusCode()
+	if status < fiber.StatusOK || status >= fiber.StatusMultipleChoices {
+		releaseIdempotentRequest(userID, key)
+		return nil
+	}
+	if err := service.CompleteIdempotentRequest(userID, key, status, string(c.Response().Header.ContentType()), c.Response().Body()); err != nil {
+		logger.Warn("保存幂等请求响应失败: %v", err)
+	}
+	return nil
+}
+
+func releaseIdempotentRequest(userID string, key string) {
+	if err := service.ReleaseIdempotentRequest(userID, key); err != nil {
+		logger.Warn("释放幂等请求记录失败: %v", err)
+	}
+}
+
+// LedgerSelector 返回请求指定的账本 ID，ledgerId 查询参数优先于 X-Ledger-Id 请求头，未指定时为空。
+func LedgerSelector(c *fiber.Ctx) string {
+	if ledgerID := c.Query("ledgerId"); ledgerID != "" {
+		return ledgerID
+	}
+	return c.Get(HeaderLedgerID)
+}
+
+// requestFingerprint 计算请求方法、路径、查询参数、所选账本和请求体的摘要。multipart 请求按字段和文件内容计算，
+// 避免客户端重试时 boundary 变化导致同一请求被误判为不同内容。所选账本也计入摘要，
+// 同一个键改投到其他账本时按内容不同处理。
+func requestFingerprint(c *fiber.Ctx) (string, error) {
+	hash := sha256.New()
+	io.WriteString(hash, c.Method()+"\n"+c.Path()+"\n"+string(c.Request().URI().QueryString())+"\n")
+	io.WriteString(hash, "ledger:"+LedgerSelector(c)+"\n")
+
+	contentType := string(c.Request().Header.ContentType())
+	if !strings.HasPrefix(contentType, fiber.MIMEMultipartForm) {
+		hash.Write(c.Body())
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range sortedKeys(form.Value) {
+		for _, value := range form.Value[name] {
+			io.WriteString(hash, "field:"+name+"="+value+"\n")
+		}
+	}
+	for _, name := range sortedKeys(form.File) {
+		for _, header := range form.File[name] {
+			io.WriteString(hash, "file:"+name+"="+header.Filename+"\n")
+			file, err := header.Open()
+			if err != nil {
+				return "", err
+			}
+			_, copyErr := io.Copy(hash, file)
+			file.Close()
+			if copyErr != nil {
+				return "", copyErr
+			}
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
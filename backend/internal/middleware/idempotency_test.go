@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-fiber-starter/internal/api/response"
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofiber/fiber/v2"
+	jwtware "github.com/gofiber/jwt/v3"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+func setupIdempotencyTestApp(t *testing.T) (*fiber.App, string, *int) {
+	t.Helper()
+
+	prevDB := db.DB
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		t.Fatalf("get sql db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := gormDB.AutoMigrate(&model.User{}, &model.IdempotencyKey{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	db.DB = gormDB
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+		db.DB = prevDB
+	})
+
+	user := model.User{Username: "alice", Role: model.RoleUser}
+	if err := db.DB.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": user.Id.String()}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	calls := 0
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(jwtware.New(jwtware.Config{SigningKey: []byte("test-secret")}))
+	app.Post("/tickets", Idempotency, func(c *fiber.Ctx) error {
+		calls++
+		if c.Query("fail") == "1" {
+			return fiber.NewError(fiber.StatusBadGateway, "upstream failed")
+		}
+		return response.Success(c, fiber.Map{"call": calls}, fiber.StatusCreated)
+	})
+	return app, "Bearer " + token, &calls
+}
+
+func sendIdempotentRequest(t *testing.T, app *fiber.App, token string, url string, key string, body string) (*http.Response, response.Response) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
+	req.Header.Set(fiber.HeaderAuthorization, token)
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	envelope := response.Response{}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp, envelope
+}
+
+func TestIdempotencyReplaysOriginalResponse(t *testing.T) {
+	app, token, calls := setupIdempotencyTestApp(t)
+
+	first, firstBody := sendIdempotentRequest(t, app, token, "/tickets", "retry-1", `{"issue":"2026030"}`)
+	replay, replayBody := sendIdempotentRequest(t, app, token, "/tickets", "retry-1", `{"issue":"2026030"}`)
+	if first.StatusCode != http.StatusCreated || replay.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201 for both requests, got %d and %d", first.StatusCode, replay.StatusCode)
+	}
+	if *calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", *calls)
+	}
+	if replay.Header.Get(HeaderIdempotencyReplayed) != "true" || replayBody.Time != firstBody.Time {
+		t.Fatalf("expected replayed original response")
+	}
+
+	reused, reusedBody := sendIdempotentRequest(t, app, token, "/tickets", "retry-1", `{"issue":"2026031"}`)
+	if reused.StatusCode != http.StatusUnprocessableEntity || reusedBody.ErrorCode != "IDEMPOTENCY_KEY_REUSED" {
+		t.Fatalf("expected 422 for reused key, got %d/%s", reused.StatusCode, reusedBody.ErrorCode)
+	}
+
+	sendIdempotentRequest(t, app, token, "/tickets", "", `{"issue":"2026030"}`)
+	if *calls != 2 {
+		t.Fatalf("requests without key should not be deduplicated, handler ran %d times", *calls)
+	}
+}
+
+func TestIdempotencyReleasesFailedRequests(t *testing.T) {
+	app, token, calls := setupIdempotencyTestApp(t)
+
+	failed, _ := sendIdempotentRequest(t, app, token, "/tickets?fail=1", "retry-2", `{}`)
+	if failed.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d", failed.StatusCode)
+	}
+	retried, _ := sendIdempotentRequest(t, app, token, "/tickets?fail=1", "retry-2", `{}`)
+	if retried.StatusCode != http.StatusBadGateway || *calls != 2 {
+		t.Fatalf("failed requests should be retried, got %d after %d calls", retried.StatusCode, *calls)
+	}
+
+	var count int64
+	if err := db.DB.Model(&model.IdempotencyKey{}).Count(&count).Error; err != nil {
+		t.Fatalf("count records: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected failed requests to leave no record, got %d", count)
+	}
+}
+
+func TestIdempotencyRejectsKeyReusedForAnotherLedger(t *testing.T) {
+	app, token, calls := setupIdempotencyTestApp(t)
+
+	send := func(ledgerID string) int {
+		req := httptest.NewRequest(http.MethodPost, "/tickets", bytes.NewBufferString(`{"issue":"2026030"}`))
+		req.Header.Set(fiber.HeaderAuthorization, token)
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(HeaderIdempotencyKey, "retry-ledger")
+		if ledgerID != "" {
+			req.Header.Set(HeaderLedgerID, ledgerID)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := send("ledger-a"); status != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if status := send("ledger-a"); status != http.StatusCreated || *calls != 1 {
+		t.Fatalf("expected replay for the same ledger, got %d after %d calls", status, *calls)
+	}
+	if status := send("ledger-b"); status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for key reused with another ledger, got %d", status)
+	}
+	if status := send(""); status != http.StatusUnprocessableEntity || *calls != 1 {
+		t.Fatalf("expected 422 for key reused with personal data, got %d after %d calls", status, *calls)
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"time"
+
+	"go-fiber-starter/internal/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics 按路由模板记录请求数和耗时。处理链返回的错误在这里交给全局错误处理器转换成响应，
+// 以便记录到最终的状态码。
+func Metrics(c *fiber.Ctx) error {
+	startedAt := time.Now()
+	if err := c.Next(); err != nil {
+		if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+			_ = c.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+	metrics.ObserveHTTPRequest(c.Method(), c.Route().Path, c.Response().StatusCode(), time.Since(startedAt))
+	return nil
+}
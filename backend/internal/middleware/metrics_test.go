@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-fiber-starter/internal/metrics"
+	"go-fiber-starter/internal/service/apperr"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func httpRequestCount(t *testing.T, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "lottery_http_requests_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matched := 0
+			for _, pair := range metric.GetLabel() {
+				if labels[pair.GetName()] == pair.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(labels) {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestMetricsRecordsRouteTemplateAndFinalStatus(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Metrics)
+	app.Get("/tickets/:ticketId", func(c *fiber.Ctx) error {
+		if c.Params("ticketId") == "missing" {
+			return apperr.ErrNotFound
+		}
+		return c.SendStatus(http.StatusOK)
+	})
+
+	okLabels := map[string]string{"method": "GET", "route": "/tickets/:ticketId", "status": "200"}
+	missingLabels := map[string]string{"method": "GET", "route": "/tickets/:ticketId", "status": "404"}
+	okBefore := httpRequestCount(t, okLabels)
+	missingBefore := httpRequestCount(t, missingLabels)
+
+	for _, path := range []string{"/tickets/a", "/tickets/b", "/tickets/missing"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		if err != nil {
+			t.Fatalf("request %s: %v", path, err)
+		}
+		if path == "/tickets/missing" && resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected 404 from error handler, got %d", resp.StatusCode)
+		}
+	}
+
+	if got := httpRequestCount(t, okLabels) - okBefore; got != 2 {
+		t.Fatalf("expected 2 successful requests on route template, got %v", got)
+	}
+	if got := httpRequestCount(t, missingLabels) - missingBefore; got != 1 {
+		t.Fatalf("expected 1 not found request, got %v", got)
+	}
+}
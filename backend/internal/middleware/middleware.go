@@ -8,11 +8,24 @@
 package middleware
 
 import (
+	"errors"
+
 	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// ErrorHandler 统一把处理器返回的错误转换为响应：服务层类型化错误按分类映射状态码，
+// 记录不存在映射为 404，其余错误按 500 处理。5xx 错误同时写入带请求 ID 的错误日志。
 func ErrorHandler(c *fiber.Ctx, err error) error {
-	return response.Error(c, err.Error(), fiber.StatusInternalServerError)
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return response.Error(c, fiberErr.Message, fiberErr.Code)
+	}
+	result := response.Fail(c, err)
+	if c.Response().StatusCode() >= fiber.StatusInternalServerError {
+		logger.ErrorContext(c.UserContext(), "请求处理失败 %s %s: %v", c.Method(), c.Path(), err)
+	}
+	return result
 }
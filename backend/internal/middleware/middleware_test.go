@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/internal/i18n"
+	"go-fiber-starter/internal/service/apperr"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func TestErrorHandlerMapsTypedErrors(t *testing.T) {
+	duplicate := apperr.New(apperr.KindConflict, "TICKET_DUPLICATE", "相同票据已存在")
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"conflict", duplicate, http.StatusConflict, "TICKET_DUPLICATE"},
+		{"wrapped", fmt.Errorf("保存失败: %w", duplicate), http.StatusConflict, "TICKET_DUPLICATE"},
+		{"detail", duplicate.WithMessage("第 2 注重复"), http.StatusConflict, "TICKET_DUPLICATE"},
+		{"unprocessable", apperr.Unprocessable("TICKET_ENTRY_INVALID", "红球号码不能重复"), http.StatusUnprocessableEntity, "TICKET_ENTRY_INVALID"},
+		{"not found", gorm.ErrRecordNotFound, http.StatusNotFound, apperr.CodeNotFound},
+		{"upstream", apperr.Upstream("DRAW_SOURCE_FAILED", errors.New("timeout")), http.StatusBadGateway, "DRAW_SOURCE_FAILED"},
+		{"fiber", fiber.NewError(http.StatusForbidden, "forbidden"), http.StatusForbidden, apperr.CodeForbidden},
+		{"internal", errors.New("boom"), http.StatusInternalServerError, apperr.CodeInternal},
+	}
+
+	for _, tc := range cases {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+		app.Get("/", func(c *fiber.Ctx) error { return tc.err })
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tc.name, err)
+		}
+		envelope := response.Response{}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("%s: decode response: %v", tc.name, err)
+		}
+		if resp.StatusCode != tc.status || envelope.Code != tc.status || envelope.ErrorCode != tc.code {
+			t.Errorf("%s: got %d/%s, want %d/%s", tc.name, resp.StatusCode, envelope.ErrorCode, tc.status, tc.code)
+		}
+	}
+
+	if !errors.Is(duplicate.WithMessage("x"), duplicate) {
+		t.Errorf("derived error should match its sentinel")
+	}
+}
+
+func TestErrorHandlerLocalizesMessages(t *testing.T) {
+	duplicate := apperr.New(apperr.KindConflict, "TICKET_DUPLICATE", "相同票据已存在，不能重复录入")
+	cases := []struct {
+		name           string
+		acceptLanguage string
+		preference     string
+		err            error
+		msg            string
+		contentLang    string
+	}{
+		{"default", "", "", duplicate, "相同票据已存在，不能重复录入", "zh-CN"},
+		{"english header", "en-US,en;q=0.9", "", duplicate, "An identical ticket already exists", "en"},
+		{"weighted header", "en;q=0.5, zh-CN;q=0.8", "", duplicate.WithMessage("第 2 注重复"), "第 2 注重复", "zh-CN"},
+		{"preference wins", "zh-CN", "en", duplicate, "An identical ticket already exists", "en"},
+		{"generic status", "en", "", fiber.NewError(http.StatusBadRequest, "参数不正确"), "Invalid request parameters", "en"},
+		{"missing translation", "en", "", apperr.New(apperr.KindConflict, "UNKNOWN_CODE", "未知错误"), "未知错误", "en"},
+	}
+
+	for _, tc := range cases {
+		app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+		app.Get("/", func(c *fiber.Ctx) error {
+			i18n.SetPreference(c, tc.preference)
+			return tc.err
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.acceptLanguage != "" {
+			req.Header.Set(fiber.HeaderAcceptLanguage, tc.acceptLanguage)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", tc.name, err)
+		}
+		envelope := response.Response{}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("%s: decode response: %v", tc.name, err)
+		}
+		if envelope.Msg != tc.msg {
+			t.Errorf("%s: got msg %q, want %q", tc.name, envelope.Msg, tc.msg)
+		}
+		if got := resp.Header.Get(fiber.HeaderContentLanguage); got != tc.contentLang {
+			t.Errorf("%s: got Content-Language %q, want %q", tc.name, got, tc.contentLang)
+		}
+	}
+}
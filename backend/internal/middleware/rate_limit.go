@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/internal/service"
+	"go-fiber-starter/internal/service/apperr"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var rateLimitMessages = map[string]string{
+	service.RateLimitOCR:  "票据识别过于频繁，请稍后再试",
+	service.RateLimitAI:   "推荐生成过于频繁，请稍后再试",
+	service.RateLimitSync: "开奖同步过于频繁，请稍后再试",
+}
+
+// RateLimit 按当前用户限制 OCR、AI、同步等高成本接口的调用频率，超出后返回 429。
+func RateLimit(category string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, err := service.CurrentUser(c)
+		if err != nil {
+			return response.Fail(c, apperr.ErrUnauthorized)
+		}
+		if wait, ok := service.AllowUserRequest(category, user.Id.String()); !ok {
+			return response.TooManyRequests(c, rateLimitMessages[category], wait)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"time"
+
+	"go-fiber-starter/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const maxRequestIDLength = 128
+
+// RequestID 为每个请求分配请求 ID：沿用客户端或网关传入的 X-Request-ID，没有或不合法时生成新的 UUID。
+// 请求 ID 通过响应头回传，并写入 UserContext 的日志字段，处理链中用 logger.InfoContext 等函数输出的日志都会带上它。
+func RequestID(c *fiber.Ctx) error {
+	requestID := c.Get(fiber.HeaderXRequestID)
+	if !validRequestID(requestID) {
+		requestID = uuid.NewString()
+	}
+	c.Set(fiber.HeaderXRequestID, requestID)
+	c.SetUserContext(logger.WithFields(c.UserContext(), logger.FieldRequestID, requestID))
+	return c.Next()
+}
+
+// validRequestID 只接受可见 ASCII 字符，避免把换行等内容写进日志和响应头。
+func validRequestID(value string) bool {
+	if value == "" || len(value) > maxRequestIDLength {
+		return false
+	}
+	for _, char := range value {
+		if char <= ' ' || char > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// AccessLog 按字段输出访问日志，携带请求 ID、用户 ID 和追踪 ID。处理链返回的错误先交给全局错误处理器，
+// 以便记录最终的状态码。健康检查和指标抓取请求频繁，不写入访问日志。
+func AccessLog(c *fiber.Ctx) error {
+	if isProbePath(c.Path()) {
+		return c.Next()
+	}
+	startedAt := time.Now()
+	if err := c.Next(); err != nil {
+		if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+			_ = c.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+	logger.InfowContext(c.UserContext(), "HTTP 请求",
+		"method", c.Method(),
+		"path", c.Path(),
+		"route", c.Route().Path,
+		"status", c.Response().StatusCode(),
+		"latency_ms", time.Since(startedAt).Milliseconds(),
+		"ip", c.IP(),
+	)
+	return nil
+}
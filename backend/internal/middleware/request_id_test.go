@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-fiber-starter/internal/api/response"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func TestRequestIDEchoesHeaderAndEnvelope(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(RequestID)
+	app.Get("/fields", func(c *fiber.Ctx) error {
+		ctx := logger.WithFields(c.UserContext(), logger.FieldTicketID, "ticket-1")
+		return c.SendString(logger.Field(ctx, logger.FieldRequestID) + "|" + logger.Field(ctx, logger.FieldTicketID))
+	})
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		return apperr.ErrNotFound
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/fields", nil)
+	request.Header.Set(fiber.HeaderXRequestID, "gateway-42")
+	resp, err := app.Test(request)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if got := resp.Header.Get(fiber.HeaderXRequestID); got != "gateway-42" {
+		t.Fatalf("expected incoming request id to be echoed, got %q", got)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if string(body) != "gateway-42|ticket-1" {
+		t.Fatalf("expected request id and ticket id in log fields, got %q", body)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "/missing", nil)
+	request.Header.Set(fiber.HeaderXRequestID, "bad\tvalue")
+	resp, err = app.Test(request)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	generated := resp.Header.Get(fiber.HeaderXRequestID)
+	if _, err := uuid.Parse(generated); err != nil {
+		t.Fatalf("expected invalid request id to be replaced by uuid, got %q", generated)
+	}
+	envelope := response.Response{}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if envelope.RequestID != generated {
+		t.Fatalf("expected envelope requestId %q, got %q", generated, envelope.RequestID)
+	}
+}
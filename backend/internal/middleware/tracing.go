@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"go-fiber-starter/internal/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing 为每个请求创建服务端 span，沿用请求头中的 W3C traceparent，
+// 并把带 span 的上下文放入 UserContext，处理函数应把 c.UserContext() 传给服务层。
+// 健康检查和指标抓取请求频繁且没有排查价值，不创建 span。
+func Tracing(c *fiber.Ctx) error {
+	if isProbePath(c.Path()) {
+		return c.Next()
+	}
+	header := http.Header{}
+	for key, values := range c.GetReqHeaders() {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), propagation.HeaderCarrier(header))
+	ctx, span := tracing.Tracer().Start(ctx, c.Method()+" "+c.Path(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(c.Method()), semconv.URLPath(c.Path())),
+	)
+	defer span.End()
+	c.SetUserContext(ctx)
+
+	err := c.Next()
+	route := c.Route().Path
+	span.SetName(c.Method() + " " + route)
+	span.SetAttributes(semconv.HTTPRoute(route))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	status := c.Response().StatusCode()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+	if status >= fiber.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	return nil
+}
+
+// isProbePath 判断是否为健康检查或指标抓取请求。
+func isProbePath(path string) bool {
+	return strings.HasPrefix(path, "/api/health/") || path == "/metrics"
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"go-fiber-starter/internal/tracing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+type tracingTestRecord struct {
+	ID   uint
+	Name string
+}
+
+func TestTracingLinksRequestAndQuerySpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		_ = provider.Shutdown(t.Context())
+	})
+
+	database, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tracing.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := database.AutoMigrate(&tracingTestRecord{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := database.Use(tracing.GormPlugin{}); err != nil {
+		t.Fatalf("register plugin: %v", err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(Tracing)
+	app.Get("/records/:id", func(c *fiber.Ctx) error {
+		record := tracingTestRecord{}
+		if err := database.WithContext(c.UserContext()).Where("id = ?", c.Params("id")).Find(&record).Error; err != nil {
+			return err
+		}
+		return c.SendStatus(http.StatusOK)
+	})
+	app.Get("/api/health/live", func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+
+	parentTraceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	request := httptest.NewRequest(http.MethodGet, "/records/1", nil)
+	request.Header.Set("traceparent", "00-"+parentTraceID+"-00f067aa0ba902b7-01")
+	if _, err := app.Test(request); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if _, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/health/live", nil)); err != nil {
+		t.Fatalf("health request: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected request and query spans only, got %d", len(spans))
+	}
+	var serverSpan, querySpan tracetest.SpanStub
+	for _, span := range spans {
+		switch span.SpanKind {
+		case trace.SpanKindServer:
+			serverSpan = span
+		case trace.SpanKindClient:
+			querySpan = span
+		}
+	}
+	if serverSpan.Name != "GET /records/:id" {
+		t.Fatalf("expected server span named by route template, got %q", serverSpan.Name)
+	}
+	if serverSpan.SpanContext.TraceID().String() != parentTraceID {
+		t.Fatalf("expected incoming traceparent to be continued, got %s", serverSpan.SpanContext.TraceID())
+	}
+	if querySpan.Name != "gorm.query" || querySpan.Parent.SpanID() != serverSpan.SpanContext.SpanID() {
+		t.Fatalf("expected gorm.query child of request span, got %q parent %s", querySpan.Name, querySpan.Parent.SpanID())
+	}
+}
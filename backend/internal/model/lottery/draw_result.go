@@ -15,8 +15,10 @@ type DrawResult struct {
 	DrawDate        time.Time   `json:"drawDate"`
 	RedNumbers      string      `gorm:"size:64" json:"redNumbers"`
 	BlueNumbers     string      `gorm:"size:32" json:"blueNumbers"`
-	SaleAmount      float64     `json:"saleAmount"`
-	PrizePoolAmount float64     `json:"prizePoolAmount"`
+	RedMask         int64       `gorm:"not null;default:0" json:"-"`
+	BlueMask        int64       `gorm:"not null;default:0" json:"-"`
+	SaleAmount      Money       `gorm:"column:sale_amount_cents" json:"saleAmount" swaggertype:"number"`
+	PrizePoolAmount Money       `gorm:"column:prize_pool_amount_cents" json:"prizePoolAmount" swaggertype:"number"`
 	Source          string      `gorm:"size:32" json:"source"`
 	RawPayload      string      `gorm:"type:text" json:"rawPayload"`
 	PrizeDetails    []DrawPrize `json:"prizeDetails"`
@@ -28,7 +30,7 @@ type DrawPrize struct {
 	PrizeName    string    `gorm:"size:32" json:"prizeName"`
 	PrizeRule    string    `gorm:"size:128" json:"prizeRule"`
 	WinnerCount  int       `json:"winnerCount"`
-	SingleBonus  float64   `json:"singleBonus"`
+	SingleBonus  Money     `gorm:"column:single_bonus_cents" json:"singleBonus" swaggertype:"number"`
 }
 
 func (DrawResult) TableName() string {
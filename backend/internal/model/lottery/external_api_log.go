@@ -0,0 +1,32 @@
+package lottery
+
+import (
+	"go-fiber-starter/internal/model/base"
+
+	"github.com/google/uuid"
+)
+
+// ExternalAPILog 记录一次极速数据、AI 或 PaddleOCR 调用。地址中的密钥已脱敏，请求和响应按长度截断；
+// 由识票或生成推荐触发的调用会关联对应的上传记录或推荐记录，便于排查单张票据或单条推荐的问题。
+type ExternalAPILog struct {
+	base.BaseModel
+	Provider         string     `gorm:"index;size:32" json:"provider"`
+	Method           string     `gorm:"size:16" json:"method"`
+	Endpoint         string     `gorm:"size:512" json:"endpoint"`
+	StatusCode       int        `json:"statusCode"`
+	Success          bool       `gorm:"index" json:"success"`
+	DurationMs       int64      `json:"durationMs"`
+	Request          string     `gorm:"type:text" json:"request,omitempty"`
+	Response         string     `gorm:"type:text" json:"response,omitempty"`
+	ErrorMessage     string     `gorm:"type:text" json:"errorMessage"`
+	RequestID        string     `gorm:"index;size:128" json:"requestId"`
+	TraceID          string     `gorm:"size:32" json:"traceId"`
+	JobName          string     `gorm:"size:64" json:"jobName"`
+	UserID           *uuid.UUID `gorm:"type:uuid;index" json:"userId"`
+	UploadID         *uuid.UUID `gorm:"type:uuid;index" json:"uploadId"`
+	RecommendationID *uuid.UUID `gorm:"type:uuid;index" json:"recommendationId"`
+}
+
+func (ExternalAPILog) TableName() string {
+	return "external_api_logs"
+}
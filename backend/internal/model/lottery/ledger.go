@@ -0,0 +1,45 @@
+package lottery
+
+import (
+	"go-fiber-starter/internal/model/base"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LedgerRoleOwner  = "owner"
+	LedgerRoleEditor = "editor"
+	LedgerRoleViewer = "viewer"
+)
+
+// Ledger 是多人共享的账本，账本内的票据和推荐对全部成员可见。
+type Ledger struct {
+	base.BaseModel
+	Name    string    `gorm:"size:64" json:"name"`
+	OwnerID uuid.UUID `gorm:"type:uuid;index" json:"ownerId"`
+}
+
+// LedgerMember 记录账本成员及其角色：owner 管理成员，editor 可录票和生成推荐，viewer 只读。
+type LedgerMember struct {
+	base.BaseModel
+	LedgerID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_ledger_members_ledger_user" json:"ledgerId"`
+	UserID   uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_ledger_members_ledger_user;index" json:"userId"`
+	Role     string    `gorm:"size:16" json:"role"`
+}
+
+func (Ledger) TableName() string {
+	return "ledgers"
+}
+
+func (LedgerMember) TableName() string {
+	return "ledger_members"
+}
+
+func IsValidLedgerRole(role string) bool {
+	return role == LedgerRoleOwner || role == LedgerRoleEditor || role == LedgerRoleViewer
+}
+
+// CanWrite 判断角色是否允许新增、修改和删除账本内的数据。
+func (m LedgerMember) CanWrite() bool {
+	return m.Role == LedgerRoleOwner || m.Role == LedgerRoleEditor
+}
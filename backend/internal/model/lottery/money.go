@@ -0,0 +1,196 @@
+package lottery
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money 以分为单位保存金额，数据库中是整数列，求和与倍数计算不会产生浮点误差。
+// JSON 中仍按元输出十进制数字（如 10.5），与原来的 float64 字段格式一致。
+type Money int64
+
+const (
+	Cent Money = 1
+	Yuan Money = 100
+)
+
+// MoneyFromFloat 把以元为单位的浮点数四舍五入到分，用于解析第三方接口和识别结果。
+func MoneyFromFloat(value float64) Money {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+	return Money(math.Round(value * 100))
+}
+
+// ParseMoney 精确解析以元为单位的十进制字符串，超过两位的小数按四舍五入处理。
+func ParseMoney(value string) (Money, error) {
+	text := strings.TrimSpace(value)
+	if text == "" {
+		return 0, fmt.Errorf("金额为空")
+	}
+	if strings.ContainsAny(text, "eE") {
+		parsed, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) || math.Abs(parsed) > math.MaxInt64/100 {
+			return 0, fmt.Errorf("金额格式不正确: %s", value)
+		}
+		return MoneyFromFloat(parsed), nil
+	}
+
+	negative := false
+	switch text[0] {
+	case '-':
+		negative = true
+		text = text[1:]
+	case '+':
+		text = text[1:]
+	}
+	whole, fraction, _ := strings.Cut(text, ".")
+	if (whole == "" && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("金额格式不正确: %s", value)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("金额超出范围: %s", value)
+	}
+
+	padded := fraction + "000"
+	cents := int64(padded[0]-'0')*10 + int64(padded[1]-'0')
+	if padded[2] >= '5' {
+		cents++
+	}
+	amount := Money(units*100 + cents)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func isDigits(value string) bool {
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Cents 返回以分为单位的整数值。
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 返回以元为单位的浮点数，仅用于展示或与外部浮点接口交互，不要用于累加。
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// MulRatio 按 numerator/denominator 缩放金额并四舍五入到分，例如追加投注一二等奖按 18/10 计算。
+func (m Money) MulRatio(numerator int64, denominator int64) Money {
+	if denominator == 0 {
+		return 0
+	}
+	product := int64(m) * numerator
+	quotient := product / denominator
+	remainder := product % denominator
+	if remainder < 0 {
+		remainder = -remainder
+	}
+	if remainder*2 >= abs64(denominator) {
+		if (product < 0) != (denominator < 0) {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return Money(quotient)
+}
+
+func abs64(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// String 按元输出最短的十进制表示：2 元输出 "2"，10 元 5 角输出 "10.5"。
+func (m Money) String() string {
+	cents := int64(m)
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	units := cents / 100
+	fraction := cents % 100
+	switch {
+	case fraction == 0:
+		return sign + strconv.FormatInt(units, 10)
+	case fraction%10 == 0:
+		return fmt.Sprintf("%s%d.%d", sign, units, fraction/10)
+	default:
+		return fmt.Sprintf("%s%d.%02d", sign, units, fraction)
+	}
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON 接受数字或数字字符串，null 视为 0。
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		*m = 0
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan 读取以分为单位的整数列，兼容驱动把聚合结果返回为浮点或文本的情况。
+func (m *Money) Scan(value any) error {
+	switch typed := value.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = Money(typed)
+	case float64:
+		*m = Money(math.Round(typed))
+	case []byte:
+		return m.scanText(string(typed))
+	case string:
+		return m.scanText(typed)
+	default:
+		return fmt.Errorf("不支持的金额类型 %T", value)
+	}
+	return nil
+}
+
+func (m *Money) scanText(value string) error {
+	parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return fmt.Errorf("金额格式不正确: %s", value)
+	}
+	*m = Money(parsed)
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return int64(m), nil
+}
@@ -0,0 +1,48 @@
+package lottery
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoneyJSONKeepsDecimalYuanFormat(t *testing.T) {
+	cases := map[Money]string{
+		0:       "0",
+		200:     "2",
+		1050:    "10.5",
+		7:       "0.07",
+		-33333:  "-333.33",
+		1234567: "12345.67",
+	}
+	for amount, expected := range cases {
+		payload, err := json.Marshal(amount)
+		if err != nil || string(payload) != expected {
+			t.Fatalf("marshal %d: got %s (%v), want %s", amount, payload, err, expected)
+		}
+		var decoded Money
+		if err := json.Unmarshal(payload, &decoded); err != nil || decoded != amount {
+			t.Fatalf("unmarshal %s: got %d (%v)", payload, decoded, err)
+		}
+	}
+
+	var rounded Money
+	if err := json.Unmarshal([]byte(`"0.125"`), &rounded); err != nil || rounded != 13 {
+		t.Fatalf("expected half-up rounding to 13 cents, got %d (%v)", rounded, err)
+	}
+	if err := json.Unmarshal([]byte(`"1.2.3"`), &rounded); err == nil {
+		t.Fatalf("expected invalid amount rejected")
+	}
+}
+
+func TestMoneyMulRatioIsExact(t *testing.T) {
+	// 追加投注按 18/10 计算，结果四舍五入到分。
+	if actual := Money(123456789).MulRatio(18, 10); actual != 222222220 {
+		t.Fatalf("unexpected additional prize: %d", actual)
+	}
+	if actual := Money(100).MulRatio(1, 3); actual != 33 {
+		t.Fatalf("unexpected split: %d", actual)
+	}
+	if actual := Money(-5).MulRatio(1, 2); actual != -3 {
+		t.Fatalf("unexpected negative rounding: %d", actual)
+	}
+}
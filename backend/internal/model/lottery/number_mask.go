@@ -0,0 +1,61 @@
+package lottery
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MaxMaskNumber 是位图能表示的最大号码，现有彩种的号码都在该范围内。
+const MaxMaskNumber = 62
+
+// NumberBit 返回单个号码在位图中的位，超出范围时返回 0。
+func NumberBit(number int) int64 {
+	if number < 0 || number > MaxMaskNumber {
+		return 0
+	}
+	return int64(1) << number
+}
+
+// NumberMask 把逗号分隔的号码转换为位图，第 n 位表示包含号码 n。
+// 位图随号码一起写入，按号码筛选和统计时不必再解析字符串。
+func NumberMask(numbers string) int64 {
+	var mask int64
+	for _, part := range strings.Split(numbers, ",") {
+		number, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		mask |= NumberBit(number)
+	}
+	return mask
+}
+
+// MaskNumbers 返回位图包含的号码，按从小到大排列。
+func MaskNumbers(mask int64) []int {
+	numbers := make([]int, 0, bits.OnesCount64(uint64(mask)))
+	for value := uint64(mask); value != 0; value &= value - 1 {
+		numbers = append(numbers, bits.TrailingZeros64(value))
+	}
+	return numbers
+}
+
+func (draw *DrawResult) BeforeSave(tx *gorm.DB) error {
+	draw.RedMask = NumberMask(draw.RedNumbers)
+	draw.BlueMask = NumberMask(draw.BlueNumbers)
+	return nil
+}
+
+func (entry *TicketEntry) BeforeSave(tx *gorm.DB) error {
+	entry.RedMask = NumberMask(entry.RedNumbers)
+	entry.BlueMask = NumberMask(entry.BlueNumbers)
+	return nil
+}
+
+func (entry *RecommendationEntry) BeforeSave(tx *gorm.DB) error {
+	entry.RedMask = NumberMask(entry.RedNumbers)
+	entry.BlueMask = NumberMask(entry.BlueNumbers)
+	return nil
+}
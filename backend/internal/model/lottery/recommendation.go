@@ -11,6 +11,7 @@ import (
 type Recommendation struct {
 	base.BaseModel
 	UserID        *uuid.UUID            `gorm:"type:uuid;index" json:"-"`
+	LedgerID      *uuid.UUID            `gorm:"type:uuid;index" json:"ledgerId,omitempty"`
 	LotteryCode   string                `gorm:"index;size:32" json:"lotteryCode"`
 	Issue         string                `gorm:"index;size:32" json:"issue"`
 	DrawDate      *time.Time            `json:"drawDate"`
@@ -22,7 +23,7 @@ type Recommendation struct {
 	Basis         string                `gorm:"type:text" json:"basis"`
 	RawPayload    string                `gorm:"type:text" json:"rawPayload"`
 	CheckedAt     *time.Time            `json:"checkedAt"`
-	PrizeAmount   float64               `json:"prizeAmount"`
+	PrizeAmount   Money                 `gorm:"column:prize_amount_cents" json:"prizeAmount" swaggertype:"number"`
 	Entries       []RecommendationEntry `json:"entries"`
 }
 
@@ -32,11 +33,13 @@ type RecommendationEntry struct {
 	Sequence         int       `json:"sequence"`
 	RedNumbers       string    `gorm:"size:64" json:"redNumbers"`
 	BlueNumbers      string    `gorm:"size:32" json:"blueNumbers"`
+	RedMask          int64     `gorm:"not null;default:0" json:"-"`
+	BlueMask         int64     `gorm:"not null;default:0" json:"-"`
 	Confidence       float64   `json:"confidence"`
 	Reason           string    `gorm:"type:text" json:"reason"`
 	IsWinning        bool      `json:"isWinning"`
 	PrizeName        string    `gorm:"size:32" json:"prizeName"`
-	PrizeAmount      float64   `json:"prizeAmount"`
+	PrizeAmount      Money     `gorm:"column:prize_amount_cents" json:"prizeAmount" swaggertype:"number"`
 	MatchSummary     string    `gorm:"size:64" json:"matchSummary"`
 }
 
@@ -11,6 +11,7 @@ import (
 type Ticket struct {
 	base.BaseModel
 	UserID           *uuid.UUID    `gorm:"type:uuid;index" json:"-"`
+	LedgerID         *uuid.UUID    `gorm:"type:uuid;index" json:"ledgerId,omitempty"`
 	LotteryCode      string        `gorm:"index;size:32" json:"lotteryCode"`
 	RecommendationID *uuid.UUID    `gorm:"type:uuid;index" json:"recommendationId"`
 	Issue            string        `gorm:"index;size:32" json:"issue"`
@@ -20,11 +21,12 @@ type Ticket struct {
 	ImagePath        string        `gorm:"size:255" json:"imagePath"`
 	RecognizedText   string        `gorm:"type:text" json:"recognizedText"`
 	Status           string        `gorm:"size:32" json:"status"`
-	CostAmount       float64       `json:"costAmount"`
-	PrizeAmount      float64       `json:"prizeAmount"`
+	CostAmount       Money         `gorm:"column:cost_amount_cents" json:"costAmount" swaggertype:"number"`
+	PrizeAmount      Money         `gorm:"column:prize_amount_cents" json:"prizeAmount" swaggertype:"number"`
 	PurchasedAt      time.Time     `json:"purchasedAt"`
 	CheckedAt        *time.Time    `json:"checkedAt"`
 	Notes            string        `gorm:"type:text" json:"notes"`
+	IsSyndicate      bool          `gorm:"index" json:"isSyndicate"`
 	Entries          []TicketEntry `json:"entries"`
 }
 
@@ -34,11 +36,13 @@ type TicketEntry struct {
 	Sequence     int       `json:"sequence"`
 	RedNumbers   string    `gorm:"size:64" json:"redNumbers"`
 	BlueNumbers  string    `gorm:"size:32" json:"blueNumbers"`
+	RedMask      int64     `gorm:"not null;default:0" json:"-"`
+	BlueMask     int64     `gorm:"not null;default:0" json:"-"`
 	Multiple     int       `json:"multiple"`
 	IsAdditional bool      `json:"isAdditional"`
 	IsWinning    bool      `json:"isWinning"`
 	PrizeName    string    `gorm:"size:32" json:"prizeName"`
-	PrizeAmount  float64   `json:"prizeAmount"`
+	PrizeAmount  Money     `gorm:"column:prize_amount_cents" json:"prizeAmount" swaggertype:"number"`
 	MatchSummary string    `gorm:"size:64" json:"matchSummary"`
 }
 
@@ -0,0 +1,23 @@
+package lottery
+
+import (
+	"go-fiber-starter/internal/model/base"
+
+	"github.com/google/uuid"
+)
+
+// TicketShare 记录合买票据的一位参与人。ShareAmount 是出资份额，
+// CostAmount 和 PrizeAmount 是按份额比例分摊后的成本和奖金，判奖后重新计算。
+type TicketShare struct {
+	base.BaseModel
+	TicketID    uuid.UUID  `gorm:"type:uuid;index" json:"ticketId"`
+	UserID      *uuid.UUID `gorm:"type:uuid;index" json:"userId"`
+	Name        string     `gorm:"size:64" json:"name"`
+	ShareAmount Money      `gorm:"column:share_amount_cents" json:"shareAmount" swaggertype:"number"`
+	CostAmount  Money      `gorm:"column:cost_amount_cents" json:"costAmount" swaggertype:"number"`
+	PrizeAmount Money      `gorm:"column:prize_amount_cents" json:"prizeAmount" swaggertype:"number"`
+}
+
+func (TicketShare) TableName() string {
+	return "ticket_shares"
+}
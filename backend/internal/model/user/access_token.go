@@ -0,0 +1,64 @@
+package user
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"go-fiber-starter/internal/model/base"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScopeRead                 = "read"
+	ScopeTicketsWrite         = "tickets:write"
+	ScopeRecommendationsWrite = "recommendations:write"
+	ScopeDrawsSync            = "draws:sync"
+)
+
+// AllScopes 列出个人访问令牌可申请的全部权限范围。
+var AllScopes = []string{ScopeRead, ScopeTicketsWrite, ScopeRecommendationsWrite, ScopeDrawsSync}
+
+// AccessToken 是供脚本和快捷指令使用的个人访问令牌，只保存令牌摘要，明文仅在创建时返回一次。
+type AccessToken struct {
+	base.BaseModel
+	UserID     uuid.UUID  `gorm:"type:uuid;index" json:"userId"`
+	Name       string     `gorm:"size:64" json:"name"`
+	TokenHash  string     `gorm:"size:64;uniqueIndex" json:"-"`
+	TokenHint  string     `gorm:"size:16" json:"tokenHint"`
+	Scopes     string     `gorm:"size:255" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	LastUsedIP string     `gorm:"size:64" json:"lastUsedIp"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+}
+
+func (AccessToken) TableName() string {
+	return "user_access_tokens"
+}
+
+func (t AccessToken) IsActive(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+func (t AccessToken) ScopeList() []string {
+	result := make([]string, 0)
+	for _, scope := range strings.Split(t.Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
+
+func (t AccessToken) HasScope(scope string) bool {
+	return slices.Contains(t.ScopeList(), scope)
+}
+
+func IsValidScope(scope string) bool {
+	return slices.Contains(AllScopes, scope)
+}
@@ -0,0 +1,29 @@
+package user
+
+import (
+	"time"
+
+	"go-fiber-starter/internal/model/base"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey 记录带 Idempotency-Key 请求头的写请求：Fingerprint 是请求方法、路径和请求体的摘要，
+// 请求成功后保存响应，保留期内相同的重试直接返回原响应。CompletedAt 为空表示请求仍在处理中。
+type IdempotencyKey struct {
+	base.BaseModel
+	UserID       uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_idempotency_keys_user_key" json:"userId"`
+	Key          string     `gorm:"column:idempotency_key;size:128;uniqueIndex:idx_idempotency_keys_user_key" json:"key"`
+	Method       string     `gorm:"size:16" json:"method"`
+	Path         string     `gorm:"size:255" json:"path"`
+	Fingerprint  string     `gorm:"size:64" json:"-"`
+	StatusCode   int        `json:"statusCode"`
+	ContentType  string     `gorm:"size:128" json:"-"`
+	ResponseBody []byte     `json:"-"`
+	CompletedAt  *time.Time `json:"completedAt"`
+	ExpiresAt    time.Time  `gorm:"index" json:"expiresAt"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
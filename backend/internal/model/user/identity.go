@@ -0,0 +1,23 @@
+package user
+
+import (
+	"time"
+
+	"go-fiber-starter/internal/model/base"
+
+	"github.com/google/uuid"
+)
+
+// Identity 把外部身份提供方的账号（Issuer + Subject）绑定到本地用户。
+type Identity struct {
+	base.BaseModel
+	UserID      uuid.UUID `gorm:"type:uuid;index" json:"userId"`
+	Issuer      string    `gorm:"size:255;uniqueIndex:idx_user_identities_issuer_subject" json:"issuer"`
+	Subject     string    `gorm:"size:255;uniqueIndex:idx_user_identities_issuer_subject" json:"subject"`
+	Email       string    `gorm:"size:255" json:"email"`
+	LastLoginAt time.Time `json:"lastLoginAt"`
+}
+
+func (Identity) TableName() string {
+	return "user_identities"
+}
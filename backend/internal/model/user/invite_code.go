@@ -0,0 +1,25 @@
+package user
+
+import (
+	"time"
+
+	"go-fiber-starter/internal/model/base"
+
+	"github.com/google/uuid"
+)
+
+// InviteCode 是管理员发放的注册邀请码，MaxUses 为 0 表示不限次数。
+type InviteCode struct {
+	base.BaseModel
+	Code      string     `gorm:"size:32;uniqueIndex" json:"code"`
+	CreatedBy uuid.UUID  `gorm:"type:uuid;index" json:"createdBy"`
+	Note      string     `gorm:"size:255" json:"note"`
+	MaxUses   int        `json:"maxUses"`
+	UsedCount int        `json:"usedCount"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	RevokedAt *time.Time `json:"revokedAt"`
+}
+
+func (InviteCode) TableName() string {
+	return "user_invite_codes"
+}
@@ -0,0 +1,30 @@
+package user
+
+import (
+	"time"
+
+	"go-fiber-starter/internal/model/base"
+
+	"github.com/google/uuid"
+)
+
+// Session 对应一次登录，保存当前有效的刷新令牌摘要，用于轮换、注销和会话列表。
+type Session struct {
+	base.BaseModel
+	UserID            uuid.UUID  `gorm:"type:uuid;index" json:"userId"`
+	RefreshTokenHash  string     `gorm:"size:64;uniqueIndex" json:"-"`
+	PreviousTokenHash string     `gorm:"size:64;index" json:"-"`
+	UserAgent         string     `gorm:"size:255" json:"userAgent"`
+	IP                string     `gorm:"size:64" json:"ip"`
+	ExpiresAt         time.Time  `json:"expiresAt"`
+	LastUsedAt        time.Time  `json:"lastUsedAt"`
+	RevokedAt         *time.Time `json:"revokedAt"`
+}
+
+func (Session) TableName() string {
+	return "user_sessions"
+}
+
+func (s Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -11,8 +11,25 @@ import (
 	"go-fiber-starter/internal/model/base"
 )
 
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
 type User struct {
 	base.BaseModel
-	Username string `gorm:"uniqueIndex;size:64" json:"username" example:"admin"`
-	Password string `json:"-" example:"123456"`
+	Username    string `gorm:"uniqueIndex;size:64" json:"username" example:"admin"`
+	DisplayName string `gorm:"size:64" json:"displayName" example:"小王"`
+	Password    string `json:"-" example:"123456"`
+	Role        string `gorm:"size:16;default:user" json:"role" example:"user"`
+	Disabled    bool   `gorm:"default:false" json:"disabled" example:"false"`
+	Language    string `gorm:"size:16" json:"language" example:"zh-CN"`
+}
+
+func (u User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+func IsValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleUser
 }
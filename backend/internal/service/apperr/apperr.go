@@ -0,0 +1,187 @@
+// Package apperr 定义服务层使用的类型化错误。每个错误带有稳定的错误码和分类，
+// 接口层统一按分类映射 HTTP 状态码，客户端按错误码区分具体原因。
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// Kind 表示错误分类，决定返回给客户端的 HTTP 状态码。
+type Kind string
+
+const (
+	KindInvalid         Kind = "invalid"
+	KindUnauthorized    Kind = "unauthorized"
+	KindForbidden       Kind = "forbidden"
+	KindNotFound        Kind = "not_found"
+	KindConflict        Kind = "conflict"
+	KindUnprocessable   Kind = "unprocessable"
+	KindTooManyRequests Kind = "too_many_requests"
+	KindUpstream        Kind = "upstream"
+	KindInternal        Kind = "internal"
+)
+
+// 通用错误码，业务错误码由各服务在声明错误时给出。
+const (
+	CodeInvalidRequest  = "INVALID_REQUEST"
+	CodeUnauthorized    = "UNAUTHORIZED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeUnprocessable   = "UNPROCESSABLE"
+	CodeTooManyRequests = "TOO_MANY_REQUESTS"
+	CodeUpstream        = "UPSTREAM_ERROR"
+	CodeInternal        = "INTERNAL_ERROR"
+)
+
+var (
+	ErrInvalidRequest = New(KindInvalid, CodeInvalidRequest, "参数不正确")
+	ErrUnauthorized   = New(KindUnauthorized, CodeUnauthorized, "认证失败，请先登录")
+	ErrNotFound       = New(KindNotFound, CodeNotFound, "记录不存在")
+)
+
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	cause   error
+}
+
+func New(kind Kind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Invalid 用于请求参数缺失或格式错误。
+func Invalid(code string, format string, args ...any) *Error {
+	return New(KindInvalid, code, fmt.Sprintf(format, args...))
+}
+
+// Unprocessable 用于参数格式正确但不满足业务规则的请求，例如号码超出范围。
+func Unprocessable(code string, format string, args ...any) *Error {
+	return New(KindUnprocessable, code, fmt.Sprintf(format, args...))
+}
+
+func NotFound(code string, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+// Upstream 包装调用外部服务（开奖数据源、OCR、模型）失败的错误，响应为 502。
+// 错误链中已有类型化错误时沿用其分类和错误码，只保留外层的完整描述。
+func Upstream(code string, err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Kind != KindInternal {
+		return &Error{Kind: appErr.Kind, Code: appErr.Code, cause: err}
+	}
+	return &Error{Kind: KindUpstream, Code: code, cause: err}
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.cause == nil:
+		return e.Message
+	case e.Message == "":
+		return e.cause.Error()
+	default:
+		return e.Message + ": " + e.cause.Error()
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is 按错误码比较，WithMessage、Wrap 派生出的错误仍能用 errors.Is 匹配原始错误。
+func (e *Error) Is(target error) bool {
+	var other *Error
+	if !errors.As(target, &other) {
+		return false
+	}
+	return other.Code == e.Code
+}
+
+// WithMessage 返回错误码不变、消息替换为具体原因的副本。
+func (e *Error) WithMessage(format string, args ...any) *Error {
+	clone := *e
+	clone.Message = fmt.Sprintf(format, args...)
+	return &clone
+}
+
+// Wrap 返回附带底层原因的副本，便于日志定位，错误码和分类保持不变。
+func (e *Error) Wrap(cause error) *Error {
+	clone := *e
+	clone.cause = cause
+	return &clone
+}
+
+// From 从错误链中取出类型化错误；记录不存在的数据库错误视为 404，其余未分类错误返回 nil。
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Status 返回错误分类对应的 HTTP 状态码。
+func Status(kind Kind) int {
+	switch kind {
+	case KindInvalid:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	case KindTooManyRequests:
+		return http.StatusTooManyRequests
+	case KindUpstream:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CodeForStatus 为没有业务错误码的响应按 HTTP 状态码给出通用错误码。
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessable
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusBadGateway:
+		return CodeUpstream
+	default:
+		if status >= http.StatusInternalServerError {
+			return CodeInternal
+		}
+		return ""
+	}
+}
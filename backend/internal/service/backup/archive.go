@@ -0,0 +1,316 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go-fiber-starter/pkg/db"
+)
+
+// writeArchive 依次写入数据库文件、上传目录和清单，文件摘要在写入时同步计算。
+func writeArchive(target string, workDir string, databaseFiles []string, uploadDir string, manifest *Manifest) error {
+	file, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("创建备份文件失败: %w", err)
+	}
+	defer file.Close()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifest.Files = make([]FileManifest, 0, len(databaseFiles))
+	for _, name := range databaseFiles {
+		entry, err := addArchiveFile(tarWriter, filepath.Join(workDir, filepath.FromSlash(name)), name)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+
+	if uploadDir != "" {
+		err := filepath.WalkDir(uploadDir, func(current string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+			relative, err := filepath.Rel(uploadDir, current)
+			if err != nil {
+				return err
+			}
+			item, err := addArchiveFile(tarWriter, current, path.Join(uploadsDir, filepath.ToSlash(relative)))
+			if err != nil {
+				return err
+			}
+			manifest.Files = append(manifest.Files, item)
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("打包上传目录失败: %w", err)
+		}
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{Name: manifestName, Mode: 0o644, Size: int64(len(content)), ModTime: manifest.CreatedAt}); err != nil {
+		return err
+	}
+	if _, err := tarWriter.Write(content); err != nil {
+		return err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func addArchiveFile(writer *tar.Writer, source string, name string) (FileManifest, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return FileManifest{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return FileManifest{}, err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return FileManifest{}, err
+	}
+	header.Name = name
+	if err := writer.WriteHeader(header); err != nil {
+		return FileManifest{}, err
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(writer, hash), file)
+	if err != nil {
+		return FileManifest{}, fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	return FileManifest{Path: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// extractArchive 把归档解压到 dest 并核对清单：只接受 database/、uploads/ 下的普通文件和清单本身，
+// 每个文件的大小和摘要都必须与清单一致，且不能多出或缺少文件。
+func extractArchive(source string, dest string) (*Manifest, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return nil, ErrInvalidArchive.WithMessage("备份文件不是有效的 gzip 归档: %v", err)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+
+	extracted := make(map[string]FileManifest)
+	var manifest *Manifest
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidArchive.WithMessage("读取备份归档失败: %v", err)
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		name, ok := archiveEntryName(header)
+		if !ok {
+			return nil, ErrInvalidArchive.WithMessage("备份中包含不允许的条目 %q", header.Name)
+		}
+
+		if name == manifestName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tarReader).Decode(manifest); err != nil {
+				return nil, ErrInvalidArchive.WithMessage("备份清单格式不正确: %v", err)
+			}
+			continue
+		}
+		if _, exists := extracted[name]; exists {
+			return nil, ErrInvalidArchive.WithMessage("备份中 %s 重复出现", name)
+		}
+		entry, err := extractArchiveFile(tarReader, filepath.Join(dest, filepath.FromSlash(name)), name)
+		if err != nil {
+			return nil, err
+		}
+		extracted[name] = entry
+	}
+
+	if manifest == nil {
+		return nil, ErrInvalidArchive.WithMessage("备份中缺少 %s", manifestName)
+	}
+	if manifest.FormatVersion != formatVersion {
+		return nil, ErrIncompatibleDump.WithMessage("不支持的备份格式版本 %d", manifest.FormatVersion)
+	}
+	for _, expected := range manifest.Files {
+		actual, ok := extracted[expected.Path]
+		if !ok {
+			return nil, ErrInvalidArchive.WithMessage("备份中缺少文件 %s", expected.Path)
+		}
+		if actual.Size != expected.Size || actual.SHA256 != expected.SHA256 {
+			return nil, ErrInvalidArchive.WithMessage("文件 %s 的校验和与清单不一致", expected.Path)
+		}
+		delete(extracted, expected.Path)
+	}
+	for name := range extracted {
+		return nil, ErrInvalidArchive.WithMessage("文件 %s 未登记在清单中", name)
+	}
+	return manifest, nil
+}
+
+// archiveEntryName 规范化条目名称，拒绝绝对路径、上级目录和链接等可能写到解压目录之外的条目。
+func archiveEntryName(header *tar.Header) (string, bool) {
+	if header.Typeflag != tar.TypeReg {
+		return "", false
+	}
+	name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	if name == manifestName {
+		return name, true
+	}
+	if strings.HasPrefix(name, databaseDir+"/") || strings.HasPrefix(name, uploadsDir+"/") {
+		return name, true
+	}
+	return "", false
+}
+
+func extractArchiveFile(reader io.Reader, target string, name string) (FileManifest, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return FileManifest{}, err
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return FileManifest{}, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), reader)
+	if err != nil {
+		return FileManifest{}, ErrInvalidArchive.WithMessage("解压 %s 失败: %v", name, err)
+	}
+	if err := file.Close(); err != nil {
+		return FileManifest{}, err
+	}
+	return FileManifest{Path: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// verifyContents 在文件摘要之外核对数据库内容：迁移版本不能比当前程序新，
+// SQLite 快照需要通过完整性检查，各表行数需要与清单一致。
+func verifyContents(dir string, manifest *Manifest) error {
+	if manifest.SchemaVersion <= 0 {
+		return ErrIncompatibleDump.WithMessage("备份没有记录有效的迁移版本")
+	}
+	if latest := db.LatestSchemaVersion(); manifest.SchemaVersion > latest {
+		return ErrIncompatibleDump.WithMessage("备份的迁移版本 %d 高于当前程序支持的 %d，请升级程序后再恢复", manifest.SchemaVersion, latest)
+	}
+	tables, err := db.DataTables()
+	if err != nil {
+		return err
+	}
+	known := make(map[string]struct{}, len(tables))
+	for _, table := range tables {
+		known[table.Name] = struct{}{}
+	}
+	for _, table := range manifest.Tables {
+		if _, ok := known[table.Name]; !ok {
+			return ErrIncompatibleDump.WithMessage("备份中包含未知的数据表 %s", table.Name)
+		}
+	}
+
+	switch manifest.Format {
+	case FormatSQLite:
+		return verifySQLiteSnapshot(filepath.Join(dir, databaseDir, sqliteFileName), manifest)
+	case FormatJSONL:
+		for _, table := range manifest.Tables {
+			rows, err := countLines(filepath.Join(dir, databaseDir, table.Name+".jsonl"))
+			if err != nil {
+				return ErrInvalidArchive.WithMessage("读取 %s 导出文件失败: %v", table.Name, err)
+			}
+			if rows != table.Rows {
+				return ErrInvalidArchive.WithMessage("%s 导出 %d 行，清单记录 %d 行", table.Name, rows, table.Rows)
+			}
+		}
+		return nil
+	default:
+		return ErrIncompatibleDump.WithMessage("不支持的数据库备份格式 %q", manifest.Format)
+	}
+}
+
+func verifySQLiteSnapshot(path string, manifest *Manifest) error {
+	snapshot, err := db.OpenSQLiteFile(path)
+	if err != nil {
+		return ErrInvalidArchive.WithMessage("打开 SQLite 快照失败: %v", err)
+	}
+	defer db.CloseDatabase(snapshot)
+
+	if err := db.CheckSQLiteIntegrity(snapshot); err != nil {
+		return ErrInvalidArchive.WithMessage("%v", err)
+	}
+	version, err := db.SchemaVersion(snapshot)
+	if err != nil {
+		return ErrInvalidArchive.WithMessage("读取快照迁移版本失败: %v", err)
+	}
+	if version != manifest.SchemaVersion {
+		return ErrInvalidArchive.WithMessage("快照迁移版本 %d 与清单记录的 %d 不一致", version, manifest.SchemaVersion)
+	}
+	for _, table := range manifest.Tables {
+		rows, err := db.CountRows(snapshot, table.Name)
+		if err != nil {
+			return ErrInvalidArchive.WithMessage("统计 %s 行数失败: %v", table.Name, err)
+		}
+		if rows != table.Rows {
+			return ErrInvalidArchive.WithMessage("%s 有 %d 行，清单记录 %d 行", table.Name, rows, table.Rows)
+		}
+	}
+	return nil
+}
+
+func countLines(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	lines := int64(0)
+	for {
+		_, err := reader.ReadSlice('\n')
+		if err == nil {
+			lines++
+			continue
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return lines, nil
+		}
+		return 0, err
+	}
+}
@@ -0,0 +1,323 @@
+// Package backup 负责数据库与上传目录的备份、保留策略和恢复。
+// 每份备份是一个 tar.gz 归档：database/ 下是数据库内容，uploads/ 下是上传文件，
+// 最后写入的 manifest.json 记录迁移版本、每张表的行数和每个文件的 SHA-256，恢复前据此校验。
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+	"go-fiber-starter/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+const (
+	formatVersion = 1
+
+	// FormatSQLite 表示数据库部分是 VACUUM INTO 生成的 SQLite 文件。
+	FormatSQLite = "sqlite"
+	// FormatJSONL 表示数据库部分是逐表导出的 JSON Lines，可以恢复到任意支持的数据库。
+	FormatJSONL = "jsonl"
+
+	archivePrefix    = "lottery-backup-"
+	archiveSuffix    = ".tar.gz"
+	archiveTimestamp = "20060102-150405"
+	manifestName     = "manifest.json"
+	databaseDir      = "database"
+	uploadsDir       = "uploads"
+	sqliteFileName   = "lottery.sqlite"
+)
+
+var (
+	ErrBackupRunning    = apperr.New(apperr.KindConflict, "BACKUP_RUNNING", "已有备份或恢复任务正在执行，请稍后再试")
+	ErrBackupNotFound   = apperr.New(apperr.KindNotFound, "BACKUP_NOT_FOUND", "备份文件不存在")
+	ErrInvalidArchive   = apperr.New(apperr.KindUnprocessable, "BACKUP_INVALID", "备份文件校验失败")
+	ErrIncompatibleDump = apperr.New(apperr.KindUnprocessable, "BACKUP_INCOMPATIBLE", "备份与当前数据库不兼容")
+)
+
+// running 保证同一进程内备份与恢复不会并发执行。
+var running sync.Mutex
+
+// Manifest 是归档内的清单，记录恢复时需要校验的全部信息。
+type Manifest struct {
+	FormatVersion int             `json:"formatVersion"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	Driver        string          `json:"driver"`
+	Format        string          `json:"format"`
+	SchemaVersion int64           `json:"schemaVersion"`
+	Tables        []TableManifest `json:"tables"`
+	Files         []FileManifest  `json:"files"`
+}
+
+// TableManifest 记录备份时每张表的行数。
+type TableManifest struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+}
+
+// FileManifest 记录归档内每个文件的大小和 SHA-256。
+type FileManifest struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Archive 是备份目录中的一份备份文件。
+type Archive struct {
+	Name      string    `json:"name" example:"lottery-backup-20260316-043000.tar.gz"`
+	Size      int64     `json:"size" example:"1048576"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateResult 是一次备份的结果，Pruned 为按保留策略删除的旧备份。
+type CreateResult struct {
+	Archive  Archive  `json:"archive"`
+	Manifest Manifest `json:"manifest"`
+	Pruned   []string `json:"pruned"`
+}
+
+// Create 备份当前数据库和上传目录，写入完成后按保留策略清理旧备份。
+// 归档先写到 .partial 临时文件，全部写完才改名，中途失败不会留下不完整的备份。
+func Create(ctx context.Context) (*CreateResult, error) {
+	if !running.TryLock() {
+		return nil, ErrBackupRunning
+	}
+	defer running.Unlock()
+	return create(ctx, time.Now(), defaultFormat())
+}
+
+// defaultFormat 按当前驱动选择备份格式：SQLite 使用文件快照，其他数据库逐表导出。
+func defaultFormat() string {
+	if db.IsSQLite() {
+		return FormatSQLite
+	}
+	return FormatJSONL
+}
+
+func create(ctx context.Context, now time.Time, format string) (*CreateResult, error) {
+	dir := config.Current.Backup.Directory()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建备份目录失败: %w", err)
+	}
+	workDir, err := os.MkdirTemp(dir, ".work-")
+	if err != nil {
+		return nil, fmt.Errorf("创建备份临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	manifest := Manifest{FormatVersion: formatVersion, CreatedAt: now, Driver: db.DriverName()}
+	databaseFiles, err := dumpDatabase(ctx, workDir, format, &manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	name := archivePrefix + now.Format(archiveTimestamp) + archiveSuffix
+	target := filepath.Join(dir, name)
+	if _, err := os.Stat(target); err == nil {
+		return nil, ErrBackupRunning.WithMessage("同一秒内已经生成过备份，请稍后再试")
+	}
+	partial := target + ".partial"
+	if err := writeArchive(partial, workDir, databaseFiles, config.Current.Storage.UploadDir, &manifest); err != nil {
+		_ = os.Remove(partial)
+		return nil, err
+	}
+	if err := os.Rename(partial, target); err != nil {
+		_ = os.Remove(partial)
+		return nil, fmt.Errorf("保存备份文件失败: %w", err)
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	result := &CreateResult{
+		Archive:  Archive{Name: name, Size: info.Size(), CreatedAt: now},
+		Manifest: manifest,
+	}
+	pruned, err := prune(now)
+	if err != nil {
+		logger.WarnContext(ctx, "清理旧备份失败: %v", err)
+	}
+	result.Pruned = pruned
+	return result, nil
+}
+
+// dumpDatabase 把数据库内容写到 workDir/database 下，返回相对 workDir 的文件路径。
+// SQLite 快照由 VACUUM INTO 生成；逐表导出在同一个事务中完成，保证各表数据一致。
+func dumpDatabase(ctx context.Context, workDir string, format string, manifest *Manifest) ([]string, error) {
+	if err := os.MkdirAll(filepath.Join(workDir, databaseDir), 0o755); err != nil {
+		return nil, err
+	}
+	manifest.Format = format
+	if format == FormatSQLite {
+		relative := filepath.ToSlash(filepath.Join(databaseDir, sqliteFileName))
+		snapshot := filepath.Join(workDir, relative)
+		if err := db.SnapshotSQLite(db.DB.WithContext(ctx), snapshot); err != nil {
+			return nil, err
+		}
+		source, err := db.OpenSQLiteFile(snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("打开 SQLite 快照失败: %w", err)
+		}
+		defer db.CloseDatabase(source)
+		if err := describeDatabase(source, manifest); err != nil {
+			return nil, err
+		}
+		return []string{relative}, nil
+	}
+
+	files := make([]string, 0)
+	err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := describeDatabase(tx, manifest); err != nil {
+			return err
+		}
+		tables, err := db.DataTables()
+		if err != nil {
+			return err
+		}
+		for index, table := range tables {
+			relative := filepath.ToSlash(filepath.Join(databaseDir, table.Name+".jsonl"))
+			rows, err := exportTable(tx, table, filepath.Join(workDir, relative))
+			if err != nil {
+				return err
+			}
+			manifest.Tables[index].Rows = rows
+			files = append(files, relative)
+		}
+		return nil
+	}, db.SnapshotReadOptions(db.DB))
+	if err != nil {
+		return nil, fmt.Errorf("导出数据库失败: %w", err)
+	}
+	return files, nil
+}
+
+// describeDatabase 把迁移版本和各表行数写入清单。
+func describeDatabase(database *gorm.DB, manifest *Manifest) error {
+	version, err := db.SchemaVersion(database)
+	if err != nil {
+		return err
+	}
+	tables, err := db.DataTables()
+	if err != nil {
+		return err
+	}
+	manifest.SchemaVersion = version
+	manifest.Tables = make([]TableManifest, 0, len(tables))
+	for _, table := range tables {
+		rows, err := db.CountRows(database, table.Name)
+		if err != nil {
+			return fmt.Errorf("统计 %s 行数失败: %w", table.Name, err)
+		}
+		manifest.Tables = append(manifest.Tables, TableManifest{Name: table.Name, Rows: rows})
+	}
+	return nil
+}
+
+func exportTable(database *gorm.DB, table db.DataTable, path string) (int64, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	rows := int64(0)
+	if err := db.ExportRows(database, table, func(row map[string]any) error {
+		rows++
+		return encoder.Encode(row)
+	}); err != nil {
+		return 0, fmt.Errorf("导出 %s 失败: %w", table.Name, err)
+	}
+	return rows, file.Close()
+}
+
+// List 按时间倒序返回备份目录中的全部备份。
+func List() ([]Archive, error) {
+	entries, err := os.ReadDir(config.Current.Backup.Directory())
+	if os.IsNotExist(err) {
+		return []Archive{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	archives := make([]Archive, 0, len(entries))
+	for _, entry := range entries {
+		createdAt, ok := parseArchiveName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, Archive{Name: entry.Name(), Size: info.Size(), CreatedAt: createdAt})
+	}
+	sort.Slice(archives, func(left int, right int) bool {
+		return archives[left].CreatedAt.After(archives[right].CreatedAt)
+	})
+	return archives, nil
+}
+
+// prune 按份数和天数清理旧备份，最新的一份始终保留。
+func prune(now time.Time) ([]string, error) {
+	archives, err := List()
+	if err != nil {
+		return nil, err
+	}
+	settings := config.Current.Backup
+	cutoff := now.AddDate(0, 0, -settings.RetentionDays)
+
+	pruned := make([]string, 0)
+	for index, archive := range archives {
+		if index == 0 {
+			continue
+		}
+		expiredByCount := settings.RetentionCount > 0 && index >= settings.RetentionCount
+		expiredByAge := settings.RetentionDays > 0 && archive.CreatedAt.Before(cutoff)
+		if !expiredByCount && !expiredByAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(config.Current.Backup.Directory(), archive.Name)); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, archive.Name)
+	}
+	return pruned, nil
+}
+
+func parseArchiveName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, archivePrefix) || !strings.HasSuffix(name, archiveSuffix) {
+		return time.Time{}, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, archivePrefix), archiveSuffix)
+	createdAt, err := time.ParseInLocation(archiveTimestamp, stamp, time.Local)
+	return createdAt, err == nil
+}
+
+// ResolvePath 把备份名称解析为备份目录中的路径；传入包含目录的路径时原样使用，便于恢复从其他机器拷来的备份。
+func ResolvePath(name string) (string, error) {
+	path := name
+	if filepath.Base(name) == name {
+		path = filepath.Join(config.Current.Backup.Directory(), name)
+	}
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrBackupNotFound
+		}
+		return "", err
+	}
+	return path, nil
+}
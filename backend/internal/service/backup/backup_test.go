@@ -0,0 +1,298 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	userModel "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+	"go-fiber-starter/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+func setupBackupTestEnv(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	prevConfig, prevDB, prevLogger := config.Current, db.DB, logger.Logger
+	t.Cleanup(func() {
+		db.CloseDatabase(db.DB)
+		config.Current, db.DB, logger.Logger = prevConfig, prevDB, prevLogger
+	})
+	logger.Logger = zap.NewNop().Sugar()
+	config.Current.Database = config.DatabaseConfig{Driver: "sqlite", Path: filepath.Join(root, "lottery.sqlite")}
+	config.Current.Storage.UploadDir = filepath.Join(root, "uploads")
+	config.Current.Backup = config.BackupConfig{Dir: filepath.Join(root, "backups")}
+	if err := db.Open(); err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := db.MigrateUp(0); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	writeTestFile(t, filepath.Join(config.Current.Storage.UploadDir, "2026", "ticket.jpg"), "original image")
+	createBackupTestUser(t, "alice")
+	return root
+}
+
+func createBackupTestUser(t *testing.T, username string) {
+	t.Helper()
+	if err := db.DB.Create(&userModel.User{Username: username, Password: "x"}).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+}
+
+func countUsers(t *testing.T) int64 {
+	t.Helper()
+	var count int64
+	if err := db.DB.Model(&userModel.User{}).Count(&count).Error; err != nil {
+		t.Fatalf("count users: %v", err)
+	}
+	return count
+}
+
+func TestCreateVerifyAndRestoreSQLiteSnapshot(t *testing.T) {
+	root := setupBackupTestEnv(t)
+	original := userModel.User{}
+	if err := db.DB.First(&original, "username = ?", "alice").Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+
+	created, err := Create(context.Background())
+	if err != nil {
+		t.Fatalf("create backup: %v", err)
+	}
+	if created.Manifest.Format != FormatSQLite || len(created.Manifest.Files) != 2 {
+		t.Fatalf("unexpected manifest: %+v", created.Manifest)
+	}
+	if _, err := Verify(created.Archive.Name); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	createBackupTestUser(t, "bob")
+	writeTestFile(t, filepath.Join(config.Current.Storage.UploadDir, "2026", "ticket.jpg"), "changed image")
+	db.CloseDatabase(db.DB)
+
+	result, err := Restore(context.Background(), created.Archive.Name)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if result.PreviousDatabase == "" || result.PreviousUploads == "" {
+		t.Fatalf("expected replaced data kept aside: %+v", result)
+	}
+	if err := db.Open(); err != nil {
+		t.Fatalf("reopen db: %v", err)
+	}
+	restored := userModel.User{}
+	if err := db.DB.First(&restored, "username = ?", "alice").Error; err != nil {
+		t.Fatalf("load restored user: %v", err)
+	}
+	if countUsers(t) != 1 || restored.Id != original.Id || !restored.CreatedAt.Equal(original.CreatedAt) {
+		t.Fatalf("expected original user restored, got %+v", restored)
+	}
+	content, err := os.ReadFile(filepath.Join(root, "uploads", "2026", "ticket.jpg"))
+	if err != nil || string(content) != "original image" {
+		t.Fatalf("expected upload restored, got %q (%v)", content, err)
+	}
+}
+
+func TestRestoreRejectsTamperedArchive(t *testing.T) {
+	setupBackupTestEnv(t)
+
+	created, err := Create(context.Background())
+	if err != nil {
+		t.Fatalf("create backup: %v", err)
+	}
+	path := filepath.Join(config.Current.Backup.Directory(), created.Archive.Name)
+	rewriteArchiveEntry(t, path, "uploads/2026/ticket.jpg", "tampered")
+	createBackupTestUser(t, "bob")
+
+	if _, err := Verify(created.Archive.Name); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("expected invalid archive from verify, got %v", err)
+	}
+	if _, err := Restore(context.Background(), created.Archive.Name); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("expected invalid archive from restore, got %v", err)
+	}
+	if countUsers(t) != 2 {
+		t.Fatalf("expected current data untouched after rejected restore")
+	}
+}
+
+func TestRestoreJSONLExportReplacesTables(t *testing.T) {
+	setupBackupTestEnv(t)
+	original := userModel.User{}
+	if err := db.DB.First(&original, "username = ?", "alice").Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+
+	created, err := create(context.Background(), time.Now().Add(-time.Hour), FormatJSONL)
+	if err != nil {
+		t.Fatalf("create jsonl backup: %v", err)
+	}
+	if created.Manifest.Format != FormatJSONL {
+		t.Fatalf("unexpected format %q", created.Manifest.Format)
+	}
+	createBackupTestUser(t, "bob")
+
+	result, err := Restore(context.Background(), created.Archive.Name)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if result.SafetyBackup == "" {
+		t.Fatalf("expected safety backup before importing tables")
+	}
+	restored := userModel.User{}
+	if err := db.DB.First(&restored, "username = ?", "alice").Error; err != nil {
+		t.Fatalf("load restored user: %v", err)
+	}
+	if countUsers(t) != 1 || restored.Id != original.Id || !restored.CreatedAt.Equal(original.CreatedAt) {
+		t.Fatalf("expected tables replaced by export, got %d users (%+v)", countUsers(t), restored)
+	}
+	if _, err := Verify(result.SafetyBackup); err != nil {
+		t.Fatalf("verify safety backup: %v", err)
+	}
+}
+
+func TestImportTablesRollsBackSchemaChangeOnFailure(t *testing.T) {
+	setupBackupTestEnv(t)
+	created, err := create(context.Background(), time.Now().Add(-time.Hour), FormatJSONL)
+	if err != nil {
+		t.Fatalf("create jsonl backup: %v", err)
+	}
+	workDir := t.TempDir()
+	manifest, err := unpack(filepath.Join(config.Current.Backup.Directory(), created.Archive.Name), workDir)
+	if err != nil {
+		t.Fatalf("unpack: %v", err)
+	}
+	createBackupTestUser(t, "bob")
+
+	// 备份来自较旧的版本，且导出文件在导入时才会发现的错误，迁移回滚和清空都不应生效。
+	latest := db.LatestSchemaVersion()
+	manifest.SchemaVersion = latest - 1
+	writeTestFile(t, filepath.Join(workDir, databaseDir, "users.jsonl"), "{\"id\":\n")
+	if err := importTables(context.Background(), filepath.Join(workDir, databaseDir), manifest); err == nil {
+		t.Fatalf("expected import to fail")
+	}
+	if version, err := db.SchemaVersion(db.DB); err != nil || version != latest {
+		t.Fatalf("expected schema to stay at %d after failed import, got %d (%v)", latest, version, err)
+	}
+	if !db.DB.Migrator().HasColumn("ticket_entries", "red_mask") {
+		t.Fatalf("expected columns dropped by the rolled back migration to remain")
+	}
+	if countUsers(t) != 2 {
+		t.Fatalf("expected current data untouched after failed import, got %d users", countUsers(t))
+	}
+
+	manifest.SchemaVersion = 0
+	if err := verifyContents(workDir, manifest); !errors.Is(err, ErrIncompatibleDump) {
+		t.Fatalf("expected manifest without schema version to be rejected, got %v", err)
+	}
+	manifest.SchemaVersion = latest + 1
+	if err := verifyContents(workDir, manifest); !errors.Is(err, ErrIncompatibleDump) {
+		t.Fatalf("expected newer schema version to be rejected, got %v", err)
+	}
+}
+
+func TestPruneKeepsNewestWithinRetention(t *testing.T) {
+	setupBackupTestEnv(t)
+	config.Current.Backup.RetentionCount = 3
+	config.Current.Backup.RetentionDays = 10
+
+	now := time.Date(2026, 3, 20, 4, 30, 0, 0, time.Local)
+	dir := config.Current.Backup.Directory()
+	for _, days := range []int{0, 1, 2, 3, 20} {
+		name := archivePrefix + now.AddDate(0, 0, -days).Format(archiveTimestamp) + archiveSuffix
+		writeTestFile(t, filepath.Join(dir, name), "archive")
+	}
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "keep")
+
+	pruned, err := prune(now)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if len(pruned) != 2 {
+		t.Fatalf("expected 2 archives pruned, got %v", pruned)
+	}
+	archives, err := List()
+	if err != nil || len(archives) != 3 || !archives[0].CreatedAt.Equal(now) {
+		t.Fatalf("unexpected remaining archives %+v (%v)", archives, err)
+	}
+
+	config.Current.Backup.RetentionCount = 0
+	config.Current.Backup.RetentionDays = 1
+	if _, err := prune(now.AddDate(1, 0, 0)); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if archives, _ := List(); len(archives) != 1 {
+		t.Fatalf("expected newest archive always kept, got %+v", archives)
+	}
+}
+
+// rewriteArchiveEntry 替换归档中某个文件的内容而保留原清单，模拟备份文件被篡改或损坏。
+func rewriteArchiveEntry(t *testing.T, path string, name string, content string) {
+	t.Helper()
+	source, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read archive: %v", err)
+	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(source))
+	if err != nil {
+		t.Fatalf("open gzip: %v", err)
+	}
+	reader := tar.NewReader(gzipReader)
+
+	buffer := bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(&buffer)
+	writer := tar.NewWriter(gzipWriter)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read entry: %v", err)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("read entry data: %v", err)
+		}
+		if header.Name == name {
+			data = []byte(content)
+			header.Size = int64(len(data))
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if _, err := writer.Write(data); err != nil {
+			t.Fatalf("write data: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("close gzip: %v", err)
+	}
+	if err := os.WriteFile(path, buffer.Bytes(), 0o644); err != nil {
+		t.Fatalf("write archive: %v", err)
+	}
+}
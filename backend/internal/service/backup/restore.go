@@ -0,0 +1,277 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+	"go-fiber-starter/pkg/util"
+
+	"gorm.io/gorm"
+)
+
+const restoredSuffix = ".before-restore-"
+
+// RestoreResult 描述一次恢复：被替换下来的数据库文件和上传目录会改名保留，
+// 逐表导入前生成的安全备份记录在 SafetyBackup 中，确认无误后可以手动删除。
+type RestoreResult struct {
+	Manifest         Manifest `json:"manifest"`
+	PreviousDatabase string   `json:"previousDatabase,omitempty"`
+	PreviousUploads  string   `json:"previousUploads,omitempty"`
+	SafetyBackup     string   `json:"safetyBackup,omitempty"`
+}
+
+// Verify 解压备份到临时目录并完整校验，不修改任何数据。
+func Verify(name string) (*Manifest, error) {
+	archive, err := ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	workDir, err := makeWorkDir(".verify-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+	return unpack(archive, workDir)
+}
+
+func unpack(archive string, workDir string) (*Manifest, error) {
+	manifest, err := extractArchive(archive, workDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyContents(workDir, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Restore 用备份替换当前数据库和上传目录，只在归档全部校验通过后才开始替换。
+// SQLite 快照直接替换数据库文件，需要先停止服务；JSONL 备份在一个事务中把目标库迁移到备份时的版本，
+// 再清空并导入全部表，任何一步失败都会连同表结构变更一起回滚，之后再替换上传目录。
+func Restore(ctx context.Context, name string) (*RestoreResult, error) {
+	if !running.TryLock() {
+		return nil, ErrBackupRunning
+	}
+	defer running.Unlock()
+
+	archive, err := ResolvePath(name)
+	if err != nil {
+		return nil, err
+	}
+	workDir, err := makeWorkDir(".restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+	manifest, err := unpack(archive, workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	suffix := restoredSuffix + now.Format(archiveTimestamp)
+	result := &RestoreResult{Manifest: *manifest}
+	switch manifest.Format {
+	case FormatSQLite:
+		if !db.IsSQLite() {
+			return nil, ErrIncompatibleDump.WithMessage("SQLite 快照只能恢复到 SQLite 数据库，当前驱动为 %s", db.DriverName())
+		}
+		previous, err := replaceSQLiteFile(filepath.Join(workDir, databaseDir, sqliteFileName), config.Current.Database.Path, suffix)
+		if err != nil {
+			return nil, err
+		}
+		result.PreviousDatabase = previous
+	case FormatJSONL:
+		if db.DB == nil {
+			if err := db.Open(); err != nil {
+				return nil, fmt.Errorf("连接数据库失败: %w", err)
+			}
+		}
+		safety, err := create(ctx, now, defaultFormat())
+		if err != nil {
+			return nil, fmt.Errorf("恢复前备份当前数据失败: %w", err)
+		}
+		result.SafetyBackup = safety.Archive.Name
+		if err := importTables(ctx, filepath.Join(workDir, databaseDir), manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	previous, err := replaceUploads(filepath.Join(workDir, uploadsDir), config.Current.Storage.UploadDir, suffix)
+	if err != nil {
+		return nil, err
+	}
+	result.PreviousUploads = previous
+	return result, nil
+}
+
+func makeWorkDir(pattern string) (string, error) {
+	dir := config.Current.Backup.Directory()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("创建备份目录失败: %w", err)
+	}
+	return os.MkdirTemp(dir, pattern)
+}
+
+// importTables 在一个事务中把表结构迁移到备份时的版本，按依赖倒序清空全部表，再按依赖顺序导入，
+// 导入后逐表核对行数。迁移与导入同在一个事务中，导入失败时数据库保持恢复前的版本和数据。
+func importTables(ctx context.Context, dir string, manifest *Manifest) error {
+	tables, err := db.DataTables()
+	if err != nil {
+		return err
+	}
+	expected := make(map[string]int64, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		expected[table.Name] = table.Rows
+	}
+
+	return db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := db.MigrateTo(tx, manifest.SchemaVersion); err != nil {
+			return fmt.Errorf("准备表结构失败: %w", err)
+		}
+		if err := db.ClearTables(tx, tables); err != nil {
+			return err
+		}
+		for _, table := range tables {
+			rows, ok := expected[table.Name]
+			if !ok {
+				continue
+			}
+			if err := importTable(tx, table, filepath.Join(dir, table.Name+".jsonl")); err != nil {
+				return err
+			}
+			count, err := db.CountRows(tx, table.Name)
+			if err != nil {
+				return err
+			}
+			if count != rows {
+				return fmt.Errorf("%s 导入后有 %d 行，备份记录 %d 行", table.Name, count, rows)
+			}
+		}
+		return nil
+	})
+}
+
+func importTable(tx *gorm.DB, table db.DataTable, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	decoder.UseNumber()
+	writer := db.NewTableWriter(tx, table)
+	for {
+		row := make(map[string]any)
+		if err := decoder.Decode(&row); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("读取 %s 导出文件失败: %w", table.Name, err)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// replaceSQLiteFile 把当前数据库文件及其日志文件改名保留，再把快照移动到数据库路径，返回保留的文件路径。
+func replaceSQLiteFile(snapshot string, target string, suffix string) (string, error) {
+	if err := util.EnsureDir(target); err != nil {
+		return "", err
+	}
+	previous := ""
+	for _, sidecar := range []string{"", "-journal", "-wal", "-shm"} {
+		current := target + sidecar
+		if _, err := os.Stat(current); err != nil {
+			continue
+		}
+		if err := os.Rename(current, current+suffix); err != nil {
+			return "", fmt.Errorf("保留当前数据库文件失败: %w", err)
+		}
+		if sidecar == "" {
+			previous = current + suffix
+		}
+	}
+	if err := moveFile(snapshot, target); err != nil {
+		return "", fmt.Errorf("写入恢复的数据库文件失败: %w", err)
+	}
+	return previous, nil
+}
+
+// replaceUploads 把当前上传目录改名保留，再换成备份中的上传目录，返回保留的目录路径。
+func replaceUploads(source string, target string, suffix string) (string, error) {
+	if target == "" {
+		return "", nil
+	}
+	if err := os.MkdirAll(source, 0o755); err != nil {
+		return "", err
+	}
+	previous := ""
+	if _, err := os.Stat(target); err == nil {
+		previous = filepath.Clean(target) + suffix
+		if err := os.Rename(target, previous); err != nil {
+			return "", fmt.Errorf("保留当前上传目录失败: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(filepath.Clean(target)), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(source, target); err == nil {
+		return previous, nil
+	}
+	// 备份目录与上传目录不在同一文件系统时无法直接改名，逐个复制。
+	err := filepath.WalkDir(source, func(current string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		relative, err := filepath.Rel(source, current)
+		if err != nil {
+			return err
+		}
+		destination := filepath.Join(target, relative)
+		if entry.IsDir() {
+			return os.MkdirAll(destination, 0o755)
+		}
+		return moveFile(current, destination)
+	})
+	if err != nil {
+		return "", fmt.Errorf("写入恢复的上传目录失败: %w", err)
+	}
+	return previous, nil
+}
+
+func moveFile(source string, target string) error {
+	if err := os.Rename(source, target); err == nil {
+		return nil
+	}
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	output, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(output, input); err != nil {
+		output.Close()
+		return err
+	}
+	if err := output.Close(); err != nil {
+		return err
+	}
+	return os.Remove(source)
+}
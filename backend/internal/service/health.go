@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	lotteryService "go-fiber-starter/internal/service/lottery"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+)
+
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+	HealthStatusDown     = "down"
+	HealthStatusDisabled = "disabled"
+)
+
+// HealthCheck 是单项检查结果。Critical 为 true 的检查失败时实例视为未就绪，外部服务只影响整体状态为 degraded。
+type HealthCheck struct {
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	Critical  bool       `json:"critical"`
+	Message   string     `json:"message,omitempty"`
+	LatencyMs int64      `json:"latencyMs"`
+	CheckedAt *time.Time `json:"checkedAt,omitempty"`
+}
+
+type HealthReport struct {
+	Status string        `json:"status"`
+	Ready  bool          `json:"ready"`
+	Checks []HealthCheck `json:"checks"`
+}
+
+// externalProbe 描述一个按基础地址探测连通性的外部服务。
+type externalProbe struct {
+	name    string
+	baseURL string
+}
+
+type cachedHealthCheck struct {
+	check     HealthCheck
+	expiresAt time.Time
+}
+
+var (
+	externalHealthMu    sync.Mutex
+	externalHealthCache = map[string]cachedHealthCheck{}
+)
+
+// CheckReadiness 依次检查数据库连接、迁移、定时任务和上传目录，按配置附带外部服务的连通性。
+func CheckReadiness(ctx context.Context) HealthReport {
+	checks := []HealthCheck{
+		runHealthCheck("database", true, func() (string, error) {
+			return "", db.Ping(ctx)
+		}),
+		runHealthCheck("migrations", true, checkMigrations),
+		checkScheduler(),
+		runHealthCheck("uploadDir", true, checkUploadDir),
+	}
+	if config.Current.Health.CheckExternal {
+		checks = append(checks, checkExternalServices(ctx)...)
+	}
+	return buildHealthReport(checks)
+}
+
+func buildHealthReport(checks []HealthCheck) HealthReport {
+	report := HealthReport{Status: HealthStatusOK, Ready: true, Checks: checks}
+	for _, check := range checks {
+		if check.Status != HealthStatusDown {
+			continue
+		}
+		if check.Critical {
+			report.Status = HealthStatusDown
+			report.Ready = false
+			continue
+		}
+		if report.Ready {
+			report.Status = HealthStatusDegraded
+		}
+	}
+	return report
+}
+
+func runHealthCheck(name string, critical bool, check func() (string, error)) HealthCheck {
+	startedAt := time.Now()
+	message, err := check()
+	result := HealthCheck{
+		Name:      name,
+		Status:    HealthStatusOK,
+		Critical:  critical,
+		Message:   message,
+		LatencyMs: time.Since(startedAt).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Message = err.Error()
+	}
+	return result
+}
+
+func checkMigrations() (string, error) {
+	missing, err := db.MissingTables()
+	if err != nil {
+		return "", err
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("缺少数据表: %s", strings.Join(missing, ", "))
+	}
+	pending, err := db.PendingMigrations()
+	if err != nil {
+		return "", err
+	}
+	if len(pending) > 0 {
+		names := make([]string, 0, len(pending))
+		for _, item := range pending {
+			names = append(names, fmt.Sprintf("%d_%s", item.Version, item.Name))
+		}
+		return "", fmt.Errorf("有迁移未执行: %s", strings.Join(names, ", "))
+	}
+	return "", nil
+}
+
+func checkScheduler() HealthCheck {
+	enabled, running := lotteryService.SchedulerStatus()
+	if !enabled {
+		return HealthCheck{Name: "scheduler", Status: HealthStatusDisabled, Critical: true, Message: "未配置定时任务"}
+	}
+	if !running {
+		return HealthCheck{Name: "scheduler", Status: HealthStatusDown, Critical: true, Message: "定时任务调度器未运行"}
+	}
+	return HealthCheck{Name: "scheduler", Status: HealthStatusOK, Critical: true}
+}
+
+// checkUploadDir 在上传目录中创建并删除一个临时文件，确认目录存在且可写。
+func checkUploadDir() (string, error) {
+	dir := config.Current.Storage.UploadDir
+	if strings.TrimSpace(dir) == "" {
+		return "", fmt.Errorf("未配置上传目录")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(dir, ".health-*")
+	if err != nil {
+		return "", err
+	}
+	name := file.Name()
+	_, writeErr := file.WriteString("ok")
+	closeErr := file.Close()
+	removeErr := os.Remove(name)
+	for _, err := range []error{writeErr, closeErr, removeErr} {
+		if err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+func externalProbes() []externalProbe {
+	probes := []externalProbe{
+		{name: "jisu", baseURL: config.Current.Jisu.BaseURL},
+		{name: "ai", baseURL: config.Current.AI.BaseURL},
+	}
+	provider := strings.TrimSpace(config.Current.Vision.Provider)
+	if provider == "" || provider == lotteryService.ProviderPaddleOCR {
+		probes = append(probes, externalProbe{name: "paddleocr", baseURL: config.Current.Vision.BaseURL})
+	}
+	return probes
+}
+
+// checkExternalServices 并发探测外部服务，结果按 health.cacheSeconds 缓存，避免频繁的就绪探测放大到外部服务。
+func checkExternalServices(ctx context.Context) []HealthCheck {
+	probes := externalProbes()
+	checks := make([]HealthCheck, len(probes))
+	var wg sync.WaitGroup
+	for index, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checks[index] = cachedExternalCheck(ctx, probe)
+		}()
+	}
+	wg.Wait()
+	return checks
+}
+
+func cachedExternalCheck(ctx context.Context, probe externalProbe) HealthCheck {
+	baseURL := strings.TrimSpace(probe.baseURL)
+	if baseURL == "" {
+		return HealthCheck{Name: probe.name, Status: HealthStatusDisabled, Message: "未配置服务地址"}
+	}
+
+	cacheKey := probe.name + "|" + baseURL
+	now := time.Now()
+	externalHealthMu.Lock()
+	cached, ok := externalHealthCache[cacheKey]
+	externalHealthMu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.check
+	}
+
+	check := runHealthCheck(probe.name, false, func() (string, error) {
+		return probeExternalService(ctx, baseURL)
+	})
+	check.CheckedAt = &now
+	externalHealthMu.Lock()
+	externalHealthCache[cacheKey] = cachedHealthCheck{check: check, expiresAt: now.Add(config.Current.Health.CacheTTL())}
+	externalHealthMu.Unlock()
+	return check
+}
+
+// probeExternalService 只确认服务地址可以建立连接并返回响应，4xx 也视为可达；5xx 视为服务异常。
+func probeExternalService(ctx context.Context, baseURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.Current.Health.Timeout())
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL, nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusInternalServerError {
+		return "", fmt.Errorf("服务返回 %d", response.StatusCode)
+	}
+	return fmt.Sprintf("HTTP %d", response.StatusCode), nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-fiber-starter/pkg/config"
+)
+
+func findHealthCheck(t *testing.T, report HealthReport, name string) HealthCheck {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("health check %s not found in %+v", name, report.Checks)
+	return HealthCheck{}
+}
+
+func TestCheckReadinessReportsMissingTables(t *testing.T) {
+	setupAccountTestDB(t)
+	prevConfig := config.Current
+	t.Cleanup(func() {
+		config.Current = prevConfig
+	})
+	config.Current.Storage.UploadDir = t.TempDir()
+	config.Current.Health.CheckExternal = false
+
+	report := CheckReadiness(context.Background())
+	if report.Ready || report.Status != HealthStatusDown {
+		t.Fatalf("expected not ready with incomplete schema, got %+v", report)
+	}
+	if check := findHealthCheck(t, report, "database"); check.Status != HealthStatusOK {
+		t.Fatalf("expected database ok, got %+v", check)
+	}
+	if check := findHealthCheck(t, report, "uploadDir"); check.Status != HealthStatusOK {
+		t.Fatalf("expected upload dir ok, got %+v", check)
+	}
+	migrations := findHealthCheck(t, report, "migrations")
+	if migrations.Status != HealthStatusDown || !strings.Contains(migrations.Message, "draw_results") {
+		t.Fatalf("expected missing draw_results table, got %+v", migrations)
+	}
+}
+
+func TestExternalHealthChecksAreCachedAndNonCritical(t *testing.T) {
+	prevConfig := config.Current
+	t.Cleanup(func() {
+		config.Current = prevConfig
+		externalHealthCache = map[string]cachedHealthCheck{}
+	})
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	config.Current.Jisu.BaseURL = server.URL
+	config.Current.AI.BaseURL = ""
+	config.Current.Vision.Provider = "openai-compatible"
+	config.Current.Health.CacheSeconds = 60
+
+	for range 2 {
+		checks := checkExternalServices(context.Background())
+		if len(checks) != 2 {
+			t.Fatalf("expected jisu and ai checks, got %+v", checks)
+		}
+		if checks[0].Status != HealthStatusDown || checks[0].Critical {
+			t.Fatalf("expected non-critical jisu failure, got %+v", checks[0])
+		}
+		if checks[1].Status != HealthStatusDisabled {
+			t.Fatalf("expected ai check disabled without base url, got %+v", checks[1])
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected cached probe to hit upstream once, got %d", calls)
+	}
+
+	report := buildHealthReport(append([]HealthCheck{{Name: "database", Status: HealthStatusOK, Critical: true}}, checkExternalServices(context.Background())...))
+	if !report.Ready || report.Status != HealthStatusDegraded {
+		t.Fatalf("expected degraded but ready report, got %+v", report)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxIdempotencyKeyLength = 128
+	// idempotencyPurgeInterval 控制清理过期幂等记录的频率，清理随写请求顺带执行。
+	idempotencyPurgeInterval = 10 * time.Minute
+	// idempotencyPendingTimeout 之后仍未完成的记录视为进程中断遗留，允许同一个键重新执行。
+	idempotencyPendingTimeout = 5 * time.Minute
+)
+
+var (
+	ErrIdempotencyKeyInvalid = apperr.Invalid("IDEMPOTENCY_KEY_INVALID", "Idempotency-Key 不能超过 %d 个字符", maxIdempotencyKeyLength)
+	ErrIdempotencyKeyReused  = apperr.Unprocessable("IDEMPOTENCY_KEY_REUSED", "该 Idempotency-Key 已用于内容不同的请求，请更换新的键")
+	ErrIdempotencyInProgress = apperr.New(apperr.KindConflict, "IDEMPOTENCY_IN_PROGRESS", "相同 Idempotency-Key 的请求正在处理中，请稍后重试")
+)
+
+// idempotencyNow 便于测试替换当前时间。
+var idempotencyNow = time.Now
+
+var (
+	idempotencyPurgeMu   sync.Mutex
+	lastIdempotencyPurge time.Time
+)
+
+// IdempotentRequest 描述一次带 Idempotency-Key 的写请求。
+type IdempotentRequest struct {
+	UserID      string
+	Key         string
+	Method      string
+	Path        string
+	Fingerprint string
+}
+
+// BeginIdempotentRequest 登记一次幂等请求。首次出现的键返回 nil，调用方继续处理请求；
+// 已成功处理过的相同请求返回保存的记录用于重放；键被内容不同的请求复用或仍在处理中时返回错误。
+func BeginIdempotentRequest(request IdempotentRequest) (*model.IdempotencyKey, error) {
+	key := strings.TrimSpace(request.Key)
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, ErrIdempotencyKeyInvalid
+	}
+	userID, err := uuid.Parse(request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	purgeExpiredIdempotencyKeys()
+
+	now := idempotencyNow()
+	existing := model.IdempotencyKey{}
+	err = db.DB.Where("user_id = ? AND idempotency_key = ?", userID, key).First(&existing).Error
+	if err == nil && (!now.Before(existing.ExpiresAt) || isAbandonedIdempotencyKey(existing, now)) {
+		if err := db.DB.Delete(&existing).Error; err != nil {
+			return nil, err
+		}
+		err = gorm.ErrRecordNotFound
+	}
+	if err == nil {
+		return matchIdempotentRequest(existing, request.Fingerprint)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	record := model.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Method:      request.Method,
+		Path:        truncateString(request.Path, 255),
+		Fingerprint: request.Fingerprint,
+		ExpiresAt:   now.Add(config.Current.Idempotency.Retention()),
+	}
+	if createErr := db.DB.Create(&record).Error; createErr != nil {
+		// 并发请求使用同一个键时唯一索引会拒绝后到的插入，此时按已有记录处理。
+		if err := db.DB.Where("user_id = ? AND idempotency_key = ?", userID, key).First(&existing).Error; err != nil {
+			return nil, createErr
+		}
+		return matchIdempotentRequest(existing, request.Fingerprint)
+	}
+	return nil, nil
+}
+
+// CompleteIdempotentRequest 保存成功响应，保留期内相同的重试直接返回该响应。
+func CompleteIdempotentRequest(userID string, key string, statusCode int, contentType string, body []byte) error {
+	now := idempotencyNow()
+	return db.DB.Model(&model.IdempotencyKey{}).
+		Where("user_id = ? AND idempotency_key = ?", userID, strings.TrimSpace(key)).
+		Updates(map[string]any{
+			"status_code":   statusCode,
+			"content_type":  truncateString(contentType, 128),
+			"response_body": append([]byte(nil), body...),
+			"completed_at":  now,
+		}).Error
+}
+
+// ReleaseIdempotentRequest 删除处理失败的请求记录，客户端可以用同一个键重试。
+func ReleaseIdempotentRequest(userID string, key string) error {
+	return db.DB.Where("user_id = ? AND idempotency_key = ? AND completed_at IS NULL", userID, strings.TrimSpace(key)).
+		Delete(&model.IdempotencyKey{}).Error
+}
+
+func matchIdempotentRequest(record model.IdempotencyKey, fingerprint string) (*model.IdempotencyKey, error) {
+	if record.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if record.CompletedAt == nil {
+		return nil, ErrIdempotencyInProgress
+	}
+	return &record, nil
+}
+
+func isAbandonedIdempotencyKey(record model.IdempotencyKey, now time.Time) bool {
+	return record.CompletedAt == nil && now.Sub(record.CreatedAt) > idempotencyPendingTimeout
+}
+
+func purgeExpiredIdempotencyKeys() {
+	idempotencyPurgeMu.Lock()
+	now := idempotencyNow()
+	if now.Sub(lastIdempotencyPurge) < idempotencyPurgeInterval {
+		idempotencyPurgeMu.Unlock()
+		return
+	}
+	lastIdempotencyPurge = now
+	idempotencyPurgeMu.Unlock()
+
+	_ = db.DB.Where("expires_at <= ?", now).Delete(&model.IdempotencyKey{}).Error
+}
@@ -3,6 +3,7 @@ package lottery
 import (
 	"context"
 
+	"go-fiber-starter/internal/metrics"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/logger"
 )
@@ -11,11 +12,13 @@ func Bootstrap() error {
 	if err := SeedLotteryTypes(); err != nil {
 		return err
 	}
+	metrics.SetPaddleOCRSlotCapacity(cap(paddleOCRWorkerSlots))
+	metrics.MustRegister(pendingTicketCollector{})
 	if err := repairLotteryState(); err != nil {
 		return err
 	}
 
-	if hasScheduledLotteries() {
+	if hasScheduledJobs() {
 		go startSyncLoop(context.Background())
 		logger.Info("彩票定时任务已启动")
 	}
@@ -38,7 +41,15 @@ func repairLotteryState() error {
 	return nil
 }
 
-func hasScheduledLotteries() bool {
+// hasScheduledJobs 判断是否需要启动调度器。调用记录写入数据库时总要按保留天数清理，
+// 即使没有配置任何彩种任务也需要启动。
+func hasScheduledJobs() bool {
+	if config.Current.ExternalLogs.Enabled && config.Current.ExternalLogs.Retention() > 0 {
+		return true
+	}
+	if config.Current.Backup.Enabled && config.Current.Backup.Cron != "" {
+		return true
+	}
 	if config.Current.Compensation.Enabled {
 		for _, job := range config.Current.Compensation.Jobs {
 			if job.Enabled && job.Cron != "" && job.Type != "" {
@@ -0,0 +1,235 @@
+package lottery
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxBulkTickets 限制单次批量操作的票据数量，避免一次请求长时间占用事务或开奖数据源。
+const maxBulkTickets = 200
+
+var (
+	ErrBulkTicketsRequired          = apperr.Invalid("BULK_TICKETS_REQUIRED", "请传入票据 ID 列表或筛选条件")
+	ErrBulkTicketsLimit             = apperr.Unprocessable("BULK_TICKETS_LIMIT", "单次批量操作最多 %d 张票据，请缩小筛选范围", maxBulkTickets)
+	ErrTicketRecommendationMismatch = apperr.Unprocessable("TICKET_RECOMMENDATION_MISMATCH", "推荐记录与票据的彩票类型不一致")
+	ErrBulkTicketNotFound           = apperr.NotFound(apperr.CodeNotFound, "票据不存在或无权访问")
+)
+
+// BulkTicketInput 描述批量操作的目标票据：传入 TicketIDs 时按 ID 处理，否则按 Filter 查询当前账本下的票据。
+// Filter 的字段含义与历史票据查询一致，分页和排序字段会被忽略。
+type BulkTicketInput struct {
+	UserID           string
+	LedgerID         string
+	TicketIDs        []string
+	Filter           *TicketQueryOptions
+	RecommendationID string
+}
+
+// BulkTicketItem 是单张票据的处理结果，失败时给出与接口错误响应一致的错误码和消息，
+// 消息由接口层按错误码和请求语言翻译。
+type BulkTicketItem struct {
+	TicketID  string        `json:"ticketId"`
+	Success   bool          `json:"success"`
+	ErrorCode string        `json:"errorCode,omitempty"`
+	Message   string        `json:"message,omitempty"`
+	Ticket    *TicketDetail `json:"ticket,omitempty"`
+}
+
+type BulkTicketResult struct {
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []BulkTicketItem `json:"items"`
+}
+
+// BulkDeleteTickets 在同一个事务中删除多张票据，每张票据使用独立的保存点，单张失败不影响其余票据。
+// 所有删除提交后再统一检查并清理不再被引用的原图。
+func BulkDeleteTickets(input BulkTicketInput) (*BulkTicketResult, error) {
+	owner := Owner{UserID: input.UserID, LedgerID: input.LedgerID}
+	ticketIDs, err := resolveBulkTicketIDs(owner, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []BulkTicketItem
+	imagePaths := make([]string, 0, len(ticketIDs))
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		items = eachBulkTicket(ticketIDs, func(ticketID string) (*TicketDetail, error) {
+			return nil, tx.Transaction(func(savepoint *gorm.DB) error {
+				imagePath, err := deleteTicketRecord(savepoint, owner, ticketID)
+				if err == nil {
+					imagePaths = append(imagePaths, imagePath)
+				}
+				return err
+			})
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	cleanupUnusedTicketImages(imagePaths)
+	return newBulkTicketResult(items), nil
+}
+
+// BulkRecheckTickets 逐张重新判奖。判奖可能需要请求开奖数据源，不放在同一个事务中；
+// 同一批次内每个缺少开奖结果的期号最多同步一次，后续票据复用第一次同步的结果或错误。
+func BulkRecheckTickets(ctx context.Context, input BulkTicketInput) (*BulkTicketResult, error) {
+	owner := Owner{UserID: input.UserID, LedgerID: input.LedgerID}
+	ticketIDs, err := resolveBulkTicketIDs(owner, input)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make(map[string]error)
+	syncOnce := func(ctx context.Context, code string, issue string) error {
+		key := code + ":" + issue
+		if err, attempted := attempts[key]; attempted {
+			return err
+		}
+		_, err := syncTicketDraw(ctx, code, issue)
+		attempts[key] = err
+		return err
+	}
+	items := eachBulkTicket(ticketIDs, func(ticketID string) (*TicketDetail, error) {
+		return recheckTicket(ctx, ticketID, "", owner, syncOnce)
+	})
+	return newBulkTicketResult(items), nil
+}
+
+// BulkLinkTickets 把多张票据关联到同一条推荐记录，RecommendationID 为空时解除关联。
+// 推荐记录的彩种与票据不一致时该票据失败，其余票据照常更新。
+func BulkLinkTickets(input BulkTicketInput) (*BulkTicketResult, error) {
+	owner := Owner{UserID: input.UserID, LedgerID: input.LedgerID}
+	recommendation, recommendationID, err := resolveRecommendation(owner, "", strings.TrimSpace(input.RecommendationID))
+	if err != nil {
+		return nil, err
+	}
+	ticketIDs, err := resolveBulkTicketIDs(owner, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []BulkTicketItem
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		items = eachBulkTicket(ticketIDs, func(ticketID string) (*TicketDetail, error) {
+			return nil, tx.Transaction(func(savepoint *gorm.DB) error {
+				ticket := model.Ticket{}
+				if err := ownerScope(savepoint, owner).First(&ticket, "id = ?", ticketID).Error; err != nil {
+					return err
+				}
+				if recommendation != nil && recommendation.LotteryCode != ticket.LotteryCode {
+					return ErrTicketRecommendationMismatch
+				}
+				return savepoint.Model(&model.Ticket{}).Where("id = ?", ticket.Id).
+					Update("recommendation_id", recommendationID).Error
+			})
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return newBulkTicketResult(items), nil
+}
+
+// resolveBulkTicketIDs 返回去重后的目标票据 ID。按 ID 传入时保留原顺序，不存在或无权访问的 ID 在结果中单独标记失败；
+// 按筛选条件时只返回当前账本下匹配的票据。
+func resolveBulkTicketIDs(owner Owner, input BulkTicketInput) ([]string, error) {
+	if len(input.TicketIDs) > 0 {
+		seen := make(map[string]struct{}, len(input.TicketIDs))
+		ticketIDs := make([]string, 0, len(input.TicketIDs))
+		for _, item := range input.TicketIDs {
+			ticketID := strings.TrimSpace(item)
+			if ticketID == "" {
+				continue
+			}
+			if _, exists := seen[ticketID]; exists {
+				continue
+			}
+			seen[ticketID] = struct{}{}
+			ticketIDs = append(ticketIDs, ticketID)
+		}
+		if len(ticketIDs) == 0 {
+			return nil, ErrBulkTicketsRequired
+		}
+		if len(ticketIDs) > maxBulkTickets {
+			return nil, ErrBulkTicketsLimit
+		}
+		return ticketIDs, nil
+	}
+	if input.Filter == nil {
+		return nil, ErrBulkTicketsRequired
+	}
+
+	filters, err := applyTicketFilters(*input.Filter)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0)
+	if err := ownerScope(db.DB.Model(&model.Ticket{}), owner).Scopes(filters).
+		Order("purchased_at desc").Order("created_at desc").
+		Limit(maxBulkTickets+1).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) > maxBulkTickets {
+		return nil, ErrBulkTicketsLimit
+	}
+	ticketIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ticketIDs = append(ticketIDs, id.String())
+	}
+	return ticketIDs, nil
+}
+
+// eachBulkTicket 依次处理每张票据并收集结果。ID 不是合法 UUID 时直接按票据不存在处理，
+// 避免 PostgreSQL 在类型转换失败时中止整个事务。
+func eachBulkTicket(ticketIDs []string, handle func(ticketID string) (*TicketDetail, error)) []BulkTicketItem {
+	items := make([]BulkTicketItem, 0, len(ticketIDs))
+	for _, ticketID := range ticketIDs {
+		if _, err := uuid.Parse(ticketID); err != nil {
+			items = append(items, newBulkTicketItem(ticketID, gorm.ErrRecordNotFound))
+			continue
+		}
+		detail, err := handle(ticketID)
+		item := newBulkTicketItem(ticketID, err)
+		item.Ticket = detail
+		items = append(items, item)
+	}
+	return items
+}
+
+func newBulkTicketItem(ticketID string, err error) BulkTicketItem {
+	item := BulkTicketItem{TicketID: ticketID, Success: err == nil}
+	if err == nil {
+		return item
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = ErrBulkTicketNotFound
+	}
+	item.ErrorCode = apperr.CodeInternal
+	if appErr := apperr.From(err); appErr != nil {
+		item.ErrorCode = appErr.Code
+	}
+	item.Message = err.Error()
+	return item
+}
+
+func newBulkTicketResult(items []BulkTicketItem) *BulkTicketResult {
+	result := &BulkTicketResult{Total: len(items), Items: items}
+	for _, item := range items {
+		if item.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+	return result
+}
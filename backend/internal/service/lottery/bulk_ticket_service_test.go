@@ -0,0 +1,141 @@
+package lottery
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-fiber-starter/internal/i18n"
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/google/uuid"
+)
+
+func TestBulkDeleteTicketsReportsPerTicketOutcome(t *testing.T) {
+	setupImportTicketTestDB(t)
+	if err := db.DB.AutoMigrate(&model.TicketUpload{}); err != nil {
+		t.Fatalf("auto migrate uploads: %v", err)
+	}
+	userID := createLedgerTestUser(t, "alice")
+	otherID := createLedgerTestUser(t, "bob")
+
+	// 两张票据共用一张原图，批量删除后原图只在两张都删除时清理。
+	imagePath := filepath.Join(t.TempDir(), "ticket.jpg")
+	if err := os.WriteFile(imagePath, []byte("image"), 0o644); err != nil {
+		t.Fatalf("write image: %v", err)
+	}
+	first := createHistoryTestTicket(t, userID, model.Ticket{Issue: "2026020", ImagePath: imagePath})
+	second := createHistoryTestTicket(t, userID, model.Ticket{Issue: "2026020", ImagePath: imagePath})
+	foreign := createHistoryTestTicket(t, otherID, model.Ticket{Issue: "2026020"})
+
+	result, err := BulkDeleteTickets(BulkTicketInput{
+		UserID:    userID,
+		TicketIDs: []string{first.Id.String(), second.Id.String(), first.Id.String(), foreign.Id.String(), "not-a-uuid"},
+	})
+	if err != nil {
+		t.Fatalf("bulk delete: %v", err)
+	}
+	if result.Total != 4 || result.Succeeded != 2 || result.Failed != 2 {
+		t.Fatalf("unexpected summary: %+v", result)
+	}
+	for _, item := range result.Items[2:] {
+		if item.Success || item.ErrorCode != "NOT_FOUND" || item.Message != ErrBulkTicketNotFound.Message {
+			t.Fatalf("expected not found for %s, got %+v", item.TicketID, item)
+		}
+		if _, ok := i18n.Message(i18n.LangEN, item.ErrorCode); !ok {
+			t.Fatalf("expected %s to be translatable", item.ErrorCode)
+		}
+	}
+
+	var remaining int64
+	db.DB.Model(&model.Ticket{}).Count(&remaining)
+	if remaining != 1 {
+		t.Fatalf("expected only the foreign ticket to remain, got %d", remaining)
+	}
+	if _, err := os.Stat(imagePath); !os.IsNotExist(err) {
+		t.Fatalf("expected orphaned image to be removed, stat err: %v", err)
+	}
+}
+
+func TestBulkLinkTicketsByFilter(t *testing.T) {
+	setupImportTicketTestDB(t)
+	userID := createLedgerTestUser(t, "alice")
+	userUUID := uuid.MustParse(userID)
+
+	recommendation := model.Recommendation{UserID: &userUUID, LotteryCode: "ssq", Issue: "2026020"}
+	if err := db.DB.Create(&recommendation).Error; err != nil {
+		t.Fatalf("create recommendation: %v", err)
+	}
+	ssqTicket := createHistoryTestTicket(t, userID, model.Ticket{Issue: "2026020"})
+	dltTicket := createHistoryTestTicket(t, userID, model.Ticket{LotteryCode: "dlt", Issue: "26020"})
+	createHistoryTestTicket(t, userID, model.Ticket{Issue: "2026021"})
+
+	result, err := BulkLinkTickets(BulkTicketInput{
+		UserID:           userID,
+		Filter:           &TicketQueryOptions{Source: "manual"},
+		RecommendationID: recommendation.Id.String(),
+	})
+	if err != nil {
+		t.Fatalf("bulk link: %v", err)
+	}
+	if result.Total != 0 {
+		t.Fatalf("expected filter to match no manual tickets, got %+v", result)
+	}
+
+	result, err = BulkLinkTickets(BulkTicketInput{
+		UserID:           userID,
+		TicketIDs:        []string{ssqTicket.Id.String(), dltTicket.Id.String()},
+		RecommendationID: recommendation.Id.String(),
+	})
+	if err != nil {
+		t.Fatalf("bulk link: %v", err)
+	}
+	if result.Succeeded != 1 || result.Items[1].ErrorCode != "TICKET_RECOMMENDATION_MISMATCH" {
+		t.Fatalf("unexpected link result: %+v", result)
+	}
+
+	linked := model.Ticket{}
+	if err := db.DB.First(&linked, "id = ?", ssqTicket.Id).Error; err != nil {
+		t.Fatalf("load ticket: %v", err)
+	}
+	if linked.RecommendationID == nil || *linked.RecommendationID != recommendation.Id {
+		t.Fatalf("expected ticket linked to recommendation, got %v", linked.RecommendationID)
+	}
+
+	if _, err := BulkLinkTickets(BulkTicketInput{UserID: userID}); err == nil {
+		t.Fatalf("expected error when neither ids nor filter are given")
+	}
+}
+
+func TestBulkRecheckTicketsSyncsEachIssueOnce(t *testing.T) {
+	setupImportTicketTestDB(t)
+	userID := createLedgerTestUser(t, "alice")
+
+	calls := make(map[string]int)
+	prevSync := syncTicketDraw
+	t.Cleanup(func() { syncTicketDraw = prevSync })
+	syncTicketDraw = func(ctx context.Context, code string, issue string) (*SyncResult, error) {
+		calls[code+":"+issue]++
+		return nil, errors.New("开奖数据源不可用")
+	}
+
+	ticketIDs := make([]string, 0, 4)
+	for _, issue := range []string{"2026020", "2026020", "2026021", "2026020"} {
+		ticket := createHistoryTestTicket(t, userID, model.Ticket{Issue: issue})
+		ticketIDs = append(ticketIDs, ticket.Id.String())
+	}
+
+	result, err := BulkRecheckTickets(context.Background(), BulkTicketInput{UserID: userID, TicketIDs: ticketIDs})
+	if err != nil {
+		t.Fatalf("bulk recheck: %v", err)
+	}
+	if result.Failed != len(ticketIDs) {
+		t.Fatalf("expected every ticket to report the sync failure, got %+v", result)
+	}
+	if len(calls) != 2 || calls["ssq:2026020"] != 1 || calls["ssq:2026021"] != 1 {
+		t.Fatalf("expected one sync per issue, got %v", calls)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/service/apperr"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/db"
 
@@ -20,6 +21,8 @@ const (
 	TicketStatusNotWon  = "not_won"
 )
 
+var ErrLotteryNotFound = apperr.NotFound("LOTTERY_NOT_FOUND", "未找到彩种配置")
+
 type RecommendationSettings struct {
 	Enabled       bool
 	Cron          string
@@ -106,7 +109,7 @@ func GetDefinition(code string) (Definition, error) {
 			return definition, nil
 		}
 	}
-	return Definition{}, fmt.Errorf("未找到彩种配置: %s", code)
+	return Definition{}, ErrLotteryNotFound.WithMessage("未找到彩种配置: %s", code)
 }
 
 func SeedLotteryTypes() error {
@@ -27,6 +27,16 @@ func RunCompensationJob(ctx context.Context, job config.CompensationJobConfig) e
 	return task(ctx, job)
 }
 
+// RunCompensationJobByName 按配置中的任务名称立即执行一次补偿任务，供管理员手动触发。
+func RunCompensationJobByName(ctx context.Context, name string) error {
+	for _, job := range config.Current.Compensation.Jobs {
+		if job.Name == name {
+			return RunCompensationJob(ctx, job)
+		}
+	}
+	return fmt.Errorf("未找到补偿任务: %s", name)
+}
+
 func compensatePreviousDrawPrizes(ctx context.Context, job config.CompensationJobConfig) error {
 	offsetDays := job.TargetDateOffsetDays
 	if offsetDays <= 0 {
@@ -39,7 +49,7 @@ func compensatePreviousDrawPrizes(ctx context.Context, job config.CompensationJo
 			continue
 		}
 		if err := compensateDefinitionDrawPrize(ctx, definition, targetDate); err != nil {
-			logger.Warn("补全 %s 开奖奖级失败: %v", definition.Code, err)
+			logger.WarnContext(ctx, "补全 %s 开奖奖级失败: %v", definition.Code, err)
 		}
 	}
 	return nil
@@ -59,10 +69,10 @@ func compensateDefinitionDrawPrize(ctx context.Context, definition Definition, t
 		return err
 	}
 	if result != nil && result.SyncedCount > 0 {
-		logger.Info("已补全 %s 第 %s 期开奖数据", definition.Code, issue)
+		logger.InfoContext(ctx, "已补全 %s 第 %s 期开奖数据", definition.Code, issue)
 		return nil
 	}
-	logger.Info("已检查 %s 第 %s 期开奖数据，第三方暂未返回可补全内容", definition.Code, issue)
+	logger.InfoContext(ctx, "已检查 %s 第 %s 期开奖数据，第三方暂未返回可补全内容", definition.Code, issue)
 	return nil
 }
 
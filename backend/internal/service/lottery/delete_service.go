@@ -6,50 +6,59 @@ import (
 	model "go-fiber-starter/internal/model/lottery"
 	"go-fiber-starter/pkg/db"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-func DeleteTicket(ticketID string, userID string) error {
-	imagePaths := make([]string, 0, 1)
-
+func DeleteTicket(ticketID string, owner Owner) error {
+	imagePath := ""
 	if err := db.DB.Transaction(func(tx *gorm.DB) error {
-		ticket := model.Ticket{}
-		if err := currentUserScope(tx, userID).First(&ticket, "id = ?", ticketID).Error; err != nil {
-			return err
-		}
-
-		if ticket.ImagePath != "" {
-			imagePaths = append(imagePaths, ticket.ImagePath)
-		}
-
-		if err := tx.Where("ticket_id = ?", ticket.Id).Delete(&model.TicketEntry{}).Error; err != nil {
-			return err
-		}
-		if ticket.ImagePath != "" {
-			if err := currentUserScope(tx.Model(&model.TicketUpload{}), userID).
-				Where("image_path = ?", ticket.ImagePath).
-				Delete(&model.TicketUpload{}).Error; err != nil {
-				return err
-			}
-		}
-		return currentUserScope(tx, userID).Delete(&model.Ticket{}, "id = ?", ticket.Id).Error
+		var err error
+		imagePath, err = deleteTicketRecord(tx, owner, ticketID)
+		return err
 	}); err != nil {
 		return err
 	}
 
-	cleanupUnusedTicketImages(imagePaths)
+	cleanupUnusedTicketImages([]string{imagePath})
 	return nil
 }
 
-func DeleteRecommendation(code string, recommendationID string, userID string) error {
+// deleteTicketRecord 在事务内删除票据及其明细、合买份额和上传记录，返回需要在提交后检查清理的原图路径。
+func deleteTicketRecord(tx *gorm.DB, owner Owner, ticketID string) (string, error) {
+	ticket := model.Ticket{}
+	if err := ownerScope(tx, owner).First(&ticket, "id = ?", ticketID).Error; err != nil {
+		return "", err
+	}
+
+	if err := tx.Where("ticket_id = ?", ticket.Id).Delete(&model.TicketEntry{}).Error; err != nil {
+		return "", err
+	}
+	if err := tx.Where("ticket_id = ?", ticket.Id).Delete(&model.TicketShare{}).Error; err != nil {
+		return "", err
+	}
+	// 上传记录属于录入票据的用户，共享账本中由其他成员删除时也一并清理。
+	if ticket.ImagePath != "" && ticket.UserID != nil {
+		if err := tx.Where("user_id = ? AND image_path = ?", *ticket.UserID, ticket.ImagePath).
+			Delete(&model.TicketUpload{}).Error; err != nil {
+			return "", err
+		}
+	}
+	if err := ownerScope(tx, owner).Delete(&model.Ticket{}, "id = ?", ticket.Id).Error; err != nil {
+		return "", err
+	}
+	return ticket.ImagePath, nil
+}
+
+func DeleteRecommendation(code string, recommendationID string, owner Owner) error {
 	if err := db.DB.Transaction(func(tx *gorm.DB) error {
 		recommendation := model.Recommendation{}
-		if err := currentUserScope(tx, userID).
+		if err := ownerScope(tx, owner).
 			First(&recommendation, "id = ? AND lottery_code = ?", recommendationID, code).Error; err != nil {
 			return err
 		}
 
-		if err := currentUserScope(tx.Model(&model.Ticket{}), userID).
+		if err := ownerScope(tx.Model(&model.Ticket{}), owner).
 			Where("recommendation_id = ?", recommendation.Id).
 			Update("recommendation_id", nil).Error; err != nil {
 			return err
@@ -58,13 +67,94 @@ func DeleteRecommendation(code string, recommendationID string, userID string) e
 		if err := tx.Where("recommendation_id = ?", recommendation.Id).Delete(&model.RecommendationEntry{}).Error; err != nil {
 			return err
 		}
-		return currentUserScope(tx, userID).Delete(&model.Recommendation{}, "id = ?", recommendation.Id).Error
+		return ownerScope(tx, owner).Delete(&model.Recommendation{}, "id = ?", recommendation.Id).Error
 	}); err != nil {
 		return err
 	}
 	return nil
 }
 
+// DeleteUserData 在调用方事务中删除用户的个人票据、上传记录、推荐及其明细，
+// 以及用户作为所有者的共享账本；用户在其他账本中录入的数据仍归账本所有，参与的合买份额保留为匿名记录。
+// 返回的图片路径需要在事务提交后交给 CleanupTicketImages 清理。
+func DeleteUserData(tx *gorm.DB, userID string) ([]string, error) {
+	userUUID, err := parseRequiredUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	imagePaths := make([]string, 0)
+	ownedLedgerIDs := make([]uuid.UUID, 0)
+	if err := tx.Model(&model.Ledger{}).Where("owner_id = ?", userUUID).Pluck("id", &ownedLedgerIDs).Error; err != nil {
+		return nil, err
+	}
+	for _, ledgerID := range ownedLedgerIDs {
+		ledgerPaths, err := deleteLedgerRecords(tx, ledgerID)
+		if err != nil {
+			return nil, err
+		}
+		imagePaths = append(imagePaths, ledgerPaths...)
+	}
+	if err := tx.Where("user_id = ?", userUUID).Delete(&model.LedgerMember{}).Error; err != nil {
+		return nil, err
+	}
+	// 用户参与的他人合买仍保留份额和金额，只解除与账号的关联。
+	if err := tx.Model(&model.TicketShare{}).Where("user_id = ?", userUUID).Update("user_id", nil).Error; err != nil {
+		return nil, err
+	}
+
+	personalPaths, err := deleteOwnedRecords(tx, PersonalOwner(userID))
+	if err != nil {
+		return nil, err
+	}
+	imagePaths = append(imagePaths, personalPaths...)
+
+	uploadPaths := make([]string, 0)
+	if err := tx.Model(&model.TicketUpload{}).Where("user_id = ? AND image_path <> ''", userID).
+		Pluck("image_path", &uploadPaths).Error; err != nil {
+		return nil, err
+	}
+	imagePaths = append(imagePaths, uploadPaths...)
+	if err := tx.Where("user_id = ?", userID).Delete(&model.TicketUpload{}).Error; err != nil {
+		return nil, err
+	}
+	return uniqueStrings(imagePaths), nil
+}
+
+// deleteOwnedRecords 删除某个归属下的票据、推荐及其明细，返回票据引用的图片路径。
+func deleteOwnedRecords(tx *gorm.DB, owner Owner) ([]string, error) {
+	imagePaths := make([]string, 0)
+	if err := ownerScope(tx.Model(&model.Ticket{}), owner).Where("image_path <> ''").
+		Pluck("image_path", &imagePaths).Error; err != nil {
+		return nil, err
+	}
+
+	ticketIDs := ownerScope(tx.Model(&model.Ticket{}), owner).Select("id")
+	if err := tx.Where("ticket_id IN (?)", ticketIDs).Delete(&model.TicketEntry{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("ticket_id IN (?)", ticketIDs).Delete(&model.TicketShare{}).Error; err != nil {
+		return nil, err
+	}
+	if err := ownerScope(tx, owner).Delete(&model.Ticket{}).Error; err != nil {
+		return nil, err
+	}
+
+	recommendationIDs := ownerScope(tx.Model(&model.Recommendation{}), owner).Select("id")
+	if err := tx.Where("recommendation_id IN (?)", recommendationIDs).Delete(&model.RecommendationEntry{}).Error; err != nil {
+		return nil, err
+	}
+	if err := ownerScope(tx, owner).Delete(&model.Recommendation{}).Error; err != nil {
+		return nil, err
+	}
+	return imagePaths, nil
+}
+
+// CleanupTicketImages 删除已不再被任何票据或上传记录引用的图片文件。
+func CleanupTicketImages(imagePaths []string) {
+	cleanupUnusedTicketImages(imagePaths)
+}
+
 func cleanupUnusedTicketImages(imagePaths []string) {
 	for _, imagePath := range uniqueStrings(imagePaths) {
 		if imagePath == "" {
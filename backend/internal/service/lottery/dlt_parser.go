@@ -1,7 +1,6 @@
 package lottery
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
 )
@@ -32,7 +31,7 @@ func ParseDLTText(text string) (*RecognitionResult, error) {
 		entries = append(entries, parseDLTLine(normalized, multiple, isAdditional)...)
 	}
 	if len(entries) == 0 {
-		return nil, fmt.Errorf("未识别到有效的大乐透号码，请补充 OCR 文本后重试")
+		return nil, ErrNumbersNotRecognized.WithMessage("未识别到有效的大乐透号码，请补充 OCR 文本后重试")
 	}
 
 	return &RecognitionResult{
@@ -1,6 +1,10 @@
 package lottery
 
-import "testing"
+import (
+	"testing"
+
+	model "go-fiber-starter/internal/model/lottery"
+)
 
 func TestParseDLTTextWithPackedEntries(t *testing.T) {
 	text := "体彩 超级大乐透 第 26025期 2026年03月11日开奖 单式票 追加投注2倍 合计12元 ①0311182632+0409 ② 06 14 21 29 34 + 02 11"
@@ -17,7 +21,7 @@ func TestParseDLTTextWithPackedEntries(t *testing.T) {
 	if result.DrawDate != "2026-03-11" {
 		t.Fatalf("unexpected draw date: %s", result.DrawDate)
 	}
-	if result.CostAmount != 12 {
+	if result.CostAmount != 12*model.Yuan {
 		t.Fatalf("unexpected cost amount: %v", result.CostAmount)
 	}
 	if len(result.Entries) != 2 {
@@ -1,7 +1,6 @@
 package lottery
 
 import (
-	"fmt"
 	"strings"
 	"time"
 
@@ -21,38 +20,45 @@ type DrawHistoryItem struct {
 	DrawDate          time.Time       `json:"drawDate"`
 	RedNumbers        string          `json:"redNumbers"`
 	BlueNumbers       string          `json:"blueNumbers"`
-	SaleAmount        float64         `json:"saleAmount"`
-	PrizePoolAmount   float64         `json:"prizePoolAmount"`
-	FirstPrizeAmount  float64         `json:"firstPrizeAmount"`
-	SecondPrizeAmount float64         `json:"secondPrizeAmount"`
+	SaleAmount        model.Money     `json:"saleAmount" swaggertype:"number"`
+	PrizePoolAmount   model.Money     `json:"prizePoolAmount" swaggertype:"number"`
+	FirstPrizeAmount  model.Money     `json:"firstPrizeAmount" swaggertype:"number"`
+	SecondPrizeAmount model.Money     `json:"secondPrizeAmount" swaggertype:"number"`
 	Source            string          `json:"source"`
 	RawPayload        string          `json:"rawPayload"`
 	PrizeDetails      []DrawPrizeItem `json:"prizeDetails"`
 }
 
 type DrawPrizeItem struct {
-	Id          uuid.UUID `json:"id"`
-	PrizeName   string    `json:"prizeName"`
-	PrizeRule   string    `json:"prizeRule"`
-	WinnerCount int       `json:"winnerCount"`
-	SingleBonus float64   `json:"singleBonus"`
+	Id          uuid.UUID   `json:"id"`
+	PrizeName   string      `json:"prizeName"`
+	PrizeRule   string      `json:"prizeRule"`
+	WinnerCount int         `json:"winnerCount"`
+	SingleBonus model.Money `json:"singleBonus" swaggertype:"number"`
 }
 
+// DrawQueryOptions 描述历史开奖查询条件，游标和号码筛选的含义与 TicketQueryOptions 一致。
 type DrawQueryOptions struct {
-	Page        int
-	PageSize    int
-	LotteryCode string
-	Issue       string
-	DrawDate    string
-	Sort        string
+	Page         int
+	PageSize     int
+	Cursor       string
+	LotteryCode  string
+	Issue        string
+	DrawDate     string
+	DrawDateFrom string
+	DrawDateTo   string
+	Number       int
+	NumberZone   string
+	Sort         string
 }
 
 type DrawPageResult struct {
-	Items    []DrawHistoryItem `json:"items"`
-	Page     int               `json:"page"`
-	PageSize int               `json:"pageSize"`
-	Total    int64             `json:"total"`
-	HasMore  bool              `json:"hasMore"`
+	Items      []DrawHistoryItem `json:"items"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"pageSize"`
+	Total      int64             `json:"total"`
+	HasMore    bool              `json:"hasMore"`
+	NextCursor string            `json:"nextCursor,omitempty"`
 }
 
 func QueryDrawResults(options DrawQueryOptions) (*DrawPageResult, error) {
@@ -65,54 +71,97 @@ func QueryDrawResults(options DrawQueryOptions) (*DrawPageResult, error) {
 		pageSize = 50
 	}
 
-	query := applyDrawFilters(db.DB.Model(&model.DrawResult{}), options)
+	filters, err := applyDrawFilters(options)
+	if err != nil {
+		return nil, err
+	}
 
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
+	if err := db.DB.Model(&model.DrawResult{}).Scopes(filters).Count(&total).Error; err != nil {
 		return nil, err
 	}
 
-	draws := make([]model.DrawResult, 0)
-	if err := applyDrawSort(query, options.Sort).
+	order := drawKeysetOrder(options.Sort)
+	query := order.apply(db.DB.Model(&model.DrawResult{}).Scopes(filters)).
 		Preload("PrizeDetails", func(query *gorm.DB) *gorm.DB {
 			return query.Order("created_at asc")
-		}).
-		Offset((page - 1) * pageSize).
-		Limit(pageSize).
-		Find(&draws).Error; err != nil {
+		})
+	if strings.TrimSpace(options.Cursor) != "" {
+		page = 0
+		if query, err = order.after(query, options.Cursor); err != nil {
+			return nil, err
+		}
+	} else {
+		query = query.Offset((page - 1) * pageSize)
+	}
+
+	draws := make([]model.DrawResult, 0, pageSize+1)
+	if err := query.Limit(pageSize + 1).Find(&draws).Error; err != nil {
 		return nil, err
 	}
+	hasMore := len(draws) > pageSize
+	if hasMore {
+		draws = draws[:pageSize]
+	}
 
 	items := make([]DrawHistoryItem, 0, len(draws))
 	for _, draw := range draws {
 		items = append(items, buildDrawHistoryItem(draw))
 	}
 
-	return &DrawPageResult{
+	result := &DrawPageResult{
 		Items:    items,
 		Page:     page,
 		PageSize: pageSize,
 		Total:    total,
-		HasMore:  int64(page*pageSize) < total,
-	}, nil
+		HasMore:  hasMore,
+	}
+	if hasMore {
+		last := draws[len(draws)-1]
+		result.NextCursor = order.encode(last.DrawDate, last.Issue, last.CreatedAt, last.Id.String())
+	}
+	return result, nil
 }
 
-func applyDrawFilters(query *gorm.DB, options DrawQueryOptions) *gorm.DB {
-	if options.LotteryCode != "" {
-		query = query.Where("lottery_code = ?", options.LotteryCode)
-	}
-	if strings.TrimSpace(options.Issue) != "" {
-		query = query.Where("issue IN ?", buildDrawIssueAliases(options.LotteryCode, options.Issue))
+func applyDrawFilters(options DrawQueryOptions) (func(*gorm.DB) *gorm.DB, error) {
+	from, to, err := parseDateRange("开奖日期", options.DrawDateFrom, options.DrawDateTo)
+	if err != nil {
+		return nil, err
 	}
-	if strings.TrimSpace(options.DrawDate) != "" {
-		drawDate, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(options.DrawDate), time.Local)
-		if err != nil {
-			query.AddError(fmt.Errorf("开奖日期格式不正确"))
-			return query
+	if value := strings.TrimSpace(options.DrawDate); value != "" {
+		if from, to, err = parseDateRange("开奖日期", value, value); err != nil {
+			return nil, err
 		}
-		query = query.Where("draw_date >= ? AND draw_date < ?", drawDate, drawDate.AddDate(0, 0, 1))
 	}
-	return query
+	numberColumns, err := numberZoneColumns(options.NumberZone, options.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(query *gorm.DB) *gorm.DB {
+		if options.LotteryCode != "" {
+			query = query.Where("lottery_code = ?", options.LotteryCode)
+		}
+		if strings.TrimSpace(options.Issue) != "" {
+			query = query.Where("issue IN ?", buildDrawIssueAliases(options.LotteryCode, options.Issue))
+		}
+		if !from.IsZero() {
+			query = query.Where("draw_date >= ?", from)
+		}
+		if !to.IsZero() {
+			query = query.Where("draw_date < ?", to)
+		}
+		if options.Number > 0 {
+			conditions := make([]string, 0, len(numberColumns))
+			args := make([]any, 0, len(numberColumns))
+			for _, column := range numberColumns {
+				conditions = append(conditions, "("+column+" & ?) <> 0")
+				args = append(args, model.NumberBit(options.Number))
+			}
+			query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+		}
+		return query
+	}, nil
 }
 
 func buildDrawIssueAliases(code string, issue string) []string {
@@ -134,13 +183,9 @@ func buildDrawIssueAliases(code string, issue string) []string {
 	return aliases
 }
 
-func applyDrawSort(query *gorm.DB, sort string) *gorm.DB {
-	switch sort {
-	case "oldest":
-		return query.Order("draw_date asc").Order("issue asc").Order("created_at asc")
-	default:
-		return query.Order("draw_date desc").Order("issue desc").Order("created_at desc")
-	}
+func drawKeysetOrder(sort string) keysetOrder {
+	columns := []keysetColumn{{"draw_date", keysetTime}, {"issue", keysetString}, {"created_at", keysetTime}, {"id", keysetString}}
+	return keysetOrder{columns: columns, desc: sort != "oldest"}
 }
 
 func buildDrawHistoryItem(draw model.DrawResult) DrawHistoryItem {
@@ -13,13 +13,19 @@ import (
 	"time"
 
 	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/internal/tracing"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/db"
+	"go-fiber-starter/pkg/logger"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// CodeDrawSourceFailed 表示调用开奖数据源失败。
+const CodeDrawSourceFailed = "DRAW_SOURCE_FAILED"
+
 type jisuResponse struct {
 	Status int             `json:"status"`
 	Msg    string          `json:"msg"`
@@ -49,6 +55,7 @@ type saveDrawOptions struct {
 }
 
 func SyncLatestDraw(ctx context.Context, code string, issue string) (*SyncResult, error) {
+	ctx = logger.WithFields(ctx, logger.FieldLotteryCode, code)
 	if strings.TrimSpace(issue) != "" {
 		return SyncDrawIssue(ctx, code, issue)
 	}
@@ -98,6 +105,7 @@ func SyncLatestDraw(ctx context.Context, code string, issue string) (*SyncResult
 }
 
 func SyncDrawIssue(ctx context.Context, code string, issue string) (*SyncResult, error) {
+	ctx = logger.WithFields(ctx, logger.FieldLotteryCode, code)
 	lotteryType, err := getLotteryType(code)
 	if err != nil {
 		return nil, err
@@ -115,7 +123,7 @@ func SyncDrawIssue(ctx context.Context, code string, issue string) (*SyncResult,
 		return nil, err
 	}
 	if expectedIssue == "" {
-		return nil, fmt.Errorf("请提供需要补全的开奖期号")
+		return nil, ErrIssueRequired.WithMessage("请提供需要补全的开奖期号")
 	}
 
 	item, err := fetchLatestDraw(ctx, lotteryType, expectedIssue)
@@ -318,56 +326,61 @@ func findHistoryItemByIssue(code string, issue string, items []map[string]any) (
 	return nil, false
 }
 
-func requestJisu(ctx context.Context, requestURL string) (*jisuResponse, error) {
+func requestJisu(ctx context.Context, requestURL string) (result *jisuResponse, err error) {
+	ctx, span := tracing.StartClient(ctx, "jisuapi "+http.MethodGet, tracing.URLAttribute(requestURL))
+	defer func() { tracing.End(span, err) }()
+
 	startedAt := time.Now()
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
-		logThirdPartyFailure("jisuapi", http.MethodGet, requestURL, map[string]any{
+		logThirdPartyFailure(ctx, "jisuapi", http.MethodGet, requestURL, map[string]any{
 			"url": maskURL(requestURL),
 		}, nil, 0, startedAt, err)
-		return nil, err
+		return nil, apperr.Upstream(CodeDrawSourceFailed, err)
 	}
+	tracing.InjectHTTP(ctx, request.Header)
 
 	client := &http.Client{Timeout: time.Duration(max(10, config.Current.Jisu.TimeoutSeconds)) * time.Second}
 	response, err := client.Do(request)
 	if err != nil {
-		logThirdPartyFailure("jisuapi", http.MethodGet, requestURL, map[string]any{
+		logThirdPartyFailure(ctx, "jisuapi", http.MethodGet, requestURL, map[string]any{
 			"url": maskURL(requestURL),
 		}, nil, 0, startedAt, err)
-		return nil, err
+		return nil, apperr.Upstream(CodeDrawSourceFailed, err)
 	}
 	defer response.Body.Close()
+	tracing.HTTPStatusAttribute(span, response.StatusCode)
 
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		logThirdPartyFailure("jisuapi", http.MethodGet, requestURL, map[string]any{
+		logThirdPartyFailure(ctx, "jisuapi", http.MethodGet, requestURL, map[string]any{
 			"url": maskURL(requestURL),
 		}, nil, response.StatusCode, startedAt, err)
-		return nil, err
+		return nil, apperr.Upstream(CodeDrawSourceFailed, err)
 	}
 	if response.StatusCode >= http.StatusBadRequest {
 		requestErr := fmt.Errorf("开奖同步失败: %s", string(body))
-		logThirdPartyFailure("jisuapi", http.MethodGet, requestURL, map[string]any{
+		logThirdPartyFailure(ctx, "jisuapi", http.MethodGet, requestURL, map[string]any{
 			"url": maskURL(requestURL),
 		}, body, response.StatusCode, startedAt, requestErr)
-		return nil, requestErr
+		return nil, apperr.Upstream(CodeDrawSourceFailed, requestErr)
 	}
 
 	parsed := jisuResponse{}
 	if err := json.Unmarshal(body, &parsed); err != nil {
-		logThirdPartyFailure("jisuapi", http.MethodGet, requestURL, map[string]any{
+		logThirdPartyFailure(ctx, "jisuapi", http.MethodGet, requestURL, map[string]any{
 			"url": maskURL(requestURL),
 		}, body, response.StatusCode, startedAt, err)
-		return nil, err
+		return nil, apperr.Upstream(CodeDrawSourceFailed, err)
 	}
 	if parsed.Status != 0 {
 		requestErr := fmt.Errorf("开奖同步失败: %s", parsed.Msg)
-		logThirdPartyFailure("jisuapi", http.MethodGet, requestURL, map[string]any{
+		logThirdPartyFailure(ctx, "jisuapi", http.MethodGet, requestURL, map[string]any{
 			"url": maskURL(requestURL),
 		}, body, response.StatusCode, startedAt, requestErr)
-		return nil, requestErr
+		return nil, apperr.Upstream(CodeDrawSourceFailed, requestErr)
 	}
-	logThirdPartySuccess("jisuapi", http.MethodGet, requestURL, map[string]any{
+	logThirdPartySuccess(ctx, "jisuapi", http.MethodGet, requestURL, map[string]any{
 		"url": maskURL(requestURL),
 	}, body, response.StatusCode, startedAt)
 	return &parsed, nil
@@ -402,8 +415,8 @@ func saveDrawItem(lotteryType model.LotteryType, item map[string]any, options sa
 	draw.DrawDate = resolveDrawDateForSave(definition, issue, options.ExpectedDrawDate, parseDrawDate(extractString(item, "opendate", "awardtime", "drawdate")))
 	draw.RedNumbers = redNumbers
 	draw.BlueNumbers = blueNumbers
-	draw.SaleAmount = parseFloat(item["saleamount"])
-	draw.PrizePoolAmount = parseFloatValues(item["poolamount"], item["totalmoney"])
+	draw.SaleAmount = parseMoney(item["saleamount"])
+	draw.PrizePoolAmount = parseMoneyValues(item["poolamount"], item["totalmoney"])
 	draw.Source = "jisuapi"
 	draw.RawPayload = mustJSON(item)
 
@@ -462,7 +475,7 @@ func saveDrawPrizes(drawID uuid.UUID, item map[string]any) error {
 			PrizeName:    normalizePrizeName(extractString(prizeItem, "name", "prizename")),
 			PrizeRule:    extractString(prizeItem, "requirement", "require", "rule"),
 			WinnerCount:  parseInt(prizeItem["num"]),
-			SingleBonus:  parseFloat(prizeItem["singlebonus"]),
+			SingleBonus:  parseMoney(prizeItem["singlebonus"]),
 		})
 	}
 
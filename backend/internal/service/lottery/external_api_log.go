@@ -1,28 +1,133 @@
 package lottery
 
 import (
+	"context"
 	"encoding/json"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"go-fiber-starter/internal/metrics"
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/tracing"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
 	"go-fiber-starter/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 const externalAPILogLimit = 4000
 
-func logThirdPartySuccess(provider string, method string, endpoint string, request any, responseBody []byte, statusCode int, startedAt time.Time) {
+func logThirdPartySuccess(ctx context.Context, provider string, method string, endpoint string, request any, responseBody []byte, statusCode int, startedAt time.Time) {
+	metrics.ObserveExternalCall(provider, statusCode, true, time.Since(startedAt))
 	payload := buildThirdPartyLogPayload(provider, method, endpoint, request, responseBody, statusCode, startedAt, "")
-	logger.Info("第三方接口调用: %s", mustJSON(payload))
+	logger.InfoContext(ctx, "第三方接口调用: %s", mustJSON(payload))
+	persistThirdPartyCall(ctx, payload, true)
 }
 
-func logThirdPartyFailure(provider string, method string, endpoint string, request any, responseBody []byte, statusCode int, startedAt time.Time, err error) {
+func logThirdPartyFailure(ctx context.Context, provider string, method string, endpoint string, request any, responseBody []byte, statusCode int, startedAt time.Time, err error) {
+	metrics.ObserveExternalCall(provider, statusCode, false, time.Since(startedAt))
 	message := ""
 	if err != nil {
 		message = err.Error()
 	}
 	payload := buildThirdPartyLogPayload(provider, method, endpoint, request, responseBody, statusCode, startedAt, message)
-	logger.Error("第三方接口调用失败: %s", mustJSON(payload))
+	logger.ErrorContext(ctx, "第三方接口调用失败: %s", mustJSON(payload))
+	persistThirdPartyCall(ctx, payload, false)
+}
+
+// persistThirdPartyCall 把已脱敏的调用记录写入数据库，并从上下文中取请求 ID、用户、任务以及关联的上传或推荐。
+// 写入失败只记警告，不影响调用方。
+func persistThirdPartyCall(ctx context.Context, payload map[string]any, success bool) {
+	if !config.Current.ExternalLogs.Enabled || db.DB == nil {
+		return
+	}
+	endpoint := stringValue(payload["endpoint"])
+	if len(endpoint) > 512 {
+		endpoint = endpoint[:512]
+	}
+	statusCode, _ := payload["statusCode"].(int)
+	durationMs, _ := payload["durationMs"].(int64)
+	record := model.ExternalAPILog{
+		Provider:     stringValue(payload["provider"]),
+		Method:       stringValue(payload["method"]),
+		Endpoint:     endpoint,
+		StatusCode:   statusCode,
+		Success:      success,
+		DurationMs:   durationMs,
+		Request:      stringValue(payload["request"]),
+		Response:     stringValue(payload["response"]),
+		ErrorMessage: truncateLogValue(stringValue(payload["error"])),
+		RequestID:    logger.Field(ctx, logger.FieldRequestID),
+		TraceID:      tracing.TraceID(ctx),
+		JobName:      logger.Field(ctx, logger.FieldJobName),
+		UserID:       parseOptionalUUID(logger.Field(ctx, logger.FieldUserID)),
+	}
+	links := externalCallLinksFrom(ctx)
+	if links != nil {
+		record.UploadID = links.uploadID
+	}
+	if err := db.DB.WithContext(context.WithoutCancel(ctx)).Create(&record).Error; err != nil {
+		logger.WarnContext(ctx, "保存第三方接口调用记录失败: %v", err)
+		return
+	}
+	if links != nil {
+		links.add(record.Id)
+	}
+}
+
+type externalCallLinksKey struct{}
+
+// externalCallLinks 收集同一业务操作中产生的调用记录，识票时预先带上上传记录 ID；
+// 生成推荐时推荐记录在调用 AI 之后才创建，保存后再通过 linkRecommendation 回填。
+type externalCallLinks struct {
+	uploadID *uuid.UUID
+	mu       sync.Mutex
+	logIDs   []uuid.UUID
+}
+
+func (l *externalCallLinks) add(id uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logIDs = append(l.logIDs, id)
+}
+
+// linkRecommendation 把收集到的调用记录关联到推荐记录。
+func (l *externalCallLinks) linkRecommendation(tx *gorm.DB, recommendationID uuid.UUID) error {
+	l.mu.Lock()
+	ids := append([]uuid.UUID(nil), l.logIDs...)
+	l.mu.Unlock()
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(&model.ExternalAPILog{}).Where("id IN ?", ids).Update("recommendation_id", recommendationID).Error
+}
+
+// trackExternalCalls 返回会收集调用记录 ID 的上下文，uploadID 非空时调用记录同时关联该上传记录。
+func trackExternalCalls(ctx context.Context, uploadID *uuid.UUID) (context.Context, *externalCallLinks) {
+	links := &externalCallLinks{uploadID: uploadID}
+	return context.WithValue(ctx, externalCallLinksKey{}, links), links
+}
+
+func externalCallLinksFrom(ctx context.Context) *externalCallLinks {
+	links, _ := ctx.Value(externalCallLinksKey{}).(*externalCallLinks)
+	return links
+}
+
+func stringValue(value any) string {
+	text, _ := value.(string)
+	return text
+}
+
+func parseOptionalUUID(value string) *uuid.UUID {
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
 }
 
 func buildThirdPartyLogPayload(provider string, method string, endpoint string, request any, responseBody []byte, statusCode int, startedAt time.Time, errorMessage string) map[string]any {
@@ -0,0 +1,134 @@
+package lottery
+
+import (
+	"strings"
+	"time"
+
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/google/uuid"
+)
+
+// ExternalAPILogQueryOptions 是管理端检索第三方接口调用记录的条件，空值表示不限。
+type ExternalAPILogQueryOptions struct {
+	Page             int
+	PageSize         int
+	Provider         string
+	Success          *bool
+	StatusCode       int
+	RequestID        string
+	UserID           string
+	UploadID         string
+	RecommendationID string
+	Keyword          string
+	From             string
+	To               string
+}
+
+type ExternalAPILogPage struct {
+	Items    []model.ExternalAPILog `json:"items"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"pageSize"`
+	Total    int64                  `json:"total"`
+	HasMore  bool                   `json:"hasMore"`
+}
+
+// QueryExternalAPILogs 按时间倒序分页返回调用记录。列表不返回请求和响应内容，需要时通过 GetExternalAPILog 查看单条详情。
+func QueryExternalAPILogs(options ExternalAPILogQueryOptions) (*ExternalAPILogPage, error) {
+	page := max(1, options.Page)
+	pageSize := options.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	query := db.DB.Model(&model.ExternalAPILog{})
+	if provider := strings.TrimSpace(options.Provider); provider != "" {
+		query = query.Where("provider = ?", provider)
+	}
+	if options.Success != nil {
+		query = query.Where("success = ?", *options.Success)
+	}
+	if options.StatusCode > 0 {
+		query = query.Where("status_code = ?", options.StatusCode)
+	}
+	if requestID := strings.TrimSpace(options.RequestID); requestID != "" {
+		query = query.Where("request_id = ?", requestID)
+	}
+	for _, filter := range []struct {
+		column string
+		value  string
+	}{
+		{"user_id", options.UserID},
+		{"upload_id", options.UploadID},
+		{"recommendation_id", options.RecommendationID},
+	} {
+		if strings.TrimSpace(filter.value) == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(strings.TrimSpace(filter.value))
+		if err != nil {
+			return nil, ErrInvalidQueryFilter.WithMessage("%s 不是有效的 ID", filter.column)
+		}
+		query = query.Where(filter.column+" = ?", parsed)
+	}
+	if keyword := strings.TrimSpace(options.Keyword); keyword != "" {
+		pattern := "%" + keyword + "%"
+		query = query.Where("(endpoint LIKE ? OR error_message LIKE ?)", pattern, pattern)
+	}
+	from, to, err := parseDateRange("调用日期", options.From, options.To)
+	if err != nil {
+		return nil, err
+	}
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("created_at < ?", to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]model.ExternalAPILog, 0)
+	if err := query.Omit("request", "response").
+		Order("created_at desc").Order("id desc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return &ExternalAPILogPage{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+	}, nil
+}
+
+// GetExternalAPILog 返回单条调用记录，包含截断后的请求和响应内容。
+func GetExternalAPILog(id string) (*model.ExternalAPILog, error) {
+	parsed, err := uuid.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return nil, apperr.ErrNotFound
+	}
+	record := model.ExternalAPILog{}
+	if err := db.DB.First(&record, "id = ?", parsed).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// PurgeExternalAPILogs 删除超过保留期的调用记录，返回删除条数。
+func PurgeExternalAPILogs(now time.Time) (int64, error) {
+	result := db.DB.Where("created_at < ?", now.Add(-config.Current.ExternalLogs.Retention())).Delete(&model.ExternalAPILog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,116 @@
+package lottery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+	"go-fiber-starter/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+func setupExternalAPILogTestDB(t *testing.T) {
+	t.Helper()
+
+	setupImportTicketTestDB(t)
+	if err := db.DB.AutoMigrate(&model.ExternalAPILog{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	config.Current.ExternalLogs = config.ExternalLogConfig{Enabled: true, RetentionDays: 7}
+}
+
+func TestExternalAPICallsArePersistedWithLinks(t *testing.T) {
+	setupExternalAPILogTestDB(t)
+
+	userID := uuid.New()
+	uploadID := uuid.New()
+	ctx := logger.WithFields(context.Background(), logger.FieldRequestID, "req-1", logger.FieldUserID, userID.String())
+	uploadCtx, _ := trackExternalCalls(ctx, &uploadID)
+	logThirdPartyFailure(uploadCtx, "paddleocr", http.MethodPost, "http://ocr.local/ocr", map[string]any{"fileType": 1}, []byte("busy"), http.StatusServiceUnavailable, time.Now(), errors.New("PaddleOCR 服务返回异常: busy"))
+
+	recommendationCtx, calls := trackExternalCalls(ctx, nil)
+	logThirdPartySuccess(recommendationCtx, "jisuapi", http.MethodGet, "https://api.jisuapi.com/caipiao/query?appkey=1234567890abcdef&caipiaoid=11", nil, []byte(`{"status":0}`), http.StatusOK, time.Now())
+	recommendationID := uuid.New()
+	if err := calls.linkRecommendation(db.DB, recommendationID); err != nil {
+		t.Fatalf("link recommendation: %v", err)
+	}
+
+	failed := false
+	page, err := QueryExternalAPILogs(ExternalAPILogQueryOptions{Success: &failed, UploadID: uploadID.String()})
+	if err != nil {
+		t.Fatalf("query logs: %v", err)
+	}
+	if page.Total != 1 || page.Items[0].Provider != "paddleocr" || page.Items[0].StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected one failed OCR call linked to upload, got %+v", page.Items)
+	}
+	if page.Items[0].RequestID != "req-1" || page.Items[0].UserID == nil || *page.Items[0].UserID != userID {
+		t.Fatalf("expected request and user from context, got %+v", page.Items[0])
+	}
+	if page.Items[0].Response != "" {
+		t.Fatalf("expected list to omit response body, got %q", page.Items[0].Response)
+	}
+
+	page, err = QueryExternalAPILogs(ExternalAPILogQueryOptions{RecommendationID: recommendationID.String()})
+	if err != nil {
+		t.Fatalf("query logs: %v", err)
+	}
+	if page.Total != 1 {
+		t.Fatalf("expected one call linked to recommendation, got %d", page.Total)
+	}
+	detail, err := GetExternalAPILog(page.Items[0].Id.String())
+	if err != nil {
+		t.Fatalf("get log: %v", err)
+	}
+	if strings.Contains(detail.Endpoint, "1234567890abcdef") || detail.Response != `{"status":0}` {
+		t.Fatalf("expected masked endpoint and stored response, got %q / %q", detail.Endpoint, detail.Response)
+	}
+}
+
+func TestPurgeExternalAPILogsKeepsRecentRecords(t *testing.T) {
+	setupExternalAPILogTestDB(t)
+
+	logThirdPartySuccess(context.Background(), "jisuapi", http.MethodGet, "https://api.jisuapi.com/caipiao/query", nil, nil, http.StatusOK, time.Now())
+	logThirdPartySuccess(context.Background(), "jisuapi", http.MethodGet, "https://api.jisuapi.com/caipiao/query", nil, nil, http.StatusOK, time.Now())
+	old := model.ExternalAPILog{}
+	if err := db.DB.First(&old).Error; err != nil {
+		t.Fatalf("load log: %v", err)
+	}
+	if err := db.DB.Model(&old).UpdateColumn("created_at", time.Now().AddDate(0, 0, -8)).Error; err != nil {
+		t.Fatalf("age log: %v", err)
+	}
+
+	deleted, err := PurgeExternalAPILogs(time.Now())
+	if err != nil {
+		t.Fatalf("purge logs: %v", err)
+	}
+	var remaining int64
+	db.DB.Model(&model.ExternalAPILog{}).Count(&remaining)
+	if deleted != 1 || remaining != 1 {
+		t.Fatalf("expected 1 purged and 1 remaining, got %d/%d", deleted, remaining)
+	}
+}
+
+func TestExternalLogRetentionStartsSchedulerWithoutLotteryJobs(t *testing.T) {
+	prevConfig := config.Current
+	t.Cleanup(func() { config.Current = prevConfig })
+
+	config.Current.Lotteries = []config.LotteryConfig{{Code: "ssq", Enabled: true}}
+	config.Current.Backup = config.BackupConfig{}
+	config.Current.Compensation = config.CompensationConfig{}
+	config.Current.ExternalLogs = config.ExternalLogConfig{Enabled: true, RetentionDays: 30}
+	if !hasScheduledJobs() {
+		t.Fatalf("expected scheduler to start for external log retention")
+	}
+
+	config.Current.ExternalLogs.Enabled = false
+	if hasScheduledJobs() {
+		t.Fatalf("expected no scheduler without any configured job")
+	}
+}
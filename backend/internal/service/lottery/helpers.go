@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	model "go-fiber-starter/internal/model/lottery"
 	"go-fiber-starter/pkg/config"
 )
 
@@ -128,22 +129,22 @@ func formatDateString(year string, month string, day string) string {
 	return fmt.Sprintf("%s-%02d-%02d", year, monthValue, dayValue)
 }
 
-func parseRecognizedCost(text string, entries []ParsedEntry) float64 {
+func parseRecognizedCost(text string, entries []ParsedEntry) model.Money {
 	if matches := costPattern.FindStringSubmatch(text); len(matches) == 2 {
-		return parseFloat(matches[1])
+		return parseMoney(matches[1])
 	}
 	return calculateEntriesCost(entries)
 }
 
-func calculateEntriesCost(entries []ParsedEntry) float64 {
-	total := 0.0
+func calculateEntriesCost(entries []ParsedEntry) model.Money {
+	var total model.Money
 	for _, entry := range entries {
 		multiple := resolveEntryMultiple(entry)
 		perBetCost := 2
 		if entry.IsAdditional {
 			perBetCost++
 		}
-		total += float64(multiple * perBetCost)
+		total += model.Money(multiple*perBetCost) * model.Yuan
 	}
 	return total
 }
@@ -170,10 +171,30 @@ func parseFloat(value any) float64 {
 	}
 }
 
-func parseFloatValues(values ...any) float64 {
+// parseMoney 把第三方返回的以元为单位的金额转换为分，字符串和 json.Number 按十进制精确解析。
+func parseMoney(value any) model.Money {
+	switch actual := value.(type) {
+	case json.Number:
+		amount, err := model.ParseMoney(actual.String())
+		if err != nil {
+			return 0
+		}
+		return amount
+	case string:
+		amount, err := model.ParseMoney(strings.ReplaceAll(actual, ",", ""))
+		if err != nil {
+			return 0
+		}
+		return amount
+	default:
+		return model.MoneyFromFloat(parseFloat(value))
+	}
+}
+
+func parseMoneyValues(values ...any) model.Money {
 	for _, value := range values {
-		if number := parseFloat(value); number > 0 {
-			return number
+		if amount := parseMoney(value); amount > 0 {
+			return amount
 		}
 	}
 	return 0
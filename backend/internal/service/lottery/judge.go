@@ -7,13 +7,13 @@ import (
 )
 
 type PrizeResult struct {
-	IsWinning    bool    `json:"isWinning"`
-	PrizeName    string  `json:"prizeName"`
-	PrizeAmount  float64 `json:"prizeAmount"`
-	MatchSummary string  `json:"matchSummary"`
+	IsWinning    bool        `json:"isWinning"`
+	PrizeName    string      `json:"prizeName"`
+	PrizeAmount  model.Money `json:"prizeAmount" swaggertype:"number"`
+	MatchSummary string      `json:"matchSummary"`
 }
 
-func JudgeNumbers(code string, redNumbers string, blueNumbers string, isAdditional bool, draw model.DrawResult, prizeMap map[string]float64) PrizeResult {
+func JudgeNumbers(code string, redNumbers string, blueNumbers string, isAdditional bool, draw model.DrawResult, prizeMap map[string]model.Money) PrizeResult {
 	switch code {
 	case "dlt":
 		return judgeDLT(redNumbers, blueNumbers, isAdditional, draw, prizeMap)
@@ -22,7 +22,7 @@ func JudgeNumbers(code string, redNumbers string, blueNumbers string, isAddition
 	}
 }
 
-func judgeSSQ(redNumbers string, blueNumbers string, draw model.DrawResult, prizeMap map[string]float64) PrizeResult {
+func judgeSSQ(redNumbers string, blueNumbers string, draw model.DrawResult, prizeMap map[string]model.Money) PrizeResult {
 	redHit := countHit(parseCSVNumbers(redNumbers), parseCSVNumbers(draw.RedNumbers))
 	blueHit := countHit(parseCSVNumbers(blueNumbers), parseCSVNumbers(draw.BlueNumbers))
 	prizeName := resolveSSQPrizeName(redHit, blueHit > 0)
@@ -40,7 +40,7 @@ func judgeSSQ(redNumbers string, blueNumbers string, draw model.DrawResult, priz
 	return result
 }
 
-func judgeDLT(redNumbers string, blueNumbers string, isAdditional bool, draw model.DrawResult, prizeMap map[string]float64) PrizeResult {
+func judgeDLT(redNumbers string, blueNumbers string, isAdditional bool, draw model.DrawResult, prizeMap map[string]model.Money) PrizeResult {
 	redHit := countHit(parseCSVNumbers(redNumbers), parseCSVNumbers(draw.RedNumbers))
 	blueHit := countHit(parseCSVNumbers(blueNumbers), parseCSVNumbers(draw.BlueNumbers))
 	prizeName := resolveDLTPrizeName(redHit, blueHit)
@@ -117,18 +117,19 @@ func resolveDLTPrizeName(redHit int, blueHit int) string {
 	}
 }
 
-func resolveDLTPrizeAmount(prizeName string, isAdditional bool, prizeMap map[string]float64) float64 {
+// resolveDLTPrizeAmount 追加投注的一二等奖额外获得基本奖金的 80%，按分精确计算。
+func resolveDLTPrizeAmount(prizeName string, isAdditional bool, prizeMap map[string]model.Money) model.Money {
 	amount := resolvePrizeAmount("dlt", prizeName, prizeMap)
 	if !isAdditional {
 		return amount
 	}
 	if prizeName == "一等奖" || prizeName == "二等奖" {
-		return amount * 1.8
+		return amount.MulRatio(18, 10)
 	}
 	return amount
 }
 
-func resolvePrizeAmount(code string, prizeName string, prizeMap map[string]float64) float64 {
+func resolvePrizeAmount(code string, prizeName string, prizeMap map[string]model.Money) model.Money {
 	if amount, ok := prizeMap[prizeName]; ok && amount > 0 {
 		return amount
 	}
@@ -137,28 +138,28 @@ func resolvePrizeAmount(code string, prizeName string, prizeMap map[string]float
 	case "dlt":
 		switch prizeName {
 		case "三等奖":
-			return 5000
+			return 5000 * model.Yuan
 		case "四等奖":
-			return 300
+			return 300 * model.Yuan
 		case "五等奖":
-			return 150
+			return 150 * model.Yuan
 		case "六等奖":
-			return 15
+			return 15 * model.Yuan
 		case "七等奖":
-			return 5
+			return 5 * model.Yuan
 		default:
 			return 0
 		}
 	default:
 		switch prizeName {
 		case "三等奖":
-			return 3000
+			return 3000 * model.Yuan
 		case "四等奖":
-			return 200
+			return 200 * model.Yuan
 		case "五等奖":
-			return 10
+			return 10 * model.Yuan
 		case "六等奖":
-			return 5
+			return 5 * model.Yuan
 		default:
 			return 0
 		}
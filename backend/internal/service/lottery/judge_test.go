@@ -12,13 +12,13 @@ func TestJudgeDLTAdditionalPrizeAmount(t *testing.T) {
 		BlueNumbers: "04,09",
 	}
 
-	result := JudgeNumbers("dlt", "03,11,18,26,32", "04,09", true, draw, map[string]float64{
-		"一等奖": 1000,
+	result := JudgeNumbers("dlt", "03,11,18,26,32", "04,09", true, draw, map[string]model.Money{
+		"一等奖": 1000 * model.Yuan,
 	})
 	if !result.IsWinning {
 		t.Fatalf("expected winning result")
 	}
-	if result.PrizeAmount != 1800 {
+	if result.PrizeAmount != 1800*model.Yuan {
 		t.Fatalf("unexpected prize amount: %v", result.PrizeAmount)
 	}
 }
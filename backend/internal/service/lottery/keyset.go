@@ -0,0 +1,148 @@
+package lottery
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/service/apperr"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCursor      = apperr.Invalid("CURSOR_INVALID", "分页游标无效，请从第一页重新加载")
+	ErrInvalidQueryFilter = apperr.Invalid("QUERY_FILTER_INVALID", "筛选条件不正确")
+)
+
+type keysetKind int
+
+const (
+	keysetTime keysetKind = iota
+	keysetMoney
+	keysetString
+)
+
+type keysetColumn struct {
+	name string
+	kind keysetKind
+}
+
+// keysetOrder 描述游标分页使用的排序列，最后一列必须唯一（通常是 id），
+// 保证翻页期间插入新记录时已返回的数据不会重复或被跳过。
+type keysetOrder struct {
+	columns []keysetColumn
+	desc    bool
+}
+
+func (o keysetOrder) apply(query *gorm.DB) *gorm.DB {
+	direction := " asc"
+	if o.desc {
+		direction = " desc"
+	}
+	for _, column := range o.columns {
+		query = query.Order(column.name + direction)
+	}
+	return query
+}
+
+// after 解析游标并追加“排在游标之后”的条件：(a < ?) OR (a = ? AND b < ?) OR ...
+func (o keysetOrder) after(query *gorm.DB, cursor string) (*gorm.DB, error) {
+	values, err := o.decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+	operator := " > ?"
+	if o.desc {
+		operator = " < ?"
+	}
+
+	clauses := make([]string, 0, len(o.columns))
+	args := make([]any, 0, len(o.columns)*(len(o.columns)+1)/2)
+	for index, column := range o.columns {
+		parts := make([]string, 0, index+1)
+		for _, previous := range o.columns[:index] {
+			parts = append(parts, previous.name+" = ?")
+		}
+		parts = append(parts, column.name+operator)
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+		args = append(args, values[:index+1]...)
+	}
+	return query.Where("("+strings.Join(clauses, " OR ")+")", args...), nil
+}
+
+func (o keysetOrder) encode(values ...any) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		switch typed := value.(type) {
+		case time.Time:
+			parts = append(parts, typed.Format(time.RFC3339Nano))
+		case model.Money:
+			parts = append(parts, strconv.FormatInt(typed.Cents(), 10))
+		case string:
+			parts = append(parts, typed)
+		default:
+			parts = append(parts, "")
+		}
+	}
+	payload, _ := json.Marshal(parts)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func (o keysetOrder) decode(cursor string) ([]any, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(cursor))
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := make([]string, 0, len(o.columns))
+	if err := json.Unmarshal(payload, &parts); err != nil || len(parts) != len(o.columns) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]any, 0, len(parts))
+	for index, column := range o.columns {
+		switch column.kind {
+		case keysetTime:
+			value, err := time.Parse(time.RFC3339Nano, parts[index])
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values = append(values, value)
+		case keysetMoney:
+			value, err := strconv.ParseInt(parts[index], 10, 64)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			values = append(values, model.Money(value))
+		default:
+			values = append(values, parts[index])
+		}
+	}
+	return values, nil
+}
+
+// parseDateRange 把 YYYY-MM-DD 格式的起止日期转换为左闭右开区间，结束日期包含当天。
+func parseDateRange(field string, from string, to string) (time.Time, time.Time, error) {
+	var start, end time.Time
+	if value := strings.TrimSpace(from); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return start, end, ErrInvalidQueryFilter.WithMessage("%s格式不正确，应为 YYYY-MM-DD", field)
+		}
+		start = parsed
+	}
+	if value := strings.TrimSpace(to); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return start, end, ErrInvalidQueryFilter.WithMessage("%s格式不正确，应为 YYYY-MM-DD", field)
+		}
+		end = parsed.AddDate(0, 0, 1)
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return start, end, ErrInvalidQueryFilter.WithMessage("%s的开始日期不能晚于结束日期", field)
+	}
+	return start, end, nil
+}
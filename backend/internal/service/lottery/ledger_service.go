@@ -0,0 +1,351 @@
+package lottery
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	model "go-fiber-starter/internal/model/lottery"
+	userModel "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxLedgerNameLength = 64
+
+var (
+	ErrLedgerNotFound         = apperr.NotFound("LEDGER_NOT_FOUND", "账本不存在或无权访问")
+	ErrLedgerReadOnly         = apperr.New(apperr.KindForbidden, "LEDGER_READ_ONLY", "当前账本角色只能查看，不能修改数据")
+	ErrLedgerOwnerRequired    = apperr.New(apperr.KindForbidden, "LEDGER_OWNER_REQUIRED", "只有账本所有者可以执行该操作")
+	ErrLedgerNameRequired     = apperr.Invalid("LEDGER_NAME_REQUIRED", "账本名称不能为空")
+	ErrLedgerMemberExists     = apperr.New(apperr.KindConflict, "LEDGER_MEMBER_EXISTS", "该用户已是账本成员")
+	ErrLedgerMemberNotFound   = apperr.NotFound("LEDGER_MEMBER_NOT_FOUND", "账本成员不存在")
+	ErrLedgerUserNotFound     = apperr.NotFound("USER_NOT_FOUND", "用户不存在")
+	ErrInvalidLedgerRole      = apperr.Invalid("LEDGER_ROLE_INVALID", "成员角色只能是 editor 或 viewer")
+	ErrLedgerOwnerCannotLeave = apperr.Invalid("LEDGER_OWNER_CANNOT_LEAVE", "账本所有者不能退出账本，请先删除账本")
+)
+
+type LedgerSummary struct {
+	model.Ledger
+	Role        string `json:"role"`
+	MemberCount int    `json:"memberCount"`
+}
+
+type LedgerMemberDetail struct {
+	model.LedgerMember
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+}
+
+// ResolveOwner 校验用户对账本的访问权限并返回数据归属，ledgerID 为空时返回个人归属。
+// write 为 true 时要求 owner 或 editor 角色。
+func ResolveOwner(userID string, ledgerID string, write bool) (Owner, error) {
+	if ledgerID == "" {
+		return PersonalOwner(userID), nil
+	}
+	member, err := findLedgerMember(db.DB, ledgerID, userID)
+	if err != nil {
+		return Owner{}, err
+	}
+	if write && !member.CanWrite() {
+		return Owner{}, ErrLedgerReadOnly
+	}
+	return Owner{UserID: userID, LedgerID: member.LedgerID.String()}, nil
+}
+
+func CreateLedger(userID string, name string) (*LedgerSummary, error) {
+	userUUID, err := parseRequiredUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	name, err = normalizeLedgerName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := model.Ledger{Name: name, OwnerID: userUUID}
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&ledger).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.LedgerMember{
+			LedgerID: ledger.Id,
+			UserID:   userUUID,
+			Role:     model.LedgerRoleOwner,
+		}).Error
+	}); err != nil {
+		return nil, err
+	}
+	return &LedgerSummary{Ledger: ledger, Role: model.LedgerRoleOwner, MemberCount: 1}, nil
+}
+
+func ListLedgers(userID string) ([]LedgerSummary, error) {
+	members := make([]model.LedgerMember, 0)
+	if err := db.DB.Where("user_id = ?", userID).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	result := make([]LedgerSummary, 0, len(members))
+	if len(members) == 0 {
+		return result, nil
+	}
+
+	roles := make(map[uuid.UUID]string, len(members))
+	ledgerIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		roles[member.LedgerID] = member.Role
+		ledgerIDs = append(ledgerIDs, member.LedgerID)
+	}
+
+	ledgers := make([]model.Ledger, 0, len(ledgerIDs))
+	if err := db.DB.Where("id IN ?", ledgerIDs).Order("created_at asc").Find(&ledgers).Error; err != nil {
+		return nil, err
+	}
+
+	type memberCount struct {
+		LedgerID uuid.UUID
+		Count    int
+	}
+	counts := make([]memberCount, 0, len(ledgerIDs))
+	if err := db.DB.Model(&model.LedgerMember{}).
+		Select("ledger_id, count(*) as count").
+		Where("ledger_id IN ?", ledgerIDs).
+		Group("ledger_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	countMap := make(map[uuid.UUID]int, len(counts))
+	for _, item := range counts {
+		countMap[item.LedgerID] = item.Count
+	}
+
+	for _, ledger := range ledgers {
+		result = append(result, LedgerSummary{
+			Ledger:      ledger,
+			Role:        roles[ledger.Id],
+			MemberCount: countMap[ledger.Id],
+		})
+	}
+	return result, nil
+}
+
+func RenameLedger(userID string, ledgerID string, name string) (*model.Ledger, error) {
+	name, err := normalizeLedgerName(name)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := requireLedgerOwner(db.DB, ledgerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ledger.Name = name
+	if err := db.DB.Model(&model.Ledger{}).Where("id = ?", ledger.Id).Update("name", name).Error; err != nil {
+		return nil, err
+	}
+	return ledger, nil
+}
+
+// DeleteLedger 删除账本及其中的全部票据和推荐，只有所有者可以操作。
+func DeleteLedger(userID string, ledgerID string) error {
+	imagePaths := make([]string, 0)
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		ledger, err := requireLedgerOwner(tx, ledgerID, userID)
+		if err != nil {
+			return err
+		}
+		imagePaths, err = deleteLedgerRecords(tx, ledger.Id)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	cleanupUnusedTicketImages(imagePaths)
+	return nil
+}
+
+func ListLedgerMembers(userID string, ledgerID string) ([]LedgerMemberDetail, error) {
+	if _, err := findLedgerMember(db.DB, ledgerID, userID); err != nil {
+		return nil, err
+	}
+
+	members := make([]model.LedgerMember, 0)
+	if err := db.DB.Where("ledger_id = ?", ledgerID).Order("created_at asc").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	userIDs := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	users := make([]userModel.User, 0, len(userIDs))
+	if len(userIDs) > 0 {
+		if err := db.DB.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+			return nil, err
+		}
+	}
+	userMap := make(map[uuid.UUID]userModel.User, len(users))
+	for _, user := range users {
+		userMap[user.Id] = user
+	}
+
+	result := make([]LedgerMemberDetail, 0, len(members))
+	for _, member := range members {
+		user := userMap[member.UserID]
+		result = append(result, LedgerMemberDetail{
+			LedgerMember: member,
+			Username:     user.Username,
+			DisplayName:  user.DisplayName,
+		})
+	}
+	return result, nil
+}
+
+// AddLedgerMember 按用户名邀请成员加入账本，所有者角色不能通过邀请授予。
+func AddLedgerMember(userID string, ledgerID string, username string, role string) (*LedgerMemberDetail, error) {
+	if role == "" {
+		role = model.LedgerRoleViewer
+	}
+	if !isAssignableLedgerRole(role) {
+		return nil, ErrInvalidLedgerRole
+	}
+
+	ledger, err := requireLedgerOwner(db.DB, ledgerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user := userModel.User{}
+	if err := db.DB.Where("username = ?", strings.TrimSpace(username)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLedgerUserNotFound
+		}
+		return nil, err
+	}
+
+	if _, err := findLedgerMember(db.DB, ledger.Id.String(), user.Id.String()); err == nil {
+		return nil, ErrLedgerMemberExists
+	} else if !errors.Is(err, ErrLedgerNotFound) {
+		return nil, err
+	}
+
+	member := model.LedgerMember{LedgerID: ledger.Id, UserID: user.Id, Role: role}
+	if err := db.DB.Create(&member).Error; err != nil {
+		if isUniqueConstraintError(err) {
+			return nil, ErrLedgerMemberExists
+		}
+		return nil, err
+	}
+	return &LedgerMemberDetail{LedgerMember: member, Username: user.Username, DisplayName: user.DisplayName}, nil
+}
+
+func UpdateLedgerMemberRole(userID string, ledgerID string, memberUserID string, role string) error {
+	if !isAssignableLedgerRole(role) {
+		return ErrInvalidLedgerRole
+	}
+	ledger, err := requireLedgerOwner(db.DB, ledgerID, userID)
+	if err != nil {
+		return err
+	}
+	if ledger.OwnerID.String() == memberUserID {
+		return ErrInvalidLedgerRole
+	}
+
+	result := db.DB.Model(&model.LedgerMember{}).
+		Where("ledger_id = ? AND user_id = ?", ledger.Id, memberUserID).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLedgerMemberNotFound
+	}
+	return nil
+}
+
+// RemoveLedgerMember 由所有者移除成员，成员也可以移除自己以退出账本；成员录入的数据仍保留在账本中。
+func RemoveLedgerMember(userID string, ledgerID string, memberUserID string) error {
+	member, err := findLedgerMember(db.DB, ledgerID, userID)
+	if err != nil {
+		return err
+	}
+	if memberUserID == userID {
+		if member.Role == model.LedgerRoleOwner {
+			return ErrLedgerOwnerCannotLeave
+		}
+	} else if member.Role != model.LedgerRoleOwner {
+		return ErrLedgerOwnerRequired
+	}
+
+	result := db.DB.Where("ledger_id = ? AND user_id = ? AND role <> ?", member.LedgerID, memberUserID, model.LedgerRoleOwner).
+		Delete(&model.LedgerMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLedgerMemberNotFound
+	}
+	return nil
+}
+
+func findLedgerMember(database *gorm.DB, ledgerID string, userID string) (*model.LedgerMember, error) {
+	if _, err := uuid.Parse(ledgerID); err != nil {
+		return nil, ErrLedgerNotFound
+	}
+	member := model.LedgerMember{}
+	if err := database.Where("ledger_id = ? AND user_id = ?", ledgerID, userID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLedgerNotFound
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+func requireLedgerOwner(database *gorm.DB, ledgerID string, userID string) (*model.Ledger, error) {
+	member, err := findLedgerMember(database, ledgerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role != model.LedgerRoleOwner {
+		return nil, ErrLedgerOwnerRequired
+	}
+	ledger := model.Ledger{}
+	if err := database.First(&ledger, "id = ?", member.LedgerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLedgerNotFound
+		}
+		return nil, err
+	}
+	return &ledger, nil
+}
+
+// deleteLedgerRecords 在调用方事务中删除账本、成员以及账本内的票据和推荐，返回待清理的图片路径。
+func deleteLedgerRecords(tx *gorm.DB, ledgerID uuid.UUID) ([]string, error) {
+	imagePaths, err := deleteOwnedRecords(tx, Owner{LedgerID: ledgerID.String()})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Where("ledger_id = ?", ledgerID).Delete(&model.LedgerMember{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Delete(&model.Ledger{}, "id = ?", ledgerID).Error; err != nil {
+		return nil, err
+	}
+	return imagePaths, nil
+}
+
+func normalizeLedgerName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrLedgerNameRequired
+	}
+	if utf8.RuneCountInString(name) > maxLedgerNameLength {
+		name = string([]rune(name)[:maxLedgerNameLength])
+	}
+	return name, nil
+}
+
+func isAssignableLedgerRole(role string) bool {
+	return model.IsValidLedgerRole(role) && role != model.LedgerRoleOwner
+}
@@ -0,0 +1,110 @@
+package lottery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	model "go-fiber-starter/internal/model/lottery"
+	userModel "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/pkg/db"
+)
+
+func createLedgerTestUser(t *testing.T, username string) string {
+	t.Helper()
+
+	user := userModel.User{Username: username, Password: "hash", Role: userModel.RoleUser}
+	if err := db.DB.Create(&user).Error; err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	return user.Id.String()
+}
+
+func TestLedgerMembersShareTicketsByRole(t *testing.T) {
+	setupImportTicketTestDB(t)
+	alice := createLedgerTestUser(t, "alice")
+	bob := createLedgerTestUser(t, "bob")
+	carol := createLedgerTestUser(t, "carol")
+	dave := createLedgerTestUser(t, "dave")
+
+	ledger, err := CreateLedger(alice, "家庭账本")
+	if err != nil {
+		t.Fatalf("create ledger: %v", err)
+	}
+	ledgerID := ledger.Id.String()
+	if _, err := AddLedgerMember(alice, ledgerID, "bob", "editor"); err != nil {
+		t.Fatalf("add editor: %v", err)
+	}
+	if _, err := AddLedgerMember(alice, ledgerID, "carol", "viewer"); err != nil {
+		t.Fatalf("add viewer: %v", err)
+	}
+	if _, err := AddLedgerMember(bob, ledgerID, "dave", "viewer"); !errors.Is(err, ErrLedgerOwnerRequired) {
+		t.Fatalf("expected only owner to invite, got %v", err)
+	}
+	if _, err := AddLedgerMember(alice, ledgerID, "carol", "editor"); !errors.Is(err, ErrLedgerMemberExists) {
+		t.Fatalf("expected duplicate member error, got %v", err)
+	}
+
+	if _, err := ResolveOwner(carol, ledgerID, true); !errors.Is(err, ErrLedgerReadOnly) {
+		t.Fatalf("expected viewer write to be rejected, got %v", err)
+	}
+	if _, err := ResolveOwner(dave, ledgerID, false); !errors.Is(err, ErrLedgerNotFound) {
+		t.Fatalf("expected non-member to be rejected, got %v", err)
+	}
+
+	editor, err := ResolveOwner(bob, ledgerID, true)
+	if err != nil {
+		t.Fatalf("resolve editor: %v", err)
+	}
+	if _, err := CreateTicket(context.Background(), CreateTicketInput{
+		UserID:   editor.UserID,
+		LedgerID: editor.LedgerID,
+		Code:     "ssq",
+		Issue:    "2099010",
+		DrawDate: time.Now().AddDate(1, 0, 0),
+		Entries:  []ParsedEntry{{Red: []int{1, 2, 3, 4, 5, 6}, Blue: []int{7}}},
+	}); err != nil {
+		t.Fatalf("create ledger ticket: %v", err)
+	}
+
+	viewer, err := ResolveOwner(carol, ledgerID, false)
+	if err != nil {
+		t.Fatalf("resolve viewer: %v", err)
+	}
+	dashboard, err := GetGlobalDashboard(viewer)
+	if err != nil {
+		t.Fatalf("ledger dashboard: %v", err)
+	}
+	if dashboard.Stats.TotalTickets != 1 || dashboard.Stats.TotalCost != 2*model.Yuan {
+		t.Fatalf("expected viewer to see shared ticket, got %+v", dashboard.Stats)
+	}
+
+	personal, err := ListAllTickets(50, PersonalOwner(bob))
+	if err != nil {
+		t.Fatalf("list personal tickets: %v", err)
+	}
+	if len(personal) != 0 {
+		t.Fatalf("ledger ticket should not appear in personal list, got %d", len(personal))
+	}
+
+	if err := DeleteLedger(bob, ledgerID); !errors.Is(err, ErrLedgerOwnerRequired) {
+		t.Fatalf("expected editor delete to be rejected, got %v", err)
+	}
+	if err := RemoveLedgerMember(carol, ledgerID, carol); err != nil {
+		t.Fatalf("viewer leave ledger: %v", err)
+	}
+	if _, err := ResolveOwner(carol, ledgerID, false); !errors.Is(err, ErrLedgerNotFound) {
+		t.Fatalf("expected removed member to lose access, got %v", err)
+	}
+	if err := DeleteLedger(alice, ledgerID); err != nil {
+		t.Fatalf("delete ledger: %v", err)
+	}
+	remaining, err := ListAllTickets(50, Owner{LedgerID: ledgerID})
+	if err != nil {
+		t.Fatalf("list ledger tickets: %v", err)
+	}
+	if len(remaining) != 0 {
+		t.Fatalf("expected ledger tickets to be removed with the ledger, got %d", len(remaining))
+	}
+}
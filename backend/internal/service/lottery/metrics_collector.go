@@ -0,0 +1,58 @@
+package lottery
+
+import (
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/pkg/db"
+	"go-fiber-starter/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var pendingTicketsDesc = prometheus.NewDesc(
+	"lottery_pending_tickets",
+	"待开奖票据数量，按彩种统计。",
+	[]string{"lottery_code"},
+	nil,
+)
+
+// pendingTicketCollector 在每次抓取指标时统计待开奖票据，数量随判奖变化，不需要在业务代码中维护计数。
+type pendingTicketCollector struct{}
+
+func (pendingTicketCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pendingTicketsDesc
+}
+
+func (pendingTicketCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := countPendingTickets()
+	if err != nil {
+		logger.Warn("统计待开奖票据失败: %v", err)
+		return
+	}
+	for _, definition := range ListDefinitions() {
+		if _, exists := counts[definition.Code]; !exists && definition.Enabled {
+			counts[definition.Code] = 0
+		}
+	}
+	for code, count := range counts {
+		ch <- prometheus.MustNewConstMetric(pendingTicketsDesc, prometheus.GaugeValue, float64(count), code)
+	}
+}
+
+func countPendingTickets() (map[string]int64, error) {
+	rows := make([]struct {
+		LotteryCode string
+		Total       int64
+	}, 0)
+	if err := db.DB.Model(&model.Ticket{}).
+		Select("lottery_code, COUNT(*) AS total").
+		Where("status = ?", TicketStatusPending).
+		Group("lottery_code").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.LotteryCode] = row.Total
+	}
+	return counts, nil
+}
@@ -0,0 +1,25 @@
+package lottery
+
+import (
+	"testing"
+
+	model "go-fiber-starter/internal/model/lottery"
+)
+
+func TestCountPendingTicketsGroupsByLottery(t *testing.T) {
+	setupImportTicketTestDB(t)
+	userID := createLedgerTestUser(t, "alice")
+
+	createHistoryTestTicket(t, userID, model.Ticket{Issue: "2026020"})
+	createHistoryTestTicket(t, userID, model.Ticket{Issue: "2026021"})
+	createHistoryTestTicket(t, userID, model.Ticket{LotteryCode: "dlt", Issue: "26020"})
+	createHistoryTestTicket(t, userID, model.Ticket{LotteryCode: "dlt", Issue: "26019", Status: TicketStatusWon})
+
+	counts, err := countPendingTickets()
+	if err != nil {
+		t.Fatalf("count pending tickets: %v", err)
+	}
+	if counts["ssq"] != 2 || counts["dlt"] != 1 {
+		t.Fatalf("unexpected pending counts: %v", counts)
+	}
+}
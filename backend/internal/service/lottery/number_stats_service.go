@@ -0,0 +1,100 @@
+package lottery
+
+import (
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/pkg/db"
+)
+
+const (
+	defaultFrequencyDraws = 30
+	maxFrequencyDraws     = 500
+)
+
+// NumberFrequency 是单个号码在样本中出现的次数。
+type NumberFrequency struct {
+	Number int `json:"number"`
+	Count  int `json:"count"`
+}
+
+// NumberFrequencyResult 按区域列出彩种号码范围内每个号码的出现次数，未出现的号码次数为 0。
+// 开奖统计的 SampleSize 是期数，投注统计的 SampleSize 是注数。
+type NumberFrequencyResult struct {
+	LotteryCode string            `json:"lotteryCode"`
+	SampleSize  int               `json:"sampleSize"`
+	Red         []NumberFrequency `json:"red"`
+	Blue        []NumberFrequency `json:"blue"`
+}
+
+type numberMaskRow struct {
+	RedMask  int64
+	BlueMask int64
+}
+
+// GetDrawNumberFrequency 统计最近 limit 期开奖号码的出现次数，只读取号码位图列。
+func GetDrawNumberFrequency(code string, limit int) (*NumberFrequencyResult, error) {
+	definition, err := GetDefinition(code)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultFrequencyDraws
+	}
+	limit = min(limit, maxFrequencyDraws)
+
+	rows := make([]numberMaskRow, 0, limit)
+	if err := db.DB.Model(&model.DrawResult{}).
+		Select("red_mask", "blue_mask").
+		Where("lottery_code = ?", code).
+		Order("draw_date desc").
+		Order("issue desc").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return buildNumberFrequency(definition, rows), nil
+}
+
+// GetTicketNumberFrequency 统计当前账户或共享账本已录入投注中每个号码被选中的注数。
+func GetTicketNumberFrequency(code string, owner Owner) (*NumberFrequencyResult, error) {
+	definition, err := GetDefinition(code)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := ownerScope(db.DB.Model(&model.Ticket{}), owner).Select("id").Where("lottery_code = ?", code)
+	rows := make([]numberMaskRow, 0)
+	if err := db.DB.Model(&model.TicketEntry{}).
+		Select("red_mask", "blue_mask").
+		Where("ticket_id IN (?)", tickets).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return buildNumberFrequency(definition, rows), nil
+}
+
+func buildNumberFrequency(definition Definition, rows []numberMaskRow) *NumberFrequencyResult {
+	redCounts := make(map[int]int)
+	blueCounts := make(map[int]int)
+	for _, row := range rows {
+		for _, number := range model.MaskNumbers(row.RedMask) {
+			redCounts[number]++
+		}
+		for _, number := range model.MaskNumbers(row.BlueMask) {
+			blueCounts[number]++
+		}
+	}
+	return &NumberFrequencyResult{
+		LotteryCode: definition.Code,
+		SampleSize:  len(rows),
+		Red:         numberFrequencyRange(definition.RedMin, definition.RedMax, redCounts),
+		Blue:        numberFrequencyRange(definition.BlueMin, definition.BlueMax, blueCounts),
+	}
+}
+
+func numberFrequencyRange(minNumber int, maxNumber int, counts map[int]int) []NumberFrequency {
+	items := make([]NumberFrequency, 0, max(0, maxNumber-minNumber+1))
+	for number := minNumber; number <= maxNumber; number++ {
+		items = append(items, NumberFrequency{Number: number, Count: counts[number]})
+	}
+	return items
+}
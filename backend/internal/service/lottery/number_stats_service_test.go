@@ -0,0 +1,66 @@
+package lottery
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/pkg/db"
+)
+
+func TestNumberFrequencyUsesMasksMaintainedOnWrite(t *testing.T) {
+	setupImportTicketTestDB(t)
+	userID := createLedgerTestUser(t, "alice")
+
+	base := time.Date(2026, 3, 1, 21, 15, 0, 0, time.Local)
+	for index, numbers := range [][2]string{
+		{"01,07,12,20,28,33", "16"},
+		{"02,07,13,21,29,33", "05"},
+		{"03,08,14,22,30,32", "16"},
+	} {
+		draw := model.DrawResult{LotteryCode: "ssq", Issue: fmt.Sprintf("202600%d", index+1), DrawDate: base.AddDate(0, 0, index), RedNumbers: numbers[0], BlueNumbers: numbers[1]}
+		if err := db.DB.Create(&draw).Error; err != nil {
+			t.Fatalf("create draw: %v", err)
+		}
+	}
+
+	frequency, err := GetDrawNumberFrequency("ssq", 2)
+	if err != nil {
+		t.Fatalf("draw frequency: %v", err)
+	}
+	if frequency.SampleSize != 2 || len(frequency.Red) != 33 || len(frequency.Blue) != 16 {
+		t.Fatalf("unexpected frequency shape: %+v", frequency)
+	}
+	// 最近两期为第 2、3 期：红球 07 只在第 2 期出现，蓝球 16 只在第 3 期出现。
+	if frequency.Red[6].Number != 7 || frequency.Red[6].Count != 1 || frequency.Red[0].Count != 0 || frequency.Blue[15].Count != 1 {
+		t.Fatalf("unexpected frequency counts: red=%+v blue=%+v", frequency.Red[:7], frequency.Blue)
+	}
+
+	ticket := createHistoryTestTicket(t, userID, model.Ticket{Issue: "2026003", Entries: []model.TicketEntry{
+		{Sequence: 1, RedNumbers: "03,08,14,22,30,32", BlueNumbers: "16", Multiple: 1},
+		{Sequence: 2, RedNumbers: "03,09,15,23,31,33", BlueNumbers: "01", Multiple: 1},
+	}})
+	if err := EvaluateTicket(ticket.Id.String()); err != nil {
+		t.Fatalf("evaluate ticket: %v", err)
+	}
+	if err := resetTicketPending(ticket.Id.String()); err != nil {
+		t.Fatalf("reset ticket: %v", err)
+	}
+
+	ticketFrequency, err := GetTicketNumberFrequency("ssq", PersonalOwner(userID))
+	if err != nil {
+		t.Fatalf("ticket frequency: %v", err)
+	}
+	if ticketFrequency.SampleSize != 2 || ticketFrequency.Red[2].Count != 2 || ticketFrequency.Red[7].Count != 1 || ticketFrequency.Blue[0].Count != 1 {
+		t.Fatalf("unexpected ticket frequency: %+v", ticketFrequency)
+	}
+
+	page, err := QueryAllTickets(TicketQueryOptions{UserID: userID, Number: 3, NumberZone: "red"})
+	if err != nil || page.Total != 1 {
+		t.Fatalf("expected ticket matched by red 03, got %+v (%v)", page, err)
+	}
+	if _, err := QueryAllTickets(TicketQueryOptions{UserID: userID, Number: 99}); err == nil {
+		t.Fatalf("expected out of range number rejected")
+	}
+}
@@ -12,9 +12,16 @@ import (
 	"strings"
 	"time"
 
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/internal/tracing"
 	"go-fiber-starter/pkg/config"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// CodeModelRequestFailed 表示调用 OpenAI 兼容模型失败。
+const CodeModelRequestFailed = "MODEL_REQUEST_FAILED"
+
 type openAIMessage struct {
 	Role    string `json:"role"`
 	Content any    `json:"content"`
@@ -89,7 +96,7 @@ func callVisionModel(ctx context.Context, model string, prompt string, imagePath
 	)
 }
 
-func callOpenAICompatible(ctx context.Context, baseURL string, apiKey string, model string, timeout time.Duration, messages []openAIMessage) (string, error) {
+func callOpenAICompatible(ctx context.Context, baseURL string, apiKey string, model string, timeout time.Duration, messages []openAIMessage) (content string, err error) {
 	if baseURL == "" || apiKey == "" || model == "" {
 		return "", fmt.Errorf("未配置 OpenAI 兼容模型")
 	}
@@ -99,6 +106,11 @@ func callOpenAICompatible(ctx context.Context, baseURL string, apiKey string, mo
 	if !strings.HasSuffix(endpoint, "/chat/completions") {
 		endpoint += "/chat/completions"
 	}
+	ctx, span := tracing.StartClient(ctx, "openai-compatible "+http.MethodPost,
+		tracing.URLAttribute(endpoint),
+		attribute.String("gen_ai.request.model", model),
+	)
+	defer func() { tracing.End(span, err) }()
 
 	requestBody := openAIRequest{
 		Model:    model,
@@ -106,50 +118,52 @@ func callOpenAICompatible(ctx context.Context, baseURL string, apiKey string, mo
 	}
 	rawRequest, err := json.Marshal(requestBody)
 	if err != nil {
-		logThirdPartyFailure("openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), nil, 0, startedAt, err)
-		return "", err
+		logThirdPartyFailure(ctx, "openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), nil, 0, startedAt, err)
+		return "", apperr.Upstream(CodeModelRequestFailed, err)
 	}
 
 	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(rawRequest))
 	if err != nil {
-		logThirdPartyFailure("openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), nil, 0, startedAt, err)
-		return "", err
+		logThirdPartyFailure(ctx, "openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), nil, 0, startedAt, err)
+		return "", apperr.Upstream(CodeModelRequestFailed, err)
 	}
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Authorization", "Bearer "+apiKey)
+	tracing.InjectHTTP(ctx, request.Header)
 
 	client := &http.Client{Timeout: timeout}
 	response, err := client.Do(request)
 	if err != nil {
-		logThirdPartyFailure("openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), nil, 0, startedAt, err)
-		return "", err
+		logThirdPartyFailure(ctx, "openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), nil, 0, startedAt, err)
+		return "", apperr.Upstream(CodeModelRequestFailed, err)
 	}
 	defer response.Body.Close()
+	tracing.HTTPStatusAttribute(span, response.StatusCode)
 
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		logThirdPartyFailure("openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), nil, response.StatusCode, startedAt, err)
-		return "", err
+		logThirdPartyFailure(ctx, "openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), nil, response.StatusCode, startedAt, err)
+		return "", apperr.Upstream(CodeModelRequestFailed, err)
 	}
 	if response.StatusCode >= http.StatusBadRequest {
 		requestErr := buildOpenAIHTTPError(response.StatusCode, response.Header.Get("Content-Type"), body)
-		logThirdPartyFailure("openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), body, response.StatusCode, startedAt, requestErr)
-		return "", requestErr
+		logThirdPartyFailure(ctx, "openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), body, response.StatusCode, startedAt, requestErr)
+		return "", apperr.Upstream(CodeModelRequestFailed, requestErr)
 	}
 
 	parsed := openAIResponse{}
 	if err := json.Unmarshal(body, &parsed); err != nil {
 		requestErr := buildOpenAIJSONError(response.StatusCode, response.Header.Get("Content-Type"), body, err)
-		logThirdPartyFailure("openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), body, response.StatusCode, startedAt, requestErr)
-		return "", requestErr
+		logThirdPartyFailure(ctx, "openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), body, response.StatusCode, startedAt, requestErr)
+		return "", apperr.Upstream(CodeModelRequestFailed, requestErr)
 	}
 	if len(parsed.Choices) == 0 {
 		requestErr := fmt.Errorf("模型未返回内容")
-		logThirdPartyFailure("openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), body, response.StatusCode, startedAt, requestErr)
-		return "", requestErr
+		logThirdPartyFailure(ctx, "openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), body, response.StatusCode, startedAt, requestErr)
+		return "", apperr.Upstream(CodeModelRequestFailed, requestErr)
 	}
 
-	logThirdPartySuccess("openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), body, response.StatusCode, startedAt)
+	logThirdPartySuccess(ctx, "openai-compatible", http.MethodPost, endpoint, buildOpenAIRequestLog(requestBody), body, response.StatusCode, startedAt)
 	return stripJSONFence(parsed.Choices[0].Message.Content), nil
 }
 
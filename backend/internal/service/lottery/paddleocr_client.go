@@ -16,6 +16,9 @@ import (
 	"strings"
 	"time"
 
+	"go-fiber-starter/internal/metrics"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/internal/tracing"
 	"go-fiber-starter/pkg/config"
 
 	_ "image/gif"
@@ -24,6 +27,9 @@ import (
 	xdraw "golang.org/x/image/draw"
 )
 
+// CodeOCRRequestFailed 表示调用 PaddleOCR 服务失败。
+const CodeOCRRequestFailed = "OCR_REQUEST_FAILED"
+
 type paddleOCRPayload struct {
 	RawText    string   `json:"rawText"`
 	Confidence float64  `json:"confidence"`
@@ -131,12 +137,12 @@ func callPaddleOCR(ctx context.Context, imagePath string) ([]byte, error) {
 		body, statusCode, err := doPaddleOCRRequest(ctx, baseURL, requestPayload, perAttemptTimeout)
 		requestLog := buildPaddleOCRRequestLog(imagePath, requestPayload, imageMeta)
 		if err == nil {
-			logThirdPartySuccess("paddleocr", http.MethodPost, baseURL, requestLog, body, statusCode, attemptStartedAt)
+			logThirdPartySuccess(ctx, "paddleocr", http.MethodPost, baseURL, requestLog, body, statusCode, attemptStartedAt)
 			return body, nil
 		}
 
 		lastErr = err
-		logThirdPartyFailure("paddleocr", http.MethodPost, baseURL, requestLog, body, statusCode, attemptStartedAt, err)
+		logThirdPartyFailure(ctx, "paddleocr", http.MethodPost, baseURL, requestLog, body, statusCode, attemptStartedAt, err)
 		if !shouldRetryPaddleOCR(statusCode, err) {
 			break
 		}
@@ -145,7 +151,7 @@ func callPaddleOCR(ctx context.Context, imagePath string) ([]byte, error) {
 	if lastErr == nil {
 		lastErr = fmt.Errorf("PaddleOCR 服务未返回结果")
 	}
-	return nil, lastErr
+	return nil, apperr.Upstream(CodeOCRRequestFailed, lastErr)
 }
 
 func parsePaddleOCRPayload(output []byte) (*paddleOCRPayload, error) {
@@ -335,7 +341,10 @@ func applyBinaryThreshold(target *image.RGBA) {
 	}
 }
 
-func doPaddleOCRRequest(ctx context.Context, baseURL string, requestPayload paddleOCRRequest, timeout time.Duration) ([]byte, int, error) {
+func doPaddleOCRRequest(ctx context.Context, baseURL string, requestPayload paddleOCRRequest, timeout time.Duration) (body []byte, statusCode int, err error) {
+	ctx, span := tracing.StartClient(ctx, "paddleocr "+http.MethodPost, tracing.URLAttribute(baseURL))
+	defer func() { tracing.End(span, err) }()
+
 	requestBody, err := json.Marshal(requestPayload)
 	if err != nil {
 		return nil, 0, fmt.Errorf("构造 PaddleOCR 请求失败: %w", err)
@@ -352,14 +361,16 @@ func doPaddleOCRRequest(ctx context.Context, baseURL string, requestPayload padd
 	if token := strings.TrimSpace(config.Current.Vision.APIKey); token != "" {
 		request.Header.Set("Authorization", "token "+token)
 	}
+	tracing.InjectHTTP(ctx, request.Header)
 
 	response, err := http.DefaultClient.Do(request)
 	if err != nil {
 		return nil, 0, fmt.Errorf("调用 PaddleOCR 服务失败: %w", err)
 	}
 	defer response.Body.Close()
+	tracing.HTTPStatusAttribute(span, response.StatusCode)
 
-	body, err := io.ReadAll(response.Body)
+	body, err = io.ReadAll(response.Body)
 	if err != nil {
 		return nil, response.StatusCode, fmt.Errorf("读取 PaddleOCR 响应失败: %w", err)
 	}
@@ -404,8 +415,10 @@ func buildPaddleOCRImageSource(fileType int, fileBytes []byte) paddleOCRImageSou
 }
 
 func acquirePaddleOCRWorkerSlot(ctx context.Context) error {
+	startedAt := time.Now()
 	select {
 	case paddleOCRWorkerSlots <- struct{}{}:
+		metrics.PaddleOCRSlotAcquired(time.Since(startedAt))
 		return nil
 	case <-ctx.Done():
 		return fmt.Errorf("等待 OCR 处理资源失败: %w", ctx.Err())
@@ -415,6 +428,7 @@ func acquirePaddleOCRWorkerSlot(ctx context.Context) error {
 func releasePaddleOCRWorkerSlot() {
 	select {
 	case <-paddleOCRWorkerSlots:
+		metrics.PaddleOCRSlotReleased()
 	default:
 	}
 }
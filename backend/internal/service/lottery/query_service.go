@@ -14,52 +14,91 @@ type DashboardData struct {
 }
 
 type DashboardStats struct {
-	TotalTickets             int     `json:"totalTickets"`
-	WonTickets               int     `json:"wonTickets"`
-	TotalCost                float64 `json:"totalCost"`
-	TotalPrize               float64 `json:"totalPrize"`
-	TotalRecommendations     int     `json:"totalRecommendations"`
-	PurchasedRecommendations int     `json:"purchasedRecommendations"`
+	TotalTickets             int         `json:"totalTickets"`
+	WonTickets               int         `json:"wonTickets"`
+	TotalCost                model.Money `json:"totalCost" swaggertype:"number"`
+	TotalPrize               model.Money `json:"totalPrize" swaggertype:"number"`
+	TotalRecommendations     int         `json:"totalRecommendations"`
+	PurchasedRecommendations int         `json:"purchasedRecommendations"`
+	SyndicateTickets         int         `json:"syndicateTickets"`
+	SyndicateCost            model.Money `json:"syndicateCost" swaggertype:"number"`
+	SyndicatePrize           model.Money `json:"syndicatePrize" swaggertype:"number"`
 }
 
-func loadDashboardStats(code string, userID string) DashboardStats {
+func loadDashboardStats(code string, owner Owner) DashboardStats {
 	stats := DashboardStats{}
 	var totalTickets int64
 	var wonTickets int64
 
-	query := currentUserScope(db.DB.Model(&model.Ticket{}), userID)
+	query := ownerScope(db.DB.Model(&model.Ticket{}), owner)
 	if code != "" {
 		query = query.Where("lottery_code = ?", code)
 	}
 	query.Count(&totalTickets)
 
-	winQuery := currentUserScope(db.DB.Model(&model.Ticket{}), userID).Where("status = ?", TicketStatusWon)
+	winQuery := ownerScope(db.DB.Model(&model.Ticket{}), owner).Where("status = ?", TicketStatusWon)
 	if code != "" {
 		winQuery = winQuery.Where("lottery_code = ?", code)
 	}
 	winQuery.Count(&wonTickets)
 
-	costQuery := currentUserScope(db.DB.Model(&model.Ticket{}), userID)
+	// 个人统计中合买票据只计入本人份额，账本统计仍按票据整体金额计算。
+	personal := owner.LedgerID == "" && owner.UserID != ""
+
+	costQuery := ownerScope(db.DB.Model(&model.Ticket{}), owner)
 	if code != "" {
 		costQuery = costQuery.Where("lottery_code = ?", code)
 	}
-	costQuery.Select("COALESCE(sum(cost_amount), 0)").Scan(&stats.TotalCost)
+	if personal {
+		costQuery = costQuery.Where("is_syndicate = ?", false)
+	}
+	costQuery.Select("COALESCE(sum(cost_amount_cents), 0)").Scan(&stats.TotalCost)
 
-	prizeQuery := currentUserScope(db.DB.Model(&model.Ticket{}), userID)
+	prizeQuery := ownerScope(db.DB.Model(&model.Ticket{}), owner)
 	if code != "" {
 		prizeQuery = prizeQuery.Where("lottery_code = ?", code)
 	}
-	prizeQuery.Select("COALESCE(sum(prize_amount), 0)").Scan(&stats.TotalPrize)
+	if personal {
+		prizeQuery = prizeQuery.Where("is_syndicate = ?", false)
+	}
+	prizeQuery.Select("COALESCE(sum(prize_amount_cents), 0)").Scan(&stats.TotalPrize)
+
+	if personal {
+		loadSyndicateStats(code, owner.UserID, &stats)
+		stats.TotalCost += stats.SyndicateCost
+		stats.TotalPrize += stats.SyndicatePrize
+	}
 
 	stats.TotalTickets = int(totalTickets)
 	stats.WonTickets = int(wonTickets)
-	stats.TotalRecommendations = loadRecommendationCount(code, userID)
-	stats.PurchasedRecommendations = loadPurchasedRecommendationCount(code, userID)
+	stats.TotalRecommendations = loadRecommendationCount(code, owner)
+	stats.PurchasedRecommendations = loadPurchasedRecommendationCount(code, owner)
 	return stats
 }
 
-func loadRecommendationCount(code string, userID string) int {
-	query := currentUserScope(db.DB.Model(&model.Recommendation{}), userID)
+func loadSyndicateStats(code string, userID string, stats *DashboardStats) {
+	var result struct {
+		Tickets int64
+		Cost    model.Money
+		Prize   model.Money
+	}
+	query := db.DB.Model(&model.TicketShare{}).
+		Joins("JOIN tickets ON tickets.id = ticket_shares.ticket_id").
+		Where("ticket_shares.user_id = ?", userID)
+	if code != "" {
+		query = query.Where("tickets.lottery_code = ?", code)
+	}
+	if err := query.Select("count(*) as tickets, COALESCE(sum(ticket_shares.cost_amount_cents), 0) as cost, COALESCE(sum(ticket_shares.prize_amount_cents), 0) as prize").
+		Scan(&result).Error; err != nil {
+		return
+	}
+	stats.SyndicateTickets = int(result.Tickets)
+	stats.SyndicateCost = result.Cost
+	stats.SyndicatePrize = result.Prize
+}
+
+func loadRecommendationCount(code string, owner Owner) int {
+	query := ownerScope(db.DB.Model(&model.Recommendation{}), owner)
 	if code != "" {
 		query = query.Where("lottery_code = ?", code)
 	}
@@ -71,8 +110,8 @@ func loadRecommendationCount(code string, userID string) int {
 	return int(count)
 }
 
-func loadPurchasedRecommendationCount(code string, userID string) int {
-	query := currentUserScope(db.DB.Model(&model.Ticket{}), userID).Where("recommendation_id IS NOT NULL")
+func loadPurchasedRecommendationCount(code string, owner Owner) int {
+	query := ownerScope(db.DB.Model(&model.Ticket{}), owner).Where("recommendation_id IS NOT NULL")
 	if code != "" {
 		query = query.Where("lottery_code = ?", code)
 	}
@@ -100,15 +139,15 @@ func ListLotteryTypes() ([]model.LotteryType, error) {
 	return items, nil
 }
 
-func GetLatestRecommendation(code string, userID string) (*model.Recommendation, error) {
+func GetLatestRecommendation(code string, owner Owner) (*model.Recommendation, error) {
 	item := model.Recommendation{}
-	if err := currentUserScope(db.DB.Preload("Entries"), userID).Where("lottery_code = ?", code).Order("created_at desc").First(&item).Error; err != nil {
+	if err := ownerScope(db.DB.Preload("Entries"), owner).Where("lottery_code = ?", code).Order("created_at desc").First(&item).Error; err != nil {
 		return nil, err
 	}
 	return &item, nil
 }
 
-func GetDashboard(code string, userID string) (*DashboardData, error) {
+func GetDashboard(code string, owner Owner) (*DashboardData, error) {
 	lotteryType, err := getLotteryType(code)
 	if err != nil {
 		return nil, err
@@ -121,12 +160,12 @@ func GetDashboard(code string, userID string) (*DashboardData, error) {
 	}
 
 	var latestRecommendation *model.Recommendation
-	recommendation, err := GetLatestRecommendation(code, userID)
+	recommendation, err := GetLatestRecommendation(code, owner)
 	if err == nil {
 		latestRecommendation = recommendation
 	}
 
-	recentTickets, err := ListTickets(code, 10, userID)
+	recentTickets, err := ListTickets(code, 10, owner)
 	if err != nil {
 		return nil, err
 	}
@@ -136,13 +175,13 @@ func GetDashboard(code string, userID string) (*DashboardData, error) {
 		LatestDraw:           latestDraw,
 		LatestRecommendation: latestRecommendation,
 		RecentTickets:        recentTickets,
-		Stats:                loadDashboardStats(code, userID),
+		Stats:                loadDashboardStats(code, owner),
 	}, nil
 }
 
-func GetGlobalDashboard(userID string) (*DashboardData, error) {
+func GetGlobalDashboard(owner Owner) (*DashboardData, error) {
 	return &DashboardData{
 		RecentTickets: make([]TicketDetail, 0),
-		Stats:         loadDashboardStats("", userID),
+		Stats:         loadDashboardStats("", owner),
 	}, nil
 }
@@ -1,7 +1,6 @@
 package lottery
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -51,7 +50,7 @@ func DetectLotteryByText(text string) (*RecognitionResult, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("未识别出彩票类型，请补充 OCR 文本或手动选择彩种")
+	return nil, ErrLotteryCodeUnresolved.WithMessage("未识别出彩票类型，请补充 OCR 文本或手动选择彩种")
 }
 
 func detectLikelyLotteryCodes(text string) []string {
@@ -68,7 +67,7 @@ func detectLikelyLotteryCodes(text string) []string {
 func getLotteryRecognitionParser(code string) (LotteryRecognitionParser, error) {
 	parser, ok := lotteryRecognitionParsers[code]
 	if !ok {
-		return nil, fmt.Errorf("当前暂不支持 %s 的自动识别", code)
+		return nil, ErrLotteryCodeUnresolved.WithMessage("当前暂不支持 %s 的自动识别", code)
 	}
 	return parser, nil
 }
@@ -10,10 +10,10 @@ import (
 	"time"
 
 	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/service/apperr"
 	"go-fiber-starter/pkg/db"
 	"go-fiber-starter/pkg/logger"
 
-	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -62,7 +62,7 @@ func (provider *openAIRecommendationProvider) Generate(ctx context.Context, defi
 		result, content, err := provider.generateOnce(ctx, definition, prompt, blocklist, count)
 		if err == nil {
 			if attempt > 1 {
-				logger.Info("AI 推荐生成第 %d 次补偿成功", attempt)
+				logger.InfoContext(ctx, "AI 推荐生成第 %d 次补偿成功", attempt)
 			}
 			return result, nil
 		}
@@ -73,17 +73,17 @@ func (provider *openAIRecommendationProvider) Generate(ctx context.Context, defi
 			break
 		}
 
-		logger.Warn("AI 推荐生成第 %d 次失败，将补偿重试: %s", attempt, detectRecommendationFailureCause(err))
+		logger.WarnContext(ctx, "AI 推荐生成第 %d 次失败，将补偿重试: %s", attempt, detectRecommendationFailureCause(err))
 		prompt = buildRecommendationCompensationPrompt(definition, history, blocklist, count, attempt, err, content)
 	}
 
-	return nil, fmt.Errorf(
+	return nil, apperr.Upstream(CodeModelRequestFailed, fmt.Errorf(
 		"AI 推荐生成失败，已尝试 %d 次，最后原因：%s，响应片段：%s: %w",
 		attempted,
 		detectRecommendationFailureCause(lastErr),
 		formatRecommendationResponsePreview(lastContent),
 		lastErr,
-	)
+	))
 }
 
 func (provider *openAIRecommendationProvider) generateOnce(ctx context.Context, definition Definition, prompt string, blocklist RecommendationBlocklist, count int) (*RecommendationResult, string, error) {
@@ -401,8 +401,13 @@ func formatRecommendationResponsePreview(content string) string {
 	return truncateLogValue(preview)
 }
 
-func GenerateRecommendation(ctx context.Context, code string, count int, userID string) (*model.Recommendation, error) {
-	userUUID, err := parseRequiredUserID(userID)
+func GenerateRecommendation(ctx context.Context, code string, count int, owner Owner) (*model.Recommendation, error) {
+	ctx = logger.WithFields(ctx, logger.FieldLotteryCode, code)
+	userUUID, err := parseRequiredUserID(owner.UserID)
+	if err != nil {
+		return nil, err
+	}
+	ledgerUUID, err := owner.ledgerUUID()
 	if err != nil {
 		return nil, err
 	}
@@ -421,7 +426,7 @@ func GenerateRecommendation(ctx context.Context, code string, count int, userID
 
 	historyWindow := max(10, definition.Recommendation.HistoryWindow)
 	history := make([]model.DrawResult, 0)
-	if err := db.DB.Where("lottery_code = ?", code).Order("draw_date desc").Order("issue desc").Limit(historyWindow).Find(&history).Error; err != nil {
+	if err := db.DB.WithContext(ctx).Where("lottery_code = ?", code).Order("draw_date desc").Order("issue desc").Limit(historyWindow).Find(&history).Error; err != nil {
 		return nil, err
 	}
 
@@ -432,7 +437,7 @@ func GenerateRecommendation(ctx context.Context, code string, count int, userID
 	if err != nil {
 		return nil, err
 	}
-	existing, err := findExistingRecommendation(userUUID, code, targetIssue)
+	existing, err := findExistingRecommendation(owner, code, targetIssue)
 	if err != nil {
 		return nil, err
 	}
@@ -444,18 +449,19 @@ func GenerateRecommendation(ctx context.Context, code string, count int, userID
 	if provider == nil {
 		return nil, fmt.Errorf("未配置可用的推荐模型提供方")
 	}
-	blocklist, err := buildRecommendationBlocklist(userUUID, code, history)
+	blocklist, err := buildRecommendationBlocklist(owner, code, history)
 	if err != nil {
 		return nil, err
 	}
+	ctx, externalCalls := trackExternalCalls(ctx, nil)
 	result, err := provider.Generate(ctx, definition, lotteryType, history, blocklist, count)
 	if err != nil {
 		return nil, err
 	}
 
 	recommendation := model.Recommendation{}
-	if err := db.DB.Transaction(func(tx *gorm.DB) error {
-		if existing, err := findExistingRecommendationWithDB(tx, userUUID, code, targetIssue); err != nil {
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if existing, err := findExistingRecommendationWithDB(tx, owner, code, targetIssue); err != nil {
 			return err
 		} else if existing != nil {
 			recommendation = *existing
@@ -464,6 +470,7 @@ func GenerateRecommendation(ctx context.Context, code string, count int, userID
 
 		recommendation = model.Recommendation{
 			UserID:        &userUUID,
+			LedgerID:      ledgerUUID,
 			LotteryCode:   code,
 			Issue:         targetIssue,
 			DrawDate:      &targetDrawDate,
@@ -479,7 +486,7 @@ func GenerateRecommendation(ctx context.Context, code string, count int, userID
 		}
 		if err := tx.Create(&recommendation).Error; err != nil {
 			if isUniqueConstraintError(err) {
-				existing, queryErr := findExistingRecommendationWithDB(tx, userUUID, code, targetIssue)
+				existing, queryErr := findExistingRecommendationWithDB(tx, owner, code, targetIssue)
 				if queryErr != nil {
 					return queryErr
 				}
@@ -509,21 +516,24 @@ func GenerateRecommendation(ctx context.Context, code string, count int, userID
 	}); err != nil {
 		return nil, err
 	}
+	if err := externalCalls.linkRecommendation(db.DB.WithContext(ctx), recommendation.Id); err != nil {
+		logger.WarnContext(ctx, "关联推荐的第三方接口调用记录失败: %v", err)
+	}
 
-	if err := db.DB.Preload("Entries").First(&recommendation, "id = ?", recommendation.Id).Error; err != nil {
+	if err := db.DB.WithContext(ctx).Preload("Entries").First(&recommendation, "id = ?", recommendation.Id).Error; err != nil {
 		return nil, err
 	}
 	return &recommendation, nil
 }
 
-func findExistingRecommendation(userID uuid.UUID, code string, issue string) (*model.Recommendation, error) {
-	return findExistingRecommendationWithDB(db.DB, userID, code, issue)
+func findExistingRecommendation(owner Owner, code string, issue string) (*model.Recommendation, error) {
+	return findExistingRecommendationWithDB(db.DB, owner, code, issue)
 }
 
-func findExistingRecommendationWithDB(database *gorm.DB, userID uuid.UUID, code string, issue string) (*model.Recommendation, error) {
+func findExistingRecommendationWithDB(database *gorm.DB, owner Owner, code string, issue string) (*model.Recommendation, error) {
 	recommendation := model.Recommendation{}
-	err := database.Preload("Entries").
-		Where("user_id = ? AND lottery_code = ? AND issue = ?", userID, code, issue).
+	err := ownerScope(database.Preload("Entries"), owner).
+		Where("lottery_code = ? AND issue = ?", code, issue).
 		Order("created_at asc").
 		Order("id asc").
 		First(&recommendation).Error
@@ -536,8 +546,8 @@ func findExistingRecommendationWithDB(database *gorm.DB, userID uuid.UUID, code
 	return &recommendation, nil
 }
 
-func buildRecommendationBlocklist(userID uuid.UUID, code string, history []model.DrawResult) (RecommendationBlocklist, error) {
-	recent, err := loadRecentRecommendationSignatures(userID, code, 20)
+func buildRecommendationBlocklist(owner Owner, code string, history []model.DrawResult) (RecommendationBlocklist, error) {
+	recent, err := loadRecentRecommendationSignatures(owner, code, 20)
 	if err != nil {
 		return RecommendationBlocklist{}, err
 	}
@@ -558,10 +568,10 @@ func buildRecommendationBlocklistFromHistory(history []model.DrawResult) Recomme
 	}
 }
 
-func loadRecentRecommendationSignatures(userID uuid.UUID, code string, limit int) ([]string, error) {
+func loadRecentRecommendationSignatures(owner Owner, code string, limit int) ([]string, error) {
 	recommendations := make([]model.Recommendation, 0)
-	if err := db.DB.Preload("Entries").
-		Where("user_id = ? AND lottery_code = ?", userID, code).
+	if err := ownerScope(db.DB.Preload("Entries"), owner).
+		Where("lottery_code = ?", code).
 		Order("created_at desc").
 		Order("id desc").
 		Limit(max(1, limit)).
@@ -22,6 +22,7 @@ type RecommendationDetail struct {
 
 type RecommendationQueryOptions struct {
 	UserID      string
+	LedgerID    string
 	Page        int
 	PageSize    int
 	LotteryCode string
@@ -37,19 +38,19 @@ type RecommendationPageResult struct {
 	HasMore  bool                   `json:"hasMore"`
 }
 
-func ListRecommendations(code string, limit int, userID string) ([]RecommendationDetail, error) {
+func ListRecommendations(code string, limit int, owner Owner) ([]RecommendationDetail, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 
 	recommendations := make([]model.Recommendation, 0)
-	if err := currentUserScope(db.DB.Preload("Entries"), userID).Where("lottery_code = ?", code).Order("created_at desc").Limit(limit).Find(&recommendations).Error; err != nil {
+	if err := ownerScope(db.DB.Preload("Entries"), owner).Where("lottery_code = ?", code).Order("created_at desc").Limit(limit).Find(&recommendations).Error; err != nil {
 		return nil, err
 	}
 
 	result := make([]RecommendationDetail, 0, len(recommendations))
 	for _, recommendation := range recommendations {
-		detail, err := buildRecommendationDetail(recommendation, userID)
+		detail, err := buildRecommendationDetail(recommendation, owner)
 		if err != nil {
 			return nil, err
 		}
@@ -58,19 +59,19 @@ func ListRecommendations(code string, limit int, userID string) ([]Recommendatio
 	return result, nil
 }
 
-func ListAllRecommendations(limit int, userID string) ([]RecommendationDetail, error) {
+func ListAllRecommendations(limit int, owner Owner) ([]RecommendationDetail, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 
 	recommendations := make([]model.Recommendation, 0)
-	if err := currentUserScope(db.DB.Preload("Entries"), userID).Order("created_at desc").Limit(limit).Find(&recommendations).Error; err != nil {
+	if err := ownerScope(db.DB.Preload("Entries"), owner).Order("created_at desc").Limit(limit).Find(&recommendations).Error; err != nil {
 		return nil, err
 	}
 
 	result := make([]RecommendationDetail, 0, len(recommendations))
 	for _, recommendation := range recommendations {
-		detail, err := buildRecommendationDetail(recommendation, userID)
+		detail, err := buildRecommendationDetail(recommendation, owner)
 		if err != nil {
 			return nil, err
 		}
@@ -89,7 +90,8 @@ func QueryRecommendations(options RecommendationQueryOptions) (*RecommendationPa
 		pageSize = 50
 	}
 
-	query := applyRecommendationFilters(currentUserScope(db.DB.Model(&model.Recommendation{}), options.UserID), options)
+	owner := Owner{UserID: options.UserID, LedgerID: options.LedgerID}
+	query := applyRecommendationFilters(ownerScope(db.DB.Model(&model.Recommendation{}), owner), options)
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -107,7 +109,7 @@ func QueryRecommendations(options RecommendationQueryOptions) (*RecommendationPa
 
 	items := make([]RecommendationDetail, 0, len(recommendations))
 	for _, recommendation := range recommendations {
-		detail, err := buildRecommendationDetail(recommendation, options.UserID)
+		detail, err := buildRecommendationDetail(recommendation, owner)
 		if err != nil {
 			return nil, err
 		}
@@ -123,15 +125,15 @@ func QueryRecommendations(options RecommendationQueryOptions) (*RecommendationPa
 	}, nil
 }
 
-func GetRecommendationDetail(code string, recommendationID string, userID string) (*RecommendationDetail, error) {
+func GetRecommendationDetail(code string, recommendationID string, owner Owner) (*RecommendationDetail, error) {
 	recommendation := model.Recommendation{}
-	if err := currentUserScope(db.DB.Preload("Entries"), userID).First(&recommendation, "id = ? AND lottery_code = ?", recommendationID, code).Error; err != nil {
+	if err := ownerScope(db.DB.Preload("Entries"), owner).First(&recommendation, "id = ? AND lottery_code = ?", recommendationID, code).Error; err != nil {
 		return nil, err
 	}
-	return buildRecommendationDetail(recommendation, userID)
+	return buildRecommendationDetail(recommendation, owner)
 }
 
-func buildRecommendationDetail(recommendation model.Recommendation, userID string) (*RecommendationDetail, error) {
+func buildRecommendationDetail(recommendation model.Recommendation, owner Owner) (*RecommendationDetail, error) {
 	detail := &RecommendationDetail{
 		Recommendation: recommendation,
 		EntryCount:     len(recommendation.Entries),
@@ -144,7 +146,7 @@ func buildRecommendationDetail(recommendation model.Recommendation, userID strin
 	}
 
 	tickets := make([]model.Ticket, 0)
-	err := currentUserScope(db.DB.Preload("Entries"), userID).
+	err := ownerScope(db.DB.Preload("Entries"), owner).
 		Where("lottery_code = ? AND recommendation_id = ?", recommendation.LotteryCode, recommendation.Id).
 		Order("created_at desc").
 		Find(&tickets).Error
@@ -195,9 +197,9 @@ func applyRecommendationFilters(query *gorm.DB, options RecommendationQueryOptio
 	case TicketStatusPending:
 		query = query.Where("checked_at IS NULL")
 	case TicketStatusWon:
-		query = query.Where("checked_at IS NOT NULL").Where("prize_amount > 0")
+		query = query.Where("checked_at IS NOT NULL").Where("prize_amount_cents > 0")
 	case TicketStatusNotWon:
-		query = query.Where("checked_at IS NOT NULL").Where("prize_amount <= 0")
+		query = query.Where("checked_at IS NOT NULL").Where("prize_amount_cents <= 0")
 	}
 
 	return query
@@ -212,7 +214,7 @@ func applyRecommendationSort(query *gorm.DB, sort string) *gorm.DB {
 	case "draw_oldest":
 		return query.Order("draw_date asc").Order("created_at asc").Order("id asc")
 	case "prize_high":
-		return query.Order("prize_amount desc").Order("created_at desc").Order("id desc")
+		return query.Order("prize_amount_cents desc").Order("created_at desc").Order("id desc")
 	default:
 		return query.Order("created_at desc").Order("id desc")
 	}
@@ -2,7 +2,6 @@ package lottery
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	model "go-fiber-starter/internal/model/lottery"
@@ -11,32 +10,32 @@ import (
 	"gorm.io/gorm"
 )
 
-func RecheckRecommendation(ctx context.Context, code string, recommendationID string, userID string) (*RecommendationDetail, error) {
+func RecheckRecommendation(ctx context.Context, code string, recommendationID string, owner Owner) (*RecommendationDetail, error) {
 	recommendation := model.Recommendation{}
-	if err := currentUserScope(db.DB.Preload("Entries"), userID).First(&recommendation, "id = ? AND lottery_code = ?", recommendationID, code).Error; err != nil {
+	if err := ownerScope(db.DB.WithContext(ctx).Preload("Entries"), owner).First(&recommendation, "id = ? AND lottery_code = ?", recommendationID, code).Error; err != nil {
 		return nil, err
 	}
 
 	issue := normalizeIssueByCode(recommendation.LotteryCode, recommendation.Issue)
 	if issue == "" {
-		return nil, fmt.Errorf("推荐期号不能为空")
+		return nil, ErrIssueRequired.WithMessage("推荐期号不能为空")
 	}
 	if issue != recommendation.Issue {
 		recommendation.Issue = issue
-		if err := db.DB.Omit("Entries").Save(&recommendation).Error; err != nil {
+		if err := db.DB.WithContext(ctx).Omit("Entries").Save(&recommendation).Error; err != nil {
 			return nil, err
 		}
 	}
 
 	if err := findRecommendationSettlementDraw(recommendation.LotteryCode, issue, &model.DrawResult{}); err != nil {
 		if _, syncErr := SyncLatestDraw(ctx, recommendation.LotteryCode, issue); syncErr != nil {
-			return buildRecommendationDetail(recommendation, userID)
+			return buildRecommendationDetail(recommendation, owner)
 		}
 	}
 	if err := EvaluateRecommendationsByIssue(recommendation.LotteryCode, issue); err != nil {
 		return nil, err
 	}
-	return GetRecommendationDetail(recommendation.LotteryCode, recommendation.Id.String(), userID)
+	return GetRecommendationDetail(recommendation.LotteryCode, recommendation.Id.String(), owner)
 }
 
 func EvaluateRecommendationsByIssue(code string, issue string) error {
@@ -61,14 +60,14 @@ func EvaluateRecommendationsByIssue(code string, issue string) error {
 }
 
 func evaluateRecommendationsWithDraw(recommendations []model.Recommendation, code string, draw model.DrawResult) error {
-	prizeMap := make(map[string]float64, len(draw.PrizeDetails))
+	prizeMap := make(map[string]model.Money, len(draw.PrizeDetails))
 	for _, prize := range draw.PrizeDetails {
 		prizeMap[normalizePrizeName(prize.PrizeName)] = prize.SingleBonus
 	}
 
 	checkedAt := time.Now()
 	for _, recommendation := range recommendations {
-		totalPrize := 0.0
+		var totalPrize model.Money
 		for _, entry := range recommendation.Entries {
 			result := JudgeNumbers(code, entry.RedNumbers, entry.BlueNumbers, false, draw, prizeMap)
 			entry.IsWinning = result.IsWinning
@@ -117,10 +116,10 @@ func resetRecommendationsPendingByIssueWithDB(tx *gorm.DB, code string, issue st
 			if err := tx.Model(&model.RecommendationEntry{}).
 				Where("id = ?", entry.Id).
 				Updates(map[string]any{
-					"is_winning":    false,
-					"prize_name":    "",
-					"prize_amount":  0,
-					"match_summary": "待开奖",
+					"is_winning":         false,
+					"prize_name":         "",
+					"prize_amount_cents": 0,
+					"match_summary":      "待开奖",
 				}).Error; err != nil {
 				return err
 			}
@@ -129,8 +128,8 @@ func resetRecommendationsPendingByIssueWithDB(tx *gorm.DB, code string, issue st
 		if err := tx.Model(&model.Recommendation{}).
 			Where("id = ?", recommendation.Id).
 			Updates(map[string]any{
-				"checked_at":   nil,
-				"prize_amount": 0,
+				"checked_at":         nil,
+				"prize_amount_cents": 0,
 			}).Error; err != nil {
 			return err
 		}
@@ -2,32 +2,64 @@ package lottery
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
+	"go-fiber-starter/internal/metrics"
+	"go-fiber-starter/internal/service/backup"
+	"go-fiber-starter/internal/tracing"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/logger"
 
 	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// schedulerRunning 记录定时任务调度器是否在运行，供就绪检查读取。
+var schedulerRunning atomic.Bool
+
+// SchedulerStatus 返回是否配置了定时任务以及调度器当前是否在运行。
+func SchedulerStatus() (enabled bool, running bool) {
+	return hasScheduledJobs(), schedulerRunning.Load()
+}
+
+// runScheduledJob 执行一次定时任务并记录执行结果和耗时指标，任一步骤失败即记为失败。
+// 每次执行都作为一条独立链路的根 span，任务内的外部调用和查询挂在它下面。
+func runScheduledJob(ctx context.Context, jobType string, name string, run func(context.Context) error) {
+	startedAt := time.Now()
+	jobCtx, span := tracing.Start(ctx, "job."+jobType,
+		attribute.String("job.type", jobType),
+		attribute.String("job.name", name),
+	)
+	jobCtx = logger.WithFields(jobCtx, logger.FieldJobName, name, "job_type", jobType)
+	err := run(jobCtx)
+	tracing.End(span, err)
+	metrics.ObserveJob(jobType, name, err == nil, time.Since(startedAt))
+}
+
 func startSyncLoop(ctx context.Context) {
 	scheduler := cron.New(cron.WithSeconds())
 
 	registerCompensationJobs(ctx, scheduler)
+	registerMaintenanceJobs(ctx, scheduler)
 
 	for _, definition := range ListDefinitions() {
 		code := definition.Code
 		if definition.Enabled && definition.Sync.Enabled && definition.Sync.Cron != "" {
 			_, err := scheduler.AddFunc(definition.Sync.Cron, func() {
-				result, syncErr := SyncLatestDraw(ctx, code, "")
-				if syncErr != nil {
-					logger.Error("定时同步 %s 失败: %v", code, syncErr)
-					return
-				}
-				if result != nil && result.SyncedCount > 0 {
-					logger.Info("已按计划同步 %s 当期开奖", code)
-					return
-				}
-				logger.Info("已检查 %s 当期开奖，当前暂无可入库号码", code)
+				runScheduledJob(ctx, "sync", code, func(jobCtx context.Context) error {
+					result, syncErr := SyncLatestDraw(jobCtx, code, "")
+					if syncErr != nil {
+						logger.ErrorContext(jobCtx, "定时同步 %s 失败: %v", code, syncErr)
+						return syncErr
+					}
+					if result != nil && result.SyncedCount > 0 {
+						logger.InfoContext(jobCtx, "已按计划同步 %s 当期开奖", code)
+						return nil
+					}
+					logger.InfoContext(jobCtx, "已检查 %s 当期开奖，当前暂无可入库号码", code)
+					return nil
+				})
 			})
 			if err != nil {
 				logger.Warn("忽略非法同步 cron 配置 %s: %v", code, err)
@@ -36,17 +68,22 @@ func startSyncLoop(ctx context.Context) {
 
 		if definition.Enabled && definition.Recommendation.Enabled && definition.Recommendation.Cron != "" {
 			_, err := scheduler.AddFunc(definition.Recommendation.Cron, func() {
-				users, userErr := loadSchedulerUsers()
-				if userErr != nil {
-					logger.Error("加载推荐用户 %s 失败: %v", code, userErr)
-					return
-				}
-				for _, user := range users {
-					if _, recommendationErr := GenerateRecommendation(ctx, code, 0, user.Id.String()); recommendationErr != nil {
-						logger.Error("定时生成推荐 %s/%s 失败: %v", code, user.Username, recommendationErr)
+				runScheduledJob(ctx, "recommendation", code, func(jobCtx context.Context) error {
+					users, userErr := loadSchedulerUsers()
+					if userErr != nil {
+						logger.ErrorContext(jobCtx, "加载推荐用户 %s 失败: %v", code, userErr)
+						return userErr
 					}
-				}
-				logger.Info("已按计划生成 %s 推荐", code)
+					var lastErr error
+					for _, user := range users {
+						if _, recommendationErr := GenerateRecommendation(jobCtx, code, 0, PersonalOwner(user.Id.String())); recommendationErr != nil {
+							logger.ErrorContext(jobCtx, "定时生成推荐 %s/%s 失败: %v", code, user.Username, recommendationErr)
+							lastErr = recommendationErr
+						}
+					}
+					logger.InfoContext(jobCtx, "已按计划生成 %s 推荐", code)
+					return lastErr
+				})
 			})
 			if err != nil {
 				logger.Warn("忽略非法推荐 cron 配置 %s: %v", code, err)
@@ -55,7 +92,9 @@ func startSyncLoop(ctx context.Context) {
 	}
 
 	scheduler.Start()
+	schedulerRunning.Store(true)
 	<-ctx.Done()
+	schedulerRunning.Store(false)
 	stopContext := scheduler.Stop()
 	<-stopContext.Done()
 }
@@ -71,14 +110,57 @@ func registerCompensationJobs(ctx context.Context, scheduler *cron.Cron) {
 			continue
 		}
 		_, err := scheduler.AddFunc(job.Cron, func() {
-			if runErr := RunCompensationJob(ctx, job); runErr != nil {
-				logger.Error("补偿任务 %s 执行失败: %v", job.Name, runErr)
-				return
-			}
-			logger.Info("补偿任务 %s 执行完成", job.Name)
+			runScheduledJob(ctx, "compensation", job.Name, func(jobCtx context.Context) error {
+				if runErr := RunCompensationJob(jobCtx, job); runErr != nil {
+					logger.ErrorContext(jobCtx, "补偿任务 %s 执行失败: %v", job.Name, runErr)
+					return runErr
+				}
+				logger.InfoContext(jobCtx, "补偿任务 %s 执行完成", job.Name)
+				return nil
+			})
 		})
 		if err != nil {
 			logger.Warn("忽略非法补偿 cron 配置 %s: %v", job.Name, err)
 		}
 	}
 }
+
+// externalLogRetentionCron 每天凌晨清理过期的第三方接口调用记录。开启记录时调度器总会启动；
+// 关闭记录后，调度器因其他任务运行时仍会清理已有的旧记录。
+const externalLogRetentionCron = "0 30 3 * * *"
+
+func registerMaintenanceJobs(ctx context.Context, scheduler *cron.Cron) {
+	_, err := scheduler.AddFunc(externalLogRetentionCron, func() {
+		runScheduledJob(ctx, "maintenance", "external-api-log-retention", func(jobCtx context.Context) error {
+			deleted, purgeErr := PurgeExternalAPILogs(time.Now())
+			if purgeErr != nil {
+				logger.ErrorContext(jobCtx, "清理第三方接口调用记录失败: %v", purgeErr)
+				return purgeErr
+			}
+			logger.InfoContext(jobCtx, "已清理 %d 条过期的第三方接口调用记录", deleted)
+			return nil
+		})
+	})
+	if err != nil {
+		logger.Warn("注册调用记录清理任务失败: %v", err)
+	}
+
+	settings := config.Current.Backup
+	if !settings.Enabled || settings.Cron == "" {
+		return
+	}
+	_, err = scheduler.AddFunc(settings.Cron, func() {
+		runScheduledJob(ctx, "maintenance", "backup", func(jobCtx context.Context) error {
+			result, backupErr := backup.Create(jobCtx)
+			if backupErr != nil {
+				logger.ErrorContext(jobCtx, "定时备份失败: %v", backupErr)
+				return backupErr
+			}
+			logger.InfoContext(jobCtx, "已生成备份 %s，清理旧备份 %d 份", result.Archive.Name, len(result.Pruned))
+			return nil
+		})
+	})
+	if err != nil {
+		logger.Warn("忽略非法备份 cron 配置: %v", err)
+	}
+}
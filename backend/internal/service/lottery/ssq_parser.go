@@ -1,9 +1,10 @@
 package lottery
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
+
+	model "go-fiber-starter/internal/model/lottery"
 )
 
 type ssqRecognitionParser struct{}
@@ -27,7 +28,7 @@ type RecognitionResult struct {
 	LotteryCode string        `json:"lotteryCode"`
 	Issue       string        `json:"issue"`
 	DrawDate    string        `json:"drawDate"`
-	CostAmount  float64       `json:"costAmount"`
+	CostAmount  model.Money   `json:"costAmount" swaggertype:"number"`
 	RawText     string        `json:"rawText"`
 	Confidence  float64       `json:"confidence"`
 	Entries     []ParsedEntry `json:"entries"`
@@ -45,7 +46,7 @@ func ParseSSQText(text string) (*RecognitionResult, error) {
 		entries = append(entries, parseSSQLine(normalized)...)
 	}
 	if len(entries) == 0 {
-		return nil, fmt.Errorf("未识别到有效的双色球号码，请补充 OCR 文本后重试")
+		return nil, ErrNumbersNotRecognized.WithMessage("未识别到有效的双色球号码，请补充 OCR 文本后重试")
 	}
 
 	return &RecognitionResult{
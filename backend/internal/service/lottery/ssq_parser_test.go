@@ -1,6 +1,10 @@
 package lottery
 
-import "testing"
+import (
+	"testing"
+
+	model "go-fiber-starter/internal/model/lottery"
+)
 
 func TestParseSSQTextWithMultiple(t *testing.T) {
 	result, err := ParseSSQText("双色球 A. 03 09 14 21 25 32 - 07 (2)")
@@ -36,7 +40,7 @@ func TestParseSSQTextWithMetaFields(t *testing.T) {
 	if result.DrawDate != "2026-03-18" {
 		t.Fatalf("unexpected draw date: %s", result.DrawDate)
 	}
-	if result.CostAmount != 4 {
+	if result.CostAmount != 4*model.Yuan {
 		t.Fatalf("unexpected cost amount: %v", result.CostAmount)
 	}
 }
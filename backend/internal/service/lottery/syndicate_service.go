@@ -0,0 +1,231 @@
+package lottery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	model "go-fiber-starter/internal/model/lottery"
+	userModel "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxTicketShares = 50
+
+var ErrInvalidTicketShares = apperr.Invalid("TICKET_SHARES_INVALID", "合买参与人信息不正确")
+
+// TicketShareInput 描述一位合买参与人，Username 对应系统用户，Name 用于记录没有账号的参与人。
+type TicketShareInput struct {
+	Username    string      `json:"username"`
+	Name        string      `json:"name"`
+	ShareAmount model.Money `json:"shareAmount" swaggertype:"number"`
+}
+
+type SetTicketSharesInput struct {
+	UserID   string
+	LedgerID string
+	TicketID string
+	Shares   []TicketShareInput
+}
+
+// SyndicateShareDetail 是参与人视角的合买记录，附带票据的整体金额和判奖状态。
+type SyndicateShareDetail struct {
+	model.TicketShare
+	LotteryCode       string      `json:"lotteryCode"`
+	Issue             string      `json:"issue"`
+	Status            string      `json:"status"`
+	TicketCostAmount  model.Money `json:"ticketCostAmount" swaggertype:"number"`
+	TicketPrizeAmount model.Money `json:"ticketPrizeAmount" swaggertype:"number"`
+	PurchasedAt       time.Time   `json:"purchasedAt"`
+	CheckedAt         *time.Time  `json:"checkedAt"`
+}
+
+// SetTicketShares 覆盖票据的合买参与人，传入空列表时取消合买。
+func SetTicketShares(input SetTicketSharesInput) (*TicketDetail, error) {
+	owner := Owner{UserID: input.UserID, LedgerID: input.LedgerID}
+	if len(input.Shares) > maxTicketShares {
+		return nil, fmt.Errorf("%w: 参与人不能超过 %d 位", ErrInvalidTicketShares, maxTicketShares)
+	}
+
+	shares, err := buildTicketShares(input.Shares)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		ticket := model.Ticket{}
+		if err := ownerScope(tx, owner).First(&ticket, "id = ?", input.TicketID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ticket_id = ?", ticket.Id).Delete(&model.TicketShare{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Ticket{}).Where("id = ?", ticket.Id).Update("is_syndicate", len(shares) > 0).Error; err != nil {
+			return err
+		}
+		if len(shares) == 0 {
+			return nil
+		}
+		for index := range shares {
+			shares[index].TicketID = ticket.Id
+		}
+		if err := tx.Create(&shares).Error; err != nil {
+			return err
+		}
+		return refreshTicketShares(tx, ticket.Id.String())
+	}); err != nil {
+		return nil, err
+	}
+	return GetTicketDetail(input.TicketID, owner)
+}
+
+// ListSyndicateShares 返回用户参与的合买记录，包括其他用户或共享账本录入的票据。
+func ListSyndicateShares(userID string, code string) ([]SyndicateShareDetail, error) {
+	shares := make([]model.TicketShare, 0)
+	query := db.DB.Model(&model.TicketShare{}).
+		Select("ticket_shares.*").
+		Joins("JOIN tickets ON tickets.id = ticket_shares.ticket_id").
+		Where("ticket_shares.user_id = ?", userID)
+	if code != "" {
+		query = query.Where("tickets.lottery_code = ?", code)
+	}
+	if err := query.Order("tickets.purchased_at desc").Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	result := make([]SyndicateShareDetail, 0, len(shares))
+	if len(shares) == 0 {
+		return result, nil
+	}
+
+	ticketIDs := make([]uuid.UUID, 0, len(shares))
+	for _, share := range shares {
+		ticketIDs = append(ticketIDs, share.TicketID)
+	}
+	tickets := make([]model.Ticket, 0, len(ticketIDs))
+	if err := db.DB.Where("id IN ?", ticketIDs).Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	ticketMap := make(map[uuid.UUID]model.Ticket, len(tickets))
+	for _, ticket := range tickets {
+		ticketMap[ticket.Id] = ticket
+	}
+
+	for _, share := range shares {
+		ticket := ticketMap[share.TicketID]
+		result = append(result, SyndicateShareDetail{
+			TicketShare:       share,
+			LotteryCode:       ticket.LotteryCode,
+			Issue:             ticket.Issue,
+			Status:            ticket.Status,
+			TicketCostAmount:  ticket.CostAmount,
+			TicketPrizeAmount: ticket.PrizeAmount,
+			PurchasedAt:       ticket.PurchasedAt,
+			CheckedAt:         ticket.CheckedAt,
+		})
+	}
+	return result, nil
+}
+
+// refreshTicketShares 按出资比例把票据的成本和奖金分摊给参与人，
+// 按分计算后把舍入误差计入最后一位参与人，保证分摊合计与票据金额一致。
+func refreshTicketShares(tx *gorm.DB, ticketID string) error {
+	shares := make([]model.TicketShare, 0)
+	if err := tx.Where("ticket_id = ?", ticketID).Order("created_at asc").Order("id asc").Find(&shares).Error; err != nil {
+		return err
+	}
+	if len(shares) == 0 {
+		return nil
+	}
+	ticket := model.Ticket{}
+	if err := tx.Select("id", "cost_amount_cents", "prize_amount_cents").First(&ticket, "id = ?", ticketID).Error; err != nil {
+		return err
+	}
+
+	weights := make([]model.Money, 0, len(shares))
+	for _, share := range shares {
+		weights = append(weights, share.ShareAmount)
+	}
+	costs := splitByWeight(ticket.CostAmount, weights)
+	prizes := splitByWeight(ticket.PrizeAmount, weights)
+	for index, share := range shares {
+		if err := tx.Model(&model.TicketShare{}).Where("id = ?", share.Id).Updates(map[string]any{
+			"cost_amount_cents":  costs[index],
+			"prize_amount_cents": prizes[index],
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildTicketShares(items []TicketShareInput) ([]model.TicketShare, error) {
+	shares := make([]model.TicketShare, 0, len(items))
+	seenUsers := make(map[uuid.UUID]struct{}, len(items))
+	for _, item := range items {
+		if item.ShareAmount <= 0 {
+			return nil, fmt.Errorf("%w: 出资份额必须大于 0", ErrInvalidTicketShares)
+		}
+		share := model.TicketShare{
+			Name:        truncateRunes(strings.TrimSpace(item.Name), 64),
+			ShareAmount: item.ShareAmount,
+		}
+
+		if username := strings.TrimSpace(item.Username); username != "" {
+			user := userModel.User{}
+			if err := db.DB.Where("username = ?", username).First(&user).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, fmt.Errorf("%w: 用户 %s 不存在", ErrInvalidTicketShares, username)
+				}
+				return nil, err
+			}
+			if _, exists := seenUsers[user.Id]; exists {
+				return nil, fmt.Errorf("%w: 用户 %s 重复", ErrInvalidTicketShares, username)
+			}
+			seenUsers[user.Id] = struct{}{}
+			share.UserID = &user.Id
+			if share.Name == "" {
+				share.Name = resolveValue(user.DisplayName, user.Username)
+			}
+		}
+		if share.Name == "" {
+			return nil, fmt.Errorf("%w: 请填写参与人用户名或名称", ErrInvalidTicketShares)
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
+
+func splitByWeight(total model.Money, weights []model.Money) []model.Money {
+	result := make([]model.Money, len(weights))
+	var weightSum model.Money
+	for _, weight := range weights {
+		weightSum += weight
+	}
+	if weightSum <= 0 || len(weights) == 0 {
+		return result
+	}
+
+	var allocated model.Money
+	for index, weight := range weights {
+		if index == len(weights)-1 {
+			result[index] = total - allocated
+			break
+		}
+		result[index] = total.MulRatio(weight.Cents(), weightSum.Cents())
+		allocated += result[index]
+	}
+	return result
+}
+
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,111 @@
+package lottery
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/pkg/db"
+)
+
+func TestSyndicateSharesSplitCostAndPrize(t *testing.T) {
+	setupImportTicketTestDB(t)
+	alice := createLedgerTestUser(t, "alice")
+	bob := createLedgerTestUser(t, "bob")
+
+	ticket, err := CreateTicket(context.Background(), CreateTicketInput{
+		UserID:   alice,
+		Code:     "ssq",
+		Issue:    "2099010",
+		DrawDate: time.Now().AddDate(1, 0, 0),
+		Entries:  []ParsedEntry{{Red: []int{1, 2, 3, 4, 5, 6}, Blue: []int{7}}},
+	})
+	if err != nil {
+		t.Fatalf("create ticket: %v", err)
+	}
+	ticketID := ticket.Id.String()
+
+	if _, err := SetTicketShares(SetTicketSharesInput{
+		UserID:   alice,
+		TicketID: ticketID,
+		Shares:   []TicketShareInput{{Username: "bob", ShareAmount: model.Yuan}, {Username: "bob", ShareAmount: model.Yuan}},
+	}); !errors.Is(err, ErrInvalidTicketShares) {
+		t.Fatalf("expected duplicate participant to be rejected, got %v", err)
+	}
+	if _, err := SetTicketShares(SetTicketSharesInput{
+		UserID:   bob,
+		TicketID: ticketID,
+		Shares:   []TicketShareInput{{Username: "bob", ShareAmount: model.Yuan}},
+	}); err == nil {
+		t.Fatalf("expected non-owner to be rejected")
+	}
+
+	detail, err := SetTicketShares(SetTicketSharesInput{
+		UserID:   alice,
+		TicketID: ticketID,
+		Shares: []TicketShareInput{
+			{Username: "alice", ShareAmount: 2 * model.Yuan},
+			{Username: "bob", ShareAmount: model.Yuan},
+		},
+	})
+	if err != nil {
+		t.Fatalf("set shares: %v", err)
+	}
+	if !detail.IsSyndicate || len(detail.Shares) != 2 {
+		t.Fatalf("expected syndicate ticket with two shares, got %+v", detail.Shares)
+	}
+
+	draw := model.DrawResult{
+		LotteryCode: "ssq",
+		Issue:       "2099010",
+		DrawDate:    time.Now(),
+		RedNumbers:  "01,02,03,04,05,06",
+		BlueNumbers: "07",
+		PrizeDetails: []model.DrawPrize{
+			{PrizeName: "一等奖", SingleBonus: 1000 * model.Yuan},
+		},
+	}
+	if err := db.DB.Create(&draw).Error; err != nil {
+		t.Fatalf("create draw: %v", err)
+	}
+	if err := EvaluateTicket(ticketID); err != nil {
+		t.Fatalf("evaluate ticket: %v", err)
+	}
+
+	shares, err := ListSyndicateShares(bob, "ssq")
+	if err != nil {
+		t.Fatalf("list bob shares: %v", err)
+	}
+	if len(shares) != 1 || shares[0].CostAmount != 67 || shares[0].PrizeAmount != 33333 {
+		t.Fatalf("unexpected bob share: %+v", shares)
+	}
+	if shares[0].Status != TicketStatusWon || shares[0].TicketPrizeAmount != 1000*model.Yuan {
+		t.Fatalf("expected settled ticket summary, got %+v", shares[0])
+	}
+
+	aliceDashboard, err := GetGlobalDashboard(PersonalOwner(alice))
+	if err != nil {
+		t.Fatalf("alice dashboard: %v", err)
+	}
+	if aliceDashboard.Stats.TotalCost != 133 || aliceDashboard.Stats.TotalPrize != 66667 || aliceDashboard.Stats.SyndicateTickets != 1 {
+		t.Fatalf("unexpected alice stats: %+v", aliceDashboard.Stats)
+	}
+	bobDashboard, err := GetGlobalDashboard(PersonalOwner(bob))
+	if err != nil {
+		t.Fatalf("bob dashboard: %v", err)
+	}
+	if bobDashboard.Stats.TotalTickets != 0 || bobDashboard.Stats.TotalCost != 67 || bobDashboard.Stats.TotalPrize != 33333 {
+		t.Fatalf("unexpected bob stats: %+v", bobDashboard.Stats)
+	}
+
+	if err := DeleteTicket(ticketID, PersonalOwner(alice)); err != nil {
+		t.Fatalf("delete ticket: %v", err)
+	}
+	var remaining int64
+	db.DB.Model(&model.TicketShare{}).Count(&remaining)
+	if remaining != 0 {
+		t.Fatalf("expected shares to be removed with the ticket, got %d", remaining)
+	}
+}
@@ -0,0 +1,216 @@
+package lottery
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/google/uuid"
+)
+
+func createHistoryTestTicket(t *testing.T, userID string, ticket model.Ticket) model.Ticket {
+	t.Helper()
+
+	parsedUserID := uuid.MustParse(userID)
+	ticket.UserID = &parsedUserID
+	if ticket.LotteryCode == "" {
+		ticket.LotteryCode = "ssq"
+	}
+	if ticket.Status == "" {
+		ticket.Status = TicketStatusPending
+	}
+	if len(ticket.Entries) == 0 {
+		ticket.Entries = []model.TicketEntry{{Sequence: 1, RedNumbers: "01,02,03,04,05,06", BlueNumbers: "07", Multiple: 1}}
+	}
+	if err := db.DB.Create(&ticket).Error; err != nil {
+		t.Fatalf("create ticket: %v", err)
+	}
+	return ticket
+}
+
+func TestQueryAllTicketsCursorIsStableUnderInserts(t *testing.T) {
+	setupImportTicketTestDB(t)
+	userID := createLedgerTestUser(t, "alice")
+
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	expected := make(map[uuid.UUID]bool)
+	for index := range 5 {
+		ticket := createHistoryTestTicket(t, userID, model.Ticket{Issue: "2026020", PurchasedAt: base.AddDate(0, 0, index)})
+		expected[ticket.Id] = false
+	}
+
+	options := TicketQueryOptions{UserID: userID, PageSize: 2}
+	first, err := QueryAllTickets(options)
+	if err != nil {
+		t.Fatalf("query first page: %v", err)
+	}
+	if !first.HasMore || first.NextCursor == "" || len(first.Items) != 2 {
+		t.Fatalf("expected first page with cursor, got %+v", first)
+	}
+
+	// 翻页期间录入一张更新的票据，游标翻页不应出现重复或遗漏。
+	createHistoryTestTicket(t, userID, model.Ticket{Issue: "2026021", PurchasedAt: base.AddDate(0, 0, 10)})
+
+	seen := make([]uuid.UUID, 0, 5)
+	for _, item := range first.Items {
+		seen = append(seen, item.Id)
+	}
+	cursor := first.NextCursor
+	for cursor != "" {
+		options.Cursor = cursor
+		page, err := QueryAllTickets(options)
+		if err != nil {
+			t.Fatalf("query cursor page: %v", err)
+		}
+		for _, item := range page.Items {
+			seen = append(seen, item.Id)
+		}
+		cursor = page.NextCursor
+	}
+
+	if len(seen) != len(expected) {
+		t.Fatalf("expected %d tickets across pages, got %d", len(expected), len(seen))
+	}
+	for _, id := range seen {
+		visited, ok := expected[id]
+		if !ok || visited {
+			t.Fatalf("unexpected or duplicated ticket %s", id)
+		}
+		expected[id] = true
+	}
+
+	options.Cursor = "not-a-cursor"
+	if _, err := QueryAllTickets(options); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestQueryAllTicketsFilters(t *testing.T) {
+	setupImportTicketTestDB(t)
+	userID := createLedgerTestUser(t, "alice")
+
+	recommendationID := uuid.New()
+	manualDrawDate := time.Date(2026, 3, 20, 0, 0, 0, 0, time.Local)
+	won := createHistoryTestTicket(t, userID, model.Ticket{
+		Issue:            "2026030",
+		Source:           "upload",
+		Status:           TicketStatusWon,
+		PrizeAmount:      200 * model.Yuan,
+		RecommendationID: &recommendationID,
+		PurchasedAt:      time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local),
+		Entries: []model.TicketEntry{{
+			Sequence: 1, RedNumbers: "03,09,15,21,27,33", BlueNumbers: "12", Multiple: 1,
+			IsWinning: true, PrizeName: "四等奖", PrizeAmount: 200 * model.Yuan,
+		}},
+	})
+	manual := createHistoryTestTicket(t, userID, model.Ticket{
+		Issue:          "2026031",
+		Source:         "manual",
+		ManualDrawDate: &manualDrawDate,
+		PurchasedAt:    time.Date(2026, 3, 18, 9, 0, 0, 0, time.Local),
+	})
+	imported := createHistoryTestTicket(t, userID, model.Ticket{
+		Issue:       "2026010",
+		Source:      "import",
+		PurchasedAt: time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local),
+	})
+	if err := db.DB.Create(&model.DrawResult{
+		LotteryCode: "ssq",
+		Issue:       "2026030",
+		DrawDate:    time.Date(2026, 3, 15, 21, 15, 0, 0, time.Local),
+		RedNumbers:  "03,09,15,22,28,30",
+		BlueNumbers: "12",
+	}).Error; err != nil {
+		t.Fatalf("create draw: %v", err)
+	}
+
+	minPrize := 100 * model.Yuan
+	linked := true
+	cases := []struct {
+		name    string
+		options TicketQueryOptions
+		want    []uuid.UUID
+	}{
+		{"source ocr", TicketQueryOptions{Source: "ocr"}, []uuid.UUID{won.Id}},
+		{"source import", TicketQueryOptions{Source: "import"}, []uuid.UUID{imported.Id}},
+		{"purchase range", TicketQueryOptions{PurchasedFrom: "2026-03-01", PurchasedTo: "2026-03-18"}, []uuid.UUID{manual.Id, won.Id}},
+		{"draw date range", TicketQueryOptions{DrawDateFrom: "2026-03-14", DrawDateTo: "2026-03-31"}, []uuid.UUID{manual.Id, won.Id}},
+		{"draw date excludes manual", TicketQueryOptions{DrawDateTo: "2026-03-16"}, []uuid.UUID{won.Id}},
+		{"prize range", TicketQueryOptions{MinPrize: &minPrize}, []uuid.UUID{won.Id}},
+		{"prize tier", TicketQueryOptions{PrizeName: "4"}, []uuid.UUID{won.Id}},
+		{"recommendation", TicketQueryOptions{HasRecommendation: &linked}, []uuid.UUID{won.Id}},
+		{"number red", TicketQueryOptions{Number: 27, NumberZone: "red"}, []uuid.UUID{won.Id}},
+		{"number blue", TicketQueryOptions{Number: 7, NumberZone: "blue"}, []uuid.UUID{manual.Id, imported.Id}},
+		{"issue", TicketQueryOptions{Issue: "2026010"}, []uuid.UUID{imported.Id}},
+	}
+
+	for _, tc := range cases {
+		tc.options.UserID = userID
+		result, err := QueryAllTickets(tc.options)
+		if err != nil {
+			t.Fatalf("%s: query: %v", tc.name, err)
+		}
+		if int(result.Total) != len(tc.want) || len(result.Items) != len(tc.want) {
+			t.Fatalf("%s: expected %d tickets, got total %d items %d", tc.name, len(tc.want), result.Total, len(result.Items))
+		}
+		for index, id := range tc.want {
+			if result.Items[index].Id != id {
+				t.Fatalf("%s: unexpected ticket order at %d", tc.name, index)
+			}
+		}
+	}
+
+	if _, err := QueryAllTickets(TicketQueryOptions{UserID: userID, Source: "scanner"}); !errors.Is(err, ErrInvalidQueryFilter) {
+		t.Fatalf("expected ErrInvalidQueryFilter for unknown source, got %v", err)
+	}
+	if _, err := QueryAllTickets(TicketQueryOptions{UserID: userID, PurchasedFrom: "2026/03/01"}); !errors.Is(err, ErrInvalidQueryFilter) {
+		t.Fatalf("expected ErrInvalidQueryFilter for bad date, got %v", err)
+	}
+}
+
+func TestQueryDrawResultsCursorAndNumberFilter(t *testing.T) {
+	setupImportTicketTestDB(t)
+
+	base := time.Date(2026, 3, 1, 21, 15, 0, 0, time.Local)
+	for index := range 4 {
+		blue := "05"
+		if index%2 == 0 {
+			blue = "11"
+		}
+		if err := db.DB.Create(&model.DrawResult{
+			LotteryCode: "ssq",
+			Issue:       "20260" + string(rune('1'+index)) + "0",
+			DrawDate:    base.AddDate(0, 0, index*2),
+			RedNumbers:  "01,02,03,04,05,06",
+			BlueNumbers: blue,
+		}).Error; err != nil {
+			t.Fatalf("create draw: %v", err)
+		}
+	}
+
+	first, err := QueryDrawResults(DrawQueryOptions{LotteryCode: "ssq", PageSize: 3})
+	if err != nil {
+		t.Fatalf("query first page: %v", err)
+	}
+	second, err := QueryDrawResults(DrawQueryOptions{LotteryCode: "ssq", PageSize: 3, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("query second page: %v", err)
+	}
+	if len(first.Items) != 3 || len(second.Items) != 1 || second.HasMore {
+		t.Fatalf("unexpected cursor pages: %d then %d", len(first.Items), len(second.Items))
+	}
+	if !second.Items[0].DrawDate.Equal(base) {
+		t.Fatalf("expected oldest draw on last page, got %s", second.Items[0].DrawDate)
+	}
+
+	filtered, err := QueryDrawResults(DrawQueryOptions{Number: 11, NumberZone: "blue", DrawDateFrom: "2026-03-02"})
+	if err != nil {
+		t.Fatalf("query by number: %v", err)
+	}
+	if filtered.Total != 1 || filtered.Items[0].BlueNumbers != "11" {
+		t.Fatalf("expected one draw with blue 11 after 03-02, got %d", filtered.Total)
+	}
+}
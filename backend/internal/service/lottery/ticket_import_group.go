@@ -18,7 +18,7 @@ type importGroupPayload struct {
 	Issue            string
 	DrawDate         time.Time
 	PurchasedAt      time.Time
-	CostAmount       float64
+	CostAmount       model.Money
 	Notes            string
 	ImagePath        string
 	Entries          []ParsedEntry
@@ -97,7 +97,7 @@ func buildImportGroupPayload(group importTicketGroup, images map[string]string)
 	return payload, nil
 }
 
-func resolveImportedEntryCost(row importTicketRow, entry ParsedEntry) float64 {
+func resolveImportedEntryCost(row importTicketRow, entry ParsedEntry) model.Money {
 	if row.CostAmount > 0 {
 		return row.CostAmount
 	}
@@ -108,13 +108,13 @@ func sameDay(left time.Time, right time.Time) bool {
 	return left.Year() == right.Year() && left.Month() == right.Month() && left.Day() == right.Day()
 }
 
-func matchImportRecommendation(userID string, lotteryCode string, issue string, entries []ParsedEntry) (*uuid.UUID, error) {
+func matchImportRecommendation(owner Owner, lotteryCode string, issue string, entries []ParsedEntry) (*uuid.UUID, error) {
 	if len(entries) == 0 {
 		return nil, nil
 	}
 
 	recommendations := make([]model.Recommendation, 0)
-	if err := currentUserScope(db.DB.Preload("Entries"), userID).
+	if err := ownerScope(db.DB.Preload("Entries"), owner).
 		Where("lottery_code = ? AND issue IN ?", lotteryCode, issueAliases(lotteryCode, issue)).
 		Order("created_at DESC").
 		Find(&recommendations).Error; err != nil {
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/service/apperr"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/db"
 	"go-fiber-starter/pkg/util"
@@ -25,8 +26,11 @@ import (
 
 var regexpMultipleAtEnd = regexp.MustCompile(`[（(]\s*(\d+)\s*[)）]\s*$`)
 
+var ErrImportFileInvalid = apperr.Invalid("IMPORT_FILE_INVALID", "请上传 Excel 文件")
+
 type ImportTicketsInput struct {
 	UserID        string
+	LedgerID      string
 	Workbook      []byte
 	ImagesArchive []byte
 }
@@ -64,7 +68,7 @@ type importTicketRow struct {
 	Issue            string
 	DrawDate         time.Time
 	PurchasedAt      time.Time
-	CostAmount       float64
+	CostAmount       model.Money
 	Notes            string
 	EntriesText      string
 	RedNumbers       string
@@ -76,7 +80,7 @@ type importTicketRow struct {
 
 func ImportTickets(ctx context.Context, input ImportTicketsInput) (*TicketImportResult, error) {
 	if len(input.Workbook) == 0 {
-		return nil, fmt.Errorf("请上传 Excel 文件")
+		return nil, ErrImportFileInvalid
 	}
 
 	images, cleanup, err := prepareImportedImages(input.ImagesArchive)
@@ -87,21 +91,21 @@ func ImportTickets(ctx context.Context, input ImportTicketsInput) (*TicketImport
 
 	workbook, err := excelize.OpenReader(bytes.NewReader(input.Workbook))
 	if err != nil {
-		return nil, fmt.Errorf("Excel 文件无法解析")
+		return nil, ErrImportFileInvalid.WithMessage("Excel 文件无法解析")
 	}
 	defer workbook.Close()
 
 	sheetName := workbook.GetSheetName(0)
 	if sheetName == "" {
-		return nil, fmt.Errorf("Excel 中没有可读取的工作表")
+		return nil, ErrImportFileInvalid.WithMessage("Excel 中没有可读取的工作表")
 	}
 
 	rows, err := workbook.GetRows(sheetName)
 	if err != nil {
-		return nil, fmt.Errorf("读取 Excel 行数据失败")
+		return nil, ErrImportFileInvalid.WithMessage("读取 Excel 行数据失败")
 	}
 	if len(rows) <= 1 {
-		return nil, fmt.Errorf("Excel 中没有可导入的数据")
+		return nil, ErrImportFileInvalid.WithMessage("Excel 中没有可导入的数据")
 	}
 
 	headerMap := buildImportHeaderMap(rows[0])
@@ -163,7 +167,7 @@ func ImportTickets(ctx context.Context, input ImportTicketsInput) (*TicketImport
 			continue
 		}
 
-		ticket, importErr := importTicketGroupWithImage(ctx, input.UserID, *group, images)
+		ticket, importErr := importTicketGroupWithImage(ctx, Owner{UserID: input.UserID, LedgerID: input.LedgerID}, *group, images)
 		for index := range result.Rows {
 			rowResult := &result.Rows[index]
 			if rowResult.Status != "" || rowResult.Row < 2 {
@@ -194,13 +198,13 @@ func ImportTickets(ctx context.Context, input ImportTicketsInput) (*TicketImport
 	return result, nil
 }
 
-func importTicketGroupWithImage(ctx context.Context, userID string, group importTicketGroup, images map[string]string) (*TicketDetail, error) {
+func importTicketGroupWithImage(ctx context.Context, owner Owner, group importTicketGroup, images map[string]string) (*TicketDetail, error) {
 	payload, err := buildImportGroupPayload(group, images)
 	if err != nil {
 		return nil, err
 	}
 
-	recommendation, recommendationID, err := resolveRecommendation(userID, payload.LotteryCode, payload.RecommendationID)
+	recommendation, recommendationID, err := resolveRecommendation(owner, payload.LotteryCode, payload.RecommendationID)
 	if err != nil {
 		return nil, err
 	}
@@ -237,15 +241,15 @@ func importTicketGroupWithImage(ctx context.Context, userID string, group import
 	costAmount := payload.CostAmount
 
 	if recommendationID == nil {
-		matchedRecommendationID, matchErr := matchImportRecommendation(userID, code, issue, entries)
+		matchedRecommendationID, matchErr := matchImportRecommendation(owner, code, issue, entries)
 		if matchErr != nil {
 			return nil, matchErr
 		}
 		recommendationID = matchedRecommendationID
 	}
 
-	if err := db.DB.Transaction(func(tx *gorm.DB) error {
-		tickets, findErr := findImportExistingTickets(tx, userID, code, issue)
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tickets, findErr := findImportExistingTickets(tx, owner, code, issue)
 		if findErr != nil {
 			return findErr
 		}
@@ -265,7 +269,7 @@ func importTicketGroupWithImage(ctx context.Context, userID string, group import
 
 		createdTicket, createErr := createTicketRecord(
 			tx,
-			userID,
+			owner,
 			code,
 			recommendationID,
 			issue,
@@ -293,7 +297,7 @@ func importTicketGroupWithImage(ctx context.Context, userID string, group import
 	}
 
 	if shouldDeferSettlement(code, &drawDate) {
-		return GetTicketDetail(ticketID, userID)
+		return GetTicketDetail(ticketID, owner)
 	}
 
 	ensureIssueDrawSynced(ctx, code, issue)
@@ -303,7 +307,7 @@ func importTicketGroupWithImage(ctx context.Context, userID string, group import
 		}
 	}
 
-	return GetTicketDetail(ticketID, userID)
+	return GetTicketDetail(ticketID, owner)
 }
 
 func resolveImportPurchasedAt(drawDate time.Time) time.Time {
@@ -313,9 +317,9 @@ func resolveImportPurchasedAt(drawDate time.Time) time.Time {
 	return time.Now()
 }
 
-func findImportExistingTickets(tx *gorm.DB, userID string, code string, issue string) ([]model.Ticket, error) {
+func findImportExistingTickets(tx *gorm.DB, owner Owner, code string, issue string) ([]model.Ticket, error) {
 	tickets := make([]model.Ticket, 0)
-	err := currentUserScope(tx, userID).
+	err := ownerScope(tx, owner).
 		Where("lottery_code = ? AND issue IN ?", code, issueAliases(code, issue)).
 		Order("updated_at desc").
 		Order("created_at desc").
@@ -338,7 +342,7 @@ func deleteImportedDuplicateTickets(tx *gorm.DB, tickets []model.Ticket) error {
 	return tx.Where("id IN ?", ids).Delete(&model.Ticket{}).Error
 }
 
-func updateImportedTicketRecord(tx *gorm.DB, ticketID string, recommendationID *uuid.UUID, issue string, drawDate time.Time, imagePath string, purchasedAt time.Time, costAmount float64, notes string, entries []ParsedEntry) error {
+func updateImportedTicketRecord(tx *gorm.DB, ticketID string, recommendationID *uuid.UUID, issue string, drawDate time.Time, imagePath string, purchasedAt time.Time, costAmount model.Money, notes string, entries []ParsedEntry) error {
 	totalCost := costAmount
 	if totalCost <= 0 {
 		totalCost = calculateEntriesCost(entries)
@@ -362,7 +366,7 @@ func updateImportedTicketRecord(tx *gorm.DB, ticketID string, recommendationID *
 			"image_path":        imagePath,
 			"recognized_text":   "",
 			"purchased_at":      purchasedAt,
-			"cost_amount":       totalCost,
+			"cost_amount_cents": totalCost,
 			"notes":             notes,
 		}).Error; err != nil {
 		return err
@@ -439,7 +443,7 @@ func parseImportTicketRow(row []string, headerMap map[string]int) (importTicketR
 	}
 	item.PurchasedAt = purchasedAt
 
-	costAmount, err := parseImportFloat(readImportCell(row, headerMap, "costAmount"))
+	costAmount, err := parseImportMoney(readImportCell(row, headerMap, "costAmount"))
 	if err != nil {
 		return item, false, fmt.Errorf("金额格式不正确")
 	}
@@ -709,12 +713,12 @@ func parseImportExcelDate(value string) (time.Time, bool) {
 	), true
 }
 
-func parseImportFloat(value string) (float64, error) {
+func parseImportMoney(value string) (model.Money, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
 		return 0, nil
 	}
-	return strconv.ParseFloat(value, 64)
+	return model.ParseMoney(value)
 }
 
 func parseImportInt(value string, fallback int) (int, error) {
@@ -752,7 +756,7 @@ func prepareImportedImages(archive []byte) (map[string]string, func(), error) {
 
 	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
 	if err != nil {
-		return nil, cleanup, fmt.Errorf("图片压缩包无法解析")
+		return nil, cleanup, ErrImportFileInvalid.WithMessage("图片压缩包无法解析")
 	}
 
 	batchID := time.Now().Format("20060102150405")
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	model "go-fiber-starter/internal/model/lottery"
+	userModel "go-fiber-starter/internal/model/user"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/db"
 
@@ -101,7 +102,7 @@ func TestImportTicketsOverwriteExistingTicket(t *testing.T) {
 	if secondTicket.Notes != "second import should overwrite" {
 		t.Fatalf("notes not overwritten: %s", secondTicket.Notes)
 	}
-	if secondTicket.CostAmount != 10 {
+	if secondTicket.CostAmount != 10*model.Yuan {
 		t.Fatalf("cost amount not overwritten: %v", secondTicket.CostAmount)
 	}
 
@@ -175,7 +176,7 @@ func TestImportTicketsAutoFieldsAndCodeAlias(t *testing.T) {
 	if ticket.Issue != "2026030" {
 		t.Fatalf("dlt issue not normalized: %s", ticket.Issue)
 	}
-	if ticket.CostAmount != 8 {
+	if ticket.CostAmount != 8*model.Yuan {
 		t.Fatalf("cost amount should be calculated by entries: %v", ticket.CostAmount)
 	}
 	if ticket.ManualDrawDate == nil || ticket.ManualDrawDate.Format("2006-01-02") != "2099-03-23" {
@@ -237,7 +238,7 @@ func TestImportTicketsOverwriteSameIssueWithImportedEntries(t *testing.T) {
 	}
 
 	ticket := querySingleTicketByUser(t, userID)
-	if ticket.CostAmount != 8 {
+	if ticket.CostAmount != 8*model.Yuan {
 		t.Fatalf("cost amount should be overwritten by imported entries: %v", ticket.CostAmount)
 	}
 	if ticket.Notes != "new entry one\nnew entry two" {
@@ -341,8 +342,12 @@ func setupImportTicketTestDB(t *testing.T) {
 	})
 
 	if err := gormDB.AutoMigrate(
+		&userModel.User{},
+		&model.Ledger{},
+		&model.LedgerMember{},
 		&model.Ticket{},
 		&model.TicketEntry{},
+		&model.TicketShare{},
 		&model.Recommendation{},
 		&model.RecommendationEntry{},
 		&model.DrawResult{},
@@ -3,18 +3,26 @@ package lottery
 import (
 	"context"
 	"errors"
-	"fmt"
+	"strings"
 	"time"
 
 	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/service/apperr"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/db"
+	"go-fiber-starter/pkg/logger"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrVisionNotConfigured  = apperr.Unprocessable("VISION_NOT_CONFIGURED", "未配置视觉模型，请填写 OCR 文本作为降级输入")
+	ErrNumbersNotRecognized = apperr.Unprocessable("NUMBERS_NOT_RECOGNIZED", "未从图片中识别到有效号码")
+)
+
 type ScanTicketInput struct {
 	UserID      string
+	LedgerID    string
 	Code        string
 	Issue       string
 	ImagePath   string
@@ -30,6 +38,7 @@ type TicketDetail struct {
 	DrawRedNumbers  string                      `json:"drawRedNumbers"`
 	DrawBlueNumbers string                      `json:"drawBlueNumbers"`
 	Recommendation  *TicketRecommendationDetail `json:"recommendation,omitempty"`
+	Shares          []model.TicketShare         `json:"shares,omitempty"`
 }
 
 type TicketRecommendationDetail struct {
@@ -42,32 +51,58 @@ type TicketRecommendationDetail struct {
 }
 
 type TicketRecommendationEntry struct {
-	ID          string  `json:"id"`
-	Sequence    int     `json:"sequence"`
-	RedNumbers  string  `json:"redNumbers"`
-	BlueNumbers string  `json:"blueNumbers"`
-	PrizeAmount float64 `json:"prizeAmount"`
-	PrizeName   string  `json:"prizeName"`
+	ID          string      `json:"id"`
+	Sequence    int         `json:"sequence"`
+	RedNumbers  string      `json:"redNumbers"`
+	BlueNumbers string      `json:"blueNumbers"`
+	PrizeAmount model.Money `json:"prizeAmount" swaggertype:"number"`
+	PrizeName   string      `json:"prizeName"`
 }
 
+// TicketQueryOptions 描述历史票据查询条件。传入 Cursor 时按游标翻页并忽略 Page，
+// 日期为 YYYY-MM-DD 格式，结束日期包含当天；Number 大于 0 时筛选包含该号码的票据。
 type TicketQueryOptions struct {
-	UserID      string
-	Page        int
-	PageSize    int
-	LotteryCode string
-	Status      string
-	Sort        string
+	UserID            string
+	LedgerID          string
+	Page              int
+	PageSize          int
+	Cursor            string
+	LotteryCode       string
+	Status            string
+	Issue             string
+	PurchasedFrom     string
+	PurchasedTo       string
+	DrawDateFrom      string
+	DrawDateTo        string
+	MinPrize          *model.Money
+	MaxPrize          *model.Money
+	PrizeName         string
+	Source            string
+	HasRecommendation *bool
+	Number            int
+	NumberZone        string
+	Sort              string
 }
 
 type TicketPageResult struct {
-	Items    []TicketDetail `json:"items"`
-	Page     int            `json:"page"`
-	PageSize int            `json:"pageSize"`
-	Total    int64          `json:"total"`
-	HasMore  bool           `json:"hasMore"`
+	Items      []TicketDetail `json:"items"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"pageSize"`
+	Total      int64          `json:"total"`
+	HasMore    bool           `json:"hasMore"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+// ticketSources 把接口中的来源筛选值映射为票据记录中的 source 字段，ocr 是图片识别录入的别名。
+var ticketSources = map[string]string{
+	"ocr":    "upload",
+	"upload": "upload",
+	"manual": "manual",
+	"import": "import",
 }
 
 func ScanTicket(ctx context.Context, input ScanTicketInput) (*TicketDetail, error) {
+	ctx = logger.WithFields(ctx, logger.FieldLotteryCode, input.Code)
 	upload, err := UploadTicketImage(UploadTicketImageInput{
 		UserID:           input.UserID,
 		Code:             input.Code,
@@ -90,6 +125,7 @@ func ScanTicket(ctx context.Context, input ScanTicketInput) (*TicketDetail, erro
 
 	return CreateTicket(ctx, CreateTicketInput{
 		UserID:      input.UserID,
+		LedgerID:    input.LedgerID,
 		Code:        input.Code,
 		UploadID:    upload.Id.String(),
 		Issue:       resolveValue(input.Issue, recognized.Issue),
@@ -119,7 +155,7 @@ func recognizeTicket(ctx context.Context, code string, imagePath string, ocrText
 
 	recognizer := newVisionRecognizer(provider)
 	if recognizer == nil {
-		return nil, fmt.Errorf("未配置视觉模型，请填写 OCR 文本作为降级输入")
+		return nil, ErrVisionNotConfigured
 	}
 
 	recognized, err := recognizer.Recognize(ctx, lotteryType, imagePath)
@@ -133,7 +169,7 @@ func recognizeTicket(ctx context.Context, code string, imagePath string, ocrText
 		return DetectLotteryByText(recognized.RawText)
 	}
 	if len(recognized.Entries) == 0 {
-		return nil, fmt.Errorf("未从图片中识别到有效号码")
+		return nil, ErrNumbersNotRecognized
 	}
 	if recognized.LotteryCode == "" && code != "" {
 		recognized.LotteryCode = code
@@ -201,18 +237,18 @@ func EvaluateTicket(ticketID string) error {
 		return err
 	}
 
-	prizeMap := make(map[string]float64, len(draw.PrizeDetails))
+	prizeMap := make(map[string]model.Money, len(draw.PrizeDetails))
 	for _, prize := range draw.PrizeDetails {
 		prizeMap[normalizePrizeName(prize.PrizeName)] = prize.SingleBonus
 	}
 
-	totalPrize := 0.0
+	var totalPrize model.Money
 	hasWinning := false
 	for _, entry := range ticket.Entries {
 		result := JudgeNumbers(ticket.LotteryCode, entry.RedNumbers, entry.BlueNumbers, entry.IsAdditional, *draw, prizeMap)
 		entry.IsWinning = result.IsWinning
 		entry.PrizeName = result.PrizeName
-		entry.PrizeAmount = result.PrizeAmount * float64(max(1, entry.Multiple))
+		entry.PrizeAmount = result.PrizeAmount * model.Money(max(1, entry.Multiple))
 		entry.MatchSummary = result.MatchSummary
 		totalPrize += entry.PrizeAmount
 		hasWinning = hasWinning || result.IsWinning
@@ -229,12 +265,30 @@ func EvaluateTicket(ticketID string) error {
 	} else {
 		ticket.Status = TicketStatusNotWon
 	}
-	return db.DB.Omit("Entries").Save(&ticket).Error
+	if err := db.DB.Omit("Entries").Save(&ticket).Error; err != nil {
+		return err
+	}
+	return refreshTicketShares(db.DB, ticket.Id.String())
+}
+
+// syncTicketDraw 在判奖缺少开奖结果时按期号同步，测试中可替换以避免请求开奖数据源。
+var syncTicketDraw = SyncLatestDraw
+
+// drawSyncFunc 按彩种和期号同步开奖结果。
+type drawSyncFunc func(ctx context.Context, code string, issue string) error
+
+func RecheckTicket(ctx context.Context, ticketID string, code string, owner Owner) (*TicketDetail, error) {
+	return recheckTicket(ctx, ticketID, code, owner, func(ctx context.Context, code string, issue string) error {
+		_, err := syncTicketDraw(ctx, code, issue)
+		return err
+	})
 }
 
-func RecheckTicket(ctx context.Context, ticketID string, code string, userID string) (*TicketDetail, error) {
+// recheckTicket 重新判奖，开奖结果未入库且已到开奖时间时通过 syncDraw 补同步。
+func recheckTicket(ctx context.Context, ticketID string, code string, owner Owner, syncDraw drawSyncFunc) (*TicketDetail, error) {
+	ctx = logger.WithFields(ctx, logger.FieldTicketID, ticketID, logger.FieldLotteryCode, code)
 	ticket := model.Ticket{}
-	query := currentUserScope(db.DB.Preload("Entries"), userID)
+	query := ownerScope(db.DB.WithContext(ctx).Preload("Entries"), owner)
 	if code != "" {
 		query = query.Where("lottery_code = ?", code)
 	}
@@ -244,16 +298,16 @@ func RecheckTicket(ctx context.Context, ticketID string, code string, userID str
 
 	ticketIssue := normalizeIssueByCode(ticket.LotteryCode, ticket.Issue)
 	if ticketIssue == "" {
-		return nil, fmt.Errorf("票据期号不能为空")
+		return nil, ErrIssueRequired
 	}
 	if _, err := findSettlementDraw(ticket.LotteryCode, ticketIssue); errors.Is(err, gorm.ErrRecordNotFound) {
 		if shouldDeferSettlement(ticket.LotteryCode, ticket.ManualDrawDate) {
 			if resetErr := resetTicketPending(ticket.Id.String()); resetErr != nil {
 				return nil, resetErr
 			}
-			return GetTicketDetail(ticket.Id.String(), userID)
+			return GetTicketDetail(ticket.Id.String(), owner)
 		}
-		if _, syncErr := SyncLatestDraw(ctx, ticket.LotteryCode, ticketIssue); syncErr != nil {
+		if syncErr := syncDraw(ctx, ticket.LotteryCode, ticketIssue); syncErr != nil {
 			return nil, syncErr
 		}
 	} else if err != nil {
@@ -262,24 +316,24 @@ func RecheckTicket(ctx context.Context, ticketID string, code string, userID str
 	if err := EvaluateTicket(ticket.Id.String()); err != nil {
 		return nil, err
 	}
-	return GetTicketDetail(ticket.Id.String(), userID)
+	return GetTicketDetail(ticket.Id.String(), owner)
 }
 
-func GetTicketDetail(ticketID string, userID string) (*TicketDetail, error) {
+func GetTicketDetail(ticketID string, owner Owner) (*TicketDetail, error) {
 	ticket := model.Ticket{}
-	if err := currentUserScope(db.DB.Preload("Entries"), userID).First(&ticket, "id = ?", ticketID).Error; err != nil {
+	if err := ownerScope(db.DB.Preload("Entries"), owner).First(&ticket, "id = ?", ticketID).Error; err != nil {
 		return nil, err
 	}
 	return buildTicketDetail(ticket), nil
 }
 
-func ListTickets(code string, limit int, userID string) ([]TicketDetail, error) {
+func ListTickets(code string, limit int, owner Owner) ([]TicketDetail, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 
 	tickets := make([]model.Ticket, 0)
-	if err := currentUserScope(db.DB.Preload("Entries"), userID).Where("lottery_code = ?", code).Order("created_at desc").Limit(limit).Find(&tickets).Error; err != nil {
+	if err := ownerScope(db.DB.Preload("Entries"), owner).Where("lottery_code = ?", code).Order("created_at desc").Limit(limit).Find(&tickets).Error; err != nil {
 		return nil, err
 	}
 
@@ -290,13 +344,13 @@ func ListTickets(code string, limit int, userID string) ([]TicketDetail, error)
 	return result, nil
 }
 
-func ListAllTickets(limit int, userID string) ([]TicketDetail, error) {
+func ListAllTickets(limit int, owner Owner) ([]TicketDetail, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 
 	tickets := make([]model.Ticket, 0)
-	if err := currentUserScope(db.DB.Preload("Entries"), userID).Order("created_at desc").Limit(limit).Find(&tickets).Error; err != nil {
+	if err := ownerScope(db.DB.Preload("Entries"), owner).Order("created_at desc").Limit(limit).Find(&tickets).Error; err != nil {
 		return nil, err
 	}
 
@@ -317,43 +371,80 @@ func QueryAllTickets(options TicketQueryOptions) (*TicketPageResult, error) {
 		pageSize = 50
 	}
 
-	query := currentUserScope(db.DB.Model(&model.Ticket{}), options.UserID)
-	if options.LotteryCode != "" {
-		query = query.Where("lottery_code = ?", options.LotteryCode)
-	}
-	if options.Status != "" {
-		query = query.Where("status = ?", options.Status)
+	owner := Owner{UserID: options.UserID, LedgerID: options.LedgerID}
+	filters, err := applyTicketFilters(options)
+	if err != nil {
+		return nil, err
 	}
 
 	var total int64
-	if err := query.Count(&total).Error; err != nil {
+	if err := ownerScope(db.DB.Model(&model.Ticket{}), owner).Scopes(filters).Count(&total).Error; err != nil {
 		return nil, err
 	}
 
-	tickets := make([]model.Ticket, 0)
-	if err := currentUserScope(db.DB.Preload("Entries"), options.UserID).
-		Scopes(applyTicketFilters(options), applyTicketSort(options.Sort)).
-		Offset((page - 1) * pageSize).
-		Limit(pageSize).
-		Find(&tickets).Error; err != nil {
+	order := ticketKeysetOrder(options.Sort)
+	query := order.apply(ownerScope(db.DB.Preload("Entries"), owner).Scopes(filters))
+	if strings.TrimSpace(options.Cursor) != "" {
+		page = 0
+		if query, err = order.after(query, options.Cursor); err != nil {
+			return nil, err
+		}
+	} else {
+		query = query.Offset((page - 1) * pageSize)
+	}
+
+	tickets := make([]model.Ticket, 0, pageSize+1)
+	if err := query.Limit(pageSize + 1).Find(&tickets).Error; err != nil {
 		return nil, err
 	}
+	hasMore := len(tickets) > pageSize
+	if hasMore {
+		tickets = tickets[:pageSize]
+	}
 
 	items := make([]TicketDetail, 0, len(tickets))
 	for _, ticket := range tickets {
 		items = append(items, *buildTicketDetail(ticket))
 	}
 
-	return &TicketPageResult{
+	result := &TicketPageResult{
 		Items:    items,
 		Page:     page,
 		PageSize: pageSize,
 		Total:    total,
-		HasMore:  int64(page*pageSize) < total,
-	}, nil
+		HasMore:  hasMore,
+	}
+	if hasMore {
+		result.NextCursor = ticketCursor(order, tickets[len(tickets)-1], options.Sort)
+	}
+	return result, nil
 }
 
-func applyTicketFilters(options TicketQueryOptions) func(*gorm.DB) *gorm.DB {
+func applyTicketFilters(options TicketQueryOptions) (func(*gorm.DB) *gorm.DB, error) {
+	purchasedFrom, purchasedTo, err := parseDateRange("购买日期", options.PurchasedFrom, options.PurchasedTo)
+	if err != nil {
+		return nil, err
+	}
+	drawCondition, err := buildTicketDrawDateCondition(options)
+	if err != nil {
+		return nil, err
+	}
+	if options.MinPrize != nil && options.MaxPrize != nil && *options.MinPrize > *options.MaxPrize {
+		return nil, ErrInvalidQueryFilter.WithMessage("最低奖金不能大于最高奖金")
+	}
+	source := ""
+	if value := strings.ToLower(strings.TrimSpace(options.Source)); value != "" {
+		mapped, ok := ticketSources[value]
+		if !ok {
+			return nil, ErrInvalidQueryFilter.WithMessage("来源只能是 ocr、manual 或 import")
+		}
+		source = mapped
+	}
+	numberColumns, err := numberZoneColumns(options.NumberZone, options.Number)
+	if err != nil {
+		return nil, err
+	}
+
 	return func(query *gorm.DB) *gorm.DB {
 		if options.LotteryCode != "" {
 			query = query.Where("lottery_code = ?", options.LotteryCode)
@@ -361,22 +452,125 @@ func applyTicketFilters(options TicketQueryOptions) func(*gorm.DB) *gorm.DB {
 		if options.Status != "" {
 			query = query.Where("status = ?", options.Status)
 		}
+		if issue := strings.TrimSpace(options.Issue); issue != "" {
+			query = query.Where("issue IN ?", buildDrawIssueAliases(options.LotteryCode, issue))
+		}
+		if !purchasedFrom.IsZero() {
+			query = query.Where("purchased_at >= ?", purchasedFrom)
+		}
+		if !purchasedTo.IsZero() {
+			query = query.Where("purchased_at < ?", purchasedTo)
+		}
+		if drawCondition != nil {
+			query = query.Where(drawCondition)
+		}
+		if options.MinPrize != nil {
+			query = query.Where("prize_amount_cents >= ?", *options.MinPrize)
+		}
+		if options.MaxPrize != nil {
+			query = query.Where("prize_amount_cents <= ?", *options.MaxPrize)
+		}
+		if prizeName := strings.TrimSpace(options.PrizeName); prizeName != "" {
+			query = query.Where("EXISTS (SELECT 1 FROM ticket_entries WHERE ticket_entries.ticket_id = tickets.id AND ticket_entries.prize_name = ?)", normalizePrizeName(prizeName))
+		}
+		if source != "" {
+			query = query.Where("source = ?", source)
+		}
+		if options.HasRecommendation != nil {
+			if *options.HasRecommendation {
+				query = query.Where("recommendation_id IS NOT NULL")
+			} else {
+				query = query.Where("recommendation_id IS NULL")
+			}
+		}
+		if options.Number > 0 {
+			conditions := make([]string, 0, len(numberColumns))
+			args := make([]any, 0, len(numberColumns))
+			for _, column := range numberColumns {
+				conditions = append(conditions, "(ticket_entries."+column+" & ?) <> 0")
+				args = append(args, model.NumberBit(options.Number))
+			}
+			query = query.Where("EXISTS (SELECT 1 FROM ticket_entries WHERE ticket_entries.ticket_id = tickets.id AND ("+strings.Join(conditions, " OR ")+"))", args...)
+		}
 		return query
+	}, nil
+}
+
+// buildTicketDrawDateCondition 按开奖日期筛选票据：手动填写的开奖日期优先，
+// 否则先查出区间内的开奖期号，再按彩种和期号别名匹配票据。
+func buildTicketDrawDateCondition(options TicketQueryOptions) (*gorm.DB, error) {
+	from, to, err := parseDateRange("开奖日期", options.DrawDateFrom, options.DrawDateTo)
+	if err != nil || (from.IsZero() && to.IsZero()) {
+		return nil, err
+	}
+
+	drawQuery := db.DB.Model(&model.DrawResult{}).Select("lottery_code", "issue")
+	manualCondition := db.DB.Where("manual_draw_date IS NOT NULL")
+	if !from.IsZero() {
+		drawQuery = drawQuery.Where("draw_date >= ?", from)
+		manualCondition = manualCondition.Where("manual_draw_date >= ?", from)
+	}
+	if !to.IsZero() {
+		drawQuery = drawQuery.Where("draw_date < ?", to)
+		manualCondition = manualCondition.Where("manual_draw_date < ?", to)
+	}
+	if options.LotteryCode != "" {
+		drawQuery = drawQuery.Where("lottery_code = ?", options.LotteryCode)
+	}
+	draws := make([]model.DrawResult, 0)
+	if err := drawQuery.Find(&draws).Error; err != nil {
+		return nil, err
+	}
+
+	issuesByCode := make(map[string][]string)
+	for _, draw := range draws {
+		issuesByCode[draw.LotteryCode] = append(issuesByCode[draw.LotteryCode], issueAliases(draw.LotteryCode, draw.Issue)...)
+	}
+	condition := manualCondition
+	for code, issues := range issuesByCode {
+		condition = condition.Or("manual_draw_date IS NULL AND lottery_code = ? AND issue IN ?", code, issues)
 	}
+	return condition, nil
 }
 
-func applyTicketSort(sort string) func(*gorm.DB) *gorm.DB {
-	return func(query *gorm.DB) *gorm.DB {
-		switch sort {
-		case "oldest":
-			return query.Order("purchased_at asc").Order("created_at asc")
-		case "prize_high":
-			return query.Order("prize_amount desc").Order("purchased_at desc")
-		case "cost_high":
-			return query.Order("cost_amount desc").Order("purchased_at desc")
-		default:
-			return query.Order("purchased_at desc").Order("created_at desc")
-		}
+// numberZoneColumns 返回号码筛选涉及的位图列，不指定区域时同时匹配红球（前区）和蓝球（后区）。
+func numberZoneColumns(zone string, number int) ([]string, error) {
+	if number > model.MaxMaskNumber {
+		return nil, ErrInvalidQueryFilter.WithMessage("号码不能大于 %d", model.MaxMaskNumber)
+	}
+	switch strings.ToLower(strings.TrimSpace(zone)) {
+	case "":
+		return []string{"red_mask", "blue_mask"}, nil
+	case "red":
+		return []string{"red_mask"}, nil
+	case "blue":
+		return []string{"blue_mask"}, nil
+	default:
+		return nil, ErrInvalidQueryFilter.WithMessage("号码区域只能是 red 或 blue")
+	}
+}
+
+func ticketKeysetOrder(sort string) keysetOrder {
+	switch sort {
+	case "oldest":
+		return keysetOrder{columns: []keysetColumn{{"purchased_at", keysetTime}, {"created_at", keysetTime}, {"id", keysetString}}}
+	case "prize_high":
+		return keysetOrder{columns: []keysetColumn{{"prize_amount_cents", keysetMoney}, {"purchased_at", keysetTime}, {"id", keysetString}}, desc: true}
+	case "cost_high":
+		return keysetOrder{columns: []keysetColumn{{"cost_amount_cents", keysetMoney}, {"purchased_at", keysetTime}, {"id", keysetString}}, desc: true}
+	default:
+		return keysetOrder{columns: []keysetColumn{{"purchased_at", keysetTime}, {"created_at", keysetTime}, {"id", keysetString}}, desc: true}
+	}
+}
+
+func ticketCursor(order keysetOrder, ticket model.Ticket, sort string) string {
+	switch sort {
+	case "prize_high":
+		return order.encode(ticket.PrizeAmount, ticket.PurchasedAt, ticket.Id.String())
+	case "cost_high":
+		return order.encode(ticket.CostAmount, ticket.PurchasedAt, ticket.Id.String())
+	default:
+		return order.encode(ticket.PurchasedAt, ticket.CreatedAt, ticket.Id.String())
 	}
 }
 
@@ -400,13 +594,21 @@ func buildTicketDetail(ticket model.Ticket) *TicketDetail {
 	if ticket.RecommendationID != nil {
 		recommendation := model.Recommendation{}
 		query := db.DB.Preload("Entries").Where("id = ?", *ticket.RecommendationID)
-		if ticket.UserID != nil {
+		if ticket.LedgerID != nil {
+			query = query.Where("ledger_id = ?", *ticket.LedgerID)
+		} else if ticket.UserID != nil {
 			query = query.Where("user_id = ?", *ticket.UserID)
 		}
 		if err := query.First(&recommendation).Error; err == nil {
 			detail.Recommendation = buildTicketRecommendationDetail(recommendation)
 		}
 	}
+	if ticket.IsSyndicate {
+		shares := make([]model.TicketShare, 0)
+		if err := db.DB.Where("ticket_id = ?", ticket.Id).Order("created_at asc").Order("id asc").Find(&shares).Error; err == nil {
+			detail.Shares = shares
+		}
+	}
 
 	return detail
 }
@@ -458,21 +660,24 @@ func resetTicketPendingWithDB(tx *gorm.DB, ticketID string) error {
 	if err := tx.Model(&model.TicketEntry{}).
 		Where("ticket_id = ?", ticketID).
 		Updates(map[string]any{
-			"is_winning":    false,
-			"prize_name":    "",
-			"prize_amount":  0,
-			"match_summary": "待开奖",
+			"is_winning":         false,
+			"prize_name":         "",
+			"prize_amount_cents": 0,
+			"match_summary":      "待开奖",
 		}).Error; err != nil {
 		return err
 	}
 
-	return tx.Model(&model.Ticket{}).
+	if err := tx.Model(&model.Ticket{}).
 		Where("id = ?", ticketID).
 		Updates(map[string]any{
-			"status":       TicketStatusPending,
-			"checked_at":   nil,
-			"prize_amount": 0,
-		}).Error
+			"status":             TicketStatusPending,
+			"checked_at":         nil,
+			"prize_amount_cents": 0,
+		}).Error; err != nil {
+		return err
+	}
+	return refreshTicketShares(tx, ticketID)
 }
 
 func resetTicketPending(ticketID string) error {
@@ -480,21 +685,24 @@ func resetTicketPending(ticketID string) error {
 		if err := tx.Model(&model.TicketEntry{}).
 			Where("ticket_id = ?", ticketID).
 			Updates(map[string]any{
-				"is_winning":    false,
-				"prize_name":    "",
-				"prize_amount":  0,
-				"match_summary": "待开奖",
+				"is_winning":         false,
+				"prize_name":         "",
+				"prize_amount_cents": 0,
+				"match_summary":      "待开奖",
 			}).Error; err != nil {
 			return err
 		}
 
-		return tx.Model(&model.Ticket{}).
+		if err := tx.Model(&model.Ticket{}).
 			Where("id = ?", ticketID).
 			Updates(map[string]any{
-				"status":       TicketStatusPending,
-				"checked_at":   nil,
-				"prize_amount": 0,
-			}).Error
+				"status":             TicketStatusPending,
+				"checked_at":         nil,
+				"prize_amount_cents": 0,
+			}).Error; err != nil {
+			return err
+		}
+		return refreshTicketShares(tx, ticketID)
 	})
 }
 
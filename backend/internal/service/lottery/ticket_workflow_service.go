@@ -5,12 +5,12 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	model "go-fiber-starter/internal/model/lottery"
+	"go-fiber-starter/internal/service/apperr"
 	"go-fiber-starter/pkg/db"
 	"go-fiber-starter/pkg/logger"
 
@@ -26,7 +26,18 @@ const (
 	TicketUploadStatusSaved      = "saved"
 )
 
-var ErrDuplicateTicket = errors.New("相同票据已存在，不能重复录入")
+var (
+	ErrDuplicateTicket          = apperr.New(apperr.KindConflict, "TICKET_DUPLICATE", "相同票据已存在，不能重复录入")
+	ErrTicketImageSaved         = apperr.New(apperr.KindConflict, "TICKET_IMAGE_SAVED", "这张图片已经入库，请重新上传新图片")
+	ErrTicketUploadBusy         = apperr.New(apperr.KindConflict, "TICKET_UPLOAD_BUSY", "这张图片正在处理中，请稍后重试")
+	ErrTicketUploadMismatch     = apperr.Unprocessable("TICKET_UPLOAD_MISMATCH", "上传记录的彩票类型与当前录入类型不一致")
+	ErrTicketRecognitionMissing = apperr.Unprocessable("TICKET_RECOGNITION_MISSING", "请先调用识别接口获取号码结果")
+	ErrTicketEntriesRequired    = apperr.Unprocessable("TICKET_ENTRIES_REQUIRED", "至少需要一注号码")
+	ErrInvalidTicketEntry       = apperr.Unprocessable("TICKET_ENTRY_INVALID", "号码不正确")
+	ErrIssueRequired            = apperr.Invalid("ISSUE_REQUIRED", "票据期号不能为空")
+	ErrLotteryCodeUnresolved    = apperr.Unprocessable("LOTTERY_CODE_UNRESOLVED", "未识别出彩票类型，请先完成识别或手动指定彩种")
+	ErrRecommendationNotFound   = apperr.NotFound("RECOMMENDATION_NOT_FOUND", "推荐记录不存在")
+)
 
 type UploadTicketImageInput struct {
 	UserID           string
@@ -44,26 +55,28 @@ type RecognizeUploadedTicketInput struct {
 
 type CreateTicketInput struct {
 	UserID           string
+	LedgerID         string
 	Code             string
 	UploadID         string
 	RecommendationID string
 	Issue            string
 	DrawDate         time.Time
 	PurchasedAt      time.Time
-	CostAmount       float64
+	CostAmount       model.Money
 	Notes            string
 	Entries          []ParsedEntry
 }
 
 type UpdateTicketInput struct {
 	UserID           string
+	LedgerID         string
 	TicketID         string
 	Code             string
 	RecommendationID string
 	Issue            string
 	DrawDate         time.Time
 	PurchasedAt      time.Time
-	CostAmount       float64
+	CostAmount       model.Money
 	Notes            string
 	Entries          []ParsedEntry
 }
@@ -78,7 +91,7 @@ type TicketRecognitionDraft struct {
 	LotteryCode string             `json:"lotteryCode"`
 	Issue       string             `json:"issue"`
 	DrawDate    string             `json:"drawDate"`
-	CostAmount  float64            `json:"costAmount"`
+	CostAmount  model.Money        `json:"costAmount" swaggertype:"number"`
 	RawText     string             `json:"rawText"`
 	Confidence  float64            `json:"confidence"`
 	Entries     []ParsedEntry      `json:"entries"`
@@ -104,19 +117,21 @@ func UploadTicketImage(input UploadTicketImageInput) (*TicketUploadDetail, error
 }
 
 func RecognizeUploadedTicket(ctx context.Context, input RecognizeUploadedTicketInput) (*TicketRecognitionDraft, error) {
+	ctx = logger.WithFields(ctx, logger.FieldLotteryCode, input.Code, "upload_id", input.UploadID)
 	upload, err := getTicketUpload(input.UserID, input.Code, input.UploadID)
 	if err != nil {
 		return nil, err
 	}
 	if upload.Status == TicketUploadStatusSaved {
-		return nil, fmt.Errorf("这张图片已经入库，请重新上传新图片")
+		return nil, ErrTicketImageSaved
 	}
+	ctx, _ = trackExternalCalls(ctx, &upload.Id)
 
 	recognized, err := recognizeTicket(ctx, resolveTicketCode(input.Code, upload.LotteryCode), upload.ImagePath, input.OCRText)
 	if err != nil {
 		upload.Status = TicketUploadStatusFailed
 		upload.ErrorMessage = err.Error()
-		if saveErr := db.DB.Save(&upload).Error; saveErr != nil {
+		if saveErr := db.DB.WithContext(ctx).Save(&upload).Error; saveErr != nil {
 			return nil, saveErr
 		}
 		return nil, err
@@ -129,7 +144,7 @@ func RecognizeUploadedTicket(ctx context.Context, input RecognizeUploadedTicketI
 	upload.RecognitionIssue = recognized.Issue
 	upload.RecognitionConfidence = recognized.Confidence
 	upload.RecognitionPayload = mustJSON(recognized)
-	if err := db.DB.Save(&upload).Error; err != nil {
+	if err := db.DB.WithContext(ctx).Save(&upload).Error; err != nil {
 		return nil, err
 	}
 
@@ -146,7 +161,9 @@ func RecognizeUploadedTicket(ctx context.Context, input RecognizeUploadedTicketI
 }
 
 func CreateTicket(ctx context.Context, input CreateTicketInput) (*TicketDetail, error) {
-	recommendation, recommendationID, err := resolveRecommendation(input.UserID, input.Code, input.RecommendationID)
+	ctx = logger.WithFields(ctx, logger.FieldLotteryCode, input.Code)
+	owner := Owner{UserID: input.UserID, LedgerID: input.LedgerID}
+	recommendation, recommendationID, err := resolveRecommendation(owner, input.Code, input.RecommendationID)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +178,7 @@ func CreateTicket(ctx context.Context, input CreateTicketInput) (*TicketDetail,
 	issue := ""
 	shouldEvaluate := false
 
-	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var imagePath string
 		var recognizedText string
 		var source string
@@ -187,7 +204,7 @@ func CreateTicket(ctx context.Context, input CreateTicketInput) (*TicketDetail,
 
 			if len(input.Entries) == 0 {
 				if upload.RecognitionPayload == "" {
-					return fmt.Errorf("请先识别号码，或手动填写完整号码后保存")
+					return ErrTicketRecognitionMissing.WithMessage("请先识别号码，或手动填写完整号码后保存")
 				}
 				input.Entries, reserveErr = getRecognizedEntries(upload)
 				if reserveErr != nil {
@@ -223,17 +240,17 @@ func CreateTicket(ctx context.Context, input CreateTicketInput) (*TicketDetail,
 			issue = recommendation.Issue
 		}
 		if issue == "" {
-			return fmt.Errorf("未识别到期号，请手动补充期号后重试")
+			return ErrIssueRequired.WithMessage("未识别到期号，请手动补充期号后重试")
 		}
 		issue = normalizeIssueByCode(code, issue)
 
 		drawDate := input.DrawDate
 
-		if reserveErr := validateDuplicateTicket(tx, input.UserID, code, issue, input.Entries); reserveErr != nil {
+		if reserveErr := validateDuplicateTicket(tx, owner, code, issue, input.Entries); reserveErr != nil {
 			return reserveErr
 		}
 
-		ticket, createErr := createTicketRecord(tx, input.UserID, code, recommendationID, issue, drawDate, source, imagePath, recognizedText, purchasedAt, calculateEntriesCost(input.Entries), input.Notes, input.Entries)
+		ticket, createErr := createTicketRecord(tx, owner, code, recommendationID, issue, drawDate, source, imagePath, recognizedText, purchasedAt, calculateEntriesCost(input.Entries), input.Notes, input.Entries)
 		if createErr != nil {
 			if isUniqueConstraintError(createErr) {
 				return ErrDuplicateTicket
@@ -261,24 +278,26 @@ func CreateTicket(ctx context.Context, input CreateTicketInput) (*TicketDetail,
 	}
 
 	if shouldDeferSettlement(code, &input.DrawDate) {
-		return GetTicketDetail(ticketID, input.UserID)
+		return GetTicketDetail(ticketID, owner)
 	}
 	ensureIssueDrawSynced(ctx, code, issue)
 	if shouldEvaluate {
 		if err := EvaluateTicket(ticketID); err != nil {
-			logger.Warn("票据自动判奖失败 %s/%s: %v", code, ticketID, err)
+			logger.WarnContext(ctx, "票据自动判奖失败 %s/%s: %v", code, ticketID, err)
 		}
 	}
-	return GetTicketDetail(ticketID, input.UserID)
+	return GetTicketDetail(ticketID, owner)
 }
 
 func UpdateTicket(ctx context.Context, input UpdateTicketInput) (*TicketDetail, error) {
+	ctx = logger.WithFields(ctx, logger.FieldTicketID, input.TicketID, logger.FieldLotteryCode, input.Code)
 	if input.TicketID == "" {
-		return nil, fmt.Errorf("票据 ID 不能为空")
+		return nil, apperr.ErrInvalidRequest.WithMessage("票据 ID 不能为空")
 	}
 
+	owner := Owner{UserID: input.UserID, LedgerID: input.LedgerID}
 	ticket := model.Ticket{}
-	if err := currentUserScope(db.DB.Preload("Entries"), input.UserID).First(&ticket, "id = ?", input.TicketID).Error; err != nil {
+	if err := ownerScope(db.DB.WithContext(ctx).Preload("Entries"), owner).First(&ticket, "id = ?", input.TicketID).Error; err != nil {
 		return nil, err
 	}
 
@@ -295,7 +314,7 @@ func UpdateTicket(ctx context.Context, input UpdateTicketInput) (*TicketDetail,
 
 	issue := normalizeIssueByCode(code, resolveValue(input.Issue, ticket.Issue))
 	if issue == "" {
-		return nil, fmt.Errorf("票据期号不能为空")
+		return nil, ErrIssueRequired
 	}
 
 	purchasedAt := input.PurchasedAt
@@ -311,30 +330,37 @@ func UpdateTicket(ctx context.Context, input UpdateTicketInput) (*TicketDetail,
 		return nil, err
 	}
 
-	if err := db.DB.Transaction(func(tx *gorm.DB) error {
-		if err := validateDuplicateTicketExcept(tx, input.UserID, input.TicketID, code, issue, entries); err != nil {
+	if err := db.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := validateDuplicateTicketExcept(tx, owner, input.TicketID, code, issue, entries); err != nil {
 			return err
 		}
 		if err := updateTicketRecord(tx, ticket, code, recommendationID, issue, input.DrawDate, purchasedAt, input.Notes, entries); err != nil {
 			return err
 		}
-		return replaceTicketEntries(tx, ticket.Id, entries)
+		if err := replaceTicketEntries(tx, ticket.Id, entries); err != nil {
+			return err
+		}
+		return refreshTicketShares(tx, ticket.Id.String())
 	}); err != nil {
 		return nil, err
 	}
 
 	if shouldDeferSettlement(code, &input.DrawDate) {
-		return GetTicketDetail(input.TicketID, input.UserID)
+		return GetTicketDetail(input.TicketID, owner)
 	}
 	ensureIssueDrawSynced(ctx, code, issue)
 	if err := EvaluateTicket(input.TicketID); err != nil {
-		logger.Warn("编辑票据后自动判奖失败 %s/%s: %v", code, input.TicketID, err)
+		logger.WarnContext(ctx, "编辑票据后自动判奖失败 %s/%s: %v", code, input.TicketID, err)
 	}
-	return GetTicketDetail(input.TicketID, input.UserID)
+	return GetTicketDetail(input.TicketID, owner)
 }
 
-func createTicketRecord(tx *gorm.DB, userID string, code string, recommendationID *uuid.UUID, issue string, drawDate time.Time, source string, imagePath string, recognizedText string, purchasedAt time.Time, costAmount float64, notes string, entries []ParsedEntry) (*model.Ticket, error) {
-	userUUID, err := parseRequiredUserID(userID)
+func createTicketRecord(tx *gorm.DB, owner Owner, code string, recommendationID *uuid.UUID, issue string, drawDate time.Time, source string, imagePath string, recognizedText string, purchasedAt time.Time, costAmount model.Money, notes string, entries []ParsedEntry) (*model.Ticket, error) {
+	userUUID, err := parseRequiredUserID(owner.UserID)
+	if err != nil {
+		return nil, err
+	}
+	ledgerUUID, err := owner.ledgerUUID()
 	if err != nil {
 		return nil, err
 	}
@@ -358,6 +384,7 @@ func createTicketRecord(tx *gorm.DB, userID string, code string, recommendationI
 
 	ticket := model.Ticket{
 		UserID:           &userUUID,
+		LedgerID:         ledgerUUID,
 		LotteryCode:      code,
 		RecommendationID: recommendationID,
 		Issue:            issue,
@@ -398,7 +425,7 @@ func createTicketRecord(tx *gorm.DB, userID string, code string, recommendationI
 
 func resolveUpdateRecommendationID(input UpdateTicketInput, ticket model.Ticket, code string) (*uuid.UUID, error) {
 	if input.RecommendationID != "" {
-		_, recommendationID, err := resolveRecommendation(input.UserID, code, input.RecommendationID)
+		_, recommendationID, err := resolveRecommendation(Owner{UserID: input.UserID, LedgerID: input.LedgerID}, code, input.RecommendationID)
 		return recommendationID, err
 	}
 	return ticket.RecommendationID, nil
@@ -428,18 +455,18 @@ func updateTicketRecord(tx *gorm.DB, ticket model.Ticket, code string, recommend
 	return tx.Omit("Entries").Save(&ticket).Error
 }
 
-func validateDuplicateTicket(tx *gorm.DB, userID string, code string, issue string, entries []ParsedEntry) error {
-	return validateDuplicateTicketExcept(tx, userID, "", code, issue, entries)
+func validateDuplicateTicket(tx *gorm.DB, owner Owner, code string, issue string, entries []ParsedEntry) error {
+	return validateDuplicateTicketExcept(tx, owner, "", code, issue, entries)
 }
 
-func validateDuplicateTicketExcept(tx *gorm.DB, userID string, ticketID string, code string, issue string, entries []ParsedEntry) error {
+func validateDuplicateTicketExcept(tx *gorm.DB, owner Owner, ticketID string, code string, issue string, entries []ParsedEntry) error {
 	if len(entries) == 0 {
 		return nil
 	}
 
 	signature := buildTicketEntriesHash(entries)
 	var count int64
-	query := currentUserScope(tx.Model(&model.Ticket{}), userID).
+	query := ownerScope(tx.Model(&model.Ticket{}), owner).
 		Where("lottery_code = ? AND issue IN ? AND entry_signature = ?", code, issueAliases(code, issue), signature)
 	if ticketID != "" {
 		query = query.Where("id <> ?", ticketID)
@@ -479,10 +506,10 @@ func reserveTicketUpload(tx *gorm.DB, userID string, code string, uploadID strin
 		return upload, err
 	}
 	if upload.Status == TicketUploadStatusSaved {
-		return upload, fmt.Errorf("这张图片已经入库，请勿重复保存")
+		return upload, ErrTicketImageSaved.WithMessage("这张图片已经入库，请勿重复保存")
 	}
 	if upload.Status == TicketUploadStatusSaving {
-		return upload, fmt.Errorf("这张图片正在处理中，请稍后重试")
+		return upload, ErrTicketUploadBusy
 	}
 
 	result := currentUserScope(tx.Model(&model.TicketUpload{}), userID).
@@ -492,7 +519,7 @@ func reserveTicketUpload(tx *gorm.DB, userID string, code string, uploadID strin
 		return upload, result.Error
 	}
 	if result.RowsAffected == 0 {
-		return upload, fmt.Errorf("这张图片正在处理中，请稍后重试")
+		return upload, ErrTicketUploadBusy
 	}
 
 	upload.Status = TicketUploadStatusSaving
@@ -532,7 +559,7 @@ func getTicketUploadWithDB(database *gorm.DB, userID string, code string, upload
 		return upload, err
 	}
 	if code != "" && upload.LotteryCode != "" && upload.LotteryCode != code {
-		return upload, fmt.Errorf("上传记录的彩票类型与当前录入类型不一致")
+		return upload, ErrTicketUploadMismatch
 	}
 	return upload, nil
 }
@@ -546,36 +573,36 @@ func buildTicketUploadDetail(upload model.TicketUpload) *TicketUploadDetail {
 
 func getRecognizedEntries(upload model.TicketUpload) ([]ParsedEntry, error) {
 	if upload.RecognitionPayload == "" {
-		return nil, fmt.Errorf("请先调用识别接口获取号码结果")
+		return nil, ErrTicketRecognitionMissing
 	}
 
 	recognized := RecognitionResult{}
 	if err := json.Unmarshal([]byte(upload.RecognitionPayload), &recognized); err != nil {
-		return nil, fmt.Errorf("识别结果无法解析，请重新识别")
+		return nil, ErrTicketRecognitionMissing.WithMessage("识别结果无法解析，请重新识别")
 	}
 	if len(recognized.Entries) == 0 {
-		return nil, fmt.Errorf("识别结果中没有可入库的号码")
+		return nil, ErrTicketRecognitionMissing.WithMessage("识别结果中没有可入库的号码")
 	}
 	return recognized.Entries, nil
 }
 
-func resolveRecommendation(userID string, code string, recommendationID string) (*model.Recommendation, *uuid.UUID, error) {
+func resolveRecommendation(owner Owner, code string, recommendationID string) (*model.Recommendation, *uuid.UUID, error) {
 	if recommendationID == "" {
 		return nil, nil, nil
 	}
 
 	parsedID, err := uuid.Parse(recommendationID)
 	if err != nil {
-		return nil, nil, fmt.Errorf("推荐记录不存在")
+		return nil, nil, ErrRecommendationNotFound
 	}
 
 	recommendation := model.Recommendation{}
-	query := currentUserScope(db.DB, userID)
+	query := ownerScope(db.DB, owner)
 	if code != "" {
 		query = query.Where("lottery_code = ?", code)
 	}
 	if err := query.First(&recommendation, "id = ?", parsedID).Error; err != nil {
-		return nil, nil, fmt.Errorf("推荐记录不存在")
+		return nil, nil, ErrRecommendationNotFound
 	}
 	return &recommendation, &parsedID, nil
 }
@@ -590,7 +617,7 @@ func resolveCreateTicketCode(code string, uploadCode string, recommendation *mod
 	if recommendation != nil {
 		return recommendation.LotteryCode, nil
 	}
-	return "", fmt.Errorf("未识别出彩票类型，请先完成识别或手动指定彩种")
+	return "", ErrLotteryCodeUnresolved
 }
 
 func resolveTicketCode(primary string, fallback string) string {
@@ -614,7 +641,7 @@ func ensureIssueDrawSynced(ctx context.Context, code string, issue string) {
 	}
 
 	var count int64
-	if err := db.DB.Model(&model.DrawResult{}).Where("lottery_code = ? AND issue IN ?", code, issueAliases(code, issue)).Count(&count).Error; err == nil && count > 0 {
+	if err := db.DB.WithContext(ctx).Model(&model.DrawResult{}).Where("lottery_code = ? AND issue IN ?", code, issueAliases(code, issue)).Count(&count).Error; err == nil && count > 0 {
 		return
 	}
 
@@ -625,7 +652,7 @@ func ensureIssueDrawSynced(ctx context.Context, code string, issue string) {
 
 func normalizeParsedEntries(definition Definition, entries []ParsedEntry) ([]ParsedEntry, error) {
 	if len(entries) == 0 {
-		return nil, fmt.Errorf("至少需要一注号码")
+		return nil, ErrTicketEntriesRequired
 	}
 
 	result := make([]ParsedEntry, 0, len(entries))
@@ -645,33 +672,33 @@ func normalizeParsedEntries(definition Definition, entries []ParsedEntry) ([]Par
 
 func validateParsedEntry(definition Definition, entry ParsedEntry) error {
 	if definition.Code == "ssq" && entry.IsAdditional {
-		return fmt.Errorf("双色球不支持追加")
+		return ErrInvalidTicketEntry.WithMessage("双色球不支持追加")
 	}
 	if len(entry.Red) != definition.RedCount {
-		return fmt.Errorf("红球数量不正确，应为 %d 个", definition.RedCount)
+		return ErrInvalidTicketEntry.WithMessage("红球数量不正确，应为 %d 个", definition.RedCount)
 	}
 	if len(entry.Blue) != definition.BlueCount {
-		return fmt.Errorf("蓝球数量不正确，应为 %d 个", definition.BlueCount)
+		return ErrInvalidTicketEntry.WithMessage("蓝球数量不正确，应为 %d 个", definition.BlueCount)
 	}
 	if containsDuplicate(entry.Red) {
-		return fmt.Errorf("红球号码不能重复")
+		return ErrInvalidTicketEntry.WithMessage("红球号码不能重复")
 	}
 	if containsDuplicate(entry.Blue) {
-		return fmt.Errorf("蓝球号码不能重复")
+		return ErrInvalidTicketEntry.WithMessage("蓝球号码不能重复")
 	}
 
 	for _, value := range entry.Red {
 		if value < definition.RedMin || value > definition.RedMax {
-			return fmt.Errorf("红球号码超出范围，应在 %d-%d 之间", definition.RedMin, definition.RedMax)
+			return ErrInvalidTicketEntry.WithMessage("红球号码超出范围，应在 %d-%d 之间", definition.RedMin, definition.RedMax)
 		}
 	}
 	for _, value := range entry.Blue {
 		if value < definition.BlueMin || value > definition.BlueMax {
-			return fmt.Errorf("蓝球号码超出范围，应在 %d-%d 之间", definition.BlueMin, definition.BlueMax)
+			return ErrInvalidTicketEntry.WithMessage("蓝球号码超出范围，应在 %d-%d 之间", definition.BlueMin, definition.BlueMax)
 		}
 	}
 	if resolveEntryMultiple(entry) <= 0 {
-		return fmt.Errorf("注数/倍数必须大于 0")
+		return ErrInvalidTicketEntry.WithMessage("注数/倍数必须大于 0")
 	}
 	return nil
 }
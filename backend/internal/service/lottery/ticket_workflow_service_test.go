@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	model "go-fiber-starter/internal/model/lottery"
+
 	"github.com/google/uuid"
 )
 
@@ -16,7 +18,7 @@ func TestCreateTicketUsesServerCalculatedCost(t *testing.T) {
 		Code:       "ssq",
 		Issue:      "2099001",
 		DrawDate:   time.Now().AddDate(1, 0, 0),
-		CostAmount: 999,
+		CostAmount: 999 * model.Yuan,
 		Entries: []ParsedEntry{
 			{Red: []int{1, 2, 3, 4, 5, 6}, Blue: []int{7}, Multiple: 2},
 			{Red: []int{8, 9, 10, 11, 12, 13}, Blue: []int{14}, Multiple: 3},
@@ -25,7 +27,7 @@ func TestCreateTicketUsesServerCalculatedCost(t *testing.T) {
 	if err != nil {
 		t.Fatalf("create ticket: %v", err)
 	}
-	if result.CostAmount != 10 {
+	if result.CostAmount != 10*model.Yuan {
 		t.Fatalf("server cost mismatch: got %v want 10", result.CostAmount)
 	}
 }
@@ -53,7 +55,7 @@ func TestUpdateTicketUsesServerCalculatedCost(t *testing.T) {
 		Code:       "dlt",
 		Issue:      "2099003",
 		DrawDate:   time.Now().AddDate(1, 0, 0),
-		CostAmount: 1,
+		CostAmount: model.Yuan,
 		Entries: []ParsedEntry{
 			{Red: []int{1, 2, 3, 4, 5}, Blue: []int{6, 7}, Multiple: 4, IsAdditional: true},
 		},
@@ -61,7 +63,7 @@ func TestUpdateTicketUsesServerCalculatedCost(t *testing.T) {
 	if err != nil {
 		t.Fatalf("update ticket: %v", err)
 	}
-	if updated.CostAmount != 12 {
+	if updated.CostAmount != 12*model.Yuan {
 		t.Fatalf("server cost mismatch: got %v want 12", updated.CostAmount)
 	}
 }
@@ -71,7 +73,7 @@ func TestCalculateEntriesCost(t *testing.T) {
 		{Multiple: 2},
 		{Multiple: 3, IsAdditional: true},
 	}
-	if actual := calculateEntriesCost(entries); actual != 13 {
+	if actual := calculateEntriesCost(entries); actual != 13*model.Yuan {
 		t.Fatalf("calculated cost mismatch: got %v want 13", actual)
 	}
 }
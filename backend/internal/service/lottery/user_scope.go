@@ -10,6 +10,30 @@ import (
 	"gorm.io/gorm"
 )
 
+// Owner 描述票据和推荐的归属：LedgerID 为空时是 UserID 的个人数据，否则是共享账本中的数据，
+// 此时 UserID 只记录操作人。
+type Owner struct {
+	UserID   string
+	LedgerID string
+}
+
+// PersonalOwner 返回用户个人数据的归属。
+func PersonalOwner(userID string) Owner {
+	return Owner{UserID: userID}
+}
+
+// ledgerUUID 返回写入记录时使用的账本 ID，个人数据返回 nil。
+func (o Owner) ledgerUUID() (*uuid.UUID, error) {
+	if o.LedgerID == "" {
+		return nil, nil
+	}
+	parsed, err := uuid.Parse(o.LedgerID)
+	if err != nil {
+		return nil, ErrLedgerNotFound
+	}
+	return &parsed, nil
+}
+
 func parseRequiredUserID(userID string) (uuid.UUID, error) {
 	parsed, err := uuid.Parse(userID)
 	if err != nil {
@@ -20,7 +44,7 @@ func parseRequiredUserID(userID string) (uuid.UUID, error) {
 
 func loadSchedulerUsers() ([]userModel.User, error) {
 	items := make([]userModel.User, 0)
-	if err := db.DB.Order("created_at asc").Find(&items).Error; err != nil {
+	if err := db.DB.Where("disabled = ?", false).Order("created_at asc").Find(&items).Error; err != nil {
 		return nil, err
 	}
 	return items, nil
@@ -32,3 +56,14 @@ func currentUserScope(query *gorm.DB, userID string) *gorm.DB {
 	}
 	return query.Where("user_id = ?", userID)
 }
+
+// ownerScope 按归属过滤票据和推荐：账本数据对全部成员可见，个人数据不包含自己录入到账本中的记录。
+func ownerScope(query *gorm.DB, owner Owner) *gorm.DB {
+	if owner.LedgerID != "" {
+		return query.Where("ledger_id = ?", owner.LedgerID)
+	}
+	if owner.UserID == "" {
+		return query
+	}
+	return query.Where("user_id = ? AND ledger_id IS NULL", owner.UserID)
+}
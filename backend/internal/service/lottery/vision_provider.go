@@ -70,7 +70,7 @@ func (recognizer *openAIVisionRecognizer) Recognize(ctx context.Context, lottery
 		result.LotteryCode = lotteryType.Code
 	}
 	if len(result.Entries) == 0 {
-		return nil, fmt.Errorf("未从图片中识别到有效号码")
+		return nil, ErrNumbersNotRecognized
 	}
 	return &result, nil
 }
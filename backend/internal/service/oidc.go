@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const (
+	oidcStateTTL = 10 * time.Minute
+
+	// CodeOIDCProviderFailed 表示与身份提供方交互失败。
+	CodeOIDCProviderFailed = "OIDC_PROVIDER_FAILED"
+)
+
+var (
+	ErrOIDCDisabled          = apperr.Invalid("OIDC_DISABLED", "未启用 OIDC 登录")
+	ErrOIDCStateInvalid      = apperr.Invalid("OIDC_STATE_INVALID", "登录请求已过期或无效，请重新发起登录")
+	ErrOIDCIdentityNotLinked = apperr.Unprocessable("OIDC_IDENTITY_NOT_LINKED", "该外部账号尚未绑定本地账号，请先使用账号密码登录后绑定")
+	ErrOIDCIdentityTaken     = apperr.New(apperr.KindConflict, "OIDC_IDENTITY_TAKEN", "该外部账号已绑定其他用户")
+	ErrLastLoginMethod       = apperr.Invalid("LAST_LOGIN_METHOD", "账号未设置密码，不能解绑唯一的外部登录方式")
+)
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9._@-]+`)
+
+// oidcPendingLogin 记录一次尚未完成的授权请求，回调时按 state 取出并校验 nonce 和 PKCE。
+type oidcPendingLogin struct {
+	nonce      string
+	verifier   string
+	linkUserID string
+	inviteCode string
+	expiresAt  time.Time
+}
+
+var (
+	oidcMu        sync.Mutex
+	oidcPending   = map[string]oidcPendingLogin{}
+	oidcProviders = map[string]*oidc.Provider{}
+)
+
+type OIDCSettings struct {
+	Enabled      bool   `json:"enabled"`
+	ProviderName string `json:"providerName"`
+}
+
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"-"`
+}
+
+// OIDCResult 是回调处理结果：登录时返回令牌对，绑定时只标记 Linked。
+type OIDCResult struct {
+	Tokens *TokenPair
+	Linked bool
+}
+
+func GetOIDCSettings() OIDCSettings {
+	settings := config.Current.OIDC
+	name := strings.TrimSpace(settings.ProviderName)
+	if name == "" {
+		name = "SSO"
+	}
+	return OIDCSettings{Enabled: settings.Enabled, ProviderName: name}
+}
+
+// BeginOIDCLogin 生成 state、nonce 和 PKCE 校验码，返回身份提供方的授权地址。
+// linkUserID 不为空时表示已登录用户发起的绑定流程；inviteCode 在邀请注册模式下用于自动创建账号。
+func BeginOIDCLogin(ctx context.Context, linkUserID string, inviteCode string) (*OIDCAuthorization, error) {
+	oauthConfig, _, err := oidcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomURLToken(24)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomURLToken(24)
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	oidcMu.Lock()
+	now := time.Now()
+	for key, pending := range oidcPending {
+		if now.After(pending.expiresAt) {
+			delete(oidcPending, key)
+		}
+	}
+	oidcPending[state] = oidcPendingLogin{
+		nonce:      nonce,
+		verifier:   verifier,
+		linkUserID: linkUserID,
+		inviteCode: inviteCode,
+		expiresAt:  now.Add(oidcStateTTL),
+	}
+	oidcMu.Unlock()
+
+	return &OIDCAuthorization{
+		AuthorizationURL: oauthConfig.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)),
+		State:            state,
+	}, nil
+}
+
+// CompleteOIDCLogin 用授权码换取并校验 ID Token，然后登录、绑定或自动创建本地账号。
+func CompleteOIDCLogin(ctx context.Context, state string, code string, meta SessionMeta) (*OIDCResult, error) {
+	oauthConfig, provider, err := oidcClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcMu.Lock()
+	pending, ok := oidcPending[state]
+	delete(oidcPending, state)
+	oidcMu.Unlock()
+	if !ok || state == "" || time.Now().After(pending.expiresAt) {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	oauthToken, err := oauthConfig.Exchange(ctx, code, oauth2.VerifierOption(pending.verifier))
+	if err != nil {
+		return nil, apperr.Upstream(CodeOIDCProviderFailed, fmt.Errorf("授权码换取令牌失败: %w", err))
+	}
+	rawIDToken, ok := oauthToken.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, apperr.Upstream(CodeOIDCProviderFailed, fmt.Errorf("身份提供方未返回 id_token"))
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: oauthConfig.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, apperr.Upstream(CodeOIDCProviderFailed, fmt.Errorf("id_token 校验失败: %w", err))
+	}
+	if idToken.Nonce != pending.nonce {
+		return nil, ErrOIDCStateInvalid
+	}
+
+	claims := map[string]any{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+	email, _ := claims["email"].(string)
+
+	if pending.linkUserID != "" {
+		if err := linkIdentity(pending.linkUserID, idToken.Issuer, idToken.Subject, email); err != nil {
+			return nil, err
+		}
+		return &OIDCResult{Linked: true}, nil
+	}
+
+	user, err := resolveIdentityUser(idToken.Issuer, idToken.Subject, email, claims, pending.inviteCode)
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+	tokens, err := IssueSession(user, meta)
+	if err != nil {
+		return nil, err
+	}
+	return &OIDCResult{Tokens: tokens}, nil
+}
+
+func ListIdentities(userID string) ([]model.Identity, error) {
+	items := make([]model.Identity, 0)
+	if err := db.DB.Where("user_id = ?", userID).Order("created_at asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UnlinkIdentity 解绑外部身份；未设置密码的账号至少保留一个外部身份，避免无法再登录。
+func UnlinkIdentity(userID string, identityID string) error {
+	identity := model.Identity{}
+	if err := db.DB.Where("id = ? AND user_id = ?", identityID, userID).First(&identity).Error; err != nil {
+		return err
+	}
+	user, err := db.GetUserById(userID)
+	if err != nil {
+		return err
+	}
+	if user.Password == "" {
+		var count int64
+		if err := db.DB.Model(&model.Identity{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count <= 1 {
+			return ErrLastLoginMethod
+		}
+	}
+	return db.DB.Delete(&model.Identity{}, "id = ?", identity.Id).Error
+}
+
+func linkIdentity(userID string, issuer string, subject string, email string) error {
+	user, err := db.GetUserById(userID)
+	if err != nil {
+		return err
+	}
+
+	existing := model.Identity{}
+	err = db.DB.Where("issuer = ? AND subject = ?", issuer, subject).First(&existing).Error
+	if err == nil {
+		if existing.UserID != user.Id {
+			return ErrOIDCIdentityTaken
+		}
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return db.DB.Create(&model.Identity{
+		UserID:      user.Id,
+		Issuer:      issuer,
+		Subject:     subject,
+		Email:       email,
+		LastLoginAt: time.Now(),
+	}).Error
+}
+
+func resolveIdentityUser(issuer string, subject string, email string, claims map[string]any, inviteCode string) (*model.User, error) {
+	identity := model.Identity{}
+	err := db.DB.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if err == nil {
+		if updateErr := db.DB.Model(&model.Identity{}).Where("id = ?", identity.Id).Updates(map[string]any{
+			"email":         email,
+			"last_login_at": time.Now(),
+		}).Error; updateErr != nil {
+			return nil, updateErr
+		}
+		user, err := db.GetUserById(identity.UserID.String())
+		if err != nil {
+			return nil, err
+		}
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if !config.Current.OIDC.AutoProvision {
+		return nil, ErrOIDCIdentityNotLinked
+	}
+
+	// 自动创建的账号不设置密码，只能通过外部身份登录，之后可由用户自行设置或由管理员重置密码。
+	// 自动创建等同于注册，同样受注册模式限制，邀请注册模式下会消耗一次邀请码。
+	user := model.User{Role: model.RoleUser}
+	if name, ok := claims["name"].(string); ok {
+		user.DisplayName = truncateString(strings.TrimSpace(name), maxDisplayNameLength)
+	}
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		firstUser, err := isFirstUser(tx)
+		if err != nil {
+			return err
+		}
+		if firstUser {
+			user.Role = model.RoleAdmin
+		} else if err := checkRegistrationAllowed(tx, inviteCode); err != nil {
+			return err
+		}
+		username, err := uniqueUsername(tx, preferredUsername(claims, subject))
+		if err != nil {
+			return err
+		}
+		user.Username = username
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.Identity{
+			UserID:      user.Id,
+			Issuer:      issuer,
+			Subject:     subject,
+			Email:       email,
+			LastLoginAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func preferredUsername(claims map[string]any, subject string) string {
+	claimNames := []string{config.Current.OIDC.UsernameClaim, "preferred_username", "email"}
+	for _, name := range claimNames {
+		if name == "" {
+			continue
+		}
+		if value, ok := claims[name].(string); ok {
+			if cleaned := usernameInvalidChars.ReplaceAllString(strings.TrimSpace(value), ""); cleaned != "" {
+				return truncateString(cleaned, 48)
+			}
+		}
+	}
+	return "sso-" + truncateString(usernameInvalidChars.ReplaceAllString(subject, ""), 40)
+}
+
+func uniqueUsername(tx *gorm.DB, base string) (string, error) {
+	existing := make([]string, 0)
+	if err := tx.Model(&model.User{}).Where("username = ? OR username LIKE ?", base, base+"-%").
+		Pluck("username", &existing).Error; err != nil {
+		return "", err
+	}
+	candidate := base
+	for index := 2; slices.Contains(existing, candidate); index++ {
+		candidate = fmt.Sprintf("%s-%d", base, index)
+	}
+	return candidate, nil
+}
+
+func oidcClient(ctx context.Context) (*oauth2.Config, *oidc.Provider, error) {
+	settings := config.Current.OIDC
+	if !settings.Enabled {
+		return nil, nil, ErrOIDCDisabled
+	}
+
+	provider, err := oidcProvider(ctx, settings.Issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+	scopes := slices.Clone(settings.Scopes)
+	if !slices.Contains(scopes, oidc.ScopeOpenID) {
+		scopes = append([]string{oidc.ScopeOpenID}, scopes...)
+	}
+	return &oauth2.Config{
+		ClientID:     settings.ClientID,
+		ClientSecret: settings.ClientSecret,
+		RedirectURL:  settings.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}, provider, nil
+}
+
+// oidcProvider 按 Issuer 缓存发现结果，避免每次登录都请求 /.well-known/openid-configuration。
+func oidcProvider(ctx context.Context, issuer string) (*oidc.Provider, error) {
+	oidcMu.Lock()
+	provider, ok := oidcProviders[issuer]
+	oidcMu.Unlock()
+	if ok {
+		return provider, nil
+	}
+
+	provider, err := oidc.NewProvider(context.WithoutCancel(ctx), issuer)
+	if err != nil {
+		return nil, apperr.Upstream(CodeOIDCProviderFailed, fmt.Errorf("获取 OIDC 配置失败: %w", err))
+	}
+	oidcMu.Lock()
+	oidcProviders[issuer] = provider
+	oidcMu.Unlock()
+	return provider, nil
+}
+
+func randomURLToken(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
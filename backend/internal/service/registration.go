@@ -0,0 +1,201 @@
+package service
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+
+	"gorm.io/gorm"
+)
+
+// inviteCodeAlphabet 去掉了容易混淆的 0/O、1/I/L，方便口头或截图分享。
+const (
+	inviteCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	inviteCodeLength   = 10
+)
+
+var (
+	ErrRegistrationClosed = apperr.New(apperr.KindForbidden, "REGISTRATION_CLOSED", "当前未开放注册")
+	ErrInviteCodeRequired = apperr.Invalid("INVITE_CODE_REQUIRED", "当前仅支持邀请码注册，请填写邀请码")
+	ErrInvalidInviteCode  = apperr.Invalid("INVITE_CODE_INVALID", "邀请码无效、已过期或已用完")
+	ErrUsernameTaken      = apperr.New(apperr.KindConflict, "USERNAME_TAKEN", "用户名已存在")
+	ErrUsernameRequired   = apperr.Invalid("USERNAME_REQUIRED", "用户名不能为空")
+)
+
+type RegisterInput struct {
+	Username     string
+	PasswordHash string
+	InviteCode   string
+}
+
+type RegistrationStatus struct {
+	Mode           string `json:"mode"`
+	InviteRequired bool   `json:"inviteRequired"`
+	Open           bool   `json:"open"`
+}
+
+type CreateInviteCodeInput struct {
+	MaxUses       int
+	ExpiresInDays int
+	Note          string
+}
+
+// GetRegistrationStatus 返回当前注册模式，供前端决定是否展示注册入口和邀请码输入框。
+func GetRegistrationStatus() (*RegistrationStatus, error) {
+	firstUser, err := isFirstUser(db.DB)
+	if err != nil {
+		return nil, err
+	}
+	mode := config.Current.Registration.RegistrationMode()
+	if firstUser {
+		return &RegistrationStatus{Mode: mode, Open: true}, nil
+	}
+	return &RegistrationStatus{
+		Mode:           mode,
+		InviteRequired: mode == config.RegistrationInvite,
+		Open:           mode != config.RegistrationClosed,
+	}, nil
+}
+
+// RegisterUser 按注册模式校验后创建账号。数据库中没有任何用户时，首个账号直接成为管理员，
+// 不受注册模式限制；邀请码的使用次数在同一事务中扣减。
+func RegisterUser(input RegisterInput) (*model.User, error) {
+	username := strings.TrimSpace(input.Username)
+	if username == "" {
+		return nil, ErrUsernameRequired
+	}
+
+	user := model.User{Username: username, Password: input.PasswordHash, Role: model.RoleUser}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		firstUser, err := isFirstUser(tx)
+		if err != nil {
+			return err
+		}
+		if firstUser {
+			user.Role = model.RoleAdmin
+		} else if err := checkRegistrationAllowed(tx, input.InviteCode); err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&model.User{}).Where("username = ?", username).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrUsernameTaken
+		}
+		return tx.Create(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func CreateInviteCode(operatorID string, input CreateInviteCodeInput) (*model.InviteCode, error) {
+	operator, err := db.GetUserById(operatorID)
+	if err != nil {
+		return nil, err
+	}
+	if input.MaxUses < 0 {
+		return nil, apperr.ErrInvalidRequest.WithMessage("使用次数不能为负数")
+	}
+	if input.ExpiresInDays < 0 {
+		return nil, apperr.ErrInvalidRequest.WithMessage("有效期天数不能为负数")
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	invite := model.InviteCode{
+		Code:      code,
+		CreatedBy: operator.Id,
+		Note:      truncateString(strings.TrimSpace(input.Note), 255),
+		MaxUses:   input.MaxUses,
+	}
+	if input.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, input.ExpiresInDays)
+		invite.ExpiresAt = &expiresAt
+	}
+	if err := db.DB.Create(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func ListInviteCodes() ([]model.InviteCode, error) {
+	items := make([]model.InviteCode, 0)
+	if err := db.DB.Order("created_at desc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func RevokeInviteCode(inviteID string) error {
+	invite := model.InviteCode{}
+	if err := db.DB.First(&invite, "id = ?", inviteID).Error; err != nil {
+		return err
+	}
+	return db.DB.Model(&model.InviteCode{}).
+		Where("id = ? AND revoked_at IS NULL", invite.Id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func checkRegistrationAllowed(tx *gorm.DB, inviteCode string) error {
+	switch config.Current.Registration.RegistrationMode() {
+	case config.RegistrationClosed:
+		return ErrRegistrationClosed
+	case config.RegistrationInvite:
+		code := strings.ToUpper(strings.TrimSpace(inviteCode))
+		if code == "" {
+			return ErrInviteCodeRequired
+		}
+		return consumeInviteCode(tx, code)
+	default:
+		return nil
+	}
+}
+
+// consumeInviteCode 用带条件的 UPDATE 扣减次数，并发注册时不会超出 MaxUses。
+func consumeInviteCode(tx *gorm.DB, code string) error {
+	result := tx.Model(&model.InviteCode{}).
+		Where("code = ? AND revoked_at IS NULL", code).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Where("max_uses = 0 OR used_count < max_uses").
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidInviteCode
+	}
+	return nil
+}
+
+func isFirstUser(tx *gorm.DB) (bool, error) {
+	var count int64
+	if err := tx.Model(&model.User{}).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
+
+func generateInviteCode() (string, error) {
+	var builder strings.Builder
+	limit := big.NewInt(int64(len(inviteCodeAlphabet)))
+	for range inviteCodeLength {
+		index, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteByte(inviteCodeAlphabet[index.Int64()])
+	}
+	return builder.String(), nil
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/db"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+)
+
+const defaultRefreshExpiration = 30 * 24 * 60 * 60
+
+var (
+	ErrInvalidRefreshToken = apperr.New(apperr.KindUnauthorized, "REFRESH_TOKEN_INVALID", "刷新令牌无效或已过期，请重新登录")
+	ErrSessionRevoked      = apperr.New(apperr.KindUnauthorized, "SESSION_REVOKED", "登录会话已失效，请重新登录")
+	ErrInvalidCredentials  = apperr.New(apperr.KindUnauthorized, "INVALID_CREDENTIALS", "用户名或密码不正确")
+)
+
+type SessionMeta struct {
+	UserAgent string
+	IP        string
+}
+
+type TokenPair struct {
+	Token            string `json:"token"`
+	RefreshToken     string `json:"refreshToken"`
+	ExpiresIn        int    `json:"expiresIn"`
+	RefreshExpiresIn int    `json:"refreshExpiresIn"`
+	SessionID        string `json:"sessionId"`
+}
+
+type SessionDetail struct {
+	model.Session
+	Current bool `json:"current"`
+}
+
+func SessionMetaFromCtx(c *fiber.Ctx) SessionMeta {
+	return SessionMeta{
+		UserAgent: truncateString(c.Get(fiber.HeaderUserAgent), 255),
+		IP:        c.IP(),
+	}
+}
+
+// IssueSession 为登录成功的用户创建会话，并签发访问令牌和刷新令牌。
+func IssueSession(user *model.User, meta SessionMeta) (*TokenPair, error) {
+	refreshToken, refreshHash, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := model.Session{
+		UserID:           user.Id,
+		RefreshTokenHash: refreshHash,
+		UserAgent:        meta.UserAgent,
+		IP:               meta.IP,
+		ExpiresAt:        now.Add(refreshExpiration()),
+		LastUsedAt:       now,
+	}
+	if err := db.DB.Create(&session).Error; err != nil {
+		return nil, err
+	}
+	return buildTokenPair(user, session, refreshToken)
+}
+
+// RefreshSession 使用刷新令牌换取新的令牌对，旧刷新令牌立即失效。
+// 如果已轮换掉的旧令牌再次出现，说明令牌可能泄露，会直接吊销整个会话。
+func RefreshSession(refreshToken string, meta SessionMeta) (*TokenPair, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+	presentedHash := hashToken(refreshToken)
+	now := time.Now()
+
+	session := model.Session{}
+	err := db.DB.Where("refresh_token_hash = ?", presentedHash).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		reused := model.Session{}
+		if findErr := db.DB.Where("previous_token_hash = ? AND revoked_at IS NULL", presentedHash).First(&reused).Error; findErr == nil {
+			_ = revokeSessionRecord(reused.Id.String(), now)
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsActive(now) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := db.GetUserById(session.UserID.String())
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+
+	nextToken, nextHash, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	result := db.DB.Model(&model.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.Id, presentedHash).
+		Updates(map[string]any{
+			"refresh_token_hash":  nextHash,
+			"previous_token_hash": presentedHash,
+			"user_agent":          resolveString(meta.UserAgent, session.UserAgent),
+			"ip":                  resolveString(meta.IP, session.IP),
+			"expires_at":          now.Add(refreshExpiration()),
+			"last_used_at":        now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	session.ExpiresAt = now.Add(refreshExpiration())
+	return buildTokenPair(&user, session, nextToken)
+}
+
+func ListSessions(userID string, currentSessionID string) ([]SessionDetail, error) {
+	sessions := make([]model.Session, 0)
+	if err := db.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at desc").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]SessionDetail, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, SessionDetail{
+			Session: session,
+			Current: session.Id.String() == currentSessionID,
+		})
+	}
+	return result, nil
+}
+
+func RevokeSession(userID string, sessionID string) error {
+	session := model.Session{}
+	if err := db.DB.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		return err
+	}
+	return revokeSessionRecord(session.Id.String(), time.Now())
+}
+
+func RevokeAllSessions(userID string) error {
+	return db.DB.Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func revokeOtherSessions(userID string, keepSessionID string) error {
+	query := db.DB.Model(&model.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if keepSessionID != "" {
+		query = query.Where("id <> ?", keepSessionID)
+	}
+	return query.Update("revoked_at", time.Now()).Error
+}
+
+// CurrentSessionID 返回访问令牌绑定的会话 ID，旧版本签发的令牌没有该字段。
+func CurrentSessionID(c *fiber.Ctx) string {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok || token == nil {
+		return ""
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	sessionID, _ := claims["sid"].(string)
+	return sessionID
+}
+
+func ensureSessionActive(sessionID string) error {
+	session := model.Session{}
+	if err := db.DB.Select("id", "revoked_at", "expires_at").First(&session, "id = ?", sessionID).Error; err != nil {
+		return ErrSessionRevoked
+	}
+	if !session.IsActive(time.Now()) {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+func revokeSessionRecord(sessionID string, revokedAt time.Time) error {
+	return db.DB.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func buildTokenPair(user *model.User, session model.Session, refreshToken string) (*TokenPair, error) {
+	accessToken, err := generateAccessToken(user, session.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		Token:            accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        config.Current.Jwt.Expiration,
+		RefreshExpiresIn: int(refreshExpiration() / time.Second),
+		SessionID:        session.Id.String(),
+	}, nil
+}
+
+func generateRefreshToken() (string, string, error) {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buffer)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func refreshExpiration() time.Duration {
+	seconds := config.Current.Jwt.RefreshExpiration
+	if seconds <= 0 {
+		seconds = defaultRefreshExpiration
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func resolveString(value string, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
+
+func truncateString(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
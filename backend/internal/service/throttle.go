@@ -0,0 +1,194 @@
+package service
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"go-fiber-starter/pkg/config"
+)
+
+const (
+	RateLimitOCR  = "ocr"
+	RateLimitAI   = "ai"
+	RateLimitSync = "sync"
+)
+
+// throttlePruneThreshold 超过该数量的计数记录时顺带清理已过期的记录，防止内存无限增长。
+const throttlePruneThreshold = 1024
+
+// throttleNow 便于测试替换当前时间。
+var throttleNow = time.Now
+
+type loginAttemptState struct {
+	failures     int
+	windowStart  time.Time
+	lockedUntil  time.Time
+	lockoutCount int
+}
+
+type rateWindowState struct {
+	count       int
+	windowStart time.Time
+	window      time.Duration
+}
+
+var (
+	throttleMu    sync.Mutex
+	loginAttempts = map[string]*loginAttemptState{}
+	rateWindows   = map[string]*rateWindowState{}
+)
+
+// CheckLoginAllowed 在校验密码前调用，IP 或用户名任一处于锁定期时返回剩余等待时间。
+func CheckLoginAllowed(ip string, username string) (time.Duration, bool) {
+	settings := config.Current.Security.Login
+	if !settings.Enabled {
+		return 0, true
+	}
+
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+
+	now := throttleNow()
+	var wait time.Duration
+	for _, key := range loginAttemptKeys(ip, username) {
+		if state, ok := loginAttempts[key]; ok && now.Before(state.lockedUntil) {
+			wait = max(wait, state.lockedUntil.Sub(now))
+		}
+	}
+	return wait, wait == 0
+}
+
+// RecordLoginFailure 记录一次失败登录，达到次数上限后按指数退避锁定，返回本次触发的锁定时长。
+func RecordLoginFailure(ip string, username string) time.Duration {
+	settings := config.Current.Security.Login
+	if !settings.Enabled {
+		return 0
+	}
+	maxAttempts := max(1, settings.MaxAttempts)
+	window := secondsOrDefault(settings.WindowSeconds, 900)
+	baseLockout := secondsOrDefault(settings.LockoutSeconds, 60)
+	maxLockout := max(baseLockout, secondsOrDefault(settings.MaxLockoutSeconds, 3600))
+
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+
+	now := throttleNow()
+	pruneThrottleState(now)
+
+	var lockout time.Duration
+	for _, key := range loginAttemptKeys(ip, username) {
+		state, ok := loginAttempts[key]
+		if !ok {
+			state = &loginAttemptState{}
+			loginAttempts[key] = state
+		}
+		if now.Sub(state.windowStart) > window {
+			state.failures = 0
+			state.windowStart = now
+			// 上次锁定结束后一个完整窗口内没有再失败，退避重新从基础时长开始。
+			if now.Sub(state.lockedUntil) > window {
+				state.lockoutCount = 0
+			}
+		}
+		state.failures++
+		if state.failures < maxAttempts {
+			continue
+		}
+
+		duration := min(baseLockout<<min(state.lockoutCount, 16), maxLockout)
+		state.lockedUntil = now.Add(duration)
+		state.lockoutCount++
+		state.failures = 0
+		state.windowStart = now
+		lockout = max(lockout, duration)
+	}
+	return lockout
+}
+
+// RecordLoginSuccess 登录成功后清除该用户名和 IP 的失败记录。
+func RecordLoginSuccess(ip string, username string) {
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+
+	for _, key := range loginAttemptKeys(ip, username) {
+		delete(loginAttempts, key)
+	}
+}
+
+// AllowUserRequest 按用户和接口类别做固定窗口计数，超出配置的次数时返回距离窗口重置的时间。
+func AllowUserRequest(category string, userID string) (time.Duration, bool) {
+	rule, ok := rateLimitRule(category)
+	if !ok || rule.Limit <= 0 {
+		return 0, true
+	}
+	window := secondsOrDefault(rule.WindowSeconds, 3600)
+
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+
+	now := throttleNow()
+	pruneThrottleState(now)
+
+	key := category + ":" + userID
+	state, exists := rateWindows[key]
+	if !exists || now.Sub(state.windowStart) >= window {
+		state = &rateWindowState{windowStart: now, window: window}
+		rateWindows[key] = state
+	}
+	if state.count >= rule.Limit {
+		return state.windowStart.Add(window).Sub(now), false
+	}
+	state.count++
+	return 0, true
+}
+
+func rateLimitRule(category string) (config.RateLimitRuleConfig, bool) {
+	limits := config.Current.Security.RateLimits
+	switch category {
+	case RateLimitOCR:
+		return limits.OCR, true
+	case RateLimitAI:
+		return limits.AI, true
+	case RateLimitSync:
+		return limits.Sync, true
+	default:
+		return config.RateLimitRuleConfig{}, false
+	}
+}
+
+func loginAttemptKeys(ip string, username string) []string {
+	keys := make([]string, 0, 2)
+	if ip != "" {
+		keys = append(keys, "ip:"+ip)
+	}
+	if username = strings.ToLower(strings.TrimSpace(username)); username != "" {
+		keys = append(keys, "user:"+username)
+	}
+	return keys
+}
+
+func pruneThrottleState(now time.Time) {
+	if len(loginAttempts) > throttlePruneThreshold {
+		loginWindow := secondsOrDefault(config.Current.Security.Login.WindowSeconds, 900)
+		for key, state := range loginAttempts {
+			if now.After(state.lockedUntil) && now.Sub(state.windowStart) > loginWindow {
+				delete(loginAttempts, key)
+			}
+		}
+	}
+	if len(rateWindows) > throttlePruneThreshold {
+		for key, state := range rateWindows {
+			if now.Sub(state.windowStart) >= state.window {
+				delete(rateWindows, key)
+			}
+		}
+	}
+}
+
+func secondsOrDefault(seconds int, fallback int) time.Duration {
+	if seconds <= 0 {
+		seconds = fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
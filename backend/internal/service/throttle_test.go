@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"go-fiber-starter/pkg/config"
+)
+
+func setupThrottleTest(t *testing.T) *time.Time {
+	t.Helper()
+
+	prevConfig := config.Current
+	prevNow := throttleNow
+	current := time.Date(2026, 3, 16, 10, 0, 0, 0, time.UTC)
+	throttleNow = func() time.Time { return current }
+	resetThrottleState()
+	t.Cleanup(func() {
+		config.Current = prevConfig
+		throttleNow = prevNow
+		resetThrottleState()
+	})
+	return &current
+}
+
+func resetThrottleState() {
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+
+	loginAttempts = map[string]*loginAttemptState{}
+	rateWindows = map[string]*rateWindowState{}
+}
+
+func TestLoginLockoutBacksOff(t *testing.T) {
+	now := setupThrottleTest(t)
+	config.Current.Security.Login = config.LoginProtectionConfig{
+		Enabled:           true,
+		MaxAttempts:       3,
+		WindowSeconds:     900,
+		LockoutSeconds:    60,
+		MaxLockoutSeconds: 100,
+	}
+
+	for attempt := 1; attempt < 3; attempt++ {
+		if lockout := RecordLoginFailure("10.0.0.1", "alice"); lockout != 0 {
+			t.Fatalf("attempt %d should not lock, got %s", attempt, lockout)
+		}
+	}
+	if lockout := RecordLoginFailure("10.0.0.1", "alice"); lockout != time.Minute {
+		t.Fatalf("expected first lockout of 1m, got %s", lockout)
+	}
+	if wait, ok := CheckLoginAllowed("10.0.0.2", "Alice"); ok || wait != time.Minute {
+		t.Fatalf("username lockout should apply from another IP, got %s %v", wait, ok)
+	}
+	if wait, ok := CheckLoginAllowed("10.0.0.1", "bob"); ok || wait != time.Minute {
+		t.Fatalf("IP lockout should apply to another username, got %s %v", wait, ok)
+	}
+
+	*now = now.Add(61 * time.Second)
+	if _, ok := CheckLoginAllowed("10.0.0.1", "alice"); !ok {
+		t.Fatalf("lockout should expire")
+	}
+	RecordLoginFailure("10.0.0.1", "alice")
+	RecordLoginFailure("10.0.0.1", "alice")
+	if lockout := RecordLoginFailure("10.0.0.1", "alice"); lockout != 100*time.Second {
+		t.Fatalf("expected doubled lockout capped at 100s, got %s", lockout)
+	}
+
+	*now = now.Add(101 * time.Second)
+	RecordLoginSuccess("10.0.0.1", "alice")
+	if _, ok := CheckLoginAllowed("10.0.0.1", "alice"); !ok {
+		t.Fatalf("success should clear failures")
+	}
+}
+
+func TestAllowUserRequestUsesFixedWindow(t *testing.T) {
+	now := setupThrottleTest(t)
+	config.Current.Security.RateLimits.AI = config.RateLimitRuleConfig{Limit: 2, WindowSeconds: 60}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if _, ok := AllowUserRequest(RateLimitAI, "user-1"); !ok {
+			t.Fatalf("request %d should be allowed", attempt)
+		}
+	}
+	*now = now.Add(20 * time.Second)
+	wait, ok := AllowUserRequest(RateLimitAI, "user-1")
+	if ok || wait != 40*time.Second {
+		t.Fatalf("expected limit with 40s wait, got %s %v", wait, ok)
+	}
+	if _, ok := AllowUserRequest(RateLimitAI, "user-2"); !ok {
+		t.Fatalf("other users should have separate limits")
+	}
+	if _, ok := AllowUserRequest(RateLimitOCR, "user-1"); !ok {
+		t.Fatalf("unconfigured category should not be limited")
+	}
+
+	*now = now.Add(40 * time.Second)
+	if _, ok := AllowUserRequest(RateLimitAI, "user-1"); !ok {
+		t.Fatalf("window should reset")
+	}
+}
@@ -7,23 +7,35 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
+	"go-fiber-starter/internal/i18n"
 	model "go-fiber-starter/internal/model/user"
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/db"
 )
 
-func GenerateJWT(user *model.User) (string, error) {
+// generateAccessToken 签发访问令牌，sid 用于在注销或吊销会话后让令牌立即失效。
+func generateAccessToken(user *model.User, sessionID uuid.UUID) (string, error) {
 	// 自定义声明：除了标准的 exp，还加载你的业务字段
 	claims := jwt.MapClaims{
 		"user_id":   user.Id,
 		"user_name": user.Username,
 		"exp":       time.Now().Add(time.Duration(config.Current.Jwt.Expiration) * time.Second).Unix(),
 	}
+	if sessionID != uuid.Nil {
+		claims["sid"] = sessionID.String()
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(config.Current.Jwt.Secret))
 }
 
+// currentUserLocalKey 缓存本次请求已加载的用户，避免中间件和处理器重复查询。
+const currentUserLocalKey = "currentUser"
+
 func CurrentUser(c *fiber.Ctx) (user *model.User, err error) {
+	if cached, ok := c.Locals(currentUserLocalKey).(*model.User); ok && cached != nil {
+		return cached, nil
+	}
+
 	raw := c.Locals("user")
 	if raw == nil {
 		return nil, errors.New("no jwt token in context")
@@ -46,12 +58,22 @@ func CurrentUser(c *fiber.Ctx) (user *model.User, err error) {
 	if err != nil {
 		return nil, err
 	}
+	if sessionID, ok := claims["sid"].(string); ok && sessionID != "" {
+		if err := ensureSessionActive(sessionID); err != nil {
+			return nil, err
+		}
+	}
 
 	dbUser, err := db.GetUserById(userId)
 	if err != nil {
 		return nil, err
 	}
+	if dbUser.Disabled {
+		return nil, ErrUserDisabled
+	}
 
+	c.Locals(currentUserLocalKey, &dbUser)
+	i18n.SetPreference(c, dbUser.Language)
 	return &dbUser, nil
 }
 
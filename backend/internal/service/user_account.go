@@ -0,0 +1,176 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-fiber-starter/internal/i18n"
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service/apperr"
+	lotteryService "go-fiber-starter/internal/service/lottery"
+	"go-fiber-starter/pkg/db"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	minPasswordLength       = 6
+	maxDisplayNameLength    = 64
+	maxPasswordBcryptLength = 72
+
+	// oidcReauthWindow 是未设置密码的账号执行敏感操作前，最近一次单点登录的有效期。
+	oidcReauthWindow = 10 * time.Minute
+)
+
+var (
+	ErrIncorrectPassword = apperr.Invalid("PASSWORD_INCORRECT", "当前密码不正确")
+	ErrInvalidPassword   = apperr.Invalid("PASSWORD_INVALID", "密码不符合要求")
+	ErrUnsupportedLang   = apperr.Invalid("LANGUAGE_UNSUPPORTED", "不支持的语言，可选 zh-CN 或 en")
+	ErrReauthRequired    = apperr.Invalid("REAUTH_REQUIRED", "账号未设置密码，请先重新通过单点登录验证身份")
+)
+
+// UpdateProfileInput 中 Language 为 nil 时保持原值，空字符串表示清除偏好、改为跟随 Accept-Language。
+type UpdateProfileInput struct {
+	DisplayName string
+	Language    *string
+}
+
+// ChangePassword 校验当前密码后更新为新密码，并吊销除当前会话以外的全部登录会话。
+// 通过单点登录自动创建、尚未设置密码的账号可以直接设置首个密码，不需要提供当前密码。
+func ChangePassword(userID string, currentSessionID string, currentPassword string, newPassword string) error {
+	user, err := db.GetUserById(userID)
+	if err != nil {
+		return err
+	}
+	if user.Password != "" {
+		if err := verifyPassword(user, currentPassword); err != nil {
+			return err
+		}
+	}
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+	if user.Password != "" && currentPassword == newPassword {
+		return ErrInvalidPassword.WithMessage("新密码不能与当前密码相同")
+	}
+
+	hash, err := HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("密码加密失败")
+	}
+	if err := db.DB.Model(&model.User{}).Where("id = ?", user.Id).Update("password", hash).Error; err != nil {
+		return err
+	}
+	return revokeOtherSessions(user.Id.String(), currentSessionID)
+}
+
+func UpdateProfile(userID string, input UpdateProfileInput) (*model.User, error) {
+	user, err := db.GetUserById(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := strings.TrimSpace(input.DisplayName)
+	if len([]rune(displayName)) > maxDisplayNameLength {
+		return nil, apperr.ErrInvalidRequest.WithMessage("显示名称不能超过 %d 个字符", maxDisplayNameLength)
+	}
+	updates := map[string]any{"display_name": displayName}
+	if input.Language != nil {
+		language := ""
+		if raw := strings.TrimSpace(*input.Language); raw != "" {
+			if language = i18n.Normalize(raw); language == "" {
+				return nil, ErrUnsupportedLang
+			}
+		}
+		updates["language"] = language
+		user.Language = language
+	}
+	if err := db.DB.Model(&model.User{}).Where("id = ?", user.Id).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	user.DisplayName = displayName
+	return &user, nil
+}
+
+// DeleteAccount 校验密码后在同一事务中删除账号及其票据、上传记录、推荐、会话、访问令牌和外部身份，
+// 事务提交后再清理不再被引用的票据图片。未设置密码的账号需要在最近一段时间内重新通过单点登录验证身份。
+func DeleteAccount(userID string, password string) error {
+	user, err := db.GetUserById(userID)
+	if err != nil {
+		return err
+	}
+	if user.Password != "" {
+		err = verifyPassword(user, password)
+	} else {
+		err = ensureRecentOIDCLogin(user)
+	}
+	if err != nil {
+		return err
+	}
+	if user.IsAdmin() {
+		if err := ensureAnotherActiveAdmin(user.Id.String()); err != nil {
+			return err
+		}
+	}
+
+	imagePaths := make([]string, 0)
+	if err := db.DB.Transaction(func(tx *gorm.DB) error {
+		paths, err := lotteryService.DeleteUserData(tx, user.Id.String())
+		if err != nil {
+			return err
+		}
+		imagePaths = paths
+
+		if err := tx.Where("user_id = ?", user.Id).Delete(&model.Session{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.Id).Delete(&model.AccessToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.Id).Delete(&model.Identity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.Id).Delete(&model.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.User{}, "id = ?", user.Id).Error
+	}); err != nil {
+		return err
+	}
+
+	lotteryService.CleanupTicketImages(imagePaths)
+	return nil
+}
+
+func verifyPassword(user model.User, password string) error {
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return ErrIncorrectPassword
+	}
+	return nil
+}
+
+// ensureRecentOIDCLogin 要求账号最近一次通过外部身份登录的时间在 oidcReauthWindow 之内。
+func ensureRecentOIDCLogin(user model.User) error {
+	var count int64
+	if err := db.DB.Model(&model.Identity{}).
+		Where("user_id = ? AND last_login_at >= ?", user.Id, time.Now().Add(-oidcReauthWindow)).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrReauthRequired
+	}
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return ErrInvalidPassword.WithMessage("密码长度不能少于 %d 位", minPasswordLength)
+	}
+	if len(password) > maxPasswordBcryptLength {
+		return ErrInvalidPassword.WithMessage("密码长度不能超过 %d 字节", maxPasswordBcryptLength)
+	}
+	return nil
+}
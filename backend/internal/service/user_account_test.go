@@ -0,0 +1,291 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	lotteryModel "go-fiber-starter/internal/model/lottery"
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/pkg/db"
+	"gorm.io/gorm"
+)
+
+func setupAccountTestDB(t *testing.T) {
+	t.Helper()
+
+	prevDB := db.DB
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		t.Fatalf("get sql db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := gormDB.AutoMigrate(
+		&model.User{},
+		&model.Session{},
+		&model.AccessToken{},
+		&model.Identity{},
+		&model.IdempotencyKey{},
+		&lotteryModel.Ledger{},
+		&lotteryModel.LedgerMember{},
+		&lotteryModel.TicketUpload{},
+		&lotteryModel.Ticket{},
+		&lotteryModel.TicketEntry{},
+		&lotteryModel.TicketShare{},
+		&lotteryModel.Recommendation{},
+		&lotteryModel.RecommendationEntry{},
+	); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	db.DB = gormDB
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+		db.DB = prevDB
+	})
+}
+
+func createAccountTestUser(t *testing.T, username string, password string) model.User {
+	t.Helper()
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	user := model.User{Username: username, Password: hash, Role: model.RoleUser}
+	if err := db.DB.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func createAccountTestTicket(t *testing.T, userID uuid.UUID, imagePath string) lotteryModel.Ticket {
+	t.Helper()
+
+	ticket := lotteryModel.Ticket{
+		UserID:      &userID,
+		LotteryCode: "ssq",
+		Issue:       "2026001",
+		ImagePath:   imagePath,
+		Entries:     []lotteryModel.TicketEntry{{Sequence: 1, RedNumbers: "01,02,03,04,05,06", BlueNumbers: "07", Multiple: 1}},
+	}
+	if err := db.DB.Create(&ticket).Error; err != nil {
+		t.Fatalf("create ticket: %v", err)
+	}
+	return ticket
+}
+
+func TestDeleteAccountCascadesUserData(t *testing.T) {
+	setupAccountTestDB(t)
+
+	user := createAccountTestUser(t, "alice", "pass123")
+	other := createAccountTestUser(t, "bob", "pass123")
+
+	imagePath := filepath.Join(t.TempDir(), "ticket.jpg")
+	if err := os.WriteFile(imagePath, []byte("image"), 0o644); err != nil {
+		t.Fatalf("write image: %v", err)
+	}
+	createAccountTestTicket(t, user.Id, imagePath)
+	otherTicket := createAccountTestTicket(t, other.Id, "")
+	if err := db.DB.Create(&lotteryModel.TicketUpload{UserID: &user.Id, ImagePath: imagePath}).Error; err != nil {
+		t.Fatalf("create upload: %v", err)
+	}
+	recommendation := lotteryModel.Recommendation{
+		UserID:      &user.Id,
+		LotteryCode: "ssq",
+		Entries:     []lotteryModel.RecommendationEntry{{Sequence: 1, RedNumbers: "01,02,03,04,05,06", BlueNumbers: "07"}},
+	}
+	if err := db.DB.Create(&recommendation).Error; err != nil {
+		t.Fatalf("create recommendation: %v", err)
+	}
+	if _, err := IssueSession(&user, SessionMeta{}); err != nil {
+		t.Fatalf("issue session: %v", err)
+	}
+
+	if err := DeleteAccount(user.Id.String(), "wrong"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("expected incorrect password error, got %v", err)
+	}
+	if err := DeleteAccount(user.Id.String(), "pass123"); err != nil {
+		t.Fatalf("delete account: %v", err)
+	}
+
+	for _, item := range []struct {
+		name  string
+		model any
+		query string
+	}{
+		{"user", &model.User{}, "id = ?"},
+		{"sessions", &model.Session{}, "user_id = ?"},
+		{"tickets", &lotteryModel.Ticket{}, "user_id = ?"},
+		{"uploads", &lotteryModel.TicketUpload{}, "user_id = ?"},
+		{"recommendations", &lotteryModel.Recommendation{}, "user_id = ?"},
+	} {
+		var count int64
+		if err := db.DB.Model(item.model).Where(item.query, user.Id).Count(&count).Error; err != nil {
+			t.Fatalf("count %s: %v", item.name, err)
+		}
+		if count != 0 {
+			t.Fatalf("expected %s to be deleted, got %d", item.name, count)
+		}
+	}
+
+	var entryCount int64
+	db.DB.Model(&lotteryModel.TicketEntry{}).Count(&entryCount)
+	if entryCount != 1 {
+		t.Fatalf("expected only other user's ticket entry to remain, got %d", entryCount)
+	}
+	var recommendationEntryCount int64
+	db.DB.Model(&lotteryModel.RecommendationEntry{}).Count(&recommendationEntryCount)
+	if recommendationEntryCount != 0 {
+		t.Fatalf("expected recommendation entries to be deleted, got %d", recommendationEntryCount)
+	}
+	if err := db.DB.First(&lotteryModel.Ticket{}, "id = ?", otherTicket.Id).Error; err != nil {
+		t.Fatalf("other user's ticket should remain: %v", err)
+	}
+	if _, err := os.Stat(imagePath); !os.IsNotExist(err) {
+		t.Fatalf("expected ticket image to be removed, stat err: %v", err)
+	}
+}
+
+func TestChangePasswordRequiresCurrentPassword(t *testing.T) {
+	setupAccountTestDB(t)
+
+	user := createAccountTestUser(t, "alice", "pass123")
+	current, err := IssueSession(&user, SessionMeta{})
+	if err != nil {
+		t.Fatalf("issue session: %v", err)
+	}
+	other, err := IssueSession(&user, SessionMeta{})
+	if err != nil {
+		t.Fatalf("issue session: %v", err)
+	}
+
+	if err := ChangePassword(user.Id.String(), current.SessionID, "wrong", "newPass456"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("expected incorrect password error, got %v", err)
+	}
+	if err := ChangePassword(user.Id.String(), current.SessionID, "pass123", "newPass456"); err != nil {
+		t.Fatalf("change password: %v", err)
+	}
+
+	updated, err := db.GetUserById(user.Id.String())
+	if err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if err := verifyPassword(updated, "newPass456"); err != nil {
+		t.Fatalf("new password should verify: %v", err)
+	}
+	if err := ensureSessionActive(current.SessionID); err != nil {
+		t.Fatalf("current session should stay active: %v", err)
+	}
+	if err := ensureSessionActive(other.SessionID); !errors.Is(err, ErrSessionRevoked) {
+		t.Fatalf("other session should be revoked, got %v", err)
+	}
+}
+
+func TestUpdateProfileLanguagePreference(t *testing.T) {
+	setupAccountTestDB(t)
+	user := createAccountTestUser(t, "alice", "secret123")
+
+	english := "en-US"
+	updated, err := UpdateProfile(user.Id.String(), UpdateProfileInput{DisplayName: "Alice", Language: &english})
+	if err != nil {
+		t.Fatalf("update profile: %v", err)
+	}
+	if updated.Language != "en" {
+		t.Fatalf("expected normalized language en, got %q", updated.Language)
+	}
+
+	updated, err = UpdateProfile(user.Id.String(), UpdateProfileInput{DisplayName: "Alice"})
+	if err != nil {
+		t.Fatalf("update profile without language: %v", err)
+	}
+	if updated.Language != "en" {
+		t.Fatalf("expected language to stay en, got %q", updated.Language)
+	}
+
+	unsupported := "fr"
+	if _, err := UpdateProfile(user.Id.String(), UpdateProfileInput{Language: &unsupported}); !errors.Is(err, ErrUnsupportedLang) {
+		t.Fatalf("expected ErrUnsupportedLang, got %v", err)
+	}
+
+	cleared := ""
+	if _, err := UpdateProfile(user.Id.String(), UpdateProfileInput{Language: &cleared}); err != nil {
+		t.Fatalf("clear language: %v", err)
+	}
+	stored := model.User{}
+	if err := db.DB.First(&stored, "id = ?", user.Id).Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if stored.Language != "" {
+		t.Fatalf("expected language cleared, got %q", stored.Language)
+	}
+}
+
+// createSSOOnlyUser 模拟单点登录自动创建的账号：没有密码，只绑定了一个外部身份。
+func createSSOOnlyUser(t *testing.T, username string, lastLoginAt time.Time) model.User {
+	t.Helper()
+
+	user := model.User{Username: username, Role: model.RoleUser}
+	if err := db.DB.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	identity := model.Identity{UserID: user.Id, Issuer: "https://idp.example.com", Subject: username, LastLoginAt: lastLoginAt}
+	if err := db.DB.Create(&identity).Error; err != nil {
+		t.Fatalf("create identity: %v", err)
+	}
+	return user
+}
+
+func TestChangePasswordSetsFirstPasswordForSSOOnlyUser(t *testing.T) {
+	setupAccountTestDB(t)
+
+	user := createSSOOnlyUser(t, "alice", time.Now().Add(-24*time.Hour))
+	current, err := IssueSession(&user, SessionMeta{})
+	if err != nil {
+		t.Fatalf("issue session: %v", err)
+	}
+
+	if err := ChangePassword(user.Id.String(), current.SessionID, "", "short"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("expected new password to be validated, got %v", err)
+	}
+	if err := ChangePassword(user.Id.String(), current.SessionID, "", "newPass456"); err != nil {
+		t.Fatalf("set first password: %v", err)
+	}
+
+	updated, err := db.GetUserById(user.Id.String())
+	if err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if err := verifyPassword(updated, "newPass456"); err != nil {
+		t.Fatalf("first password should verify: %v", err)
+	}
+	// 设置密码后再修改就需要提供当前密码。
+	if err := ChangePassword(user.Id.String(), current.SessionID, "", "another789"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("expected current password to be required once set, got %v", err)
+	}
+}
+
+func TestDeleteAccountRequiresRecentOIDCLoginForSSOOnlyUser(t *testing.T) {
+	setupAccountTestDB(t)
+
+	stale := createSSOOnlyUser(t, "alice", time.Now().Add(-oidcReauthWindow-time.Minute))
+	if err := DeleteAccount(stale.Id.String(), ""); !errors.Is(err, ErrReauthRequired) {
+		t.Fatalf("expected stale sso login to require re-authentication, got %v", err)
+	}
+
+	recent := createSSOOnlyUser(t, "bob", time.Now())
+	if err := DeleteAccount(recent.Id.String(), ""); err != nil {
+		t.Fatalf("delete account after recent sso login: %v", err)
+	}
+	var count int64
+	if err := db.DB.Model(&model.Identity{}).Where("user_id = ?", recent.Id).Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("expected identities deleted with the account, got %d (%v)", count, err)
+	}
+}
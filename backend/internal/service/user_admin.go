@@ -0,0 +1,189 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	model "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/internal/service/apperr"
+	"go-fiber-starter/pkg/db"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUserDisabled      = apperr.New(apperr.KindForbidden, "USER_DISABLED", "账号已被禁用")
+	ErrAdminSelfChange   = apperr.Invalid("ADMIN_SELF_CHANGE", "不能修改当前登录管理员的状态或角色")
+	ErrLastAdminRequired = apperr.Invalid("LAST_ADMIN_REQUIRED", "至少需要保留一个可用的管理员账号")
+)
+
+var hashPassword = bcrypt.GenerateFromPassword
+
+type UserQueryOptions struct {
+	Page     int
+	PageSize int
+	Keyword  string
+	Role     string
+}
+
+type UserPageResult struct {
+	Items    []model.User `json:"items"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"pageSize"`
+	Total    int64        `json:"total"`
+	HasMore  bool         `json:"hasMore"`
+}
+
+type ResetPasswordResult struct {
+	UserID            string `json:"userId"`
+	TemporaryPassword string `json:"temporaryPassword,omitempty"`
+}
+
+func HashPassword(password string) (string, error) {
+	hash, err := hashPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func QueryUsers(options UserQueryOptions) (*UserPageResult, error) {
+	page := max(1, options.Page)
+	pageSize := options.PageSize
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	query := db.DB.Model(&model.User{})
+	if keyword := strings.TrimSpace(options.Keyword); keyword != "" {
+		query = query.Where("username LIKE ?", "%"+keyword+"%")
+	}
+	if options.Role != "" {
+		query = query.Where("role = ?", options.Role)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]model.User, 0)
+	if err := query.Order("created_at asc").Offset((page - 1) * pageSize).Limit(pageSize).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return &UserPageResult{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+	}, nil
+}
+
+func SetUserDisabled(operatorID string, userID string, disabled bool) (*model.User, error) {
+	if disabled && operatorID == userID {
+		return nil, ErrAdminSelfChange.WithMessage("不能禁用当前登录的管理员账号")
+	}
+
+	user, err := db.GetUserById(userID)
+	if err != nil {
+		return nil, err
+	}
+	if disabled && user.IsAdmin() {
+		if err := ensureAnotherActiveAdmin(user.Id.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	user.Disabled = disabled
+	if err := db.DB.Model(&model.User{}).Where("id = ?", user.Id).Update("disabled", disabled).Error; err != nil {
+		return nil, err
+	}
+	if disabled {
+		if err := RevokeAllSessions(user.Id.String()); err != nil {
+			return nil, err
+		}
+	}
+	return &user, nil
+}
+
+func SetUserRole(operatorID string, userID string, role string) (*model.User, error) {
+	if !model.IsValidRole(role) {
+		return nil, apperr.ErrInvalidRequest.WithMessage("不支持的角色: %s", role)
+	}
+
+	user, err := db.GetUserById(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsAdmin() && role != model.RoleAdmin {
+		if operatorID == userID {
+			return nil, ErrAdminSelfChange.WithMessage("不能取消当前登录账号的管理员角色")
+		}
+		if err := ensureAnotherActiveAdmin(user.Id.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	user.Role = role
+	if err := db.DB.Model(&model.User{}).Where("id = ?", user.Id).Update("role", role).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ResetUserPassword 由管理员重置密码，未指定新密码时生成一次性临时密码返回给管理员。
+func ResetUserPassword(userID string, password string) (*ResetPasswordResult, error) {
+	user, err := db.GetUserById(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ResetPasswordResult{UserID: user.Id.String()}
+	if password == "" {
+		password, err = generateTemporaryPassword()
+		if err != nil {
+			return nil, err
+		}
+		result.TemporaryPassword = password
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("密码加密失败")
+	}
+	if err := db.DB.Model(&model.User{}).Where("id = ?", user.Id).Update("password", hash).Error; err != nil {
+		return nil, err
+	}
+	if err := RevokeAllSessions(user.Id.String()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func ensureAnotherActiveAdmin(excludedUserID string) error {
+	var count int64
+	if err := db.DB.Model(&model.User{}).
+		Where("role = ? AND disabled = ? AND id <> ?", model.RoleAdmin, false, excludedUserID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrLastAdminRequired
+	}
+	return nil
+}
+
+func generateTemporaryPassword() (string, error) {
+	buffer := make([]byte, 12)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
@@ -16,7 +16,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func TestGenerateJWTUsesConfigExpiration(t *testing.T) {
+func TestGenerateAccessTokenUsesConfigExpiration(t *testing.T) {
 	setTestJWTConfig(t)
 
 	user := &model.User{
@@ -25,9 +25,9 @@ func TestGenerateJWTUsesConfigExpiration(t *testing.T) {
 	}
 
 	start := time.Now()
-	tokenString, err := GenerateJWT(user)
+	tokenString, err := generateAccessToken(user, uuid.New())
 	if err != nil {
-		t.Fatalf("GenerateJWT returned error: %v", err)
+		t.Fatalf("generateAccessToken returned error: %v", err)
 	}
 
 	parsed, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
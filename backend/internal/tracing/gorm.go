@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// GormPlugin 为 GORM 查询创建 span。只有语句上下文中已有 span（即通过 WithContext 传入请求或任务的上下文）
+// 时才记录，避免没有上下文的后台查询产生大量孤立的根 span。
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []struct {
+		operation string
+		before    func(string, func(*gorm.DB)) error
+		after     func(string, func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+	for _, item := range registrations {
+		operation := item.operation
+		if err := item.before("tracing:before_"+operation, func(tx *gorm.DB) {
+			startGormSpan(tx, operation)
+		}); err != nil {
+			return err
+		}
+		if err := item.after("tracing:after_"+operation, endGormSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startGormSpan(tx *gorm.DB, operation string) {
+	ctx := tx.Statement.Context
+	if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	ctx, span := Tracer().Start(ctx, "gorm."+operation, trace.WithSpanKind(trace.SpanKindClient))
+	span.SetAttributes(gormSystem(tx), semconv.DBOperationName(operation))
+	tx.Statement.Context = ctx
+	tx.InstanceSet(gormSpanKey, span)
+}
+
+func endGormSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	if tx.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(tx.Statement.Table))
+	}
+	span.SetAttributes(semconv.DBQueryText(tx.Statement.SQL.String()))
+	err := tx.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	End(span, err)
+}
+
+func gormSystem(tx *gorm.DB) attribute.KeyValue {
+	if tx.Dialector != nil && tx.Dialector.Name() == "postgres" {
+		return semconv.DBSystemNamePostgreSQL
+	}
+	return semconv.DBSystemNameSQLite
+}
@@ -0,0 +1,110 @@
+// Package tracing 初始化 OpenTelemetry 链路追踪，并提供创建 span、传播追踪头的辅助函数。
+// 未开启追踪时使用 OpenTelemetry 默认的空实现，调用这些函数没有额外开销。
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go-fiber-starter/pkg/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "go-fiber-starter"
+
+// Init 按配置创建 OTLP/HTTP 导出器并设置全局 TracerProvider，返回的函数用于退出前刷新未发送的 span。
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	settings := config.Current.Tracing
+	if !settings.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(settings.Endpoint)}
+	if settings.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(settings.ResolveServiceName()),
+		semconv.DeploymentEnvironmentName(config.Current.App.Env),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(settings.ResolveSampleRatio()))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start 创建内部 span。
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// StartClient 为调用外部服务创建客户端 span。
+func StartClient(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+// End 结束 span，err 非空时记录错误并把 span 标记为失败。
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectHTTP 把当前追踪上下文写入外部请求头，下游服务支持 W3C Trace Context 时可以串联链路。
+func InjectHTTP(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// TraceID 返回上下文中的追踪 ID，没有有效 span 时返回空字符串。
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+// HTTPStatusAttribute 记录外部接口响应状态码，5xx 同时把 span 标记为失败。
+func HTTPStatusAttribute(span trace.Span, statusCode int) {
+	span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+	if statusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+}
+
+// URLAttribute 记录去掉查询参数的请求地址，避免 appKey 等密钥出现在追踪数据中。
+func URLAttribute(rawURL string) attribute.KeyValue {
+	if index := strings.IndexAny(rawURL, "?#"); index >= 0 {
+		rawURL = rawURL[:index]
+	}
+	return semconv.URLFull(rawURL)
+}
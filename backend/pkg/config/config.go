@@ -1,16 +1,29 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	App          AppConfig
+	Log          LogConfig `mapstructure:"log"`
 	Jwt          JwtConfig
+	Registration RegistrationConfig `mapstructure:"registration"`
+	Security     SecurityConfig     `mapstructure:"security"`
+	Idempotency  IdempotencyConfig  `mapstructure:"idempotency"`
+	Health       HealthConfig       `mapstructure:"health"`
+	Metrics      MetricsConfig      `mapstructure:"metrics"`
+	Tracing      TracingConfig      `mapstructure:"tracing"`
+	ExternalLogs ExternalLogConfig  `mapstructure:"externalLogs"`
+	OIDC         OIDCConfig         `mapstructure:"oidc"`
 	Database     DatabaseConfig
 	Storage      StorageConfig
+	Backup       BackupConfig `mapstructure:"backup"`
 	Jisu         JisuConfig
 	Compensation CompensationConfig `mapstructure:"compensation"`
 	AI           AIConnectionConfig
@@ -23,30 +36,201 @@ type AppConfig struct {
 	Env  string `mapstructure:"env"`
 }
 
+// LogConfig 控制日志级别和输出位置，Paths 中的 stdout、stderr 表示控制台，其余为按大小轮转的 JSON 日志文件。
+type LogConfig struct {
+	Level      string   `mapstructure:"level"`
+	Paths      []string `mapstructure:"paths"`
+	MaxSizeMB  int      `mapstructure:"maxSizeMB"`
+	MaxBackups int      `mapstructure:"maxBackups"`
+	MaxAgeDays int      `mapstructure:"maxAgeDays"`
+}
+
 type JwtConfig struct {
-	Secret     string `mapstructure:"secret"`
-	Expiration int    `mapstructure:"expiration"`
+	Secret            string `mapstructure:"secret"`
+	Expiration        int    `mapstructure:"expiration"`
+	RefreshExpiration int    `mapstructure:"refreshExpiration"`
+}
+
+// RegistrationConfig 控制公开注册入口，数据库中还没有任何用户时始终允许注册首个管理员。
+type RegistrationConfig struct {
+	Mode string `mapstructure:"mode"`
+}
+
+const (
+	RegistrationOpen   = "open"
+	RegistrationClosed = "closed"
+	RegistrationInvite = "invite"
+)
+
+// RegistrationMode 返回生效的注册模式，未配置时保持开放注册以兼容旧配置。
+func (c RegistrationConfig) RegistrationMode() string {
+	mode := strings.ToLower(strings.TrimSpace(c.Mode))
+	if mode == "" {
+		return RegistrationOpen
+	}
+	return mode
+}
+
+type SecurityConfig struct {
+	Login      LoginProtectionConfig `mapstructure:"login"`
+	RateLimits RateLimitsConfig      `mapstructure:"rateLimits"`
+}
+
+// IdempotencyConfig 控制 Idempotency-Key 请求记录的保留时长，超过保留期的键可以重新使用。
+type IdempotencyConfig struct {
+	RetentionHours int `mapstructure:"retentionHours"`
+}
+
+// Retention 返回幂等记录的保留时长，未配置时默认 24 小时。
+func (c IdempotencyConfig) Retention() time.Duration {
+	if c.RetentionHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(c.RetentionHours) * time.Hour
+}
+
+// HealthConfig 控制就绪检查中的外部服务探测。外部服务只影响报告内容，不会让实例变为未就绪。
+type HealthConfig struct {
+	CheckExternal  bool `mapstructure:"checkExternal"`
+	CacheSeconds   int  `mapstructure:"cacheSeconds"`
+	TimeoutSeconds int  `mapstructure:"timeoutSeconds"`
+}
+
+// CacheTTL 返回外部服务探测结果的缓存时长，未配置时默认 60 秒。
+func (c HealthConfig) CacheTTL() time.Duration {
+	if c.CacheSeconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(c.CacheSeconds) * time.Second
+}
+
+// Timeout 返回单个外部服务探测的超时时间，未配置时默认 3 秒。
+func (c HealthConfig) Timeout() time.Duration {
+	if c.TimeoutSeconds <= 0 {
+		return 3 * time.Second
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// MetricsConfig 控制 Prometheus 指标接口。Token 非空时抓取请求需携带 Authorization: Bearer <token>。
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Token   string `mapstructure:"token"`
+}
+
+// TracingConfig 控制 OpenTelemetry 链路追踪，开启后通过 OTLP/HTTP 把 span 发送到 Endpoint 指定的采集器。
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	ServiceName string  `mapstructure:"serviceName"`
+	SampleRatio float64 `mapstructure:"sampleRatio"`
+}
+
+// ResolveServiceName 返回上报的服务名，未配置时默认 lottery。
+func (c TracingConfig) ResolveServiceName() string {
+	if strings.TrimSpace(c.ServiceName) == "" {
+		return "lottery"
+	}
+	return c.ServiceName
+}
+
+// ResolveSampleRatio 返回采样比例，未配置或超出 0~1 时按全部采样处理。
+func (c TracingConfig) ResolveSampleRatio() float64 {
+	if c.SampleRatio <= 0 || c.SampleRatio > 1 {
+		return 1
+	}
+	return c.SampleRatio
+}
+
+// ExternalLogConfig 控制第三方接口调用记录是否写入数据库以及保留天数，文件日志不受影响。
+type ExternalLogConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	RetentionDays int  `mapstructure:"retentionDays"`
+}
+
+// Retention 返回调用记录的保留时长，未配置时默认 30 天。
+func (c ExternalLogConfig) Retention() time.Duration {
+	if c.RetentionDays <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
+
+// LoginProtectionConfig 控制登录失败锁定：同一 IP 或用户名在窗口内连续失败达到次数后锁定，
+// 每次再被锁定时锁定时长翻倍，直到上限。
+type LoginProtectionConfig struct {
+	Enabled           bool `mapstructure:"enabled"`
+	MaxAttempts       int  `mapstructure:"maxAttempts"`
+	WindowSeconds     int  `mapstructure:"windowSeconds"`
+	LockoutSeconds    int  `mapstructure:"lockoutSeconds"`
+	MaxLockoutSeconds int  `mapstructure:"maxLockoutSeconds"`
+}
+
+type RateLimitsConfig struct {
+	OCR  RateLimitRuleConfig `mapstructure:"ocr"`
+	AI   RateLimitRuleConfig `mapstructure:"ai"`
+	Sync RateLimitRuleConfig `mapstructure:"sync"`
+}
+
+// RateLimitRuleConfig 表示每个用户在窗口内最多调用次数，Limit 为 0 表示不限制。
+type RateLimitRuleConfig struct {
+	Limit         int `mapstructure:"limit"`
+	WindowSeconds int `mapstructure:"windowSeconds"`
+}
+
+// OIDCConfig 配置 OpenID Connect 单点登录，使用授权码模式并启用 PKCE。
+type OIDCConfig struct {
+	Enabled       bool     `mapstructure:"enabled"`
+	ProviderName  string   `mapstructure:"providerName"`
+	Issuer        string   `mapstructure:"issuer"`
+	ClientID      string   `mapstructure:"clientId"`
+	ClientSecret  string   `mapstructure:"clientSecret"`
+	RedirectURL   string   `mapstructure:"redirectURL"`
+	Scopes        []string `mapstructure:"scopes"`
+	UsernameClaim string   `mapstructure:"usernameClaim"`
+	AutoProvision bool     `mapstructure:"autoProvision"`
+	FrontendURL   string   `mapstructure:"frontendURL"`
 }
 
 type DatabaseConfig struct {
-	Driver       string `mapstructure:"driver"`
-	Path         string `mapstructure:"path"`
-	DSN          string `mapstructure:"dsn"`
-	Host         string `mapstructure:"host"`
-	Port         int    `mapstructure:"port"`
-	User         string `mapstructure:"user"`
-	Password     string `mapstructure:"password"`
-	Name         string `mapstructure:"name"`
-	SSLMode      string `mapstructure:"sslMode"`
-	TimeZone     string `mapstructure:"timeZone"`
-	MaxIdleConns int    `mapstructure:"maxIdleConns"`
-	MaxOpenConns int    `mapstructure:"maxOpenConns"`
+	Driver         string `mapstructure:"driver"`
+	Path           string `mapstructure:"path"`
+	DSN            string `mapstructure:"dsn"`
+	Host           string `mapstructure:"host"`
+	Port           int    `mapstructure:"port"`
+	User           string `mapstructure:"user"`
+	Password       string `mapstructure:"password"`
+	Name           string `mapstructure:"name"`
+	SSLMode        string `mapstructure:"sslMode"`
+	TimeZone       string `mapstructure:"timeZone"`
+	MaxIdleConns   int    `mapstructure:"maxIdleConns"`
+	MaxOpenConns   int    `mapstructure:"maxOpenConns"`
+	MigrateOnStart bool   `mapstructure:"migrateOnStart"`
 }
 
 type StorageConfig struct {
 	UploadDir string `mapstructure:"uploadDir"`
 }
 
+// BackupConfig 控制数据库与上传目录的定时备份。RetentionCount 和 RetentionDays 同时生效，
+// 为 0 表示不按该条件清理，最新的一份备份始终保留。
+type BackupConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	Dir            string `mapstructure:"dir"`
+	Cron           string `mapstructure:"cron"`
+	RetentionCount int    `mapstructure:"retentionCount"`
+	RetentionDays  int    `mapstructure:"retentionDays"`
+}
+
+// Directory 返回备份文件目录，未配置时默认 data/backups。
+func (c BackupConfig) Directory() string {
+	if strings.TrimSpace(c.Dir) == "" {
+		return "data/backups"
+	}
+	return c.Dir
+}
+
 type JisuConfig struct {
 	BaseURL        string `mapstructure:"baseURL"`
 	AppKey         string `mapstructure:"appKey"`
@@ -124,6 +308,9 @@ type LotterySyncRuleConfig struct {
 	HistorySize int    `mapstructure:"historySize"`
 }
 
+// DefaultJwtSecret 是 config.yaml 中的示例密钥，生产环境必须覆盖。
+const DefaultJwtSecret = "123456789"
+
 var Current Config
 var IsProduction bool
 
@@ -143,7 +330,38 @@ func Init() error {
 	if err := viper.Unmarshal(&Current); err != nil {
 		return err
 	}
+	if err := applyEnvOverrides(&Current, os.LookupEnv); err != nil {
+		return err
+	}
 
 	IsProduction = Current.App.Env == "production"
+	return Validate()
+}
+
+// Validate 检查启动前必须满足的安全约束。
+func Validate() error {
+	switch Current.Registration.RegistrationMode() {
+	case RegistrationOpen, RegistrationClosed, RegistrationInvite:
+	default:
+		return fmt.Errorf("不支持的注册模式 %q，可选值：open / closed / invite", Current.Registration.Mode)
+	}
+
+	if Current.OIDC.Enabled {
+		if strings.TrimSpace(Current.OIDC.Issuer) == "" || strings.TrimSpace(Current.OIDC.ClientID) == "" || strings.TrimSpace(Current.OIDC.RedirectURL) == "" {
+			return fmt.Errorf("启用 OIDC 登录时必须配置 oidc.issuer、oidc.clientId 和 oidc.redirectURL")
+		}
+	}
+
+	if !IsProduction {
+		return nil
+	}
+
+	secret := strings.TrimSpace(Current.Jwt.Secret)
+	if secret == "" || secret == DefaultJwtSecret {
+		return fmt.Errorf("生产环境禁止使用默认 JWT 密钥，请通过 %s_JWT_SECRET 或 config.local.yaml 覆盖 jwt.secret", EnvPrefix)
+	}
+	if Current.Metrics.Enabled && strings.TrimSpace(Current.Metrics.Token) == "" {
+		return fmt.Errorf("生产环境开启 /metrics 时必须配置抓取令牌，请通过 %s_METRICS_TOKEN 或 config.local.yaml 设置 metrics.token", EnvPrefix)
+	}
 	return nil
 }
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EnvPrefix 是所有配置环境变量的统一前缀，例如 LOTTERY_JWT_SECRET。
+const EnvPrefix = "LOTTERY"
+
+// envFileSuffix 用于从文件读取密钥，例如 LOTTERY_JWT_SECRET_FILE=/run/secrets/jwt。
+const envFileSuffix = "_FILE"
+
+// applyEnvOverrides 按字段路径读取环境变量覆盖配置。
+// 字段名按驼峰拆分为大写下划线，彩种按 code、补偿任务按 name 定位，
+// 例如 LOTTERY_AI_API_KEY、LOTTERY_LOTTERIES_SSQ_RECOMMENDATION_MODEL。
+func applyEnvOverrides(target *Config, lookup func(string) (string, bool)) error {
+	return applyStructEnv(reflect.ValueOf(target).Elem(), EnvPrefix, lookup)
+}
+
+func applyStructEnv(value reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	valueType := value.Type()
+	for index := 0; index < valueType.NumField(); index++ {
+		field := valueType.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+		key := prefix + "_" + envKeySegment(fieldConfigName(field))
+		if err := applyFieldEnv(value.Field(index), key, lookup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyFieldEnv(field reflect.Value, key string, lookup func(string) (string, bool)) error {
+	switch field.Kind() {
+	case reflect.Struct:
+		return applyStructEnv(field, key, lookup)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Struct {
+			return applySliceEnv(field, key, lookup)
+		}
+	}
+
+	raw, ok, err := lookupEnvValue(key, lookup)
+	if err != nil || !ok {
+		return err
+	}
+	if err := setFieldValue(field, raw); err != nil {
+		return fmt.Errorf("环境变量 %s 无效: %w", key, err)
+	}
+	return nil
+}
+
+// applySliceEnv 处理彩种、补偿任务等列表配置，按元素的 Code 或 Name 生成键名。
+func applySliceEnv(field reflect.Value, key string, lookup func(string) (string, bool)) error {
+	for index := 0; index < field.Len(); index++ {
+		item := field.Index(index)
+		identity := sliceItemIdentity(item)
+		if identity == "" {
+			continue
+		}
+		if err := applyStructEnv(item, key+"_"+envKeySegment(identity), lookup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sliceItemIdentity(item reflect.Value) string {
+	for _, name := range []string{"Code", "Name"} {
+		field := item.FieldByName(name)
+		if field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+			return field.String()
+		}
+	}
+	return ""
+}
+
+// lookupEnvValue 同时支持直接赋值和 *_FILE 文件引用，两者同时设置时视为配置错误。
+func lookupEnvValue(key string, lookup func(string) (string, bool)) (string, bool, error) {
+	value, hasValue := lookup(key)
+	filePath, hasFile := lookup(key + envFileSuffix)
+	if hasValue && hasFile {
+		return "", false, fmt.Errorf("环境变量 %s 与 %s 不能同时设置", key, key+envFileSuffix)
+	}
+	if hasValue {
+		return value, true, nil
+	}
+	if !hasFile {
+		return "", false, nil
+	}
+
+	content, err := os.ReadFile(strings.TrimSpace(filePath))
+	if err != nil {
+		return "", false, fmt.Errorf("读取 %s 指向的文件失败: %w", key+envFileSuffix, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+func setFieldValue(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(raw), field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Slice:
+		return setSliceValue(field, raw)
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", field.Kind())
+	}
+	return nil
+}
+
+// setSliceValue 把逗号分隔的值写入 []int 或 []string，例如 LOTTERY_LOTTERIES_SSQ_DRAW_SCHEDULE_WEEKDAYS=2,4,0。
+func setSliceValue(field reflect.Value, raw string) error {
+	parts := make([]string, 0)
+	for _, part := range strings.Split(raw, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			parts = append(parts, trimmed)
+		}
+	}
+
+	result := reflect.MakeSlice(field.Type(), 0, len(parts))
+	for _, part := range parts {
+		item := reflect.New(field.Type().Elem()).Elem()
+		if err := setFieldValue(item, part); err != nil {
+			return err
+		}
+		result = reflect.Append(result, item)
+	}
+	field.Set(result)
+	return nil
+}
+
+func fieldConfigName(field reflect.StructField) string {
+	if tag := strings.Split(field.Tag.Get("mapstructure"), ",")[0]; tag != "" {
+		return tag
+	}
+	return field.Name
+}
+
+// envKeySegment 把 baseURL、remoteLotteryId、previous-draw-prize 这类名称转换为 BASE_URL 风格。
+func envKeySegment(name string) string {
+	runes := []rune(name)
+	builder := strings.Builder{}
+	for index, current := range runes {
+		if !unicode.IsLetter(current) && !unicode.IsDigit(current) {
+			builder.WriteRune('_')
+			continue
+		}
+		if index > 0 && unicode.IsUpper(current) {
+			previous := runes[index-1]
+			nextIsLower := index+1 < len(runes) && unicode.IsLower(runes[index+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				builder.WriteRune('_')
+			}
+		}
+		builder.WriteRune(unicode.ToUpper(current))
+	}
+	return builder.String()
+}
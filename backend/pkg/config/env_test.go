@@ -0,0 +1,245 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestEnvKeySegment(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "secret", want: "SECRET"},
+		{name: "apiKey", want: "API_KEY"},
+		{name: "baseURL", want: "BASE_URL"},
+		{name: "remoteLotteryId", want: "REMOTE_LOTTERY_ID"},
+		{name: "useDocOrientationClassify", want: "USE_DOC_ORIENTATION_CLASSIFY"},
+		{name: "AI", want: "AI"},
+		{name: "previous-draw-prize", want: "PREVIOUS_DRAW_PRIZE"},
+	}
+
+	for _, tt := range tests {
+		if got := envKeySegment(tt.name); got != tt.want {
+			t.Fatalf("envKeySegment(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "ai-key")
+	if err := os.WriteFile(secretPath, []byte("file-secret\n"), 0600); err != nil {
+		t.Fatalf("write secret file: %v", err)
+	}
+
+	cfg := Config{
+		Jwt:      JwtConfig{Secret: DefaultJwtSecret, Expiration: 60},
+		Database: DatabaseConfig{Port: 5432},
+		Compensation: CompensationConfig{
+			Jobs: []CompensationJobConfig{{Name: "previous-draw-prize", Cron: "0 0 3 * * *"}},
+		},
+		Lotteries: []LotteryConfig{
+			{Code: "ssq", Recommendation: LotteryRecommendationConfig{Model: "old"}},
+			{Code: "dlt", Recommendation: LotteryRecommendationConfig{Model: "keep"}},
+		},
+	}
+	env := map[string]string{
+		"LOTTERY_JWT_SECRET":                                 "env-secret",
+		"LOTTERY_JWT_EXPIRATION":                             "3600",
+		"LOTTERY_DATABASE_PORT":                              "6543",
+		"LOTTERY_AI_API_KEY_FILE":                            secretPath,
+		"LOTTERY_VISION_USE_DOC_UNWARPING":                   "true",
+		"LOTTERY_COMPENSATION_JOBS_PREVIOUS_DRAW_PRIZE_CRON": "0 0 8 * * *",
+		"LOTTERY_LOTTERIES_SSQ_RECOMMENDATION_MODEL":         "gpt-test",
+		"LOTTERY_LOTTERIES_SSQ_DRAW_SCHEDULE_WEEKDAYS":       "2, 4, 0",
+	}
+
+	if err := applyEnvOverrides(&cfg, mapLookup(env)); err != nil {
+		t.Fatalf("applyEnvOverrides returned error: %v", err)
+	}
+
+	if cfg.Jwt.Secret != "env-secret" || cfg.Jwt.Expiration != 3600 {
+		t.Fatalf("unexpected jwt config: %+v", cfg.Jwt)
+	}
+	if cfg.Database.Port != 6543 {
+		t.Fatalf("unexpected database port: %d", cfg.Database.Port)
+	}
+	if cfg.AI.APIKey != "file-secret" {
+		t.Fatalf("unexpected ai api key: %q", cfg.AI.APIKey)
+	}
+	if !cfg.Vision.UseDocUnwarping {
+		t.Fatalf("expected vision useDocUnwarping override")
+	}
+	if cfg.Compensation.Jobs[0].Cron != "0 0 8 * * *" {
+		t.Fatalf("unexpected compensation cron: %q", cfg.Compensation.Jobs[0].Cron)
+	}
+	if cfg.Lotteries[0].Recommendation.Model != "gpt-test" || cfg.Lotteries[1].Recommendation.Model != "keep" {
+		t.Fatalf("unexpected lottery models: %+v", cfg.Lotteries)
+	}
+	if !slices.Equal(cfg.Lotteries[0].DrawSchedule.Weekdays, []int{2, 4, 0}) {
+		t.Fatalf("unexpected weekdays: %v", cfg.Lotteries[0].DrawSchedule.Weekdays)
+	}
+}
+
+// TestApplyEnvOverridesCoversEveryField 为配置中的每个叶子字段设置环境变量，
+// 新增字段使用尚未支持的类型时这里会失败。
+func TestApplyEnvOverridesCoversEveryField(t *testing.T) {
+	cfg := Config{
+		Compensation: CompensationConfig{Jobs: []CompensationJobConfig{{Name: "job"}}},
+		Lotteries:    []LotteryConfig{{Code: "ssq"}},
+	}
+	samples := map[reflect.Kind]string{
+		reflect.String:  "value",
+		reflect.Int:     "7",
+		reflect.Int64:   "7",
+		reflect.Float32: "0.5",
+		reflect.Float64: "0.5",
+		reflect.Bool:    "true",
+	}
+	env := map[string]string{}
+	leaves := map[string]reflect.Value{}
+	var collect func(value reflect.Value, prefix string)
+	collect = func(value reflect.Value, prefix string) {
+		for index := 0; index < value.NumField(); index++ {
+			field := value.Type().Field(index)
+			if !field.IsExported() {
+				continue
+			}
+			key := prefix + "_" + envKeySegment(fieldConfigName(field))
+			fieldValue := value.Field(index)
+			switch {
+			case fieldValue.Kind() == reflect.Struct:
+				collect(fieldValue, key)
+				continue
+			case fieldValue.Kind() == reflect.Slice && fieldValue.Type().Elem().Kind() == reflect.Struct:
+				for item := 0; item < fieldValue.Len(); item++ {
+					collect(fieldValue.Index(item), key+"_"+envKeySegment(sliceItemIdentity(fieldValue.Index(item))))
+				}
+				continue
+			}
+			kind := fieldValue.Kind()
+			if kind == reflect.Slice {
+				kind = fieldValue.Type().Elem().Kind()
+			}
+			sample, ok := samples[kind]
+			if !ok {
+				t.Fatalf("no sample value for %s (%s)", key, fieldValue.Type())
+			}
+			env[key] = sample
+			leaves[key] = fieldValue
+		}
+	}
+	collect(reflect.ValueOf(&cfg).Elem(), EnvPrefix)
+
+	if err := applyEnvOverrides(&cfg, mapLookup(env)); err != nil {
+		t.Fatalf("applyEnvOverrides returned error: %v", err)
+	}
+	for key, field := range leaves {
+		if field.IsZero() {
+			t.Errorf("expected %s to be applied", key)
+		}
+	}
+	if cfg.Tracing.SampleRatio != 0.5 {
+		t.Fatalf("unexpected tracing sample ratio: %v", cfg.Tracing.SampleRatio)
+	}
+}
+
+func TestApplyEnvOverridesRejectsValueAndFile(t *testing.T) {
+	cfg := Config{}
+	err := applyEnvOverrides(&cfg, mapLookup(map[string]string{
+		"LOTTERY_JWT_SECRET":      "a",
+		"LOTTERY_JWT_SECRET_FILE": "/tmp/b",
+	}))
+	if err == nil {
+		t.Fatalf("expected error when both value and file are set")
+	}
+}
+
+func TestApplyEnvOverridesInvalidNumber(t *testing.T) {
+	cfg := Config{}
+	if err := applyEnvOverrides(&cfg, mapLookup(map[string]string{"LOTTERY_DATABASE_PORT": "abc"})); err == nil {
+		t.Fatalf("expected error for invalid number")
+	}
+}
+
+func TestValidateRejectsDefaultSecretInProduction(t *testing.T) {
+	prevConfig := Current
+	prevProduction := IsProduction
+	t.Cleanup(func() {
+		Current = prevConfig
+		IsProduction = prevProduction
+	})
+
+	IsProduction = true
+	Current.Jwt.Secret = DefaultJwtSecret
+	if err := Validate(); err == nil {
+		t.Fatalf("expected default secret to be rejected in production")
+	}
+
+	Current.Jwt.Secret = "a-real-secret"
+	if err := Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	IsProduction = false
+	Current.Jwt.Secret = DefaultJwtSecret
+	if err := Validate(); err != nil {
+		t.Fatalf("default secret should be allowed outside production: %v", err)
+	}
+}
+
+func TestValidateRejectsUnprotectedMetricsInProduction(t *testing.T) {
+	prevConfig := Current
+	prevProduction := IsProduction
+	t.Cleanup(func() {
+		Current = prevConfig
+		IsProduction = prevProduction
+	})
+
+	IsProduction = true
+	Current.Jwt.Secret = "a-real-secret"
+	Current.Metrics = MetricsConfig{Enabled: true}
+	if err := Validate(); err == nil {
+		t.Fatalf("expected metrics without token to be rejected in production")
+	}
+
+	Current.Metrics.Token = "scrape-token"
+	if err := Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	IsProduction = false
+	Current.Metrics.Token = ""
+	if err := Validate(); err != nil {
+		t.Fatalf("metrics without token should be allowed outside production: %v", err)
+	}
+}
+
+func mapLookup(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func TestValidateRejectsUnknownRegistrationMode(t *testing.T) {
+	prevConfig := Current
+	prevProduction := IsProduction
+	t.Cleanup(func() {
+		Current = prevConfig
+		IsProduction = prevProduction
+	})
+	IsProduction = false
+
+	Current.Registration.Mode = "public"
+	if err := Validate(); err == nil {
+		t.Fatalf("expected unknown registration mode to be rejected")
+	}
+	Current.Registration.Mode = "Invite"
+	if err := Validate(); err != nil {
+		t.Fatalf("expected invite mode to be accepted: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// sensitiveKeyParts 命中这些片段的字段在对外展示时会被脱敏。
+var sensitiveKeyParts = []string{"secret", "password", "apikey", "appkey", "dsn", "token"}
+
+// Sanitized 返回脱敏后的当前配置，键名与 config.yaml 保持一致，供管理员排查使用。
+func Sanitized() map[string]any {
+	return sanitizeStruct(reflect.ValueOf(Current))
+}
+
+func sanitizeStruct(value reflect.Value) map[string]any {
+	result := make(map[string]any, value.NumField())
+	valueType := value.Type()
+	for index := 0; index < valueType.NumField(); index++ {
+		field := valueType.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+		key := lowerCamel(fieldConfigName(field))
+		result[key] = sanitizeValue(key, value.Field(index))
+	}
+	return result
+}
+
+func sanitizeValue(key string, value reflect.Value) any {
+	switch value.Kind() {
+	case reflect.Struct:
+		return sanitizeStruct(value)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.Struct {
+			return value.Interface()
+		}
+		items := make([]any, 0, value.Len())
+		for index := 0; index < value.Len(); index++ {
+			items = append(items, sanitizeStruct(value.Index(index)))
+		}
+		return items
+	case reflect.String:
+		if isSensitiveKey(key) && value.String() != "" {
+			return "******"
+		}
+		return value.String()
+	default:
+		return value.Interface()
+	}
+}
+
+func isSensitiveKey(key string) bool {
+	lowered := strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(lowered, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// lowerCamel 用于没有 mapstructure 标签的顶层字段，例如 App -> app、AI -> ai。
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for index := range runes {
+		if index > 0 && index+1 < len(runes) && unicode.IsLower(runes[index+1]) {
+			break
+		}
+		runes[index] = unicode.ToLower(runes[index])
+	}
+	return string(runes)
+}
@@ -0,0 +1,312 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 基线迁移使用的表结构快照，对应引入版本化迁移时各模型的字段和索引。
+// 快照不随模型变化：之后的结构调整都要写成新的迁移，这里的定义不要再修改。
+
+type baselineModel struct {
+	Id        uuid.UUID `gorm:"type:uuid;primary_key;"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+type baselineUser struct {
+	Base        baselineModel `gorm:"embedded"`
+	Username    string        `gorm:"uniqueIndex;size:64"`
+	DisplayName string        `gorm:"size:64"`
+	Password    string
+	Role        string `gorm:"size:16;default:user"`
+	Disabled    bool   `gorm:"default:false"`
+	Language    string `gorm:"size:16"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineSession struct {
+	Base              baselineModel `gorm:"embedded"`
+	UserID            uuid.UUID     `gorm:"type:uuid;index"`
+	RefreshTokenHash  string        `gorm:"size:64;uniqueIndex"`
+	PreviousTokenHash string        `gorm:"size:64;index"`
+	UserAgent         string        `gorm:"size:255"`
+	IP                string        `gorm:"size:64"`
+	ExpiresAt         time.Time
+	LastUsedAt        time.Time
+	RevokedAt         *time.Time
+}
+
+func (baselineSession) TableName() string { return "user_sessions" }
+
+type baselineAccessToken struct {
+	Base       baselineModel `gorm:"embedded"`
+	UserID     uuid.UUID     `gorm:"type:uuid;index"`
+	Name       string        `gorm:"size:64"`
+	TokenHash  string        `gorm:"size:64;uniqueIndex"`
+	TokenHint  string        `gorm:"size:16"`
+	Scopes     string        `gorm:"size:255"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	LastUsedIP string `gorm:"size:64"`
+	RevokedAt  *time.Time
+}
+
+func (baselineAccessToken) TableName() string { return "user_access_tokens" }
+
+type baselineInviteCode struct {
+	Base      baselineModel `gorm:"embedded"`
+	Code      string        `gorm:"size:32;uniqueIndex"`
+	CreatedBy uuid.UUID     `gorm:"type:uuid;index"`
+	Note      string        `gorm:"size:255"`
+	MaxUses   int
+	UsedCount int
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
+
+func (baselineInviteCode) TableName() string { return "user_invite_codes" }
+
+type baselineIdentity struct {
+	Base        baselineModel `gorm:"embedded"`
+	UserID      uuid.UUID     `gorm:"type:uuid;index"`
+	Issuer      string        `gorm:"size:255;uniqueIndex:idx_user_identities_issuer_subject"`
+	Subject     string        `gorm:"size:255;uniqueIndex:idx_user_identities_issuer_subject"`
+	Email       string        `gorm:"size:255"`
+	LastLoginAt time.Time
+}
+
+func (baselineIdentity) TableName() string { return "user_identities" }
+
+type baselineIdempotencyKey struct {
+	Base         baselineModel `gorm:"embedded"`
+	UserID       uuid.UUID     `gorm:"type:uuid;uniqueIndex:idx_idempotency_keys_user_key"`
+	Key          string        `gorm:"column:idempotency_key;size:128;uniqueIndex:idx_idempotency_keys_user_key"`
+	Method       string        `gorm:"size:16"`
+	Path         string        `gorm:"size:255"`
+	Fingerprint  string        `gorm:"size:64"`
+	StatusCode   int
+	ContentType  string `gorm:"size:128"`
+	ResponseBody []byte
+	CompletedAt  *time.Time
+	ExpiresAt    time.Time `gorm:"index"`
+}
+
+func (baselineIdempotencyKey) TableName() string { return "idempotency_keys" }
+
+type baselineLedger struct {
+	Base    baselineModel `gorm:"embedded"`
+	Name    string        `gorm:"size:64"`
+	OwnerID uuid.UUID     `gorm:"type:uuid;index"`
+}
+
+func (baselineLedger) TableName() string { return "ledgers" }
+
+type baselineLedgerMember struct {
+	Base     baselineModel `gorm:"embedded"`
+	LedgerID uuid.UUID     `gorm:"type:uuid;uniqueIndex:idx_ledger_members_ledger_user"`
+	UserID   uuid.UUID     `gorm:"type:uuid;uniqueIndex:idx_ledger_members_ledger_user;index"`
+	Role     string        `gorm:"size:16"`
+}
+
+func (baselineLedgerMember) TableName() string { return "ledger_members" }
+
+type baselineLotteryType struct {
+	Base                   baselineModel `gorm:"embedded"`
+	Code                   string        `gorm:"uniqueIndex;size:32"`
+	Name                   string        `gorm:"size:64"`
+	Status                 string        `gorm:"size:16"`
+	RemoteLotteryID        string        `gorm:"size:32"`
+	RedCount               int
+	BlueCount              int
+	RedMin                 int
+	RedMax                 int
+	BlueMin                int
+	BlueMax                int
+	RecommendationCount    int
+	RecommendationProvider string `gorm:"size:32"`
+	RecommendationModel    string `gorm:"size:128"`
+	VisionProvider         string `gorm:"size:32"`
+	VisionModel            string `gorm:"size:128"`
+}
+
+func (baselineLotteryType) TableName() string { return "lottery_types" }
+
+type baselineDrawResult struct {
+	Base            baselineModel `gorm:"embedded"`
+	LotteryCode     string        `gorm:"uniqueIndex:idx_lottery_issue;size:32"`
+	Issue           string        `gorm:"uniqueIndex:idx_lottery_issue;size:32"`
+	DrawDate        time.Time
+	RedNumbers      string `gorm:"size:64"`
+	BlueNumbers     string `gorm:"size:32"`
+	SaleAmount      float64
+	PrizePoolAmount float64
+	Source          string              `gorm:"size:32"`
+	RawPayload      string              `gorm:"type:text"`
+	PrizeDetails    []baselineDrawPrize `gorm:"foreignKey:DrawResultID"`
+}
+
+func (baselineDrawResult) TableName() string { return "draw_results" }
+
+type baselineDrawPrize struct {
+	Base         baselineModel `gorm:"embedded"`
+	DrawResultID uuid.UUID     `gorm:"type:uuid;index"`
+	PrizeName    string        `gorm:"size:32"`
+	PrizeRule    string        `gorm:"size:128"`
+	WinnerCount  int
+	SingleBonus  float64
+}
+
+func (baselineDrawPrize) TableName() string { return "draw_prizes" }
+
+type baselineTicketUpload struct {
+	Base                  baselineModel `gorm:"embedded"`
+	UserID                *uuid.UUID    `gorm:"type:uuid;index"`
+	LotteryCode           string        `gorm:"index;size:32"`
+	Status                string        `gorm:"size:32"`
+	OriginalFilename      string        `gorm:"size:255"`
+	ImagePath             string        `gorm:"size:255"`
+	RecognizedText        string        `gorm:"type:text"`
+	RecognitionIssue      string        `gorm:"size:32"`
+	RecognitionConfidence float64
+	RecognitionPayload    string `gorm:"type:text"`
+	ErrorMessage          string `gorm:"type:text"`
+}
+
+func (baselineTicketUpload) TableName() string { return "ticket_uploads" }
+
+type baselineTicket struct {
+	Base             baselineModel `gorm:"embedded"`
+	UserID           *uuid.UUID    `gorm:"type:uuid;index"`
+	LedgerID         *uuid.UUID    `gorm:"type:uuid;index"`
+	LotteryCode      string        `gorm:"index;size:32"`
+	RecommendationID *uuid.UUID    `gorm:"type:uuid;index"`
+	Issue            string        `gorm:"index;size:32"`
+	EntrySignature   *string       `gorm:"size:64;index"`
+	ManualDrawDate   *time.Time
+	Source           string `gorm:"size:32"`
+	ImagePath        string `gorm:"size:255"`
+	RecognizedText   string `gorm:"type:text"`
+	Status           string `gorm:"size:32"`
+	CostAmount       float64
+	PrizeAmount      float64
+	PurchasedAt      time.Time
+	CheckedAt        *time.Time
+	Notes            string                `gorm:"type:text"`
+	IsSyndicate      bool                  `gorm:"index"`
+	Entries          []baselineTicketEntry `gorm:"foreignKey:TicketID"`
+}
+
+func (baselineTicket) TableName() string { return "tickets" }
+
+type baselineTicketEntry struct {
+	Base         baselineModel `gorm:"embedded"`
+	TicketID     uuid.UUID     `gorm:"type:uuid;index"`
+	Sequence     int
+	RedNumbers   string `gorm:"size:64"`
+	BlueNumbers  string `gorm:"size:32"`
+	Multiple     int
+	IsAdditional bool
+	IsWinning    bool
+	PrizeName    string `gorm:"size:32"`
+	PrizeAmount  float64
+	MatchSummary string `gorm:"size:64"`
+}
+
+func (baselineTicketEntry) TableName() string { return "ticket_entries" }
+
+type baselineTicketShare struct {
+	Base        baselineModel `gorm:"embedded"`
+	TicketID    uuid.UUID     `gorm:"type:uuid;index"`
+	UserID      *uuid.UUID    `gorm:"type:uuid;index"`
+	Name        string        `gorm:"size:64"`
+	ShareAmount float64
+	CostAmount  float64
+	PrizeAmount float64
+}
+
+func (baselineTicketShare) TableName() string { return "ticket_shares" }
+
+type baselineRecommendation struct {
+	Base          baselineModel `gorm:"embedded"`
+	UserID        *uuid.UUID    `gorm:"type:uuid;index"`
+	LedgerID      *uuid.UUID    `gorm:"type:uuid;index"`
+	LotteryCode   string        `gorm:"index;size:32"`
+	Issue         string        `gorm:"index;size:32"`
+	DrawDate      *time.Time
+	Provider      string `gorm:"size:32"`
+	Model         string `gorm:"size:128"`
+	Strategy      string `gorm:"size:64"`
+	PromptVersion string `gorm:"size:64"`
+	Summary       string `gorm:"type:text"`
+	Basis         string `gorm:"type:text"`
+	RawPayload    string `gorm:"type:text"`
+	CheckedAt     *time.Time
+	PrizeAmount   float64
+	Entries       []baselineRecommendationEntry `gorm:"foreignKey:RecommendationID"`
+}
+
+func (baselineRecommendation) TableName() string { return "recommendations" }
+
+type baselineRecommendationEntry struct {
+	Base             baselineModel `gorm:"embedded"`
+	RecommendationID uuid.UUID     `gorm:"type:uuid;index"`
+	Sequence         int
+	RedNumbers       string `gorm:"size:64"`
+	BlueNumbers      string `gorm:"size:32"`
+	Confidence       float64
+	Reason           string `gorm:"type:text"`
+	IsWinning        bool
+	PrizeName        string `gorm:"size:32"`
+	PrizeAmount      float64
+	MatchSummary     string `gorm:"size:64"`
+}
+
+func (baselineRecommendationEntry) TableName() string { return "recommendation_entries" }
+
+type baselineExternalAPILog struct {
+	Base             baselineModel `gorm:"embedded"`
+	Provider         string        `gorm:"index;size:32"`
+	Method           string        `gorm:"size:16"`
+	Endpoint         string        `gorm:"size:512"`
+	StatusCode       int
+	Success          bool `gorm:"index"`
+	DurationMs       int64
+	Request          string     `gorm:"type:text"`
+	Response         string     `gorm:"type:text"`
+	ErrorMessage     string     `gorm:"type:text"`
+	RequestID        string     `gorm:"index;size:128"`
+	TraceID          string     `gorm:"size:32"`
+	JobName          string     `gorm:"size:64"`
+	UserID           *uuid.UUID `gorm:"type:uuid;index"`
+	UploadID         *uuid.UUID `gorm:"type:uuid;index"`
+	RecommendationID *uuid.UUID `gorm:"type:uuid;index"`
+}
+
+func (baselineExternalAPILog) TableName() string { return "external_api_logs" }
+
+// baselineModels 按依赖顺序返回基线快照中的全部表。
+func baselineModels() []any {
+	return []any{
+		&baselineUser{},
+		&baselineSession{},
+		&baselineAccessToken{},
+		&baselineInviteCode{},
+		&baselineIdentity{},
+		&baselineIdempotencyKey{},
+		&baselineLedger{},
+		&baselineLedgerMember{},
+		&baselineLotteryType{},
+		&baselineDrawResult{},
+		&baselineDrawPrize{},
+		&baselineTicketUpload{},
+		&baselineTicket{},
+		&baselineTicketEntry{},
+		&baselineTicketShare{},
+		&baselineRecommendation{},
+		&baselineRecommendationEntry{},
+		&baselineExternalAPILog{},
+	}
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// CopyStat 是单张表的复制结果。
+type CopyStat struct {
+	Table      string
+	SourceRows int64
+	TargetRows int64
+}
+
+// CopyOptions 控制跨库复制。目标库已有数据时默认拒绝复制，Truncate 为 true 时先清空目标库的业务表。
+type CopyOptions struct {
+	Truncate bool
+}
+
+// CopyDatabase 把源库的全部业务表复制到目标库：在一个目标库事务中先把表结构迁移到源库的版本，
+// 再按依赖顺序逐表写入，保留 UUID 主键和创建、更新时间，每张表写完后核对源库与目标库的行数，
+// 任一张不一致都会连同表结构变更整体回滚。
+func CopyDatabase(source *gorm.DB, target *gorm.DB, options CopyOptions) ([]CopyStat, error) {
+	version, err := SchemaVersion(source)
+	if err != nil {
+		return nil, fmt.Errorf("读取源库迁移版本失败: %w", err)
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("源库还没有执行任何迁移")
+	}
+	if latest := LatestSchemaVersion(); version > latest {
+		return nil, fmt.Errorf("源库迁移版本 %d 高于当前程序支持的 %d，请升级程序后再复制", version, latest)
+	}
+	tables, err := DataTables()
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.Truncate {
+		for _, table := range tables {
+			if !target.Migrator().HasTable(table.Name) {
+				continue
+			}
+			rows, err := CountRows(target, table.Name)
+			if err != nil {
+				return nil, err
+			}
+			if rows > 0 {
+				return nil, fmt.Errorf("目标库 %s 已有 %d 行数据，确认要覆盖时请使用 -truncate", table.Name, rows)
+			}
+		}
+	}
+	stats := make([]CopyStat, 0, len(tables))
+	err = source.Transaction(func(reader *gorm.DB) error {
+		return target.Transaction(func(writer *gorm.DB) error {
+			if err := MigrateTo(writer, version); err != nil {
+				return fmt.Errorf("准备目标库表结构失败: %w", err)
+			}
+			if err := ClearTables(writer, tables); err != nil {
+				return err
+			}
+			for _, table := range tables {
+				stat, err := copyTable(reader, writer, table)
+				if err != nil {
+					return err
+				}
+				stats = append(stats, stat)
+				if stat.SourceRows != stat.TargetRows {
+					return fmt.Errorf("%s 源库 %d 行，目标库 %d 行，复制已回滚", table.Name, stat.SourceRows, stat.TargetRows)
+				}
+			}
+			return nil
+		})
+	}, SnapshotReadOptions(source))
+	return stats, err
+}
+
+func copyTable(reader *gorm.DB, writer *gorm.DB, table DataTable) (CopyStat, error) {
+	stat := CopyStat{Table: table.Name}
+	tableWriter := NewTableWriter(writer, table)
+	if err := ExportRows(reader, table, tableWriter.Write); err != nil {
+		return stat, fmt.Errorf("复制 %s 失败: %w", table.Name, err)
+	}
+	if err := tableWriter.Flush(); err != nil {
+		return stat, err
+	}
+
+	var err error
+	if stat.SourceRows, err = CountRows(reader, table.Name); err != nil {
+		return stat, err
+	}
+	if stat.TargetRows, err = CountRows(writer, table.Name); err != nil {
+		return stat, err
+	}
+	return stat, nil
+}
+
+// SnapshotReadOptions 返回逐表读取整库时使用的事务选项：PostgreSQL 使用只读的可重复读事务，
+// 保证各表读到同一时刻的数据；SQLite 的事务本身就是一致快照，使用默认选项。
+func SnapshotReadOptions(source *gorm.DB) *sql.TxOptions {
+	if source.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+}
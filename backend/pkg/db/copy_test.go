@@ -0,0 +1,98 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	lotteryModel "go-fiber-starter/internal/model/lottery"
+	userModel "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func openCopyTestDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+
+	database, err := OpenConfig(config.DatabaseConfig{Driver: "sqlite", Path: filepath.Join(t.TempDir(), name)})
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	t.Cleanup(func() { CloseDatabase(database) })
+	return database
+}
+
+func TestCopyDatabasePreservesIdentifiersAndTimestamps(t *testing.T) {
+	prevLogger := logger.Logger
+	t.Cleanup(func() { logger.Logger = prevLogger })
+	logger.Logger = zap.NewNop().Sugar()
+
+	source := openCopyTestDB(t, "source.sqlite")
+	target := openCopyTestDB(t, "target.sqlite")
+	if _, err := migrateUp(source, 0); err != nil {
+		t.Fatalf("migrate source: %v", err)
+	}
+
+	user := userModel.User{Username: "alice", Password: "x", Role: userModel.RoleAdmin}
+	if err := source.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	checkedAt := time.Date(2026, 3, 16, 21, 30, 0, 0, time.UTC)
+	ticket := lotteryModel.Ticket{
+		UserID:      &user.Id,
+		LotteryCode: "ssq",
+		Issue:       "2026030",
+		Status:      "won",
+		CostAmount:  10 * lotteryModel.Yuan,
+		PrizeAmount: 5 * lotteryModel.Yuan,
+		CheckedAt:   &checkedAt,
+		IsSyndicate: true,
+		Entries: []lotteryModel.TicketEntry{
+			{Sequence: 1, RedNumbers: "01,02,03,04,05,06", BlueNumbers: "07", Multiple: 1, IsWinning: true},
+		},
+	}
+	if err := source.Create(&ticket).Error; err != nil {
+		t.Fatalf("create ticket: %v", err)
+	}
+
+	stats, err := CopyDatabase(source, target, CopyOptions{})
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if len(stats) != len(migrationModels()) {
+		t.Fatalf("expected every table copied, got %d", len(stats))
+	}
+
+	copied := lotteryModel.Ticket{}
+	if err := target.Preload("Entries").First(&copied, "id = ?", ticket.Id).Error; err != nil {
+		t.Fatalf("load copied ticket: %v", err)
+	}
+	if !copied.CreatedAt.Equal(ticket.CreatedAt) || copied.CheckedAt == nil || !copied.CheckedAt.Equal(checkedAt) {
+		t.Fatalf("expected timestamps preserved, got %+v", copied)
+	}
+	if copied.UserID == nil || *copied.UserID != user.Id || !copied.IsSyndicate || copied.PrizeAmount != 5*lotteryModel.Yuan {
+		t.Fatalf("unexpected copied ticket %+v", copied)
+	}
+	if len(copied.Entries) != 1 || copied.Entries[0].Id != ticket.Entries[0].Id || copied.Entries[0].RedMask == 0 {
+		t.Fatalf("unexpected copied entries %+v", copied.Entries)
+	}
+	version, err := SchemaVersion(target)
+	if err != nil || version != LatestSchemaVersion() {
+		t.Fatalf("expected target migrated to source version, got %d (%v)", version, err)
+	}
+
+	if _, err := CopyDatabase(source, target, CopyOptions{}); err == nil || !strings.Contains(err.Error(), "-truncate") {
+		t.Fatalf("expected non-empty target rejected, got %v", err)
+	}
+	if _, err := CopyDatabase(source, target, CopyOptions{Truncate: true}); err != nil {
+		t.Fatalf("copy with truncate: %v", err)
+	}
+	var count int64
+	if err := target.Model(&lotteryModel.TicketEntry{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("expected entries replaced rather than duplicated, got %d (%v)", count, err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"go-fiber-starter/pkg/config"
 	"go-fiber-starter/pkg/logger"
 	"go-fiber-starter/pkg/util"
+	"path/filepath"
 	"strings"
 
 	"github.com/glebarez/sqlite"
@@ -19,17 +20,54 @@ import (
 
 var DB *gorm.DB
 
+// Init 连接数据库，database.migrateOnStart 开启时同时执行未执行的迁移。
 func Init() error {
+	if err := Open(); err != nil {
+		return err
+	}
+	if !config.Current.Database.MigrateOnStart {
+		pending, err := PendingMigrations()
+		if err != nil {
+			return fmt.Errorf("检查数据库迁移失败: %v", err)
+		}
+		if len(pending) > 0 {
+			logger.Warn("有 %d 个数据库迁移未执行，请运行 migrate up", len(pending))
+		}
+		return nil
+	}
+
+	executed, err := MigrateUp(0)
+	for _, item := range executed {
+		logger.Info("已执行数据库迁移 %d_%s", item.Version, item.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("数据库迁移失败: %v", err)
+	}
+	return nil
+}
+
+// Open 只连接数据库并设置连接池，不执行迁移，供 migrate 命令使用。
+func Open() error {
+	db, err := OpenConfig(config.Current.Database)
+	if err != nil {
+		return err
+	}
+	DB = db
+	return nil
+}
+
+// OpenConfig 按给定配置连接数据库并返回独立的连接，不修改全局 DB，供跨库复制同时打开源库和目标库。
+func OpenConfig(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	gormLogger := zapgorm2.New(logger.Logger.Desugar())
 	gormLogger.IgnoreRecordNotFoundError = true
 
-	if err := ensureDatabaseReady(); err != nil {
-		return err
+	if err := ensureDatabaseReady(cfg); err != nil {
+		return nil, err
 	}
 
-	dialector, err := buildDialector()
+	dialector, err := buildDialector(cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	db, err := gorm.Open(dialector, &gorm.Config{
@@ -38,31 +76,26 @@ func Init() error {
 	})
 
 	if err != nil {
-		return err
-	}
-	DB = db
-	configureConnectionPool(db)
-	if err := configureSQLiteJournalMode(db); err != nil {
-		return err
+		return nil, err
 	}
-	if err := autoMigrate(); err != nil {
-		return fmt.Errorf("数据库迁移失败: %v", err)
+	configureConnectionPool(db, cfg)
+	if err := configureSQLiteJournalMode(db, cfg); err != nil {
+		return nil, err
 	}
-
-	return err
+	return db, nil
 }
 
-func ensureDatabaseReady() error {
-	if !isPostgresDriver() {
+func ensureDatabaseReady(cfg config.DatabaseConfig) error {
+	if !isPostgres(cfg) {
 		return nil
 	}
-	return ensurePostgresDatabase()
+	return ensurePostgresDatabase(cfg)
 }
 
-func buildDialector() (gorm.Dialector, error) {
-	switch currentDriver() {
+func buildDialector(cfg config.DatabaseConfig) (gorm.Dialector, error) {
+	switch driverOf(cfg) {
 	case "sqlite":
-		path := config.Current.Database.Path
+		path := cfg.Path
 		if path == "" {
 			return nil, fmt.Errorf("sqlite 数据库路径不能为空")
 		}
@@ -72,18 +105,18 @@ func buildDialector() (gorm.Dialector, error) {
 		}
 		return sqlite.Open(path), nil
 	case "postgres", "postgresql":
-		dsn := buildPostgresDSN()
+		dsn := buildPostgresDSN(cfg)
 		if dsn == "" {
 			return nil, fmt.Errorf("postgres 数据库配置不完整")
 		}
 		return postgres.Open(dsn), nil
 	default:
-		return nil, fmt.Errorf("不支持的数据库驱动: %s", currentDriver())
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", driverOf(cfg))
 	}
 }
 
-func ensurePostgresDatabase() error {
-	adminDSN, databaseName, err := buildPostgresAdminDSN()
+func ensurePostgresDatabase(cfg config.DatabaseConfig) error {
+	adminDSN, databaseName, err := buildPostgresAdminDSN(cfg)
 	if err != nil {
 		return err
 	}
@@ -114,29 +147,29 @@ func ensurePostgresDatabase() error {
 	return nil
 }
 
-func buildPostgresDSN() string {
-	if strings.TrimSpace(config.Current.Database.DSN) != "" {
-		return config.Current.Database.DSN
+func buildPostgresDSN(cfg config.DatabaseConfig) string {
+	if strings.TrimSpace(cfg.DSN) != "" {
+		return cfg.DSN
 	}
 
-	if config.Current.Database.Host == "" || config.Current.Database.User == "" || config.Current.Database.Name == "" {
+	if cfg.Host == "" || cfg.User == "" || cfg.Name == "" {
 		return ""
 	}
 
 	parts := []string{
-		fmt.Sprintf("host=%s", config.Current.Database.Host),
-		fmt.Sprintf("port=%d", maxInt(config.Current.Database.Port, 5432)),
-		fmt.Sprintf("user=%s", config.Current.Database.User),
-		fmt.Sprintf("password=%s", config.Current.Database.Password),
-		fmt.Sprintf("dbname=%s", config.Current.Database.Name),
-		fmt.Sprintf("sslmode=%s", resolveValue(config.Current.Database.SSLMode, "disable")),
-		fmt.Sprintf("TimeZone=%s", resolveValue(config.Current.Database.TimeZone, "Asia/Shanghai")),
+		fmt.Sprintf("host=%s", cfg.Host),
+		fmt.Sprintf("port=%d", maxInt(cfg.Port, 5432)),
+		fmt.Sprintf("user=%s", cfg.User),
+		fmt.Sprintf("password=%s", cfg.Password),
+		fmt.Sprintf("dbname=%s", cfg.Name),
+		fmt.Sprintf("sslmode=%s", resolveValue(cfg.SSLMode, "disable")),
+		fmt.Sprintf("TimeZone=%s", resolveValue(cfg.TimeZone, "Asia/Shanghai")),
 	}
 	return strings.Join(parts, " ")
 }
 
-func buildPostgresAdminDSN() (string, string, error) {
-	dsn := buildPostgresDSN()
+func buildPostgresAdminDSN(cfg config.DatabaseConfig) (string, string, error) {
+	dsn := buildPostgresDSN(cfg)
 	if dsn == "" {
 		return "", "", fmt.Errorf("postgres 数据库配置不完整")
 	}
@@ -155,18 +188,18 @@ func buildPostgresAdminDSN() (string, string, error) {
 	return stdlibDsn(parsed), databaseName, nil
 }
 
-func configureConnectionPool(db *gorm.DB) {
+func configureConnectionPool(db *gorm.DB, cfg config.DatabaseConfig) {
 	rawDB, err := db.DB()
 	if err != nil {
 		return
 	}
 
-	applyPoolSetting(rawDB, config.Current.Database.MaxIdleConns, (*sql.DB).SetMaxIdleConns)
-	applyPoolSetting(rawDB, config.Current.Database.MaxOpenConns, (*sql.DB).SetMaxOpenConns)
+	applyPoolSetting(rawDB, cfg.MaxIdleConns, (*sql.DB).SetMaxIdleConns)
+	applyPoolSetting(rawDB, cfg.MaxOpenConns, (*sql.DB).SetMaxOpenConns)
 }
 
-func configureSQLiteJournalMode(db *gorm.DB) error {
-	if currentDriver() != "sqlite" {
+func configureSQLiteJournalMode(db *gorm.DB, cfg config.DatabaseConfig) error {
+	if driverOf(cfg) != "sqlite" {
 		return nil
 	}
 
@@ -197,18 +230,43 @@ func maxInt(left int, right int) int {
 }
 
 func currentDriver() string {
-	driver := strings.TrimSpace(strings.ToLower(config.Current.Database.Driver))
+	return driverOf(config.Current.Database)
+}
+
+func isPostgresDriver() bool {
+	return isPostgres(config.Current.Database)
+}
+
+func driverOf(cfg config.DatabaseConfig) string {
+	driver := strings.TrimSpace(strings.ToLower(cfg.Driver))
 	if driver == "" {
 		return "sqlite"
 	}
 	return driver
 }
 
-func isPostgresDriver() bool {
-	driver := currentDriver()
+func isPostgres(cfg config.DatabaseConfig) bool {
+	driver := driverOf(cfg)
 	return driver == "postgres" || driver == "postgresql"
 }
 
 func stdlibDsn(cfg *pgx.ConnConfig) string {
 	return stdlib.RegisterConnConfig(cfg)
 }
+
+// DescribeConfig 返回不含密码的数据库描述，例如 "sqlite data/lottery.sqlite" 或 "postgres 127.0.0.1:5432/lottery"，
+// 用于命令输出和判断两份配置是否指向同一个数据库。
+func DescribeConfig(cfg config.DatabaseConfig) string {
+	if !isPostgres(cfg) {
+		path := cfg.Path
+		if absolute, err := filepath.Abs(path); err == nil {
+			path = absolute
+		}
+		return driverOf(cfg) + " " + path
+	}
+	parsed, err := pgx.ParseConfig(buildPostgresDSN(cfg))
+	if err != nil {
+		return "postgres (配置不完整)"
+	}
+	return fmt.Sprintf("postgres %s:%d/%s", parsed.Host, parsed.Port, parsed.Database)
+}
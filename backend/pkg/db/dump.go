@@ -0,0 +1,275 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const dumpBatchSize = 200
+
+// DataTable 是参与备份、恢复和跨库复制的业务表，Schema 用于把导出的值还原成目标库可以接受的类型。
+type DataTable struct {
+	Name   string
+	Schema *schema.Schema
+}
+
+var dataTableCache sync.Map
+
+// DataTables 按外键依赖顺序返回全部业务表，写入时按此顺序、清空时按倒序处理。
+// schema_migrations 不在其中，它由迁移本身维护。
+func DataTables() ([]DataTable, error) {
+	models := migrationModels()
+	tables := make([]DataTable, 0, len(models))
+	for _, item := range models {
+		parsed, err := schema.Parse(item, &dataTableCache, schema.NamingStrategy{})
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, DataTable{Name: parsed.Table, Schema: parsed})
+	}
+	return tables, nil
+}
+
+// LatestSchemaVersion 返回代码中最新的迁移版本。
+func LatestSchemaVersion() int64 {
+	latest := int64(0)
+	for _, item := range migrations() {
+		latest = max(latest, item.Version)
+	}
+	return latest
+}
+
+// SchemaVersion 返回数据库已执行的最大迁移版本，没有任何迁移记录时返回 0。
+func SchemaVersion(database *gorm.DB) (int64, error) {
+	applied, err := loadAppliedMigrations(database)
+	if err != nil {
+		return 0, err
+	}
+	version := int64(0)
+	for item := range applied {
+		version = max(version, item)
+	}
+	return version, nil
+}
+
+// MigrateTo 把指定数据库迁移到 target 版本，供恢复和跨库复制在目标库上准备表结构：
+// 低于 target 时向上执行，高于 target 时回滚多出的迁移。回滚可能丢弃新增列中的数据，
+// 只应在随后会整体替换数据的场景中使用。传入事务时每个迁移以保存点执行，随事务一起提交或回滚。
+func MigrateTo(database *gorm.DB, target int64) error {
+	if latest := LatestSchemaVersion(); target <= 0 || target > latest {
+		return fmt.Errorf("目标迁移版本 %d 无效，应在 1 到 %d 之间", target, latest)
+	}
+	version, err := SchemaVersion(database)
+	if err != nil {
+		return err
+	}
+	if version > target {
+		if _, err := migrateDown(database, 0, target); err != nil {
+			return err
+		}
+	}
+	_, err = migrateUp(database, target)
+	return err
+}
+
+// CountRows 返回表中的行数。
+func CountRows(database *gorm.DB, table string) (int64, error) {
+	var count int64
+	err := database.Table(table).Count(&count).Error
+	return count, err
+}
+
+// ExportRows 按主键顺序逐行读取整张表，visit 收到的是列名到原始值的映射。
+func ExportRows(database *gorm.DB, table DataTable, visit func(row map[string]any) error) error {
+	query := database.Table(table.Name)
+	for _, name := range table.Schema.PrimaryFieldDBNames {
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: name}})
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := make(map[string]any)
+		if err := database.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := visit(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ClearTables 按依赖倒序清空表。
+func ClearTables(tx *gorm.DB, tables []DataTable) error {
+	for index := len(tables) - 1; index >= 0; index-- {
+		if err := tx.Exec("DELETE FROM ?", clause.Table{Name: tables[index].Name}).Error; err != nil {
+			return fmt.Errorf("清空 %s 失败: %w", tables[index].Name, err)
+		}
+	}
+	return nil
+}
+
+// TableWriter 把导出的行按批写入目标表。按列名直接插入，不经过模型钩子，
+// 因此 UUID 主键和创建、更新时间都保持原值。
+type TableWriter struct {
+	tx      *gorm.DB
+	table   DataTable
+	pending []map[string]any
+	Written int64
+}
+
+func NewTableWriter(tx *gorm.DB, table DataTable) *TableWriter {
+	return &TableWriter{tx: tx, table: table, pending: make([]map[string]any, 0, dumpBatchSize)}
+}
+
+// Write 把一行数据转换成目标字段类型后加入批次，批次满时写入数据库。
+func (w *TableWriter) Write(row map[string]any) error {
+	normalized, err := NormalizeRow(w.table, row)
+	if err != nil {
+		return err
+	}
+	w.pending = append(w.pending, normalized)
+	if len(w.pending) >= dumpBatchSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush 写入尚未提交的批次。
+func (w *TableWriter) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	if err := w.tx.Table(w.table.Name).Create(&w.pending).Error; err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", w.table.Name, err)
+	}
+	w.Written += int64(len(w.pending))
+	w.pending = w.pending[:0]
+	return nil
+}
+
+// NormalizeRow 按模型字段类型转换列值：JSON 中的数字、时间字符串和 base64 字节，
+// 以及 SQLite 用整数保存的布尔值，都会还原成目标库驱动能直接写入的类型。
+// 模型中没有的列原样保留。
+func NormalizeRow(table DataTable, row map[string]any) (map[string]any, error) {
+	normalized := make(map[string]any, len(row))
+	for column, value := range row {
+		field := table.Schema.LookUpField(column)
+		if field == nil || value == nil {
+			normalized[column] = normalizeUntyped(value)
+			continue
+		}
+		converted, err := normalizeValue(field.IndirectFieldType, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", table.Name, column, err)
+		}
+		normalized[column] = converted
+	}
+	return normalized, nil
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+func normalizeValue(fieldType reflect.Type, value any) (any, error) {
+	if number, ok := value.(json.Number); ok {
+		value = string(number)
+	}
+	if raw, ok := value.([]byte); ok && fieldType != bytesType {
+		value = string(raw)
+	}
+
+	switch {
+	case fieldType == timeType:
+		if text, ok := value.(string); ok {
+			return parseDumpTime(text)
+		}
+		return value, nil
+	case fieldType == bytesType:
+		if text, ok := value.(string); ok {
+			return base64.StdEncoding.DecodeString(text)
+		}
+		return value, nil
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Bool:
+		switch typed := value.(type) {
+		case int64:
+			return typed != 0, nil
+		case float64:
+			return typed != 0, nil
+		case string:
+			return strconv.ParseBool(typed)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch typed := value.(type) {
+		case float64:
+			return int64(typed), nil
+		case bool:
+			if typed {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case string:
+			return strconv.ParseInt(typed, 10, 64)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if text, ok := value.(string); ok {
+			return strconv.ParseUint(text, 10, 64)
+		}
+	case reflect.Float32, reflect.Float64:
+		if text, ok := value.(string); ok {
+			return strconv.ParseFloat(text, 64)
+		}
+	}
+	return value, nil
+}
+
+func normalizeUntyped(value any) any {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if parsed, err := number.Int64(); err == nil {
+		return parsed
+	}
+	if parsed, err := number.Float64(); err == nil {
+		return parsed
+	}
+	return string(number)
+}
+
+var dumpTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.DateOnly,
+}
+
+func parseDumpTime(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	for _, layout := range dumpTimeLayouts {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("时间格式不正确: %s", text)
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// Ping 检查数据库连接是否可用。
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return fmt.Errorf("数据库未初始化")
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// MissingTables 返回迁移模型中尚未建表的表名，为空表示迁移已完成。
+func MissingTables() ([]string, error) {
+	if DB == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+	migrator := DB.Migrator()
+	missing := make([]string, 0)
+	for _, item := range migrationModels() {
+		if migrator.HasTable(item) {
+			continue
+		}
+		statement := DB.Model(item).Statement
+		if err := statement.Parse(item); err != nil {
+			return nil, err
+		}
+		missing = append(missing, statement.Table)
+	}
+	return missing, nil
+}
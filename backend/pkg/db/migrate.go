@@ -3,92 +3,104 @@ package db
 import (
 	"crypto/sha256"
 	"encoding/hex"
-	lotteryModel "go-fiber-starter/internal/model/lottery"
-	userModel "go-fiber-starter/internal/model/user"
-	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	lotteryModel "go-fiber-starter/internal/model/lottery"
+	userModel "go-fiber-starter/internal/model/user"
+
+	"gorm.io/gorm"
 )
 
-func autoMigrate() error {
-	if err := DB.AutoMigrate(
+// migrationModels 返回当前版本的全部模型，就绪检查、备份和跨库复制据此确认和遍历数据表。
+// 迁移本身不使用这些模型：基线见 baselineModels，之后的迁移只按表名和列名操作。
+func migrationModels() []any {
+	return []any{
 		&userModel.User{},
+		&userModel.Session{},
+		&userModel.AccessToken{},
+		&userModel.InviteCode{},
+		&userModel.Identity{},
+		&userModel.IdempotencyKey{},
+		&lotteryModel.Ledger{},
+		&lotteryModel.LedgerMember{},
 		&lotteryModel.LotteryType{},
 		&lotteryModel.DrawResult{},
 		&lotteryModel.DrawPrize{},
 		&lotteryModel.TicketUpload{},
 		&lotteryModel.Ticket{},
 		&lotteryModel.TicketEntry{},
+		&lotteryModel.TicketShare{},
 		&lotteryModel.Recommendation{},
 		&lotteryModel.RecommendationEntry{},
-	); err != nil {
-		return err
+		&lotteryModel.ExternalAPILog{},
 	}
+}
 
-	if err := cleanupDuplicateTicketEntries(); err != nil {
-		return err
-	}
-	if err := backfillLotteryUserOwnership(); err != nil {
-		return err
-	}
-	if err := backfillTicketEntrySignatures(); err != nil {
-		return err
-	}
-	if err := cleanupDuplicateTickets(); err != nil {
-		return err
-	}
-	if err := cleanupDuplicateRecommendations(); err != nil {
+func backfillUserRoles(tx *gorm.DB) error {
+	if err := tx.Table("users").
+		Where("role IS NULL OR role = ''").
+		Update("role", "user").Error; err != nil {
 		return err
 	}
 
-	if err := DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_entries_ticket_sequence ON ticket_entries(ticket_id, sequence)").Error; err != nil {
+	var adminCount int64
+	if err := tx.Table("users").Where("role = ?", "admin").Count(&adminCount).Error; err != nil {
 		return err
 	}
-	if err := DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_user_lottery_issue_signature ON tickets(user_id, lottery_code, issue, entry_signature)").Error; err != nil {
-		return err
+	if adminCount > 0 {
+		return nil
 	}
-	if err := DB.Exec("DROP INDEX IF EXISTS idx_recommendations_user_lottery_issue_created").Error; err != nil {
+
+	firstUserIDs := make([]string, 0, 1)
+	if err := tx.Table("users").Order("created_at asc").Limit(1).Pluck("id", &firstUserIDs).Error; err != nil {
 		return err
 	}
-	if err := DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_recommendations_user_lottery_issue ON recommendations(user_id, lottery_code, issue)").Error; err != nil {
-		return err
+	if len(firstUserIDs) == 0 {
+		return nil
 	}
-	return DB.Exec("CREATE INDEX IF NOT EXISTS idx_tickets_user_lottery_issue ON tickets(user_id, lottery_code, issue)").Error
+	return tx.Table("users").Where("id = ?", firstUserIDs[0]).Update("role", "admin").Error
 }
 
-func backfillLotteryUserOwnership() error {
-	users := make([]userModel.User, 0)
-	if err := DB.Order("created_at asc").Find(&users).Error; err != nil {
+func backfillLotteryUserOwnership(tx *gorm.DB) error {
+	userIDs := make([]string, 0, 2)
+	if err := tx.Table("users").Order("created_at asc").Limit(2).Pluck("id", &userIDs).Error; err != nil {
 		return err
 	}
-	if len(users) != 1 {
+	if len(userIDs) != 1 {
 		return nil
 	}
 
-	userID := users[0].Id
-	if err := DB.Model(&lotteryModel.TicketUpload{}).Where("user_id IS NULL").Update("user_id", userID).Error; err != nil {
-		return err
-	}
-	if err := DB.Model(&lotteryModel.Ticket{}).Where("user_id IS NULL").Update("user_id", userID).Error; err != nil {
-		return err
+	for _, table := range []string{"ticket_uploads", "tickets", "recommendations"} {
+		if err := tx.Table(table).Where("user_id IS NULL").Update("user_id", userIDs[0]).Error; err != nil {
+			return err
+		}
 	}
-	return DB.Model(&lotteryModel.Recommendation{}).Where("user_id IS NULL").Update("user_id", userID).Error
+	return nil
 }
 
-func cleanupDuplicateTicketEntries() error {
-	entries := make([]lotteryModel.TicketEntry, 0)
-	if err := DB.Order("ticket_id asc").Order("sequence asc").Order("created_at asc").Order("id asc").Find(&entries).Error; err != nil {
+func cleanupDuplicateTicketEntries(tx *gorm.DB) error {
+	type entryRow struct {
+		Id       string
+		TicketID string
+		Sequence int
+	}
+	entries := make([]entryRow, 0)
+	if err := tx.Table("ticket_entries").Select("id", "ticket_id", "sequence").
+		Order("ticket_id asc").Order("sequence asc").Order("created_at asc").Order("id asc").
+		Find(&entries).Error; err != nil {
 		return err
 	}
 
 	seen := make(map[string]struct{}, len(entries))
-	duplicateIDs := make([]any, 0)
+	duplicateIDs := make([]string, 0)
 	affectedTicketIDs := make(map[string]struct{})
 	for _, entry := range entries {
-		key := entry.TicketID.String() + ":" + strconv.Itoa(entry.Sequence)
+		key := entry.TicketID + ":" + strconv.Itoa(entry.Sequence)
 		if _, exists := seen[key]; exists {
 			duplicateIDs = append(duplicateIDs, entry.Id)
-			affectedTicketIDs[entry.TicketID.String()] = struct{}{}
+			affectedTicketIDs[entry.TicketID] = struct{}{}
 			continue
 		}
 		seen[key] = struct{}{}
@@ -98,12 +110,12 @@ func cleanupDuplicateTicketEntries() error {
 		return nil
 	}
 
-	if err := DB.Where("id IN ?", duplicateIDs).Delete(&lotteryModel.TicketEntry{}).Error; err != nil {
+	if err := tx.Exec("DELETE FROM ticket_entries WHERE id IN ?", duplicateIDs).Error; err != nil {
 		return err
 	}
 
 	for ticketID := range affectedTicketIDs {
-		if err := refreshTicketPrizeSummary(ticketID); err != nil {
+		if err := refreshTicketPrizeSummary(tx, ticketID); err != nil {
 			return err
 		}
 	}
@@ -111,93 +123,114 @@ func cleanupDuplicateTicketEntries() error {
 	return nil
 }
 
-func backfillTicketEntrySignatures() error {
-	tickets := make([]lotteryModel.Ticket, 0)
-	if err := DB.Preload("Entries").Find(&tickets).Error; err != nil {
+func backfillTicketEntrySignatures(tx *gorm.DB) error {
+	ticketIDs := make([]string, 0)
+	if err := tx.Table("tickets").Order("id asc").Pluck("id", &ticketIDs).Error; err != nil {
 		return err
 	}
 
-	for _, ticket := range tickets {
-		signature := buildTicketEntrySignature(ticket.Entries)
+	for _, ticketID := range ticketIDs {
+		entries := make([]signatureEntry, 0)
+		if err := tx.Table("ticket_entries").
+			Select("sequence", "red_numbers", "blue_numbers", "multiple", "is_additional").
+			Where("ticket_id = ?", ticketID).
+			Order("sequence asc").Order("created_at asc").
+			Find(&entries).Error; err != nil {
+			return err
+		}
 		var value any
-		if signature != "" {
+		if signature := buildTicketEntrySignature(entries); signature != "" {
 			value = signature
-		} else {
-			value = nil
 		}
-		if err := DB.Model(&lotteryModel.Ticket{}).Where("id = ?", ticket.Id).Update("entry_signature", value).Error; err != nil {
+		if err := tx.Table("tickets").Where("id = ?", ticketID).Update("entry_signature", value).Error; err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func cleanupDuplicateTickets() error {
-	tickets := make([]lotteryModel.Ticket, 0)
-	if err := DB.Where("entry_signature IS NOT NULL AND entry_signature <> ''").Order("created_at asc").Order("id asc").Find(&tickets).Error; err != nil {
+// ownedRow 是按归属去重时读取的票据或推荐记录。
+type ownedRow struct {
+	Id             string
+	UserID         *string
+	LedgerID       *string
+	LotteryCode    string
+	Issue          string
+	EntrySignature *string
+}
+
+func cleanupDuplicateTickets(tx *gorm.DB) error {
+	tickets := make([]ownedRow, 0)
+	if err := tx.Table("tickets").Select("id", "user_id", "ledger_id", "lottery_code", "issue", "entry_signature").
+		Where("entry_signature IS NOT NULL AND entry_signature <> ''").
+		Order("created_at asc").Order("id asc").
+		Find(&tickets).Error; err != nil {
 		return err
 	}
 
 	seen := make(map[string]string, len(tickets))
-	duplicateIDs := make([]any, 0)
+	duplicateIDs := make([]string, 0)
 	for _, ticket := range tickets {
-		userID := ""
-		if ticket.UserID != nil {
-			userID = ticket.UserID.String()
-		}
-		key := strings.Join([]string{userID, ticket.LotteryCode, ticket.Issue, dereferenceString(ticket.EntrySignature)}, ":")
+		key := strings.Join([]string{ownerKey(ticket.UserID, ticket.LedgerID), ticket.LotteryCode, ticket.Issue, dereferenceString(ticket.EntrySignature)}, ":")
 		if _, exists := seen[key]; exists {
 			duplicateIDs = append(duplicateIDs, ticket.Id)
 			continue
 		}
-		seen[key] = ticket.Id.String()
+		seen[key] = ticket.Id
 	}
 	if len(duplicateIDs) == 0 {
 		return nil
 	}
-	if err := DB.Where("ticket_id IN ?", duplicateIDs).Delete(&lotteryModel.TicketEntry{}).Error; err != nil {
-		return err
-	}
-	return DB.Where("id IN ?", duplicateIDs).Delete(&lotteryModel.Ticket{}).Error
+	return execStatementsWithArgs(tx, duplicateIDs,
+		"DELETE FROM ticket_entries WHERE ticket_id IN ?",
+		"DELETE FROM tickets WHERE id IN ?",
+	)
 }
 
-func cleanupDuplicateRecommendations() error {
-	recommendations := make([]lotteryModel.Recommendation, 0)
-	if err := DB.Order("created_at asc").Order("id asc").Find(&recommendations).Error; err != nil {
+func cleanupDuplicateRecommendations(tx *gorm.DB) error {
+	recommendations := make([]ownedRow, 0)
+	if err := tx.Table("recommendations").Select("id", "user_id", "ledger_id", "lottery_code", "issue").
+		Order("created_at asc").Order("id asc").
+		Find(&recommendations).Error; err != nil {
 		return err
 	}
 
 	seen := make(map[string]string, len(recommendations))
-	duplicateIDs := make([]any, 0)
+	duplicateIDs := make([]string, 0)
 	for _, recommendation := range recommendations {
-		userID := ""
-		if recommendation.UserID != nil {
-			userID = recommendation.UserID.String()
-		}
-		key := strings.Join([]string{userID, recommendation.LotteryCode, recommendation.Issue}, ":")
+		key := strings.Join([]string{ownerKey(recommendation.UserID, recommendation.LedgerID), recommendation.LotteryCode, recommendation.Issue}, ":")
 		if _, exists := seen[key]; exists {
 			duplicateIDs = append(duplicateIDs, recommendation.Id)
 			continue
 		}
-		seen[key] = recommendation.Id.String()
+		seen[key] = recommendation.Id
 	}
 	if len(duplicateIDs) == 0 {
 		return nil
 	}
-	if err := DB.Where("recommendation_id IN ?", duplicateIDs).Delete(&lotteryModel.RecommendationEntry{}).Error; err != nil {
-		return err
-	}
-	return DB.Where("id IN ?", duplicateIDs).Delete(&lotteryModel.Recommendation{}).Error
+	return execStatementsWithArgs(tx, duplicateIDs,
+		"DELETE FROM recommendation_entries WHERE recommendation_id IN ?",
+		"DELETE FROM recommendations WHERE id IN ?",
+	)
 }
 
-func refreshTicketPrizeSummary(ticketID string) error {
-	ticket := lotteryModel.Ticket{}
-	if err := DB.First(&ticket, "id = ?", ticketID).Error; err != nil {
-		return err
+// refreshTicketPrizeSummary 按剩余明细重新汇总票据奖金和中奖状态。执行于金额改为整数分之前，
+// 此时金额仍是以元为单位的 prize_amount 浮点列。
+func refreshTicketPrizeSummary(tx *gorm.DB, ticketID string) error {
+	type ticketRow struct {
+		CheckedAt *time.Time
+	}
+	type entryRow struct {
+		PrizeAmount float64
+		IsWinning   bool
 	}
 
-	entries := make([]lotteryModel.TicketEntry, 0)
-	if err := DB.Where("ticket_id = ?", ticketID).Find(&entries).Error; err != nil {
+	ticket := ticketRow{}
+	if err := tx.Table("tickets").Select("checked_at").Where("id = ?", ticketID).Take(&ticket).Error; err != nil {
+		return err
+	}
+	entries := make([]entryRow, 0)
+	if err := tx.Table("ticket_entries").Select("prize_amount", "is_winning").Where("ticket_id = ?", ticketID).Find(&entries).Error; err != nil {
 		return err
 	}
 
@@ -219,21 +252,24 @@ func refreshTicketPrizeSummary(ticketID string) error {
 		}
 	}
 
-	return DB.Model(&lotteryModel.Ticket{}).Where("id = ?", ticketID).Updates(updates).Error
+	return tx.Table("tickets").Where("id = ?", ticketID).Updates(updates).Error
+}
+
+// signatureEntry 是计算票据明细签名时读取的字段。
+type signatureEntry struct {
+	Sequence     int
+	RedNumbers   string
+	BlueNumbers  string
+	Multiple     int
+	IsAdditional bool
 }
 
-func buildTicketEntrySignature(entries []lotteryModel.TicketEntry) string {
+// buildTicketEntrySignature 按读取顺序拼接明细后计算摘要，调用方负责按序号和创建时间排序。
+func buildTicketEntrySignature(entries []signatureEntry) string {
 	if len(entries) == 0 {
 		return ""
 	}
 
-	sort.Slice(entries, func(left int, right int) bool {
-		if entries[left].Sequence == entries[right].Sequence {
-			return entries[left].CreatedAt.Before(entries[right].CreatedAt)
-		}
-		return entries[left].Sequence < entries[right].Sequence
-	})
-
 	builder := strings.Builder{}
 	for index, entry := range entries {
 		if index > 0 {
@@ -258,9 +294,163 @@ func buildTicketEntrySignature(entries []lotteryModel.TicketEntry) string {
 	return hex.EncodeToString(sum[:])
 }
 
+// ownerKey 返回去重使用的归属键，共享账本的数据以账本为单位。
+func ownerKey(userID *string, ledgerID *string) string {
+	if ledgerID != nil {
+		return "ledger:" + *ledgerID
+	}
+	if userID != nil {
+		return "user:" + *userID
+	}
+	return ""
+}
+
 func dereferenceString(value *string) string {
 	if value == nil {
 		return ""
 	}
 	return *value
 }
+
+// moneyColumns 列出从浮点金额改为整数分的列，新列名为原列名加 _cents 后缀。
+var moneyColumns = []struct {
+	table  string
+	column string
+}{
+	{"draw_results", "sale_amount"},
+	{"draw_results", "prize_pool_amount"},
+	{"draw_prizes", "single_bonus"},
+	{"tickets", "cost_amount"},
+	{"tickets", "prize_amount"},
+	{"ticket_entries", "prize_amount"},
+	{"ticket_shares", "share_amount"},
+	{"ticket_shares", "cost_amount"},
+	{"ticket_shares", "prize_amount"},
+	{"recommendations", "prize_amount"},
+	{"recommendation_entries", "prize_amount"},
+}
+
+// convertMoneyToCents 把以元为单位的浮点金额按分四舍五入写入整数列后删除旧列。
+// 早期版本的基线直接按模型建出了整数列，这类数据库不存在旧列时跳过。
+func convertMoneyToCents(tx *gorm.DB) error {
+	for _, item := range moneyColumns {
+		if !tx.Migrator().HasColumn(item.table, item.column) {
+			continue
+		}
+		centsColumn := item.column + "_cents"
+		statements := make([]string, 0, 3)
+		if !tx.Migrator().HasColumn(item.table, centsColumn) {
+			statements = append(statements, "ALTER TABLE "+item.table+" ADD COLUMN "+centsColumn+" bigint")
+		}
+		statements = append(statements,
+			"UPDATE "+item.table+" SET "+centsColumn+" = CAST(ROUND(COALESCE("+item.column+", 0) * 100) AS BIGINT)",
+			"ALTER TABLE "+item.table+" DROP COLUMN "+item.column,
+		)
+		if err := execStatements(tx, statements...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertMoneyToYuan 是 convertMoneyToCents 的回滚，恢复以元为单位的浮点列供旧版本使用。
+func convertMoneyToYuan(tx *gorm.DB) error {
+	for _, item := range moneyColumns {
+		centsColumn := item.column + "_cents"
+		if !tx.Migrator().HasColumn(item.table, centsColumn) {
+			continue
+		}
+		statements := make([]string, 0, 3)
+		if !tx.Migrator().HasColumn(item.table, item.column) {
+			statements = append(statements, "ALTER TABLE "+item.table+" ADD COLUMN "+item.column+" double precision")
+		}
+		statements = append(statements,
+			"UPDATE "+item.table+" SET "+item.column+" = COALESCE("+centsColumn+", 0) / 100.0",
+			"ALTER TABLE "+item.table+" DROP COLUMN "+centsColumn,
+		)
+		if err := execStatements(tx, statements...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// numberMaskTables 列出保存号码位图的表，位图由 red_numbers、blue_numbers 计算得到。
+var numberMaskTables = []struct {
+	table string
+	index string
+	key   string
+}{
+	{"draw_results", "idx_draw_results_lottery_number_masks", "lottery_code"},
+	{"ticket_entries", "idx_ticket_entries_ticket_number_masks", "ticket_id"},
+	{"recommendation_entries", "idx_recommendation_entries_recommendation_number_masks", "recommendation_id"},
+}
+
+// addNumberMasks 补齐号码位图列、回填历史数据并创建覆盖索引，
+// 按号码筛选时可以只读索引判断包含关系。
+func addNumberMasks(tx *gorm.DB) error {
+	for _, item := range numberMaskTables {
+		for _, column := range []string{"red_mask", "blue_mask"} {
+			if tx.Migrator().HasColumn(item.table, column) {
+				continue
+			}
+			if err := tx.Exec("ALTER TABLE " + item.table + " ADD COLUMN " + column + " bigint NOT NULL DEFAULT 0").Error; err != nil {
+				return err
+			}
+		}
+		if err := backfillNumberMasks(tx, item.table); err != nil {
+			return err
+		}
+		if err := tx.Exec("CREATE INDEX IF NOT EXISTS " + item.index + " ON " + item.table + "(" + item.key + ", red_mask, blue_mask)").Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func backfillNumberMasks(tx *gorm.DB, table string) error {
+	type numberRow struct {
+		Id          string
+		RedNumbers  string
+		BlueNumbers string
+	}
+
+	lastID := ""
+	for {
+		query := tx.Table(table).Select("id", "red_numbers", "blue_numbers").Order("id asc").Limit(500)
+		if lastID != "" {
+			query = query.Where("id > ?", lastID)
+		}
+		rows := make([]numberRow, 0, 500)
+		if err := query.Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := tx.Table(table).Where("id = ?", row.Id).Updates(map[string]any{
+				"red_mask":  lotteryModel.NumberMask(row.RedNumbers),
+				"blue_mask": lotteryModel.NumberMask(row.BlueNumbers),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		if len(rows) < 500 {
+			return nil
+		}
+		lastID = rows[len(rows)-1].Id
+	}
+}
+
+func dropNumberMasks(tx *gorm.DB) error {
+	for _, item := range numberMaskTables {
+		statements := []string{"DROP INDEX IF EXISTS " + item.index}
+		for _, column := range []string{"red_mask", "blue_mask"} {
+			if tx.Migrator().HasColumn(item.table, column) {
+				statements = append(statements, "ALTER TABLE "+item.table+" DROP COLUMN "+column)
+			}
+		}
+		if err := execStatements(tx, statements...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
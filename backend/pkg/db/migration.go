@@ -0,0 +1,230 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationLockID 是 PostgreSQL 事务级咨询锁的键，多个实例同时启动时保证同一迁移只执行一次。
+const migrationLockID = 7301202601
+
+// baselineVersion 是建立全部基础表的基线迁移版本，回滚不会越过这一版本。
+const baselineVersion int64 = 1
+
+// SchemaMigration 记录已经执行的迁移版本。
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:128"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migration 是一个版本化迁移。Up 与 Down 在同一个事务中执行并同步更新 schema_migrations；
+// Down 为空表示迁移无法还原（例如清理重复数据），回滚时只删除版本记录。
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// MigrationState 是迁移的执行状态。
+type MigrationState struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt"`
+}
+
+// sortedMigrations 返回按版本升序排列的迁移，并检查版本号是否重复。
+func sortedMigrations() ([]Migration, error) {
+	items := append([]Migration(nil), migrations()...)
+	sort.Slice(items, func(left int, right int) bool {
+		return items[left].Version < items[right].Version
+	})
+	for index := 1; index < len(items); index++ {
+		if items[index].Version == items[index-1].Version {
+			return nil, fmt.Errorf("迁移版本 %d 重复", items[index].Version)
+		}
+	}
+	return items, nil
+}
+
+func loadAppliedMigrations(database *gorm.DB) (map[int64]SchemaMigration, error) {
+	if err := database.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("创建 schema_migrations 失败: %w", err)
+	}
+	records := make([]SchemaMigration, 0)
+	if err := database.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// MigrationStatus 返回全部迁移及其执行状态。数据库中存在代码里没有的版本时（例如回退了程序版本）一并列出。
+func MigrationStatus() ([]MigrationState, error) {
+	items, err := sortedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := loadAppliedMigrations(DB)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(items))
+	for _, item := range items {
+		state := MigrationState{Version: item.Version, Name: item.Name}
+		if record, ok := applied[item.Version]; ok {
+			appliedAt := record.AppliedAt
+			state.Applied = true
+			state.AppliedAt = &appliedAt
+			delete(applied, item.Version)
+		}
+		states = append(states, state)
+	}
+	for _, record := range applied {
+		appliedAt := record.AppliedAt
+		states = append(states, MigrationState{Version: record.Version, Name: record.Name, Applied: true, AppliedAt: &appliedAt})
+	}
+	sort.Slice(states, func(left int, right int) bool {
+		return states[left].Version < states[right].Version
+	})
+	return states, nil
+}
+
+// PendingMigrations 返回尚未执行的迁移。
+func PendingMigrations() ([]Migration, error) {
+	return pendingMigrations(DB)
+}
+
+func pendingMigrations(database *gorm.DB) ([]Migration, error) {
+	items, err := sortedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := loadAppliedMigrations(database)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]Migration, 0)
+	for _, item := range items {
+		if _, ok := applied[item.Version]; !ok {
+			pending = append(pending, item)
+		}
+	}
+	return pending, nil
+}
+
+// MigrateUp 依次执行未执行的迁移，target 大于 0 时只执行到该版本（含），返回本次执行的迁移。
+func MigrateUp(target int64) ([]Migration, error) {
+	return migrateUp(DB, target)
+}
+
+func migrateUp(database *gorm.DB, target int64) ([]Migration, error) {
+	pending, err := pendingMigrations(database)
+	if err != nil {
+		return nil, err
+	}
+	executed := make([]Migration, 0, len(pending))
+	for _, item := range pending {
+		if target > 0 && item.Version > target {
+			break
+		}
+		ran, err := runMigration(database, item, true)
+		if err != nil {
+			return executed, fmt.Errorf("执行迁移 %d_%s 失败: %w", item.Version, item.Name, err)
+		}
+		if ran {
+			executed = append(executed, item)
+		}
+	}
+	return executed, nil
+}
+
+// MigrateDown 按版本倒序回滚最近执行的 steps 个迁移，返回本次回滚的迁移。基线迁移不会被回滚。
+func MigrateDown(steps int) ([]Migration, error) {
+	return migrateDown(DB, steps, baselineVersion)
+}
+
+// migrateDown 按版本倒序回滚，steps 大于 0 时最多回滚 steps 个，版本不大于 floor 的迁移保留。
+// 基线迁移始终保留，floor 小于基线版本时按基线版本处理。
+func migrateDown(database *gorm.DB, steps int, floor int64) ([]Migration, error) {
+	items, err := sortedMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := loadAppliedMigrations(database)
+	if err != nil {
+		return nil, err
+	}
+
+	floor = max(floor, baselineVersion)
+	rolledBack := make([]Migration, 0)
+	for index := len(items) - 1; index >= 0 && (steps <= 0 || len(rolledBack) < steps); index-- {
+		item := items[index]
+		if item.Version <= floor {
+			break
+		}
+		if _, ok := applied[item.Version]; !ok {
+			continue
+		}
+		ran, err := runMigration(database, item, false)
+		if err != nil {
+			return rolledBack, fmt.Errorf("回滚迁移 %d_%s 失败: %w", item.Version, item.Name, err)
+		}
+		if ran {
+			rolledBack = append(rolledBack, item)
+		}
+	}
+	return rolledBack, nil
+}
+
+// runMigration 在事务中执行单个迁移。PostgreSQL 下先获取咨询锁并重新确认版本状态，
+// 其他实例已经执行过时直接跳过，返回值表示本次是否实际执行。
+func runMigration(database *gorm.DB, item Migration, up bool) (bool, error) {
+	ran := false
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+				return err
+			}
+		}
+		var count int64
+		if err := tx.Model(&SchemaMigration{}).Where("version = ?", item.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if up == (count > 0) {
+			return nil
+		}
+
+		if up {
+			if err := item.Up(tx); err != nil {
+				return err
+			}
+			ran = true
+			return tx.Create(&SchemaMigration{Version: item.Version, Name: item.Name, AppliedAt: time.Now()}).Error
+		}
+		if item.Down != nil {
+			if err := item.Down(tx); err != nil {
+				return err
+			}
+		}
+		ran = true
+		return tx.Where("version = ?", item.Version).Delete(&SchemaMigration{}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return ran, nil
+}
@@ -0,0 +1,257 @@
+package db
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+	"time"
+
+	lotteryModel "go-fiber-starter/internal/model/lottery"
+	userModel "go-fiber-starter/internal/model/user"
+	"go-fiber-starter/pkg/config"
+	"go-fiber-starter/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func setupMigrationTestDB(t *testing.T) {
+	t.Helper()
+
+	prevConfig, prevDB, prevLogger := config.Current, DB, logger.Logger
+	t.Cleanup(func() {
+		if sqlDB, err := DB.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+		config.Current, DB, logger.Logger = prevConfig, prevDB, prevLogger
+	})
+	logger.Logger = zap.NewNop().Sugar()
+	config.Current.Database = config.DatabaseConfig{Driver: "sqlite", Path: filepath.Join(t.TempDir(), "lottery.sqlite")}
+	if err := Open(); err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+}
+
+func TestMigrateUpRunsDataMigrationsOnce(t *testing.T) {
+	setupMigrationTestDB(t)
+
+	if _, err := MigrateUp(1); err != nil {
+		t.Fatalf("migrate baseline: %v", err)
+	}
+	insertRow(t, "users", map[string]any{"username": "first", "password": "x", "role": "", "created_at": time.Now().Add(-time.Hour)})
+	insertRow(t, "users", map[string]any{"username": "second", "password": "x", "role": ""})
+
+	executed, err := MigrateUp(0)
+	if err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if len(executed) != len(migrations())-1 {
+		t.Fatalf("expected remaining %d migrations, got %d", len(migrations())-1, len(executed))
+	}
+	first := userModel.User{}
+	if err := DB.First(&first, "username = ?", "first").Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if first.Role != userModel.RoleAdmin {
+		t.Fatalf("expected earliest user to become admin, got %q", first.Role)
+	}
+
+	executed, err = MigrateUp(0)
+	if err != nil || len(executed) != 0 {
+		t.Fatalf("expected no migrations on second run, got %d (%v)", len(executed), err)
+	}
+	pending, err := PendingMigrations()
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("expected nothing pending, got %d (%v)", len(pending), err)
+	}
+}
+
+func TestMigrateDownRollsBackInReverseOrder(t *testing.T) {
+	setupMigrationTestDB(t)
+
+	if _, err := MigrateUp(0); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	rolledBack, err := MigrateDown(1)
+	if err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	latest := migrations()[len(migrations())-1]
+	if len(rolledBack) != 1 || rolledBack[0].Version != latest.Version {
+		t.Fatalf("expected latest migration rolled back, got %+v", rolledBack)
+	}
+	if DB.Migrator().HasColumn("ticket_entries", "red_mask") || DB.Migrator().HasIndex("ticket_entries", "idx_ticket_entries_ticket_number_masks") {
+		t.Fatalf("expected number masks dropped by down migration")
+	}
+
+	states, err := MigrationStatus()
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if last := states[len(states)-1]; last.Applied || !states[0].Applied {
+		t.Fatalf("unexpected status after rollback: %+v", states)
+	}
+
+	rolledBack, err = MigrateDown(0)
+	if err != nil {
+		t.Fatalf("migrate down all: %v", err)
+	}
+	if len(rolledBack) != len(migrations())-2 {
+		t.Fatalf("expected every migration except baseline rolled back, got %d", len(rolledBack))
+	}
+	version, err := SchemaVersion(DB)
+	if err != nil || version != baselineVersion {
+		t.Fatalf("expected baseline kept, got version %d (%v)", version, err)
+	}
+	missing, err := MissingTables()
+	if err != nil {
+		t.Fatalf("missing tables: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Fatalf("expected baseline tables kept, got missing %v", missing)
+	}
+}
+
+func TestUniqueIndexMigrationRollsBackToUserIndexes(t *testing.T) {
+	setupMigrationTestDB(t)
+
+	indexes := map[string][]string{
+		"ticket_entries":  {"idx_ticket_entries_ticket_sequence"},
+		"tickets":         {"idx_tickets_user_lottery_issue_signature", "idx_tickets_personal_lottery_issue_signature", "idx_tickets_ledger_lottery_issue_signature", "idx_tickets_user_lottery_issue"},
+		"recommendations": {"idx_recommendations_user_lottery_issue", "idx_recommendations_personal_lottery_issue", "idx_recommendations_ledger_lottery_issue"},
+	}
+	existing := func() map[string]bool {
+		result := make(map[string]bool)
+		for table, names := range indexes {
+			for _, name := range names {
+				result[name] = DB.Migrator().HasIndex(table, name)
+			}
+		}
+		return result
+	}
+
+	if _, err := MigrateUp(8); err != nil {
+		t.Fatalf("migrate up to 8: %v", err)
+	}
+	applied := existing()
+	if applied["idx_tickets_user_lottery_issue_signature"] || applied["idx_recommendations_user_lottery_issue"] || !applied["idx_tickets_personal_lottery_issue_signature"] {
+		t.Fatalf("unexpected indexes after up: %v", applied)
+	}
+
+	if _, err := migrateDown(DB, 0, 7); err != nil {
+		t.Fatalf("migrate down to 7: %v", err)
+	}
+	rolledBack := existing()
+	for name, exists := range rolledBack {
+		want := name == "idx_tickets_user_lottery_issue_signature" || name == "idx_recommendations_user_lottery_issue"
+		if exists != want {
+			t.Fatalf("unexpected indexes after down: %v", rolledBack)
+		}
+	}
+
+	if _, err := MigrateUp(8); err != nil {
+		t.Fatalf("migrate up again: %v", err)
+	}
+	if reapplied := existing(); !maps.Equal(reapplied, applied) {
+		t.Fatalf("expected same indexes after up again, got %v, want %v", reapplied, applied)
+	}
+}
+
+func TestMigrateCleansDuplicateTicketEntriesAndRefreshesPrize(t *testing.T) {
+	setupMigrationTestDB(t)
+
+	if _, err := MigrateUp(2); err != nil {
+		t.Fatalf("migrate up to 2: %v", err)
+	}
+	now := time.Now()
+	ticketID := insertRow(t, "tickets", map[string]any{"lottery_code": "ssq", "issue": "2026001", "status": "pending", "prize_amount": 105.0, "checked_at": now})
+	insertRow(t, "ticket_entries", map[string]any{"ticket_id": ticketID, "sequence": 1, "is_winning": true, "prize_amount": 5.0, "created_at": now.Add(-time.Minute)})
+	insertRow(t, "ticket_entries", map[string]any{"ticket_id": ticketID, "sequence": 1, "is_winning": true, "prize_amount": 100.0, "created_at": now})
+	insertRow(t, "ticket_entries", map[string]any{"ticket_id": ticketID, "sequence": 2, "prize_amount": 0.0, "created_at": now})
+
+	if _, err := MigrateUp(0); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	var entryCount int64
+	if err := DB.Model(&lotteryModel.TicketEntry{}).Where("ticket_id = ?", ticketID).Count(&entryCount).Error; err != nil {
+		t.Fatalf("count entries: %v", err)
+	}
+	if entryCount != 2 {
+		t.Fatalf("expected duplicate entry removed, got %d entries", entryCount)
+	}
+	ticket := lotteryModel.Ticket{}
+	if err := DB.First(&ticket, "id = ?", ticketID).Error; err != nil {
+		t.Fatalf("load ticket: %v", err)
+	}
+	if ticket.PrizeAmount != 500 || ticket.Status != "won" {
+		t.Fatalf("unexpected refreshed ticket: prize=%d status=%q", ticket.PrizeAmount, ticket.Status)
+	}
+}
+
+func TestMigrateConvertsLegacyMoneyColumnsToCents(t *testing.T) {
+	setupMigrationTestDB(t)
+
+	if _, err := MigrateUp(8); err != nil {
+		t.Fatalf("migrate up to 8: %v", err)
+	}
+	ticketID := insertRow(t, "tickets", map[string]any{"lottery_code": "ssq", "issue": "2026001", "status": "pending", "cost_amount": 10.5, "prize_amount": 0.07})
+
+	if _, err := MigrateUp(0); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if DB.Migrator().HasColumn("tickets", "cost_amount") || DB.Migrator().HasColumn("tickets", "prize_amount") {
+		t.Fatalf("expected legacy money columns dropped")
+	}
+	if !DB.Migrator().HasIndex("tickets", "idx_tickets_personal_lottery_issue_signature") {
+		t.Fatalf("expected unique index kept after dropping columns")
+	}
+	converted := lotteryModel.Ticket{}
+	if err := DB.First(&converted, "id = ?", ticketID).Error; err != nil {
+		t.Fatalf("load ticket: %v", err)
+	}
+	if converted.CostAmount != 1050 || converted.PrizeAmount != 7 {
+		t.Fatalf("unexpected converted amounts: cost=%d prize=%d", converted.CostAmount, converted.PrizeAmount)
+	}
+}
+
+func TestMigrateBackfillsNumberMasks(t *testing.T) {
+	setupMigrationTestDB(t)
+
+	if _, err := MigrateUp(9); err != nil {
+		t.Fatalf("migrate up to 9: %v", err)
+	}
+	// 升级前写入的数据还没有位图列。
+	insertRow(t, "draw_results", map[string]any{"lottery_code": "ssq", "issue": "2026001", "red_numbers": "01,07,12,20,28,33", "blue_numbers": "16"})
+
+	if _, err := MigrateUp(0); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	if !DB.Migrator().HasIndex("draw_results", "idx_draw_results_lottery_number_masks") {
+		t.Fatalf("expected number mask index created")
+	}
+	var count int64
+	if err := DB.Model(&lotteryModel.DrawResult{}).
+		Where("(red_mask & ?) <> 0 AND (blue_mask & ?) <> 0", lotteryModel.NumberBit(7), lotteryModel.NumberBit(16)).
+		Count(&count).Error; err != nil {
+		t.Fatalf("query masks: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected backfilled draw matched by masks, got %d", count)
+	}
+}
+
+// insertRow 按表名直接写入一行，模拟旧版本表结构下已有的数据，返回生成的主键。
+func insertRow(t *testing.T, table string, values map[string]any) string {
+	t.Helper()
+
+	id := uuid.NewString()
+	values["id"] = id
+	if _, ok := values["created_at"]; !ok {
+		values["created_at"] = time.Now()
+	}
+	values["updated_at"] = values["created_at"]
+	if err := DB.Table(table).Create(values).Error; err != nil {
+		t.Fatalf("insert into %s: %v", table, err)
+	}
+	return id
+}
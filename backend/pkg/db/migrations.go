@@ -0,0 +1,89 @@
+package db
+
+import "gorm.io/gorm"
+
+// migrations 返回全部版本化迁移。新增迁移只能追加更大的版本号，已发布的迁移不要再修改；
+// 语句需要同时兼容 SQLite 和 PostgreSQL。
+func migrations() []Migration {
+	return []Migration{
+		{
+			// 基线按冻结的表结构快照建表，已有数据库升级时 AutoMigrate 只补齐缺失的表和列。
+			// 基线不可回滚，回滚迁移最多退回到这一版本，不会删除数据表。
+			Version: 1,
+			Name:    "baseline_schema",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(baselineModels()...)
+			},
+		},
+		{Version: 2, Name: "backfill_user_roles", Up: backfillUserRoles},
+		{Version: 3, Name: "cleanup_duplicate_ticket_entries", Up: cleanupDuplicateTicketEntries},
+		{Version: 4, Name: "backfill_lottery_user_ownership", Up: backfillLotteryUserOwnership},
+		{Version: 5, Name: "backfill_ticket_entry_signatures", Up: backfillTicketEntrySignatures},
+		{Version: 6, Name: "cleanup_duplicate_tickets", Up: cleanupDuplicateTickets},
+		{Version: 7, Name: "cleanup_duplicate_recommendations", Up: cleanupDuplicateRecommendations},
+		{
+			// 个人数据按用户去重，共享账本内的数据按账本去重，两者分别用部分唯一索引约束。
+			Version: 8,
+			Name:    "ticket_and_recommendation_unique_indexes",
+			Up: func(tx *gorm.DB) error {
+				return execStatements(tx,
+					"CREATE UNIQUE INDEX IF NOT EXISTS idx_ticket_entries_ticket_sequence ON ticket_entries(ticket_id, sequence)",
+					"DROP INDEX IF EXISTS idx_tickets_user_lottery_issue_signature",
+					"CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_personal_lottery_issue_signature ON tickets(user_id, lottery_code, issue, entry_signature) WHERE ledger_id IS NULL",
+					"CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_ledger_lottery_issue_signature ON tickets(ledger_id, lottery_code, issue, entry_signature) WHERE ledger_id IS NOT NULL",
+					"DROP INDEX IF EXISTS idx_recommendations_user_lottery_issue_created",
+					"DROP INDEX IF EXISTS idx_recommendations_user_lottery_issue",
+					"CREATE UNIQUE INDEX IF NOT EXISTS idx_recommendations_personal_lottery_issue ON recommendations(user_id, lottery_code, issue) WHERE ledger_id IS NULL",
+					"CREATE UNIQUE INDEX IF NOT EXISTS idx_recommendations_ledger_lottery_issue ON recommendations(ledger_id, lottery_code, issue) WHERE ledger_id IS NOT NULL",
+					"CREATE INDEX IF NOT EXISTS idx_tickets_user_lottery_issue ON tickets(user_id, lottery_code, issue)",
+				)
+			},
+			// 回滚时恢复按用户去重的旧唯一索引，回滚后的表结构仍保留重复数据约束。
+			Down: func(tx *gorm.DB) error {
+				return execStatements(tx,
+					"DROP INDEX IF EXISTS idx_tickets_user_lottery_issue",
+					"DROP INDEX IF EXISTS idx_recommendations_ledger_lottery_issue",
+					"DROP INDEX IF EXISTS idx_recommendations_personal_lottery_issue",
+					"DROP INDEX IF EXISTS idx_tickets_ledger_lottery_issue_signature",
+					"DROP INDEX IF EXISTS idx_tickets_personal_lottery_issue_signature",
+					"DROP INDEX IF EXISTS idx_ticket_entries_ticket_sequence",
+					"CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_user_lottery_issue_signature ON tickets(user_id, lottery_code, issue, entry_signature)",
+					"CREATE UNIQUE INDEX IF NOT EXISTS idx_recommendations_user_lottery_issue ON recommendations(user_id, lottery_code, issue)",
+				)
+			},
+		},
+		{
+			// 金额改为以分为单位的整数列，旧的浮点列换算后删除。
+			Version: 9,
+			Name:    "money_integer_cents",
+			Up:      convertMoneyToCents,
+			Down:    convertMoneyToYuan,
+		},
+		{
+			// 号码位图替代逗号分隔字符串的 LIKE 匹配，供按号码筛选和号码频次统计使用。
+			Version: 10,
+			Name:    "number_masks",
+			Up:      addNumberMasks,
+			Down:    dropNumberMasks,
+		},
+	}
+}
+
+func execStatements(tx *gorm.DB, statements ...string) error {
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execStatementsWithArgs 依次执行带同一组参数的语句。
+func execStatementsWithArgs(tx *gorm.DB, args any, statements ...string) error {
+	for _, statement := range statements {
+		if err := tx.Exec(statement, args).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"fmt"
+	"os"
+
+	"go-fiber-starter/pkg/config"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// IsSQLite 判断当前配置的数据库是否为 SQLite。
+func IsSQLite() bool {
+	return IsSQLiteConfig(config.Current.Database)
+}
+
+// IsSQLiteConfig 判断给定配置是否使用 SQLite。
+func IsSQLiteConfig(cfg config.DatabaseConfig) bool {
+	return driverOf(cfg) == "sqlite"
+}
+
+// DriverName 返回规范化后的数据库驱动名，sqlite 或 postgres。
+func DriverName() string {
+	if isPostgresDriver() {
+		return "postgres"
+	}
+	return currentDriver()
+}
+
+// SnapshotSQLite 用 VACUUM INTO 把数据库在线复制到 target。复制在一个读事务中完成，
+// 得到的是一致的快照，期间其他连接仍然可以读写。target 不能已经存在。
+func SnapshotSQLite(database *gorm.DB, target string) error {
+	if err := database.Exec("VACUUM INTO ?", target).Error; err != nil {
+		return fmt.Errorf("生成 SQLite 快照失败: %w", err)
+	}
+	return nil
+}
+
+// OpenSQLiteFile 以独立连接打开已有的 SQLite 文件，用于校验和读取备份中的快照，不会创建新文件。
+func OpenSQLiteFile(path string) (*gorm.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return gorm.Open(sqlite.Open(path), &gorm.Config{Logger: gormLogger.Discard})
+}
+
+// CheckSQLiteIntegrity 执行 PRAGMA integrity_check，数据库文件损坏时返回错误。
+func CheckSQLiteIntegrity(database *gorm.DB) error {
+	results := make([]string, 0, 1)
+	if err := database.Raw("PRAGMA integrity_check").Scan(&results).Error; err != nil {
+		return fmt.Errorf("检查 SQLite 完整性失败: %w", err)
+	}
+	if len(results) != 1 || results[0] != "ok" {
+		return fmt.Errorf("SQLite 完整性检查未通过: %v", results)
+	}
+	return nil
+}
+
+// CloseDatabase 关闭连接池，忽略已经关闭的连接。
+func CloseDatabase(database *gorm.DB) {
+	if database == nil {
+		return
+	}
+	if sqlDB, err := database.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
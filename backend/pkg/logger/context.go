@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// 上下文日志的常用字段名，同一含义在各处保持一致，便于在日志系统中按字段检索。
+const (
+	FieldRequestID   = "request_id"
+	FieldTraceID     = "trace_id"
+	FieldUserID      = "user_id"
+	FieldLotteryCode = "lottery_code"
+	FieldTicketID    = "ticket_id"
+	FieldJobName     = "job_name"
+)
+
+type fieldsKey struct{}
+
+// WithFields 返回附加了日志字段的上下文，keysAndValues 按键、值交替传入。
+// 同名字段以后加入的为准，之后通过 InfoContext 等函数输出的日志都会带上这些字段。
+func WithFields(ctx context.Context, keysAndValues ...any) context.Context {
+	if len(keysAndValues) < 2 {
+		return ctx
+	}
+	existing := Fields(ctx)
+	merged := make([]any, 0, len(existing)+len(keysAndValues))
+	for index := 0; index+1 < len(existing); index += 2 {
+		if !containsKey(keysAndValues, existing[index]) {
+			merged = append(merged, existing[index], existing[index+1])
+		}
+	}
+	merged = append(merged, keysAndValues[:len(keysAndValues)/2*2]...)
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// Fields 返回上下文中已附加的日志字段。
+func Fields(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]any)
+	return fields
+}
+
+// Field 返回上下文中指定字段的值，没有时返回空字符串。
+func Field(ctx context.Context, key string) string {
+	fields := Fields(ctx)
+	for index := 0; index+1 < len(fields); index += 2 {
+		if fields[index] == key {
+			value, _ := fields[index+1].(string)
+			return value
+		}
+	}
+	return ""
+}
+
+func containsKey(keysAndValues []any, key any) bool {
+	for index := 0; index+1 < len(keysAndValues); index += 2 {
+		if keysAndValues[index] == key {
+			return true
+		}
+	}
+	return false
+}
+
+// contextLogger 返回带上下文字段和追踪 ID 的日志器。
+func contextLogger(ctx context.Context) *zap.SugaredLogger {
+	if Logger == nil || ctx == nil {
+		return Logger
+	}
+	fields := Fields(ctx)
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		fields = append(fields[:len(fields):len(fields)], FieldTraceID, spanContext.TraceID().String())
+	}
+	if len(fields) == 0 {
+		return Logger
+	}
+	return Logger.With(fields...)
+}
+
+// DebugContext 输出带上下文字段的调试日志
+func DebugContext(ctx context.Context, format string, args ...interface{}) {
+	if log := contextLogger(ctx); log != nil {
+		log.Debugf(format, args...)
+	}
+}
+
+// InfoContext 输出带上下文字段的信息日志
+func InfoContext(ctx context.Context, format string, args ...interface{}) {
+	if log := contextLogger(ctx); log != nil {
+		log.Infof(format, args...)
+	}
+}
+
+// WarnContext 输出带上下文字段的警告日志
+func WarnContext(ctx context.Context, format string, args ...interface{}) {
+	if log := contextLogger(ctx); log != nil {
+		log.Warnf(format, args...)
+	}
+}
+
+// ErrorContext 输出带上下文字段的错误日志
+func ErrorContext(ctx context.Context, format string, args ...interface{}) {
+	if log := contextLogger(ctx); log != nil {
+		log.Errorf(format, args...)
+	}
+}
+
+// InfowContext 输出带上下文字段和额外键值对的信息日志，适合访问日志等需要按字段检索的场景。
+func InfowContext(ctx context.Context, message string, keysAndValues ...interface{}) {
+	if log := contextLogger(ctx); log != nil {
+		log.Infow(message, keysAndValues...)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"go-fiber-starter/pkg/util"
 	"io"
 	"os"
+	"strings"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -20,18 +21,43 @@ import (
 
 var Logger *zap.SugaredLogger
 
-func Init() error {
+// fileWriters 记录当前打开的日志文件，重新初始化时关闭，避免同一文件被多个写入器轮转。
+var fileWriters []*lumberjack.Logger
+
+// Options 控制日志级别和输出位置。Paths 中的 stdout、stderr 输出带颜色的控制台格式，
+// 其余视为文件路径，按 JSON 格式写入并按大小轮转。
+type Options struct {
+	Level      string
+	Paths      []string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+}
 
-	if err := util.EnsureDir("log"); err != nil {
-		return fmt.Errorf("创建日志目录失败: %w", err)
+// DefaultOptions 是读取配置前使用的默认输出：info 级别，同时写控制台和 ./log/log.json。
+func DefaultOptions() Options {
+	return Options{
+		Level:      "info",
+		Paths:      []string{"stdout", "./log/log.json"},
+		MaxSizeMB:  10,
+		MaxBackups: 3,
+		MaxAgeDays: 28,
 	}
+}
 
-	lumberjacklogger := &lumberjack.Logger{
-		Filename:   "./log/log.json",
-		MaxSize:    10, // megabytes
-		MaxBackups: 3,
-		MaxAge:     28,   //days
-		Compress:   true, // disabled by default
+func Init(options Options) error {
+	level := zap.InfoLevel
+	if strings.TrimSpace(options.Level) != "" {
+		parsed, err := zapcore.ParseLevel(strings.TrimSpace(options.Level))
+		if err != nil {
+			return fmt.Errorf("日志级别 %q 无效: %w", options.Level, err)
+		}
+		level = parsed
+	}
+	defaults := DefaultOptions()
+	paths := options.Paths
+	if len(paths) == 0 {
+		paths = defaults.Paths
 	}
 
 	// 1. 文件日志配置 - 不带颜色
@@ -48,35 +74,56 @@ func Init() error {
 		enc.AppendString("\x1b[36m" + loggerName + "\x1b[0m") // 青色，ANSI转义码
 	}
 
-	// 文件输出编码器 - 使用无颜色的配置
-	fileEncoder := zapcore.NewJSONEncoder(fileCfg)
-
-	// 控制台输出编码器 - 使用带颜色的配置
-	consoleEncoder := zapcore.NewConsoleEncoder(consoleCfg)
-
-	// 创建多核心 - 同时写入文件和控制台
-	core := zapcore.NewTee(
-		zapcore.NewCore(
-			fileEncoder,                       // 文件编码设置
-			zapcore.AddSync(lumberjacklogger), // 输出到文件
-			zap.InfoLevel,                     // 日志等级
-		),
-		zapcore.NewCore(
-			consoleEncoder,             // 控制台编码设置
-			zapcore.AddSync(os.Stdout), // 输出到控制台
-			zap.InfoLevel,              // 日志等级
-		),
-	)
+	cores := make([]zapcore.Core, 0, len(paths))
+	writers := make([]*lumberjack.Logger, 0, len(paths))
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		switch path {
+		case "":
+			continue
+		case "stdout":
+			cores = append(cores, zapcore.NewCore(zapcore.NewConsoleEncoder(consoleCfg), zapcore.AddSync(os.Stdout), level))
+		case "stderr":
+			cores = append(cores, zapcore.NewCore(zapcore.NewConsoleEncoder(consoleCfg), zapcore.AddSync(os.Stderr), level))
+		default:
+			if err := util.EnsureDir(path); err != nil {
+				return fmt.Errorf("创建日志目录失败: %w", err)
+			}
+			writer := &lumberjack.Logger{
+				Filename:   path,
+				MaxSize:    positiveOr(options.MaxSizeMB, defaults.MaxSizeMB), // megabytes
+				MaxBackups: positiveOr(options.MaxBackups, defaults.MaxBackups),
+				MaxAge:     positiveOr(options.MaxAgeDays, defaults.MaxAgeDays), //days
+				Compress:   true,
+			}
+			writers = append(writers, writer)
+			cores = append(cores, zapcore.NewCore(zapcore.NewJSONEncoder(fileCfg), zapcore.AddSync(writer), level))
+		}
+	}
 
 	// 添加调用者信息，便于调试
-	log := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	log := zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddCallerSkip(1))
 	defer log.Sync()
 
+	if Logger != nil {
+		_ = Logger.Sync()
+	}
+	for _, writer := range fileWriters {
+		_ = writer.Close()
+	}
+	fileWriters = writers
 	Logger = log.Sugar()
-	Logger.Infof("日志系统初始化完成")
+	Logger.Infof("日志系统初始化完成，级别 %s，输出 %s", level, strings.Join(paths, ", "))
 	return nil
 }
 
+func positiveOr(value int, fallback int) int {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
 // FiberLogWriter 实现 io.Writer 接口，用于接收 fiber 日志
 type FiberLogWriter struct{}
 
@@ -14,7 +14,7 @@ import { RecommendationPanel } from "@/components/lottery/recommendation-panel";
 import { RecordPanel } from "@/components/lottery/record-panel";
 import { isTrendLotteryCode, useLotteryNumberTrends } from "@/hooks/use-lottery-number-trends";
 import { getStoredToken } from "@/lib/api/client";
-import { getProfile, loginAndStoreToken, logout } from "@/lib/api/methods/auth";
+import { consumeOIDCRedirect, getProfile, loginAndStoreToken, logout } from "@/lib/api/methods/auth";
 import {
   createTicket,
   deleteRecommendation,
@@ -391,6 +391,10 @@ export default function App() {
 
   useEffect(() => {
     async function bootstrap() {
+      const oidcError = consumeOIDCRedirect();
+      if (oidcError) {
+        toast.error(oidcError);
+      }
       if (!getStoredToken()) {
         setLoading(false);
         return;
@@ -1,9 +1,14 @@
 import type { ApiResponse } from "@/types/lottery";
 
 const TOKEN_KEY = "lottery_token";
+const REFRESH_TOKEN_KEY = "lottery_refresh_token";
+// 界面为中文，固定请求中文错误消息，避免浏览器语言为英文时提示与界面不一致。
+const ACCEPT_LANGUAGE = "zh-CN";
+
+let refreshing: Promise<boolean> | null = null;
 
 function getRequestHeaders(body?: unknown) {
-  const headers = new Headers();
+  const headers = new Headers({ "Accept-Language": ACCEPT_LANGUAGE });
   const token = localStorage.getItem(TOKEN_KEY);
   if (token) {
     headers.set("Authorization", `Bearer ${token}`);
@@ -18,43 +23,84 @@ async function parseResponse<T>(response: Response): Promise<T> {
   const payload = (await response.json()) as ApiResponse<T>;
   if (!response.ok || !payload.flag) {
     if (response.status === 401) {
-      localStorage.removeItem(TOKEN_KEY);
+      clearStoredToken();
     }
     throw new Error(payload.msg || "请求失败");
   }
   return payload.data;
 }
 
+async function refreshAccessToken() {
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+  if (!refreshToken) {
+    return false;
+  }
+  try {
+    const response = await fetch("/api/auth/refresh", {
+      method: "POST",
+      headers: { "Content-Type": "application/json", "Accept-Language": ACCEPT_LANGUAGE },
+      body: JSON.stringify({ refreshToken }),
+    });
+    const payload = (await response.json()) as ApiResponse<{ token: string; refreshToken: string }>;
+    if (!response.ok || !payload.flag) {
+      return false;
+    }
+    setStoredToken(payload.data.token, payload.data.refreshToken);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+// 访问令牌过期时用刷新令牌换新并重试一次，并发请求共用同一次刷新。
+async function sendRequest(url: string, init: RequestInit, rawBody?: unknown) {
+  const response = await fetch(url, { ...init, headers: getRequestHeaders(rawBody) });
+  if (response.status !== 401 || !localStorage.getItem(REFRESH_TOKEN_KEY)) {
+    return response;
+  }
+  refreshing ??= refreshAccessToken().finally(() => {
+    refreshing = null;
+  });
+  if (!(await refreshing)) {
+    return response;
+  }
+  return fetch(url, { ...init, headers: getRequestHeaders(rawBody) });
+}
+
 export async function apiGet<T>(url: string): Promise<T> {
-  const response = await fetch(url, {
+  const response = await sendRequest(url, {
     cache: "no-store",
-    headers: getRequestHeaders(),
   });
   return parseResponse<T>(response);
 }
 
 export async function apiPost<T, B = unknown>(url: string, body?: B): Promise<T> {
-  const response = await fetch(url, {
-    method: "POST",
-    headers: getRequestHeaders(body),
-    body: body instanceof FormData ? body : body ? JSON.stringify(body) : undefined,
-  });
+  const response = await sendRequest(
+    url,
+    {
+      method: "POST",
+      body: body instanceof FormData ? body : body ? JSON.stringify(body) : undefined,
+    },
+    body,
+  );
   return parseResponse<T>(response);
 }
 
 export async function apiPut<T, B = unknown>(url: string, body?: B): Promise<T> {
-  const response = await fetch(url, {
-    method: "PUT",
-    headers: getRequestHeaders(body),
-    body: body instanceof FormData ? body : body ? JSON.stringify(body) : undefined,
-  });
+  const response = await sendRequest(
+    url,
+    {
+      method: "PUT",
+      body: body instanceof FormData ? body : body ? JSON.stringify(body) : undefined,
+    },
+    body,
+  );
   return parseResponse<T>(response);
 }
 
 export async function apiDelete<T>(url: string): Promise<T> {
-  const response = await fetch(url, {
+  const response = await sendRequest(url, {
     method: "DELETE",
-    headers: getRequestHeaders(),
   });
   return parseResponse<T>(response);
 }
@@ -63,10 +109,18 @@ export function getStoredToken() {
   return localStorage.getItem(TOKEN_KEY) || "";
 }
 
-export function setStoredToken(token: string) {
+export function getStoredRefreshToken() {
+  return localStorage.getItem(REFRESH_TOKEN_KEY) || "";
+}
+
+export function setStoredToken(token: string, refreshToken?: string) {
   localStorage.setItem(TOKEN_KEY, token);
+  if (refreshToken) {
+    localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
+  }
 }
 
 export function clearStoredToken() {
   localStorage.removeItem(TOKEN_KEY);
+  localStorage.removeItem(REFRESH_TOKEN_KEY);
 }
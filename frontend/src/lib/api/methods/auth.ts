@@ -1,5 +1,5 @@
-import { apiGet, apiPost, clearStoredToken, setStoredToken } from "../client";
-import type { AuthToken, AuthUser } from "@/types/auth";
+import { apiGet, apiPost, clearStoredToken, getStoredToken, setStoredToken } from "../client";
+import type { AuthToken, AuthUser, OIDCSettings, RegistrationStatus } from "@/types/auth";
 
 export function login(username: string, password: string) {
   return apiPost<AuthToken, { username: string; password: string }>("/api/auth/login", {
@@ -8,23 +8,55 @@ export function login(username: string, password: string) {
   });
 }
 
-export function register(username: string, password: string) {
-  return apiPost<AuthUser, { username: string; password: string }>("/api/auth/register", {
+export function register(username: string, password: string, inviteCode?: string) {
+  return apiPost<AuthUser, { username: string; password: string; inviteCode?: string }>("/api/auth/register", {
     username,
     password,
+    inviteCode,
   });
 }
 
+export function getRegistrationStatus() {
+  return apiGet<RegistrationStatus>("/api/auth/registration");
+}
+
+export function getOIDCSettings() {
+  return apiGet<OIDCSettings>("/api/auth/oidc/config");
+}
+
+export function startOIDCLogin() {
+  window.location.assign("/api/auth/oidc/login");
+}
+
+// consumeOIDCRedirect 读取单点登录回调带回的 URL 片段，保存令牌后从地址栏中清除，返回错误信息。
+export function consumeOIDCRedirect() {
+  const params = new URLSearchParams(window.location.hash.slice(1));
+  const token = params.get("token");
+  const error = params.get("oidcError");
+  if (!token && !error && !params.has("oidc")) {
+    return null;
+  }
+  if (token) {
+    setStoredToken(token, params.get("refreshToken") ?? undefined);
+  }
+  window.history.replaceState(null, "", window.location.pathname + window.location.search);
+  return error;
+}
+
 export function getProfile() {
   return apiGet<AuthUser>("/api/auth/profile");
 }
 
 export async function loginAndStoreToken(username: string, password: string) {
   const result = await login(username, password);
-  setStoredToken(result.token);
+  setStoredToken(result.token, result.refreshToken);
   return result;
 }
 
 export function logout() {
+  if (getStoredToken()) {
+    // 服务端吊销会话失败不影响本地退出。
+    void apiPost("/api/auth/logout").catch(() => undefined);
+  }
   clearStoredToken();
 }
@@ -1,8 +1,23 @@
 export interface AuthToken {
   token: string;
+  refreshToken: string;
+  expiresIn: number;
+  refreshExpiresIn: number;
+  sessionId: string;
 }
 
 export interface AuthUser {
   id: string;
   username: string;
 }
+
+export interface RegistrationStatus {
+  mode: "open" | "closed" | "invite";
+  inviteRequired: boolean;
+  open: boolean;
+}
+
+export interface OIDCSettings {
+  enabled: boolean;
+  providerName: string;
+}
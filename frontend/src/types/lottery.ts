@@ -49,6 +49,7 @@ export interface DrawResultPage {
   pageSize: number;
   total: number;
   hasMore: boolean;
+  nextCursor?: string;
 }
 
 export interface RecommendationEntry {
@@ -166,6 +167,7 @@ export interface TicketHistoryPage {
   pageSize: number;
   total: number;
   hasMore: boolean;
+  nextCursor?: string;
 }
 
 export interface TicketImportRowResult {
@@ -229,6 +231,9 @@ export interface DashboardStats {
   totalPrize: number;
   totalRecommendations: number;
   purchasedRecommendations: number;
+  syndicateTickets?: number;
+  syndicateCost?: number;
+  syndicatePrize?: number;
 }
 
 export interface DashboardData {
@@ -244,5 +249,6 @@ export interface ApiResponse<T> {
   code: number;
   data: T;
   msg?: string;
+  errorCode?: string;
   time: string;
 }